@@ -2,23 +2,25 @@ package main
 
 import (
 	"context"
-	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
-	"github.com/niklvrr/AvitoInternship2025/internal/transport"
-	"github.com/niklvrr/AvitoInternship2025/internal/transport/handler"
-	"github.com/niklvrr/AvitoInternship2025/internal/usecase/service"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/db"
+	"go.uber.org/zap"
 
 	"github.com/niklvrr/AvitoInternship2025/internal/config"
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/db"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/integration"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/notifier"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/handler"
+	"github.com/niklvrr/AvitoInternship2025/internal/usecase/service"
 	"github.com/niklvrr/AvitoInternship2025/pkg/logger"
-
-	"go.uber.org/zap"
-
-	"log"
-	"os"
-	"os/signal"
-	"syscall"
 )
 
 func main() {
@@ -48,17 +50,42 @@ func main() {
 	userRepo := repository.NewUserRepository(db, logger)
 	teamRepo := repository.NewTeamRepository(db, logger)
 	prRepo := repository.NewPrRepository(db, logger)
+	webhookRepo := repository.NewWebhookRepository(db, logger)
+	integrationRepo := repository.NewIntegrationRepository(db, logger)
+
+	// Инициализация стратегий выбора ревьюеров
+	selectors, err := service.NewReviewerSelectors(cfg.Reviewer.Strategy, cfg.Reviewer.TeamStrategies, cfg.Reviewer.PairingWindow)
+	if err != nil {
+		logger.Fatal("Reviewer selectors init error", zap.Error(err))
+	}
+
+	// Шина событий для потока /events/stream
+	eventBus := service.NewEventBus(cfg.Events.HistorySize, cfg.Events.SubscriberBuffer, logger)
 
 	// Инициализация сервисов
-	userService := service.NewUserService(userRepo, logger)
-	teamService := service.NewTeamService(teamRepo, logger)
-	prService := service.NewPrService(prRepo, logger)
+	userService := service.NewUserService(userRepo, selectors, eventBus, logger)
+	teamService := service.NewTeamService(teamRepo, selectors, eventBus, logger)
+	prService := service.NewPrService(prRepo, selectors, eventBus, logger)
+	prService.SetBusinessHours(service.BusinessHours{
+		Location: cfg.Sla.Location,
+		Start:    cfg.Sla.WorkdayStart,
+		End:      cfg.Sla.WorkdayEnd,
+	})
+	webhookService := service.NewWebhookService(webhookRepo, logger)
+	eventStreamService := service.NewEventStreamService(eventBus, teamRepo, logger)
+	integrationService := service.NewIntegrationService(integrationRepo, prService, service.IntegrationConfig{
+		GitHubSecret: cfg.Integration.GitHubWebhookSecret,
+		GitLabToken:  cfg.Integration.GitLabWebhookToken,
+	}, logger)
 
 	// Инициализация хэндлеров
 	userHandler := handler.NewUserHandler(userService, logger)
 	teamHandler := handler.NewTeamHandler(teamService, logger)
 	prHandler := handler.NewPrHandler(prService, logger)
 	statsHandler := handler.NewStatsHandler(prService, logger)
+	webhookHandler := handler.NewWebhookHandler(webhookService, logger)
+	eventsHandler := handler.NewEventsHandler(eventStreamService, logger)
+	integrationHandler := handler.NewIntegrationHandler(integrationService, logger)
 	healthHandler := handler.NewHealthHandler(logger)
 
 	// Инициализация роутера
@@ -67,6 +94,9 @@ func main() {
 		teamHandler,
 		prHandler,
 		statsHandler,
+		webhookHandler,
+		eventsHandler,
+		integrationHandler,
 		healthHandler,
 		logger,
 	)
@@ -82,6 +112,53 @@ func main() {
 
 	logger.Info("Server started", zap.String("port", cfg.App.Port))
 
+	// Запуск диспетчера вебхуков, останавливается вместе с ctx
+	dispatcher := service.NewWebhookDispatcher(webhookRepo, &http.Client{}, service.WebhookDispatcherConfig{
+		PollInterval:   cfg.Webhook.PollInterval,
+		BatchSize:      cfg.Webhook.BatchSize,
+		MaxAttempts:    cfg.Webhook.MaxAttempts,
+		BaseBackoff:    cfg.Webhook.BaseBackoff,
+		MaxBackoff:     cfg.Webhook.MaxBackoff,
+		RequestTimeout: cfg.Webhook.RequestTimeout,
+	}, logger)
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatcher.Run(ctx)
+	}()
+
+	// Передача назначенных ревьюеров в GitHub/GitLab для провайдеров с токеном API
+	reviewerClients := make(map[string]service.ReviewerClient)
+	if cfg.Integration.GitHubToken != "" {
+		reviewerClients[domain.ProviderGitHub] = integration.NewGitHubClient(cfg.Integration.GitHubAPIURL, cfg.Integration.GitHubToken, &http.Client{})
+	}
+	if cfg.Integration.GitLabToken != "" {
+		reviewerClients[domain.ProviderGitLab] = integration.NewGitLabClient(cfg.Integration.GitLabAPIURL, cfg.Integration.GitLabToken, &http.Client{})
+	}
+	reporterDone := make(chan struct{})
+	go func() {
+		defer close(reporterDone)
+		if len(reviewerClients) > 0 {
+			service.NewReviewerReporter(eventBus, integrationRepo, reviewerClients, logger).Run(ctx)
+		}
+	}()
+
+	// Фоновые задачи по расписанию
+	scheduler := service.NewScheduler(logger)
+	scheduler.Every("review_sla", cfg.Sla.CheckInterval, prService.EscalateOverdue)
+
+	// Ежедневная сводка открытых ревью
+	digestNotifier, closeNotifier, err := newDigestNotifier(cfg.Digest)
+	if err != nil {
+		logger.Fatal("Digest notifier init error", zap.Error(err))
+	}
+	defer closeNotifier()
+	if digestNotifier != nil {
+		digestService := service.NewDigestService(userRepo, digestNotifier, logger)
+		scheduler.Daily("review_digest", cfg.Digest.Hour, cfg.Digest.Minute, cfg.Sla.Location, digestService.SendDigests)
+	}
+	scheduler.Start()
+
 	// Ожидание сигнала завершения
 	<-ctx.Done()
 	logger.Info("Shutdown signal received")
@@ -90,9 +167,51 @@ func main() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Закрываем потоки событий, иначе Shutdown будет ждать их до таймаута
+	eventBus.Close()
+
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Server shutdown error", zap.Error(err))
 	} else {
 		logger.Info("Server stopped")
 	}
+
+	// Дожидаемся задач планировщика, которые уже выполняются
+	if err := scheduler.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Scheduler did not stop in time", zap.Error(err))
+	}
+
+	// Дожидаемся отправки уже захваченных доставок
+	select {
+	case <-dispatcherDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("Webhook dispatcher did not stop in time")
+	}
+	select {
+	case <-reporterDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("Reviewer reporter did not stop in time")
+	}
+}
+
+// newDigestNotifier создает способ доставки сводки по конфигурации. Для DIGEST_NOTIFIER=none
+// возвращает nil, рассылка не запускается. Функция закрытия освобождает файл сводок
+func newDigestNotifier(cfg config.DigestConfig) (service.Notifier, func(), error) {
+	noop := func() {}
+	switch cfg.Notifier {
+	case config.DigestNotifierStdout:
+		return notifier.NewWriterNotifier(os.Stdout), noop, nil
+	case config.DigestNotifierFile:
+		file, err := os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, noop, err
+		}
+		return notifier.NewWriterNotifier(file), func() { file.Close() }, nil
+	case config.DigestNotifierSMTP:
+		return notifier.NewSMTPNotifier(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From, nil), noop, nil
+	case config.DigestNotifierHTTP:
+		return notifier.NewHTTPNotifier(cfg.HTTPURL, &http.Client{Timeout: cfg.HTTPTimeout}), noop, nil
+	default:
+		return nil, noop, nil
+	}
 }
@@ -5,12 +5,26 @@ import (
 	"fmt"
 	"github.com/joho/godotenv"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	// Часовые пояса для SLA_TIMEZONE в образе без системной базы tzdata
+	_ "time/tzdata"
 )
 
 var (
-	dbUserEmptyError = errors.New("DB User is Empty")
-	dbNameEmptyError = errors.New("DB Name is Empty")
-	envLoadError     = errors.New(".env load Error")
+	dbUserEmptyError            = errors.New("DB User is Empty")
+	dbNameEmptyError            = errors.New("DB Name is Empty")
+	envLoadError                = errors.New(".env load Error")
+	reviewerTeamStrategiesError = errors.New("REVIEWER_TEAM_STRATEGIES is malformed")
+	invalidDurationError        = errors.New("invalid duration")
+	invalidPositiveIntError     = errors.New("must be a positive integer")
+	invalidNonNegativeIntError  = errors.New("must be a non-negative integer")
+	slaWorkdayError             = errors.New("SLA_WORKDAY_START must be less than SLA_WORKDAY_END, both within 0..24")
+	slaTimezoneError            = errors.New("SLA_TIMEZONE is unknown")
+	digestTimeError             = errors.New("DIGEST_TIME must be HH:MM")
+	digestNotifierError         = errors.New("DIGEST_NOTIFIER must be one of none, stdout, file, smtp, http")
+	digestSettingRequiredError  = errors.New("setting is required for DIGEST_NOTIFIER")
 )
 
 type AppConfig struct {
@@ -27,9 +41,95 @@ type DatabaseConfig struct {
 	URL      string
 }
 
+type ReviewerConfig struct {
+	// Стратегия выбора ревьюеров по умолчанию
+	Strategy string
+	// Переопределения стратегии для команд: team_name -> strategy
+	TeamStrategies map[string]string
+	// Сколько последних PR автора учитывается, чтобы реже назначать ему тех же ревьюеров, 0 отключает
+	PairingWindow int
+}
+
+type WebhookConfig struct {
+	// Как часто диспетчер проверяет очередь доставок
+	PollInterval time.Duration
+	BatchSize    int
+	// После стольких неудачных попыток доставка уходит в dead-letter
+	MaxAttempts int
+	// Задержка перед повтором растет экспоненциально от BaseBackoff до MaxBackoff
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	RequestTimeout time.Duration
+}
+
+type EventsConfig struct {
+	// Сколько последних событий хранится для продолжения потока по Last-Event-ID
+	HistorySize int
+	// Очередь событий подписчика, при переполнении подписчик отключается
+	SubscriberBuffer int
+}
+
+// SlaConfig рабочее время, в котором отсчитывается срок ревью, и частота проверки просроченных ревью
+type SlaConfig struct {
+	CheckInterval time.Duration
+	// Рабочий день с WorkdayStart до WorkdayEnd часов по будням в часовом поясе Location
+	WorkdayStart int
+	WorkdayEnd   int
+	Location     *time.Location
+}
+
+// Способы доставки ежедневной сводки ревьюеров
+const (
+	DigestNotifierNone   = "none"
+	DigestNotifierStdout = "stdout"
+	DigestNotifierFile   = "file"
+	DigestNotifierSMTP   = "smtp"
+	DigestNotifierHTTP   = "http"
+)
+
+// DigestConfig ежедневная сводка открытых ревью. Время Hour:Minute отсчитывается в часовом поясе
+// SLA_TIMEZONE, DIGEST_NOTIFIER=none отключает рассылку
+type DigestConfig struct {
+	Notifier string
+	Hour     int
+	Minute   int
+	// Файл для DIGEST_NOTIFIER=file, сводки дописываются в конец
+	File string
+	// Адрес для DIGEST_NOTIFIER=http
+	HTTPURL     string
+	HTTPTimeout time.Duration
+	SMTP        SMTPConfig
+}
+
+// SMTPConfig почтовый сервер для DIGEST_NOTIFIER=smtp, пустой Username отключает авторизацию
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// IntegrationConfig пустой секрет отключает прием событий провайдера,
+// пустой токен - передачу ревьюеров обратно провайдеру
+type IntegrationConfig struct {
+	GitHubWebhookSecret string
+	GitHubAPIURL        string
+	GitHubToken         string
+	GitLabWebhookToken  string
+	GitLabAPIURL        string
+	GitLabToken         string
+}
+
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
+	App         AppConfig
+	Database    DatabaseConfig
+	Reviewer    ReviewerConfig
+	Webhook     WebhookConfig
+	Events      EventsConfig
+	Integration IntegrationConfig
+	Sla         SlaConfig
+	Digest      DigestConfig
 }
 
 func LoadConfig() (*Config, error) {
@@ -46,12 +146,51 @@ func LoadConfig() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			User:     getEnv("DB_USER", "postgres"),
 		},
+		Reviewer: ReviewerConfig{
+			Strategy: getEnv("REVIEWER_STRATEGY", "least_loaded"),
+		},
+		Integration: IntegrationConfig{
+			GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+			GitHubAPIURL:        getEnv("GITHUB_API_URL", "https://api.github.com"),
+			GitHubToken:         os.Getenv("GITHUB_TOKEN"),
+			GitLabWebhookToken:  os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+			GitLabAPIURL:        getEnv("GITLAB_API_URL", "https://gitlab.com/api/v4"),
+			GitLabToken:         os.Getenv("GITLAB_TOKEN"),
+		},
 	}
 	err := makeDbUrl(c)
 	if err != nil {
 		return nil, err
 	}
 
+	c.Reviewer.TeamStrategies, err = parseTeamStrategies(os.Getenv("REVIEWER_TEAM_STRATEGIES"))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Reviewer.PairingWindow, err = getNonNegativeInt("REVIEWER_PAIRING_WINDOW", "5"); err != nil {
+		return nil, err
+	}
+
+	if err := loadWebhookConfig(&c.Webhook); err != nil {
+		return nil, err
+	}
+
+	if c.Events.HistorySize, err = getPositiveInt("EVENTS_HISTORY_SIZE", "1000"); err != nil {
+		return nil, err
+	}
+	if c.Events.SubscriberBuffer, err = getPositiveInt("EVENTS_SUBSCRIBER_BUFFER", "64"); err != nil {
+		return nil, err
+	}
+
+	if err := loadSlaConfig(&c.Sla); err != nil {
+		return nil, err
+	}
+
+	if err := loadDigestConfig(&c.Digest); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -62,6 +201,128 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getDuration(key, fallback string) (time.Duration, error) {
+	raw := getEnv(key, fallback)
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s: %w: %q", key, invalidDurationError, raw)
+	}
+	return d, nil
+}
+
+func getPositiveInt(key, fallback string) (int, error) {
+	raw := getEnv(key, fallback)
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s %w: %q", key, invalidPositiveIntError, raw)
+	}
+	return n, nil
+}
+
+func getNonNegativeInt(key, fallback string) (int, error) {
+	raw := getEnv(key, fallback)
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s %w: %q", key, invalidNonNegativeIntError, raw)
+	}
+	return n, nil
+}
+
+func loadWebhookConfig(cfg *WebhookConfig) error {
+	var err error
+	if cfg.PollInterval, err = getDuration("WEBHOOK_POLL_INTERVAL", "1s"); err != nil {
+		return err
+	}
+	if cfg.BatchSize, err = getPositiveInt("WEBHOOK_BATCH_SIZE", "20"); err != nil {
+		return err
+	}
+	if cfg.MaxAttempts, err = getPositiveInt("WEBHOOK_MAX_ATTEMPTS", "8"); err != nil {
+		return err
+	}
+	if cfg.BaseBackoff, err = getDuration("WEBHOOK_BASE_BACKOFF", "5s"); err != nil {
+		return err
+	}
+	if cfg.MaxBackoff, err = getDuration("WEBHOOK_MAX_BACKOFF", "1h"); err != nil {
+		return err
+	}
+	if cfg.RequestTimeout, err = getDuration("WEBHOOK_REQUEST_TIMEOUT", "5s"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func loadSlaConfig(cfg *SlaConfig) error {
+	var err error
+	if cfg.CheckInterval, err = getDuration("SLA_CHECK_INTERVAL", "5m"); err != nil {
+		return err
+	}
+	if cfg.WorkdayStart, err = getNonNegativeInt("SLA_WORKDAY_START", "9"); err != nil {
+		return err
+	}
+	if cfg.WorkdayEnd, err = getNonNegativeInt("SLA_WORKDAY_END", "18"); err != nil {
+		return err
+	}
+	if cfg.WorkdayStart >= cfg.WorkdayEnd || cfg.WorkdayEnd > 24 {
+		return slaWorkdayError
+	}
+
+	timezone := getEnv("SLA_TIMEZONE", "UTC")
+	if cfg.Location, err = time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("%w: %q", slaTimezoneError, timezone)
+	}
+	return nil
+}
+
+func loadDigestConfig(cfg *DigestConfig) error {
+	var err error
+	cfg.Notifier = strings.ToLower(getEnv("DIGEST_NOTIFIER", DigestNotifierNone))
+
+	rawTime := getEnv("DIGEST_TIME", "09:00")
+	at, err := time.Parse("15:04", rawTime)
+	if err != nil {
+		return fmt.Errorf("%w: %q", digestTimeError, rawTime)
+	}
+	cfg.Hour, cfg.Minute = at.Hour(), at.Minute()
+
+	cfg.File = os.Getenv("DIGEST_FILE")
+	cfg.HTTPURL = os.Getenv("DIGEST_HTTP_URL")
+	if cfg.HTTPTimeout, err = getDuration("DIGEST_HTTP_TIMEOUT", "10s"); err != nil {
+		return err
+	}
+	cfg.SMTP = SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if cfg.SMTP.Port, err = getPositiveInt("SMTP_PORT", "587"); err != nil {
+		return err
+	}
+
+	// Проверяем, что для выбранного способа доставки заданы обязательные настройки
+	switch cfg.Notifier {
+	case DigestNotifierNone, DigestNotifierStdout:
+	case DigestNotifierFile:
+		if cfg.File == "" {
+			return fmt.Errorf("DIGEST_FILE %w", digestSettingRequiredError)
+		}
+	case DigestNotifierSMTP:
+		if cfg.SMTP.Host == "" {
+			return fmt.Errorf("SMTP_HOST %w", digestSettingRequiredError)
+		}
+		if cfg.SMTP.From == "" {
+			return fmt.Errorf("SMTP_FROM %w", digestSettingRequiredError)
+		}
+	case DigestNotifierHTTP:
+		if cfg.HTTPURL == "" {
+			return fmt.Errorf("DIGEST_HTTP_URL %w", digestSettingRequiredError)
+		}
+	default:
+		return fmt.Errorf("%w: %q", digestNotifierError, cfg.Notifier)
+	}
+	return nil
+}
+
 func makeDbUrl(cfg *Config) error {
 	if cfg.Database.URL == "" {
 		if cfg.Database.User == "" {
@@ -80,3 +341,22 @@ func makeDbUrl(cfg *Config) error {
 	}
 	return nil
 }
+
+// parseTeamStrategies разбирает строку вида "backend=round_robin,docs=random"
+func parseTeamStrategies(raw string) (map[string]string, error) {
+	strategies := make(map[string]string)
+	if strings.TrimSpace(raw) == "" {
+		return strategies, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		teamName, strategy, ok := strings.Cut(pair, "=")
+		teamName = strings.TrimSpace(teamName)
+		strategy = strings.TrimSpace(strategy)
+		if !ok || teamName == "" || strategy == "" {
+			return nil, fmt.Errorf("%w: %q", reviewerTeamStrategiesError, pair)
+		}
+		strategies[teamName] = strategy
+	}
+	return strategies, nil
+}
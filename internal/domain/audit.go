@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Типы событий журнала назначений
+const (
+	AssignmentEventAssign   = "ASSIGN"
+	AssignmentEventUnassign = "UNASSIGN"
+	AssignmentEventReassign = "REASSIGN"
+)
+
+// Причины событий журнала назначений
+const (
+	AssignReasonPrCreated       = "pr_created"
+	AssignReasonPrReady         = "pr_ready"
+	AssignReasonPrReopened      = "pr_reopened"
+	AssignReasonPrMerged        = "pr_merged"
+	AssignReasonManualReassign  = "manual_reassign"
+	AssignReasonUserDeactivated = "user_deactivated"
+	AssignReasonReviewOverdue   = "review_overdue"
+	AssignReasonMemberRemoved   = "member_removed"
+	AssignReasonTeamDeleted     = "team_deleted"
+)
+
+// Автор изменений, если запрос не передал его явно
+const SystemActor = "system"
+
+// AssignmentEvent запись журнала назначений. UserId - ревьюер, которого назначили или сняли,
+// PreviousUserId заполняется для REASSIGN и содержит замененного ревьюера
+type AssignmentEvent struct {
+	Id             int64
+	PrId           string
+	Type           string
+	UserId         string
+	PreviousUserId string
+	Actor          string
+	Reason         string
+	RequestId      string
+	CreatedAt      time.Time
+}
+
+// Причины, по которым участник пула не мог быть выбран ревьюером
+const (
+	ExclusionAuthor          = "author"
+	ExclusionReplaced        = "replaced"
+	ExclusionLeaving         = "leaving"
+	ExclusionAlreadyAssigned = "already_assigned"
+	ExclusionInactive        = "inactive"
+	ExclusionUnavailable     = "unavailable"
+	ExclusionAtCapacity      = "at_capacity"
+)
+
+// ExplainedCandidate участник пула на момент подбора. Excluded пуст, если кандидата можно было выбрать.
+// Lead, CodeOwner и CoversTags отмечают лида при обязательном ревью лида, владельца измененных файлов
+// и кандидата, покрывающего теги PR
+type ExplainedCandidate struct {
+	UserId         string `json:"user_id"`
+	OpenReviews    int    `json:"open_reviews"`
+	RecentPairings int    `json:"recent_pairings"`
+	Lead           bool   `json:"lead,omitempty"`
+	CodeOwner      bool   `json:"code_owner,omitempty"`
+	CoversTags     bool   `json:"covers_tags,omitempty"`
+	Excluded       string `json:"excluded,omitempty"`
+	Selected       bool   `json:"selected,omitempty"`
+}
+
+// ExplainedPool пул команды, рассмотренный при подборе. Кандидаты идут в том порядке,
+// в котором переданы стратегии, - от него зависит повтор выбора
+type ExplainedPool struct {
+	TeamName   string               `json:"team_name"`
+	Strategy   string               `json:"strategy"`
+	Candidates []ExplainedCandidate `json:"candidates"`
+}
+
+// AssignmentExplanation объяснение одного подбора ревьюеров: рассмотренные пулы и seed генератора,
+// с которым стратегии сделали выбор. TeamName - команда, из которой взяты ревьюеры, пуста без ревьюеров
+type AssignmentExplanation struct {
+	Id        int64
+	PrId      string
+	Reason    string
+	TeamName  string
+	Seed      int64
+	Reviewers []string
+	Pools     []ExplainedPool
+	CreatedAt time.Time
+}
+
+// AuditInfo кто и в рамках какого запроса меняет назначения
+type AuditInfo struct {
+	Actor     string
+	RequestId string
+}
+
+type auditInfoKey struct{}
+
+func WithAuditInfo(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditInfoKey{}, info)
+}
+
+// AuditInfoFrom возвращает данные аудита из контекста, по умолчанию автор - system
+func AuditInfoFrom(ctx context.Context) AuditInfo {
+	info, _ := ctx.Value(auditInfoKey{}).(AuditInfo)
+	if info.Actor == "" {
+		info.Actor = SystemActor
+	}
+	return info
+}
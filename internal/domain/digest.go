@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// UserContact куда отправлять уведомления пользователю. Пустые email и chat_handle - контакт не задан
+type UserContact struct {
+	UserId     string
+	Email      string
+	ChatHandle string
+	// false - пользователь отказался от ежедневной сводки
+	DigestEnabled bool
+	UpdatedAt     time.Time
+}
+
+// ReviewDigest ежедневная сводка ревьюера: открытые PR, на которые он назначен, от самых новых
+type ReviewDigest struct {
+	UserId      string
+	Username    string
+	Email       string
+	ChatHandle  string
+	Prs         []*Pr
+	GeneratedAt time.Time
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Провайдеры репозиториев, из которых принимаются события PR
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+var Providers = []string{
+	ProviderGitHub,
+	ProviderGitLab,
+}
+
+// Разделители номера PR в идентификаторе, как в ссылках самих провайдеров: owner/repo#12 и group/project!12
+var providerNumberSeparators = map[string]string{
+	ProviderGitHub: "#",
+	ProviderGitLab: "!",
+}
+
+// IntegrationUser сопоставление пользователя провайдера с пользователем сервиса
+type IntegrationUser struct {
+	Provider      string
+	ExternalLogin string
+	ExternalId    string
+	UserId        string
+	CreatedAt     time.Time
+}
+
+// ExternalPr PR у провайдера. Из него строится pull_request_id, поэтому
+// по идентификатору PR всегда можно найти, куда сообщать о ревьюерах
+type ExternalPr struct {
+	Provider   string
+	Repository string
+	Number     int
+}
+
+// PrId идентификатор PR в сервисе, например github:owner/repo#12
+func (p ExternalPr) PrId() string {
+	return fmt.Sprintf("%s:%s%s%d", p.Provider, p.Repository, providerNumberSeparators[p.Provider], p.Number)
+}
+
+// ParseExternalPrId разбирает идентификатор PR, созданного интеграцией. ok = false для остальных PR
+func ParseExternalPrId(prId string) (ExternalPr, bool) {
+	provider, rest, found := strings.Cut(prId, ":")
+	separator, known := providerNumberSeparators[provider]
+	if !found || !known {
+		return ExternalPr{}, false
+	}
+
+	idx := strings.LastIndex(rest, separator)
+	if idx <= 0 {
+		return ExternalPr{}, false
+	}
+	number, err := strconv.Atoi(rest[idx+1:])
+	if err != nil || number <= 0 {
+		return ExternalPr{}, false
+	}
+
+	return ExternalPr{
+		Provider:   provider,
+		Repository: rest[:idx],
+		Number:     number,
+	}, true
+}
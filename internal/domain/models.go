@@ -3,11 +3,20 @@ package domain
 import "time"
 
 type User struct {
-	Id        string    `json:"user_id"`
-	Name      string    `json:"username"`
+	Id   string `json:"user_id"`
+	Name string `json:"username"`
+	// Основная команда пользователя
 	TeamName  string    `json:"team_name"`
 	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"-"`
+	// Все команды пользователя, основная первой. Заполняется только там, где читается состав команд
+	Teams []string `json:"-"`
+	// Предел одновременно открытых ревью, nil - без ограничения
+	MaxOpenReviews *int `json:"-"`
+	// Теги навыков пользователя, например go или postgres
+	Tags []string `json:"-"`
+	// Роль в команде, заполняется только при чтении состава команды
+	Role string `json:"-"`
 }
 
 type Team struct {
@@ -16,12 +25,88 @@ type Team struct {
 	CreatedAt time.Time
 }
 
+// TeamSettings настройки назначения ревьюеров команды.
+// Пустая стратегия означает стратегию из конфигурации сервиса
+type TeamSettings struct {
+	TeamId        string
+	ReviewerCount int
+	Strategy      string
+	LeadUserId    string
+	RequireLead   bool
+	// Число одобрений, без которого PR автора из команды нельзя смержить
+	RequiredApprovals int
+	// Назначать только ревьюеров, чьи теги покрывают теги PR, а не предпочитать их
+	RequireTags bool
+	// Срок ревью в рабочих часах от назначения ревьюера, 0 - без срока
+	ReviewSlaHours int
+	// Что делать с просроченным ревью: SlaPolicyNotify, SlaPolicyAddReviewer или SlaPolicyReassign
+	SlaPolicy string
+	UpdatedAt time.Time
+}
+
+// Политики эскалации просроченного ревью. При любой политике публикуется событие review.overdue,
+// add_reviewer дополнительно назначает еще одного ревьюера, reassign заменяет просрочившего
+const (
+	SlaPolicyNotify      = "notify"
+	SlaPolicyAddReviewer = "add_reviewer"
+	SlaPolicyReassign    = "reassign"
+)
+
+// CodeOwnerRule правило CODEOWNERS команды: владельцы файлов, подходящих под шаблон.
+// Как и в файле CODEOWNERS, для файла действует последнее подходящее правило
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// Availability период, в который пользователь не может быть назначен ревьюером
+type Availability struct {
+	Id        string
+	UserId    string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Reason    string
+	CreatedAt time.Time
+}
+
 type TeamMember struct {
 	TeamId   string
 	UserId   string
+	Role     string
 	JoinedAt time.Time
 }
 
+// Роли участника команды
+const (
+	TeamRoleMember = "MEMBER"
+	TeamRoleLead   = "LEAD"
+)
+
+// Статусы PR
+const (
+	PrStatusDraft  = "DRAFT"
+	PrStatusOpen   = "OPEN"
+	PrStatusMerged = "MERGED"
+	PrStatusClosed = "CLOSED"
+)
+
+// Вердикты ревьюера
+const (
+	ReviewVerdictApproved         = "APPROVED"
+	ReviewVerdictChangesRequested = "CHANGES_REQUESTED"
+	ReviewVerdictCommented        = "COMMENTED"
+)
+
+// Review вердикт ревьюера по PR. Хранится вся история, действует последний решающий вердикт
+type Review struct {
+	Id         int64
+	PrId       string
+	ReviewerId string
+	Verdict    string
+	Comment    string
+	CreatedAt  time.Time
+}
+
 type Pr struct {
 	Id        string
 	Name      string
@@ -29,6 +114,7 @@ type Pr struct {
 	Status    string
 	CreatedAt time.Time
 	MergedAt  *time.Time
+	ClosedAt  *time.Time
 }
 
 type PrReviewer struct {
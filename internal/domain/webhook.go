@@ -0,0 +1,116 @@
+package domain
+
+import "time"
+
+// Типы исходящих событий
+const (
+	EventPrCreated          = "pr.created"
+	EventPrMerged           = "pr.merged"
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventUserDeactivated    = "user.deactivated"
+	EventReviewOverdue      = "review.overdue"
+)
+
+var EventTypes = []string{
+	EventPrCreated,
+	EventPrMerged,
+	EventReviewerAssigned,
+	EventReviewerReassigned,
+	EventUserDeactivated,
+	EventReviewOverdue,
+}
+
+// Статусы доставки события на вебхук
+const (
+	WebhookDeliveryPending   = "PENDING"
+	WebhookDeliveryDelivered = "DELIVERED"
+	WebhookDeliveryDead      = "DEAD"
+)
+
+// OutboxEvent событие, записанное в outbox в одной транзакции с изменением, которое его породило
+type OutboxEvent struct {
+	Id        int64
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// PrEventPayload данные событий pr.created и pr.merged
+type PrEventPayload struct {
+	PrId              string     `json:"pull_request_id"`
+	PrName            string     `json:"pull_request_name"`
+	AuthorId          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	Actor             string     `json:"actor"`
+}
+
+// ReviewerEventPayload данные событий reviewer.assigned и reviewer.reassigned.
+// Для reviewer.assigned заполнен user_id, для reviewer.reassigned - old_user_id и new_user_id.
+// new_user_id отсутствует, если ревьюера сняли без замены
+type ReviewerEventPayload struct {
+	PrId      string `json:"pull_request_id"`
+	UserId    string `json:"user_id,omitempty"`
+	OldUserId string `json:"old_user_id,omitempty"`
+	NewUserId string `json:"new_user_id,omitempty"`
+	Reason    string `json:"reason"`
+	Actor     string `json:"actor"`
+}
+
+// UserDeactivatedPayload данные события user.deactivated
+type UserDeactivatedPayload struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Actor    string `json:"actor"`
+}
+
+// ReviewOverduePayload данные события review.overdue: ревьюер не оставил вердикт до срока due_at.
+// added_user_id заполнен, если по политике add_reviewer назначен дополнительный ревьюер.
+// Замена по политике reassign публикуется отдельным событием reviewer.reassigned
+type ReviewOverduePayload struct {
+	PrId        string    `json:"pull_request_id"`
+	AuthorId    string    `json:"author_id"`
+	UserId      string    `json:"user_id"`
+	TeamName    string    `json:"team_name"`
+	AssignedAt  time.Time `json:"assigned_at"`
+	DueAt       time.Time `json:"due_at"`
+	Policy      string    `json:"policy"`
+	AddedUserId string    `json:"added_user_id,omitempty"`
+	Actor       string    `json:"actor"`
+}
+
+// ActivityEvent событие для живых подписчиков потока /events/stream.
+// UserIds - автор PR и затронутые ревьюеры, по ним подписки фильтруются по команде и пользователю
+type ActivityEvent struct {
+	Id        uint64
+	Type      string
+	PrId      string
+	UserIds   []string
+	Payload   any
+	CreatedAt time.Time
+}
+
+// Webhook подписка внешнего сервиса на события. Пустой EventTypes - подписка на все события
+type Webhook struct {
+	Id         string
+	Url        string
+	Secret     string
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery состояние доставки одного события на один вебхук
+type WebhookDelivery struct {
+	Id            int64
+	WebhookId     string
+	EventId       int64
+	EventType     string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+}
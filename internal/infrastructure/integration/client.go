@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Сколько байт тела ошибки провайдера сохраняется в тексте ошибки
+const maxErrorBodyLength = 512
+
+// HTTPClient отправляет запросы в API провайдера, в тестах подменяется
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// doJSON отправляет запрос с json-телом и декодирует ответ в out, если он передан
+func doJSON(ctx context.Context, client HTTPClient, method, url string, headers map[string]string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		return fmt.Errorf("%s %s: unexpected status %d: %s", method, url, resp.StatusCode, text)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHTTPClient отвечает заданными телами по порядку и запоминает запросы
+type fakeHTTPClient struct {
+	responses []string
+	requests  []*http.Request
+	bodies    []string
+}
+
+func (c *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	c.requests = append(c.requests, req)
+	c.bodies = append(c.bodies, string(body))
+
+	text := "{}"
+	if len(c.responses) > 0 {
+		text, c.responses = c.responses[0], c.responses[1:]
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(text))}, nil
+}
+
+func TestGitHubClient_AddReviewers(t *testing.T) {
+	httpClient := &fakeHTTPClient{}
+	client := NewGitHubClient("https://api.github.com/", "token", httpClient)
+
+	err := client.AddReviewers(context.Background(),
+		domain.ExternalPr{Provider: domain.ProviderGitHub, Repository: "acme/api", Number: 12},
+		[]*domain.IntegrationUser{{ExternalLogin: "bob"}},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+	assert.Equal(t, "https://api.github.com/repos/acme/api/pulls/12/requested_reviewers", httpClient.requests[0].URL.String())
+	assert.Equal(t, "Bearer token", httpClient.requests[0].Header.Get("Authorization"))
+	assert.JSONEq(t, `{"reviewers":["bob"]}`, httpClient.bodies[0])
+}
+
+func TestGitLabClient_UpdatesFullReviewerList(t *testing.T) {
+	httpClient := &fakeHTTPClient{responses: []string{`{"reviewers":[{"id":1},{"id":2}]}`}}
+	client := NewGitLabClient("https://gitlab.example.com/api/v4", "token", httpClient)
+	pr := domain.ExternalPr{Provider: domain.ProviderGitLab, Repository: "acme/backend", Number: 7}
+
+	err := client.RemoveReviewers(context.Background(), pr, []*domain.IntegrationUser{{ExternalLogin: "bob", ExternalId: "2"}})
+
+	assert.NoError(t, err)
+	assert.Len(t, httpClient.requests, 2)
+	assert.Equal(t, http.MethodGet, httpClient.requests[0].Method)
+	assert.Equal(t, "/api/v4/projects/acme%2Fbackend/merge_requests/7", httpClient.requests[1].URL.EscapedPath())
+	assert.Equal(t, http.MethodPut, httpClient.requests[1].Method)
+	var body map[string][]int64
+	assert.NoError(t, json.Unmarshal([]byte(httpClient.bodies[1]), &body))
+	assert.Equal(t, []int64{1}, body["reviewer_ids"])
+
+	// Без числового id назначить ревьюера в GitLab нельзя
+	err = client.AddReviewers(context.Background(), pr, []*domain.IntegrationUser{{ExternalLogin: "carol"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+// GitHubClient запрашивает и снимает ревью через REST API GitHub
+type GitHubClient struct {
+	baseURL string
+	token   string
+	http    HTTPClient
+}
+
+func NewGitHubClient(baseURL, token string, client HTTPClient) *GitHubClient {
+	return &GitHubClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    client,
+	}
+}
+
+func (c *GitHubClient) AddReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error {
+	return c.requestedReviewers(ctx, http.MethodPost, pr, reviewers)
+}
+
+func (c *GitHubClient) RemoveReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error {
+	return c.requestedReviewers(ctx, http.MethodDelete, pr, reviewers)
+}
+
+func (c *GitHubClient) requestedReviewers(ctx context.Context, method string, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error {
+	logins := make([]string, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		logins = append(logins, reviewer.ExternalLogin)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.baseURL, pr.Repository, pr.Number)
+	return doJSON(ctx, c.http, method, url, c.headers(), map[string][]string{"reviewers": logins}, nil)
+}
+
+func (c *GitHubClient) headers() map[string]string {
+	return map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"Authorization":        "Bearer " + c.token,
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+}
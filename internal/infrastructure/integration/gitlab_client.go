@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+// GitLabClient меняет ревьюеров merge request через REST API GitLab.
+// API принимает только полный список ревьюеров по числовым id, поэтому текущий список сначала читается
+type GitLabClient struct {
+	baseURL string
+	token   string
+	http    HTTPClient
+}
+
+type gitlabMergeRequest struct {
+	Reviewers []struct {
+		Id int64 `json:"id"`
+	} `json:"reviewers"`
+}
+
+func NewGitLabClient(baseURL, token string, client HTTPClient) *GitLabClient {
+	return &GitLabClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    client,
+	}
+}
+
+func (c *GitLabClient) AddReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error {
+	return c.updateReviewers(ctx, pr, reviewers, true)
+}
+
+func (c *GitLabClient) RemoveReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error {
+	return c.updateReviewers(ctx, pr, reviewers, false)
+}
+
+func (c *GitLabClient) updateReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser, add bool) error {
+	changed := make([]int64, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		id, err := strconv.ParseInt(reviewer.ExternalId, 10, 64)
+		if err != nil {
+			return fmt.Errorf("gitlab user %q has no numeric external_id", reviewer.ExternalLogin)
+		}
+		changed = append(changed, id)
+	}
+
+	mrURL := fmt.Sprintf("%s/projects/%s/merge_requests/%d", c.baseURL, url.PathEscape(pr.Repository), pr.Number)
+	headers := map[string]string{"PRIVATE-TOKEN": c.token}
+
+	// Читаем текущих ревьюеров
+	var mr gitlabMergeRequest
+	if err := doJSON(ctx, c.http, http.MethodGet, mrURL, headers, nil, &mr); err != nil {
+		return err
+	}
+	ids := make([]int64, 0, len(mr.Reviewers)+len(changed))
+	for _, reviewer := range mr.Reviewers {
+		if add || !slices.Contains(changed, reviewer.Id) {
+			ids = append(ids, reviewer.Id)
+		}
+	}
+	if add {
+		for _, id := range changed {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return doJSON(ctx, c.http, http.MethodPut, mrURL, headers, map[string][]int64{"reviewer_ids": ids}, nil)
+}
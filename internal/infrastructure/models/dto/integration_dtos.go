@@ -0,0 +1,13 @@
+package dto
+
+type SetIntegrationUserDTO struct {
+	Provider      string
+	ExternalLogin string
+	ExternalId    string
+	UserId        string
+}
+
+type DeleteIntegrationUserDTO struct {
+	Provider      string
+	ExternalLogin string
+}
@@ -1,17 +1,72 @@
 package dto
 
+import "github.com/niklvrr/AvitoInternship2025/internal/domain"
+
 type CreatPrDTO struct {
 	PrId     string
 	PrName   string
 	AuthorId string
+	Status   string
+	// Пути измененных файлов, по ним подбираются владельцы кода
+	ChangedPaths []string
+	// Теги, которые должны покрывать теги ревьюеров
+	RequiredTags []string
+	// Команда автора, из которой подбираются ревьюеры, пусто - основная команда
+	TeamName string
+	// PR требует ревью лида команды, LeadReviewerId - назначенный лид, пусто для черновика
+	RequiresLeadReview bool
+	LeadReviewerId     string
+	// Объяснение подбора ревьюеров, nil для черновика
+	Explanation *domain.AssignmentExplanation
 }
 
+// MergePrDTO SkipApprovals - слияние уже произошло у провайдера, одобрения не проверяются
 type MergePrDTO struct {
-	PrId string
+	PrId          string
+	SkipApprovals bool
 }
 
+// ReassignPrDTO замена ревьюера. Reason записывается в журнал назначений,
+// Explanation - в объяснения подбора, если замена применена
 type ReassignPrDTO struct {
 	PrId          string
 	OldReviewerId string
 	ReplacedBy    string
+	Reason        string
+	Explanation   *domain.AssignmentExplanation
+}
+
+// ChangePrStatusDTO переход PR из FromStatus в ToStatus.
+// Reviewers назначаются в той же транзакции, например при переходе черновика в OPEN
+type ChangePrStatusDTO struct {
+	PrId       string
+	FromStatus string
+	ToStatus   string
+	Reviewers  []string
+	// Причина назначения Reviewers для журнала назначений
+	Reason string
+	// Лид среди Reviewers для PR с обязательным ревью лида, пусто - лид не назначается
+	LeadReviewerId string
+	// Новая команда PR, если Reviewers подобраны не из прежней, пусто - команда не меняется
+	TeamName string
+	// Объяснение подбора Reviewers, nil если ревьюеры не подбирались
+	Explanation *domain.AssignmentExplanation
+}
+
+type AddReviewDTO struct {
+	PrId       string
+	ReviewerId string
+	Verdict    string
+	Comment    string
+}
+
+// EscalateReviewDTO эскалация просроченного ревью: ревью отмечается эскалированным и в outbox
+// записывается событие Overdue. AddedReviewer, если указан, назначается на PR в той же транзакции
+type EscalateReviewDTO struct {
+	PrId          string
+	ReviewerId    string
+	AddedReviewer string
+	Overdue       *domain.ReviewOverduePayload
+	// Объяснение подбора AddedReviewer
+	Explanation *domain.AssignmentExplanation
 }
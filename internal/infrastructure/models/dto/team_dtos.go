@@ -3,10 +3,78 @@ package dto
 import "github.com/niklvrr/AvitoInternship2025/internal/domain"
 
 type AddTeamDTO struct {
-	TeamName string         `json:"team_name"`
-	Members  []*domain.User `json:"members"`
+	TeamName       string         `json:"team_name"`
+	Members        []*domain.User `json:"members"`
+	AllowMultiTeam bool           `json:"allow_multi_team"`
 }
 
 type GetTeamDTO struct {
 	TeamName string `json:"team_name"`
 }
+
+type UpdateTeamSettingsDTO struct {
+	TeamName      string
+	ReviewerCount int
+	Strategy      string
+	LeadUserId    string
+	RequireLead   bool
+	FallbackTeams []string
+	// Число одобрений, необходимое для merge
+	RequiredApprovals int
+	RequireTags       bool
+	// Срок ревью в рабочих часах и политика эскалации просроченных ревью
+	ReviewSlaHours int
+	SlaPolicy      string
+}
+
+// SetCodeOwnersDTO правила CODEOWNERS команды в порядке файла, заменяют прежние целиком
+type SetCodeOwnersDTO struct {
+	TeamName string
+	Rules    []*domain.CodeOwnerRule
+}
+
+type DeactivateMembersDTO struct {
+	TeamId        string
+	TeamName      string
+	UserIds       []string
+	Reassignments []*ReassignPrDTO
+}
+
+// AddTeamMembersDTO участники для существующей команды, данные пользователей обновляются как в /team/add
+type AddTeamMembersDTO struct {
+	TeamName string
+	Members  []*domain.User
+}
+
+// RemoveTeamMemberDTO участник, покидающий команду, и заранее подобранные замены его открытых ревью
+type RemoveTeamMemberDTO struct {
+	TeamId        string
+	TeamName      string
+	UserId        string
+	Reassignments []*ReassignPrDTO
+}
+
+// SetTeamParentDTO пустой ParentTeamName делает команду командой верхнего уровня
+type SetTeamParentDTO struct {
+	TeamName       string
+	ParentTeamName string
+}
+
+// SetTeamMemberRoleDTO Role - domain.TeamRoleMember или domain.TeamRoleLead
+type SetTeamMemberRoleDTO struct {
+	TeamName string
+	UserId   string
+	Role     string
+}
+
+type RenameTeamDTO struct {
+	TeamName    string
+	NewTeamName string
+}
+
+// DeleteTeamDTO команда для удаления и заранее подобранные замены открытых ревью ее участников
+type DeleteTeamDTO struct {
+	TeamId        string
+	TeamName      string
+	Reassignments []*ReassignPrDTO
+}
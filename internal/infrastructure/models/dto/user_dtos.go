@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type SetIsActiveDTO struct {
 	UserId   string `json:"userId"`
 	IsActive bool   `json:"is_active"`
@@ -8,3 +10,69 @@ type SetIsActiveDTO struct {
 type GetReviewDTO struct {
 	UserId string `json:"user_id"`
 }
+
+// DeactivateUserDTO пользователь для деактивации и заранее подобранные замены его открытых ревью
+type DeactivateUserDTO struct {
+	UserId        string
+	Reassignments []*ReassignPrDTO
+}
+
+// SetMaxOpenReviewsDTO nil снимает ограничение
+type SetMaxOpenReviewsDTO struct {
+	UserId         string
+	MaxOpenReviews *int
+}
+
+// SetUserTagsDTO заменяет теги пользователя целиком
+type SetUserTagsDTO struct {
+	UserId string
+	Tags   []string
+}
+
+type AddAvailabilityDTO struct {
+	UserId   string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+type ListAvailabilityDTO struct {
+	UserId string
+}
+
+type DeleteAvailabilityDTO struct {
+	AvailabilityId string
+}
+
+// SetUserContactsDTO заменяет контакты пользователя целиком
+type SetUserContactsDTO struct {
+	UserId        string
+	Email         string
+	ChatHandle    string
+	DigestEnabled bool
+}
+
+type SetPrimaryTeamDTO struct {
+	UserId   string
+	TeamName string
+}
+
+// CreateUserDTO новый пользователь. TeamName пустой, если пользователь создается без команды
+type CreateUserDTO struct {
+	UserId   string
+	Username string
+	TeamName string
+	IsActive bool
+}
+
+// UpdateUserDTO меняет только переданные поля пользователя
+type UpdateUserDTO struct {
+	UserId   string
+	Username *string
+	IsActive *bool
+}
+
+type ListUsersDTO struct {
+	TeamName string
+	IsActive *bool
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type CreateWebhookDTO struct {
+	Url        string
+	Secret     string
+	EventTypes []string
+}
+
+type DeleteWebhookDTO struct {
+	WebhookId string
+}
+
+// ListDeliveriesDTO пустой Status - доставки во всех статусах
+type ListDeliveriesDTO struct {
+	WebhookId string
+	Status    string
+	Limit     int
+}
+
+type RedeliverDTO struct {
+	DeliveryId int64
+}
+
+// DeliveryFailureDTO неудачная попытка доставки: PENDING с повтором через RetryIn или DEAD
+type DeliveryFailureDTO struct {
+	DeliveryId int64
+	Status     string
+	RetryIn    time.Duration
+	Error      string
+}
@@ -1,12 +1,22 @@
 package result
 
-import "time"
+import (
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
 
 type ReassignResult struct {
 	Pr         *PrResult
 	ReplacedBy string
 }
 
+// MergeResult Merged = false, если PR был слит раньше и повторный merge ничего не изменил
+type MergeResult struct {
+	Pr     *PrResult
+	Merged bool
+}
+
 type PrResult struct {
 	Id                string
 	Name              string
@@ -14,5 +24,62 @@ type PrResult struct {
 	Status            string
 	CreatedAt         time.Time
 	MergedAt          *time.Time
+	ClosedAt          *time.Time
 	AssignedReviewers []string
+	ChangedPaths      []string
+	RequiredTags      []string
+	// Команда, из которой подбираются ревьюеры, пусто - основная команда автора
+	TeamName string
+	// PR требует ревью лида команды, LeadReviewerId - назначенный лид
+	RequiresLeadReview bool
+	LeadReviewerId     string
+}
+
+// ReviewerCandidate участник команды вместе с количеством его открытых ревью.
+// Unavailable означает, что на момент чтения у пользователя идет период недоступности.
+// RecentPairings - сколько из последних PR автора кандидат ревьюил, заполняется сервисом.
+// IsLead означает роль лида в команде пула
+type ReviewerCandidate struct {
+	User           *domain.User
+	OpenReviews    int
+	Unavailable    bool
+	RecentPairings int
+	IsLead         bool
+}
+
+// PotentialReviewersResult команда пользователя, ее настройки и участники-кандидаты.
+// Settings равен nil, если настройки команды не заданы. CodeOwners читаются только для команды автора.
+// ParentId заполняется только для команды автора или ревьюера, пусто - команда верхнего уровня
+type PotentialReviewersResult struct {
+	TeamId     string
+	TeamName   string
+	ParentId   string
+	Settings   *domain.TeamSettings
+	Candidates []*ReviewerCandidate
+	CodeOwners []*domain.CodeOwnerRule
+}
+
+// ReviewStateResult состояние ревью PR: текущие ревьюеры, история вердиктов
+// и число одобрений, которое требуют настройки команды автора
+type ReviewStateResult struct {
+	Pr                *PrResult
+	RequiredApprovals int
+	Reviews           []*domain.Review
+}
+
+// SlaReviewResult ревью открытого PR, по которому ревьюер еще не оставил вердикт, и срок ревью
+// команды автора. Если автор состоит в нескольких командах со сроком, действует самый короткий.
+// EscalatedAt заполнен, если просрочка уже была эскалирована
+type SlaReviewResult struct {
+	PrId        string
+	PrName      string
+	AuthorId    string
+	ReviewerId  string
+	TeamName    string
+	AssignedAt  time.Time
+	EscalatedAt *time.Time
+	SlaHours    int
+	SlaPolicy   string
+	// Все ревьюеры PR
+	Reviewers []string
 }
@@ -1,9 +1,11 @@
 package result
 
 type UserStats struct {
-	UserId      string
-	Username    string
-	Assignments int
+	UserId         string
+	Username       string
+	Assignments    int
+	OpenReviews    int
+	MaxOpenReviews *int
 }
 
 type PrStats struct {
@@ -12,8 +14,18 @@ type PrStats struct {
 	ReviewersCount  int
 }
 
+// PairStats сколько PR автора ревьюил ревьюер
+type PairStats struct {
+	AuthorId   string
+	ReviewerId string
+	Reviews    int
+}
+
+// StatsResult статистика сервиса. Teams все команды с прямыми участниками для сводки по поддеревьям
 type StatsResult struct {
 	Users []UserStats
 	PRs   []PrStats
+	Pairs []PairStats
+	Teams []*TeamNodeResult
 }
 
@@ -1,13 +1,61 @@
 package result
 
-import "github.com/niklvrr/AvitoInternship2025/internal/domain"
+import (
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+)
 
+// AddTeamResult созданная команда. IgnoredUserIds - существующие пользователи, чьи username
+// и is_active из запроса не применены
 type AddTeamResult struct {
-	TeamName string
-	Members  []*domain.User
+	TeamName       string
+	Members        []*domain.User
+	IgnoredUserIds []string
 }
 
+// GetTeamResult команда с участниками. ParentTeamName и Subtree заполняются только при чтении команды
+// через Get: Subtree содержит саму команду и все ее подкоманды. IgnoredUserIds заполняется только
+// в AddMembers
 type GetTeamResult struct {
+	TeamName       string
+	Members        []*domain.User
+	ParentTeamName string
+	Subtree        []*TeamNodeResult
+	IgnoredUserIds []string
+}
+
+// TeamNodeResult команда в иерархии вместе с идентификаторами ее прямых участников.
+// ParentId пустой у команды верхнего уровня
+type TeamNodeResult struct {
+	TeamId    string
+	TeamName  string
+	ParentId  string
+	MemberIds []string
+}
+
+// TeamSettingsResult настройки команды, Settings равен nil, если они не заданы.
+// FallbackTeams названия резервных команд в порядке обращения
+type TeamSettingsResult struct {
+	TeamId        string
+	TeamName      string
+	Settings      *domain.TeamSettings
+	FallbackTeams []string
+}
+
+// CodeOwnersResult правила CODEOWNERS команды в порядке файла
+type CodeOwnersResult struct {
+	TeamId   string
 	TeamName string
-	Members  []*domain.User
+	Rules    []*domain.CodeOwnerRule
+}
+
+type DeactivateMembersResult struct {
+	DeactivatedIds []string
+	Reassigned     []*dto.ReassignPrDTO
+}
+
+// RemoveMembersResult пользователи, покинувшие команду, и примененные переназначения их ревью
+type RemoveMembersResult struct {
+	RemovedIds []string
+	Reassigned []*dto.ReassignPrDTO
 }
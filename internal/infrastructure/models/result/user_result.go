@@ -1,8 +1,28 @@
 package result
 
-import "github.com/niklvrr/AvitoInternship2025/internal/domain"
+import (
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+)
 
 type GetReviewResult struct {
 	UserId string
 	Prs    []*domain.Pr
 }
+
+// OpenReviewResult назначение ревьюера на открытый PR вместе с текущим составом ревьюеров
+// и требованием ревью лида. TeamName - команда, из которой подбирались ревьюеры PR
+type OpenReviewResult struct {
+	PrId               string
+	AuthorId           string
+	ReviewerId         string
+	Reviewers          []string
+	TeamName           string
+	RequiresLeadReview bool
+	LeadReviewerId     string
+}
+
+type DeactivateUserResult struct {
+	User       *domain.User
+	Reassigned []*dto.ReassignPrDTO
+}
@@ -0,0 +1,14 @@
+package result
+
+import "github.com/niklvrr/AvitoInternship2025/internal/domain"
+
+// DeliveryJob захваченная диспетчером доставка вместе с адресом вебхука и событием.
+// Attempt - номер текущей попытки, начиная с 1
+type DeliveryJob struct {
+	DeliveryId int64
+	WebhookId  string
+	Url        string
+	Secret     string
+	Attempt    int
+	Event      *domain.OutboxEvent
+}
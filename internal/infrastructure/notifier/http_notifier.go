@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+// Сколько байт тела ошибки получателя сохраняется в тексте ошибки
+const maxErrorBodyLength = 512
+
+// HTTPClient отправляет сводки получателю, в тестах подменяется
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HTTPNotifier отправляет сводку json-запросом POST на заданный адрес, например в бот чата,
+// который находит получателя по chat_handle
+type HTTPNotifier struct {
+	url    string
+	client HTTPClient
+}
+
+func NewHTTPNotifier(url string, client HTTPClient) *HTTPNotifier {
+	return &HTTPNotifier{
+		url:    url,
+		client: client,
+	}
+}
+
+type digestPayload struct {
+	UserId      string            `json:"user_id"`
+	Username    string            `json:"username"`
+	Email       string            `json:"email"`
+	ChatHandle  string            `json:"chat_handle"`
+	Text        string            `json:"text"`
+	Prs         []digestPrPayload `json:"pull_requests"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+}
+
+type digestPrPayload struct {
+	PrId      string    `json:"pull_request_id"`
+	PrName    string    `json:"pull_request_name"`
+	AuthorId  string    `json:"author_id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (n *HTTPNotifier) Notify(ctx context.Context, digest *domain.ReviewDigest) error {
+	payload := digestPayload{
+		UserId:      digest.UserId,
+		Username:    digest.Username,
+		Email:       digest.Email,
+		ChatHandle:  digest.ChatHandle,
+		Text:        formatDigest(digest),
+		Prs:         make([]digestPrPayload, 0, len(digest.Prs)),
+		GeneratedAt: digest.GeneratedAt.UTC(),
+	}
+	for _, pr := range digest.Prs {
+		payload.Prs = append(payload.Prs, digestPrPayload{
+			PrId:      pr.Id,
+			PrName:    pr.Name,
+			AuthorId:  pr.AuthorId,
+			CreatedAt: pr.CreatedAt.UTC(),
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		return fmt.Errorf("POST %s: unexpected status %d: %s", n.url, resp.StatusCode, text)
+	}
+	return nil
+}
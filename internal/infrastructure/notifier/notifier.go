@@ -0,0 +1,33 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+// ErrNoRecipient у пользователя нет контакта, нужного уведомителю, сводка не отправляется
+var ErrNoRecipient = errors.New("user has no contact for this notifier")
+
+// digestSubject тема сводки для писем и текстовых уведомлений
+func digestSubject(digest *domain.ReviewDigest) string {
+	return fmt.Sprintf("Открытые ревью: %d PR", len(digest.Prs))
+}
+
+// formatDigest текст сводки: по строке на PR, от самых новых
+func formatDigest(digest *domain.ReviewDigest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, вы назначены ревьюером на открытые PR (%d):\n", digest.Username, len(digest.Prs))
+	for _, pr := range digest.Prs {
+		fmt.Fprintf(&b, "- %s %q, автор %s, открыт %s\n",
+			pr.Id,
+			pr.Name,
+			pr.AuthorId,
+			pr.CreatedAt.UTC().Format(time.RFC3339),
+		)
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDigest() *domain.ReviewDigest {
+	return &domain.ReviewDigest{
+		UserId:     "u2",
+		Username:   "Bob",
+		Email:      "bob@example.com",
+		ChatHandle: "@bob",
+		Prs: []*domain.Pr{
+			{Id: "pr-2", Name: "Add search", AuthorId: "u1", Status: domain.PrStatusOpen, CreatedAt: time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC)},
+			{Id: "pr-1", Name: "Fix login", AuthorId: "u3", Status: domain.PrStatusOpen, CreatedAt: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)},
+		},
+		GeneratedAt: time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+// fakeHTTPClient отвечает заданным статусом и запоминает запросы
+type fakeHTTPClient struct {
+	status   int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (c *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	c.requests = append(c.requests, req)
+	c.bodies = append(c.bodies, string(body))
+	return &http.Response{StatusCode: c.status, Body: io.NopCloser(strings.NewReader("boom"))}, nil
+}
+
+func TestWriterNotifier_WritesDigest(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewWriterNotifier(&buf)
+
+	err := n.Notify(context.Background(), testDigest())
+
+	assert.NoError(t, err)
+	text := buf.String()
+	assert.Contains(t, text, "Bob (u2)")
+	assert.Contains(t, text, "Открытые ревью: 2 PR")
+	// PR идут в порядке сводки, от самых новых
+	assert.Less(t, strings.Index(text, "pr-2"), strings.Index(text, "pr-1"))
+	assert.Contains(t, text, `pr-1 "Fix login", автор u3, открыт 2025-01-06T10:00:00Z`)
+}
+
+func TestSMTPNotifier_SendsMail(t *testing.T) {
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	send := func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}
+	n := NewSMTPNotifier("smtp.example.com", 587, "", "", "reviews@example.com", send)
+
+	err := n.Notify(context.Background(), testDigest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.Equal(t, "reviews@example.com", gotFrom)
+	assert.Equal(t, []string{"bob@example.com"}, gotTo)
+	msg := string(gotMsg)
+	assert.Contains(t, msg, "To: bob@example.com\r\n")
+	assert.Contains(t, msg, "Subject: =?utf-8?q?")
+	assert.Contains(t, msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	assert.Contains(t, msg, "- pr-2 \"Add search\"")
+}
+
+func TestSMTPNotifier_NoEmail(t *testing.T) {
+	send := func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		t.Fatal("mail must not be sent without email")
+		return nil
+	}
+	n := NewSMTPNotifier("smtp.example.com", 587, "", "", "reviews@example.com", send)
+	digest := testDigest()
+	digest.Email = ""
+
+	assert.ErrorIs(t, n.Notify(context.Background(), digest), ErrNoRecipient)
+}
+
+func TestHTTPNotifier_PostsJSON(t *testing.T) {
+	client := &fakeHTTPClient{status: http.StatusNoContent}
+	n := NewHTTPNotifier("https://chat.example.com/digest", client)
+
+	err := n.Notify(context.Background(), testDigest())
+
+	assert.NoError(t, err)
+	if assert.Len(t, client.requests, 1) {
+		assert.Equal(t, http.MethodPost, client.requests[0].Method)
+		assert.Equal(t, "application/json", client.requests[0].Header.Get("Content-Type"))
+	}
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(client.bodies[0]), &payload))
+	assert.Equal(t, "@bob", payload["chat_handle"])
+	assert.Len(t, payload["pull_requests"], 2)
+	assert.Equal(t, "2025-01-08T09:00:00Z", payload["generatedAt"])
+}
+
+func TestHTTPNotifier_UnexpectedStatus(t *testing.T) {
+	client := &fakeHTTPClient{status: http.StatusBadGateway}
+	n := NewHTTPNotifier("https://chat.example.com/digest", client)
+
+	err := n.Notify(context.Background(), testDigest())
+
+	assert.ErrorContains(t, err, "unexpected status 502: boom")
+}
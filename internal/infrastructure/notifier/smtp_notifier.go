@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+// SendMailFunc отправляет письмо, по умолчанию smtp.SendMail, в тестах подменяется
+type SendMailFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// SMTPNotifier отправляет сводку письмом на email из контактов пользователя
+type SMTPNotifier struct {
+	addr     string
+	auth     smtp.Auth
+	from     string
+	sendMail SendMailFunc
+}
+
+// NewSMTPNotifier пустой username отключает авторизацию на SMTP-сервере
+func NewSMTPNotifier(host string, port int, username, password, from string, sendMail SendMailFunc) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	if sendMail == nil {
+		sendMail = smtp.SendMail
+	}
+	return &SMTPNotifier{
+		addr:     net.JoinHostPort(host, fmt.Sprint(port)),
+		auth:     auth,
+		from:     from,
+		sendMail: sendMail,
+	}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, digest *domain.ReviewDigest) error {
+	if digest.Email == "" {
+		return ErrNoRecipient
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return n.sendMail(n.addr, n.auth, n.from, []string{digest.Email}, n.message(digest))
+}
+
+// message письмо в формате RFC 5322, тема кодируется для кириллицы
+func (n *SMTPNotifier) message(digest *domain.ReviewDigest) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", digest.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", digestSubject(digest)))
+	fmt.Fprintf(&b, "Date: %s\r\n", digest.GeneratedAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(formatDigest(digest), "\n", "\r\n"))
+	return []byte(b.String())
+}
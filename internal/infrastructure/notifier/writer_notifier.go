@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+// WriterNotifier пишет сводки текстом в файл или stdout, для локального запуска
+type WriterNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{w: w}
+}
+
+func (n *WriterNotifier) Notify(ctx context.Context, digest *domain.ReviewDigest) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.w, "=== %s | %s (%s) | %s\n%s\n",
+		digest.GeneratedAt.UTC().Format(time.RFC3339),
+		digest.Username,
+		digest.UserId,
+		digestSubject(digest),
+		formatDigest(digest),
+	)
+	return err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReassignEvent_Replacement(t *testing.T) {
+	event := reassignEvent(&dto.ReassignPrDTO{
+		PrId:          "pr1",
+		OldReviewerId: "old",
+		ReplacedBy:    "new",
+	}, domain.AssignReasonManualReassign)
+
+	assert.Equal(t, domain.AssignmentEventReassign, event.Type)
+	assert.Equal(t, "new", event.UserId)
+	assert.Equal(t, "old", event.PreviousUserId)
+	assert.Equal(t, domain.AssignReasonManualReassign, event.Reason)
+}
+
+func TestReassignEvent_WithoutReplacementIsUnassign(t *testing.T) {
+	event := reassignEvent(&dto.ReassignPrDTO{
+		PrId:          "pr1",
+		OldReviewerId: "old",
+		Reason:        domain.AssignReasonUserDeactivated,
+	}, domain.AssignReasonManualReassign)
+
+	assert.Equal(t, domain.AssignmentEventUnassign, event.Type)
+	assert.Equal(t, "old", event.UserId)
+	assert.Empty(t, event.PreviousUserId)
+	assert.Equal(t, domain.AssignReasonUserDeactivated, event.Reason)
+}
+
+func TestAssignEvents(t *testing.T) {
+	events := assignEvents("pr1", []string{"r1", "r2"}, domain.AssignReasonPrCreated)
+
+	assert.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, "pr1", event.PrId)
+		assert.Equal(t, domain.AssignmentEventAssign, event.Type)
+		assert.Equal(t, domain.AssignReasonPrCreated, event.Reason)
+	}
+}
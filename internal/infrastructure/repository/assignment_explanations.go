@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+const (
+	insertAssignmentExplanationQuery = `
+INSERT INTO assignment_explanations (pr_id, reason, team_name, seed, reviewers, pools)
+VALUES ($1, $2, $3, $4, $5, $6::jsonb);`
+
+	selectAssignmentExplanationsQuery = `
+SELECT id, pr_id, reason, team_name, seed, reviewers, pools, created_at
+FROM assignment_explanations
+WHERE pr_id = $1
+ORDER BY id ASC;`
+)
+
+// вспомогательная функция для записи объяснений подбора в транзакции назначения, которое они объясняют.
+// nil пропускаются: ревьюеры могли не подбираться
+func recordExplanations(ctx context.Context, exec queryExecutor, prId string, explanations ...*domain.AssignmentExplanation) error {
+	for _, explanation := range explanations {
+		if explanation == nil {
+			continue
+		}
+
+		reviewers := explanation.Reviewers
+		if reviewers == nil {
+			reviewers = []string{}
+		}
+		pools := explanation.Pools
+		if pools == nil {
+			pools = []domain.ExplainedPool{}
+		}
+		data, err := json.Marshal(pools)
+		if err != nil {
+			return err
+		}
+
+		if _, err := exec.Exec(ctx, insertAssignmentExplanationQuery,
+			prId,
+			explanation.Reason,
+			explanation.TeamName,
+			explanation.Seed,
+			reviewers,
+			string(data),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readExplanations(ctx context.Context, exec queryExecutor, prId string) ([]*domain.AssignmentExplanation, error) {
+	rows, err := exec.Query(ctx, selectAssignmentExplanationsQuery, prId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	explanations := make([]*domain.AssignmentExplanation, 0)
+	for rows.Next() {
+		explanation := &domain.AssignmentExplanation{}
+		var pools []byte
+		if err := rows.Scan(
+			&explanation.Id,
+			&explanation.PrId,
+			&explanation.Reason,
+			&explanation.TeamName,
+			&explanation.Seed,
+			&explanation.Reviewers,
+			&pools,
+			&explanation.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(pools, &explanation.Pools); err != nil {
+			return nil, err
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations, rows.Err()
+}
@@ -11,7 +11,15 @@ var (
 	ErrNotFound            = errors.New("resource not found")
 	ErrAlreadyExists       = errors.New("resource already exists")
 	ErrPrMergedStatus      = errors.New("PR is merged")
+	ErrPrClosedStatus      = errors.New("PR is closed")
+	ErrPrStatusConflict    = errors.New("PR status changed concurrently")
+	ErrNotApproved         = errors.New("not enough approvals")
 	ErrReviewerNotAssigned = errors.New("reviewer not assigned")
+	ErrNotTeamMember       = errors.New("user is not a team member")
+	ErrFallbackTeamMissing = errors.New("fallback team not found")
+	ErrTeamLead            = errors.New("user is the team lead")
+	ErrTeamCycle           = errors.New("team hierarchy cycle")
+	ErrMemberOfAnotherTeam = errors.New("user is a member of another team")
 )
 
 func handleDBError(err error) error {
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"go.uber.org/zap"
+)
+
+const (
+	// Логин провайдера можно перепривязать к другому пользователю, но у пользователя один логин на провайдера
+	upsertIntegrationUserQuery = `
+INSERT INTO integration_users (provider, external_login, external_id, user_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (provider, external_login) DO UPDATE
+SET external_id = EXCLUDED.external_id, user_id = EXCLUDED.user_id
+RETURNING provider, external_login, external_id, user_id, created_at;`
+
+	selectIntegrationUsersQuery = `
+SELECT provider, external_login, external_id, user_id, created_at
+FROM integration_users
+WHERE ($1 = '' OR provider = $1)
+ORDER BY provider ASC, external_login ASC;`
+
+	deleteIntegrationUserQuery = `
+DELETE FROM integration_users
+WHERE provider = $1 AND external_login = $2;`
+
+	selectUserByExternalLoginQuery = `
+SELECT user_id
+FROM integration_users
+WHERE provider = $1 AND external_login = $2;`
+
+	selectExternalUsersQuery = `
+SELECT provider, external_login, external_id, user_id, created_at
+FROM integration_users
+WHERE provider = $1 AND user_id = ANY($2);`
+)
+
+type IntegrationRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewIntegrationRepository(db *pgxpool.Pool, log *zap.Logger) *IntegrationRepository {
+	return &IntegrationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// SetUser создает или обновляет сопоставление логина провайдера с пользователем
+func (r *IntegrationRepository) SetUser(ctx context.Context, d *dto.SetIntegrationUserDTO) (*domain.IntegrationUser, error) {
+	r.log.Info("set integration user",
+		zap.String("provider", d.Provider),
+		zap.String("external_login", d.ExternalLogin),
+		zap.String("user_id", d.UserId),
+	)
+
+	user, err := scanIntegrationUser(r.db.QueryRow(ctx, upsertIntegrationUserQuery, d.Provider, d.ExternalLogin, d.ExternalId, d.UserId))
+	if err != nil {
+		r.log.Error("failed to set integration user",
+			zap.String("provider", d.Provider),
+			zap.String("external_login", d.ExternalLogin),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return user, nil
+}
+
+// ListUsers сопоставления провайдера, пустой provider - всех провайдеров
+func (r *IntegrationRepository) ListUsers(ctx context.Context, provider string) ([]*domain.IntegrationUser, error) {
+	r.log.Debug("list integration users", zap.String("provider", provider))
+
+	rows, err := r.db.Query(ctx, selectIntegrationUsersQuery, provider)
+	if err != nil {
+		r.log.Error("failed to load integration users", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	users := make([]*domain.IntegrationUser, 0)
+	for rows.Next() {
+		user, err := scanIntegrationUser(rows)
+		if err != nil {
+			r.log.Error("failed to scan integration user", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return users, nil
+}
+
+func (r *IntegrationRepository) DeleteUser(ctx context.Context, d *dto.DeleteIntegrationUserDTO) error {
+	r.log.Info("delete integration user",
+		zap.String("provider", d.Provider),
+		zap.String("external_login", d.ExternalLogin),
+	)
+
+	cmdTag, err := r.db.Exec(ctx, deleteIntegrationUserQuery, d.Provider, d.ExternalLogin)
+	if err != nil {
+		r.log.Error("failed to delete integration user", zap.String("external_login", d.ExternalLogin), zap.Error(err))
+		return handleDBError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// FindUserId пользователь сервиса по логину провайдера
+func (r *IntegrationRepository) FindUserId(ctx context.Context, provider, login string) (string, error) {
+	var userId string
+	if err := r.db.QueryRow(ctx, selectUserByExternalLoginQuery, provider, login).Scan(&userId); err != nil {
+		return "", handleDBError(err)
+	}
+	return userId, nil
+}
+
+// FindExternalUsers сопоставления для пользователей сервиса. Пользователи без сопоставления пропускаются
+func (r *IntegrationRepository) FindExternalUsers(ctx context.Context, provider string, userIds []string) ([]*domain.IntegrationUser, error) {
+	rows, err := r.db.Query(ctx, selectExternalUsersQuery, provider, userIds)
+	if err != nil {
+		r.log.Error("failed to load external users", zap.String("provider", provider), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	users := make([]*domain.IntegrationUser, 0, len(userIds))
+	for rows.Next() {
+		user, err := scanIntegrationUser(rows)
+		if err != nil {
+			return nil, handleDBError(err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return users, nil
+}
+
+func scanIntegrationUser(row rowScanner) (*domain.IntegrationUser, error) {
+	user := &domain.IntegrationUser{}
+	err := row.Scan(
+		&user.Provider,
+		&user.ExternalLogin,
+		&user.ExternalId,
+		&user.UserId,
+		&user.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+)
+
+const (
+	// Запись событий в outbox и создание доставок для подписанных вебхуков одним запросом
+	insertOutboxEventsQuery = `
+WITH events AS (
+    INSERT INTO outbox_events (event_type, payload)
+    SELECT e.event_type, e.payload::jsonb
+    FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS e(event_type, payload, ord)
+    ORDER BY e.ord
+    RETURNING id, event_type
+)
+INSERT INTO webhook_deliveries (event_id, webhook_id)
+SELECT events.id, w.id
+FROM events
+JOIN webhooks w ON cardinality(w.event_types) = 0 OR events.event_type = ANY(w.event_types);`
+)
+
+// вспомогательная функция для записи событий в outbox в транзакции изменения, которое их породило.
+// Доставки создаются сразу для всех вебхуков, подписанных на тип события
+func enqueueOutboxEvents(ctx context.Context, exec queryExecutor, events []*domain.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	types := make([]string, 0, len(events))
+	payloads := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+		payloads = append(payloads, string(event.Payload))
+	}
+
+	_, err := exec.Exec(ctx, insertOutboxEventsQuery, types, payloads)
+	return err
+}
+
+func newOutboxEvent(eventType string, payload any) (*domain.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OutboxEvent{Type: eventType, Payload: data}, nil
+}
+
+// prOutboxEvent событие pr.created или pr.merged с текущим состоянием PR
+func prOutboxEvent(ctx context.Context, eventType string, pr *result.PrResult) (*domain.OutboxEvent, error) {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	return newOutboxEvent(eventType, &domain.PrEventPayload{
+		PrId:              pr.Id,
+		PrName:            pr.Name,
+		AuthorId:          pr.AuthorId,
+		Status:            pr.Status,
+		AssignedReviewers: reviewers,
+		MergedAt:          pr.MergedAt,
+		Actor:             domain.AuditInfoFrom(ctx).Actor,
+	})
+}
+
+// reviewerOutboxEvents события вебхуков для записей журнала назначений.
+// Снятие ревьюеров при слиянии покрывается событием pr.merged и отдельно не публикуется
+func reviewerOutboxEvents(events []*domain.AssignmentEvent, actor string) ([]*domain.OutboxEvent, error) {
+	outbox := make([]*domain.OutboxEvent, 0, len(events))
+	for _, event := range events {
+		var eventType string
+		payload := &domain.ReviewerEventPayload{
+			PrId:   event.PrId,
+			Reason: event.Reason,
+			Actor:  actor,
+		}
+		switch event.Type {
+		case domain.AssignmentEventAssign:
+			eventType = domain.EventReviewerAssigned
+			payload.UserId = event.UserId
+		case domain.AssignmentEventReassign:
+			eventType = domain.EventReviewerReassigned
+			payload.OldUserId = event.PreviousUserId
+			payload.NewUserId = event.UserId
+		case domain.AssignmentEventUnassign:
+			if event.Reason == domain.AssignReasonPrMerged {
+				continue
+			}
+			eventType = domain.EventReviewerReassigned
+			payload.OldUserId = event.UserId
+		default:
+			continue
+		}
+
+		outboxEvent, err := newOutboxEvent(eventType, payload)
+		if err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, outboxEvent)
+	}
+	return outbox, nil
+}
+
+// userDeactivatedOutboxEvents события user.deactivated для выключенных пользователей
+func userDeactivatedOutboxEvents(ctx context.Context, teamName string, userIds []string) ([]*domain.OutboxEvent, error) {
+	actor := domain.AuditInfoFrom(ctx).Actor
+	events := make([]*domain.OutboxEvent, 0, len(userIds))
+	for _, userId := range userIds {
+		event, err := newOutboxEvent(domain.EventUserDeactivated, &domain.UserDeactivatedPayload{
+			UserId:   userId,
+			TeamName: teamName,
+			Actor:    actor,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewerOutboxEvents(t *testing.T) {
+	events, err := reviewerOutboxEvents([]*domain.AssignmentEvent{
+		{PrId: "pr1", Type: domain.AssignmentEventAssign, UserId: "u1", Reason: domain.AssignReasonPrCreated},
+		{PrId: "pr1", Type: domain.AssignmentEventReassign, UserId: "u3", PreviousUserId: "u2", Reason: domain.AssignReasonManualReassign},
+		{PrId: "pr1", Type: domain.AssignmentEventUnassign, UserId: "u4", Reason: domain.AssignReasonUserDeactivated},
+		{PrId: "pr1", Type: domain.AssignmentEventUnassign, UserId: "u1", Reason: domain.AssignReasonPrMerged},
+	}, "alice")
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	var assigned, reassigned, unassigned domain.ReviewerEventPayload
+	assert.Equal(t, domain.EventReviewerAssigned, events[0].Type)
+	assert.NoError(t, json.Unmarshal(events[0].Payload, &assigned))
+	assert.Equal(t, "u1", assigned.UserId)
+	assert.Equal(t, "alice", assigned.Actor)
+
+	assert.Equal(t, domain.EventReviewerReassigned, events[1].Type)
+	assert.NoError(t, json.Unmarshal(events[1].Payload, &reassigned))
+	assert.Equal(t, "u2", reassigned.OldUserId)
+	assert.Equal(t, "u3", reassigned.NewUserId)
+
+	// Снятие без замены - reviewer.reassigned без new_user_id
+	assert.Equal(t, domain.EventReviewerReassigned, events[2].Type)
+	assert.NoError(t, json.Unmarshal(events[2].Payload, &unassigned))
+	assert.Equal(t, "u4", unassigned.OldUserId)
+	assert.Empty(t, unassigned.NewUserId)
+	assert.NotContains(t, string(events[2].Payload), "new_user_id")
+}
+
+func TestPrOutboxEvent(t *testing.T) {
+	ctx := domain.WithAuditInfo(context.Background(), domain.AuditInfo{Actor: "bob"})
+
+	event, err := prOutboxEvent(ctx, domain.EventPrCreated, &result.PrResult{
+		Id:       "pr1",
+		Name:     "Add feature",
+		AuthorId: "u1",
+		Status:   domain.PrStatusOpen,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EventPrCreated, event.Type)
+	var payload domain.PrEventPayload
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, "pr1", payload.PrId)
+	assert.Equal(t, "bob", payload.Actor)
+	assert.Equal(t, []string{}, payload.AssignedReviewers)
+	assert.Nil(t, payload.MergedAt)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/niklvrr/AvitoInternship2025/internal/domain"
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
@@ -14,37 +17,163 @@ import (
 )
 
 const (
+	// Пустое название команды сохраняется как NULL - PR следует основной команде автора.
+	// Команда, в которой автор не состоит, тоже дает NULL, это проверяется после вставки
 	insertPrQuery = `
-INSERT INTO prs(id, name, author_id)
-VALUES ($1, $2, $3)
-RETURNING id, name, author_id, status, created_at, merged_at;`
+INSERT INTO prs(id, name, author_id, status, changed_paths, required_tags, team_id, requires_lead_review, lead_reviewer_id)
+VALUES ($1, $2, $3, $4, $5, $6, (
+    SELECT t.id FROM teams t
+    JOIN team_members tm ON tm.team_id = t.id
+    WHERE t.name = NULLIF($7, '') AND tm.user_id = $3
+), $8, NULLIF($9, ''))
+RETURNING id, name, author_id, status, created_at, merged_at, closed_at, changed_paths, required_tags,
+    COALESCE((SELECT t.name FROM teams t WHERE t.id = prs.team_id), ''),
+    requires_lead_review, COALESCE(lead_reviewer_id, '');`
 
 	selectTeamQuery = `
-SELECT team_id FROM team_members
-WHERE user_id = $1
+SELECT
+    t.id,
+    t.name,
+    COALESCE(t.parent_id, ''),
+    ts.reviewer_count,
+    ts.strategy,
+    ts.lead_user_id,
+    ts.require_lead,
+    ts.required_approvals,
+    ts.require_tags,
+    ts.review_sla_hours,
+    ts.sla_policy,
+    ts.updated_at
+FROM team_members tm
+JOIN teams t ON t.id = tm.team_id
+LEFT JOIN team_settings ts ON ts.team_id = t.id
+WHERE tm.user_id = $1
+ORDER BY tm.is_primary DESC, tm.joined_at ASC
 LIMIT 1;`
 
+	// Команда пользователя по названию, нет строки - пользователь в ней не состоит
+	selectMemberTeamQuery = `
+SELECT
+    t.id,
+    t.name,
+    COALESCE(t.parent_id, ''),
+    ts.reviewer_count,
+    ts.strategy,
+    ts.lead_user_id,
+    ts.require_lead,
+    ts.required_approvals,
+    ts.require_tags,
+    ts.review_sla_hours,
+    ts.sla_policy,
+    ts.updated_at
+FROM team_members tm
+JOIN teams t ON t.id = tm.team_id
+LEFT JOIN team_settings ts ON ts.team_id = t.id
+WHERE tm.user_id = $1 AND t.name = $2;`
+
+	selectFallbackTeamsQuery = `
+SELECT
+    t.id,
+    t.name,
+    ts.reviewer_count,
+    ts.strategy,
+    ts.lead_user_id,
+    ts.require_lead,
+    ts.required_approvals,
+    ts.require_tags,
+    ts.review_sla_hours,
+    ts.sla_policy,
+    ts.updated_at
+FROM team_fallbacks tf
+JOIN teams t ON t.id = tf.fallback_team_id
+LEFT JOIN team_settings ts ON ts.team_id = t.id
+WHERE tf.team_id = $1
+ORDER BY tf.position ASC;`
+
+	// Предки команды от ближайшего. Глубина ограничена на случай цикла, который мог возникнуть в обход сервиса
+	selectParentTeamsQuery = `
+WITH RECURSIVE ancestors AS (
+    SELECT parent_id AS id, 1 AS depth
+    FROM teams
+    WHERE id = $1 AND parent_id IS NOT NULL
+    UNION ALL
+    SELECT t.parent_id, a.depth + 1
+    FROM ancestors a
+    JOIN teams t ON t.id = a.id
+    WHERE t.parent_id IS NOT NULL AND a.depth < 32
+)
+SELECT
+    t.id,
+    t.name,
+    ts.reviewer_count,
+    ts.strategy,
+    ts.lead_user_id,
+    ts.require_lead,
+    ts.required_approvals,
+    ts.require_tags,
+    ts.review_sla_hours,
+    ts.sla_policy,
+    ts.updated_at
+FROM ancestors a
+JOIN teams t ON t.id = a.id
+LEFT JOIN team_settings ts ON ts.team_id = t.id
+ORDER BY a.depth ASC;`
+
 	selectTeamMembersQuery = `
 SELECT
     u.id,
     u.name,
     u.team_name,
     u.is_active,
-    u.created_at
+    u.created_at,
+    u.max_open_reviews,
+    COUNT(p.id) AS open_reviews,
+    EXISTS (
+        SELECT 1 FROM availability a
+        WHERE a.user_id = u.id
+          AND a.starts_at <= NOW()
+          AND a.ends_at > NOW()
+    ) AS unavailable,
+    ARRAY(SELECT ut.tag FROM user_tags ut WHERE ut.user_id = u.id ORDER BY ut.tag) AS tags,
+    tm.role = 'LEAD' AS is_lead
 FROM team_members tm
 JOIN users u ON u.id = tm.user_id
-WHERE tm.team_id = $1;`
+LEFT JOIN pr_reviewers prr ON prr.user_id = u.id
+LEFT JOIN prs p ON p.id = prr.pr_id AND p.status = 'OPEN'
+WHERE tm.team_id = $1
+GROUP BY u.id, tm.role;`
 
 	insertPrReviewerQuery = `
 INSERT INTO pr_reviewers(user_id, pr_id)
 VALUES ($1, $2)
 ON CONFLICT (user_id, pr_id) DO NOTHING;`
 
+	// Блокирует PR до конца транзакции
+	lockPrQuery = `
+SELECT status FROM prs
+WHERE id = $1
+FOR UPDATE;`
+
 	mergePrQuery = `
 UPDATE prs
 SET status = 'MERGED',
     merged_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND status <> 'MERGED';`
+WHERE id = $1 AND status = 'OPEN';`
+
+	// Пустой $4 оставляет назначенного лида без изменений, пустой $5 - команду PR
+	changePrStatusQuery = `
+UPDATE prs
+SET status = $3::pr_status,
+    closed_at = CASE WHEN $3::pr_status = 'CLOSED' THEN CURRENT_TIMESTAMP ELSE NULL END,
+    lead_reviewer_id = COALESCE(NULLIF($4, ''), lead_reviewer_id),
+    team_id = COALESCE((SELECT id FROM teams WHERE name = NULLIF($5, '')), team_id)
+WHERE id = $1 AND status = $2::pr_status;`
+
+	// Замена уходящего лида становится лидом PR
+	replaceLeadReviewerQuery = `
+UPDATE prs
+SET lead_reviewer_id = NULLIF($3, '')
+WHERE id = $1 AND lead_reviewer_id = $2;`
 
 	deletePrReviewerQuery = `
 DELETE FROM pr_reviewers
@@ -58,14 +187,56 @@ WHERE pr_id = $1;`
 SELECT 1 FROM pr_reviewers
 WHERE pr_id = $1 AND user_id = $2;`
 
+	insertReviewQuery = `
+INSERT INTO pr_reviews (pr_id, reviewer_id, verdict, comment)
+VALUES ($1, $2, $3, $4)
+RETURNING id, pr_id, reviewer_id, verdict, comment, created_at;`
+
+	selectReviewsQuery = `
+SELECT id, pr_id, reviewer_id, verdict, comment, created_at
+FROM pr_reviews
+WHERE pr_id = $1
+ORDER BY id ASC;`
+
+	// Требование команды PR, для PR без команды - основной команды автора
+	selectRequiredApprovalsQuery = `
+SELECT COALESCE((
+    SELECT ts.required_approvals
+    FROM team_settings ts
+    WHERE ts.team_id = COALESCE(p.team_id, (
+        SELECT tm.team_id FROM team_members tm
+        WHERE tm.user_id = p.author_id AND tm.is_primary
+    ))
+), 0)
+FROM prs p
+WHERE p.id = $1;`
+
+	// Одобрения текущих ревьюеров: у каждого учитывается последний вердикт, кроме COMMENTED
+	countApprovalsQuery = `
+SELECT COUNT(*)
+FROM pr_reviewers pr
+WHERE pr.pr_id = $1
+  AND (
+    SELECT r.verdict
+    FROM pr_reviews r
+    WHERE r.pr_id = pr.pr_id
+      AND r.reviewer_id = pr.user_id
+      AND r.verdict <> 'COMMENTED'
+    ORDER BY r.id DESC
+    LIMIT 1
+  ) = 'APPROVED';`
+
 	selectUserStatsQuery = `
 SELECT 
     u.id,
     u.name,
-    COUNT(pr.user_id) as assignments_count
+    COUNT(pr.user_id) as assignments_count,
+    COUNT(p.id) FILTER (WHERE p.status = 'OPEN') as open_reviews,
+    u.max_open_reviews
 FROM users u
 LEFT JOIN pr_reviewers pr ON pr.user_id = u.id
-GROUP BY u.id, u.name
+LEFT JOIN prs p ON p.id = pr.pr_id
+GROUP BY u.id, u.name, u.max_open_reviews
 ORDER BY assignments_count DESC, u.name;`
 
 	selectPrStatsQuery = `
@@ -78,20 +249,115 @@ LEFT JOIN pr_reviewers pr ON pr.pr_id = p.id
 GROUP BY p.id, p.name
 ORDER BY reviewers_count DESC, p.name;`
 
+	selectPairStatsQuery = `
+SELECT
+    p.author_id,
+    pr.user_id,
+    COUNT(*) as reviews_count
+FROM pr_reviewers pr
+JOIN prs p ON p.id = pr.pr_id
+GROUP BY p.author_id, pr.user_id
+ORDER BY p.author_id, reviews_count DESC, pr.user_id;`
+
+	// Ревьюеры последних $2 PR автора и число таких PR у каждого
+	selectRecentPairingsQuery = `
+SELECT prr.user_id, COUNT(*)
+FROM (
+    SELECT id
+    FROM prs
+    WHERE author_id = $1
+    ORDER BY created_at DESC
+    LIMIT $2
+) recent
+JOIN pr_reviewers prr ON prr.pr_id = recent.id
+GROUP BY prr.user_id;`
+
+	selectOpenReviewsQuery = `
+SELECT
+    p.id,
+    p.author_id,
+    prr.user_id,
+    ARRAY(SELECT r.user_id FROM pr_reviewers r WHERE r.pr_id = p.id) AS reviewers,
+    COALESCE(t.name, ''),
+    p.requires_lead_review,
+    COALESCE(p.lead_reviewer_id, '')
+FROM pr_reviewers prr
+JOIN prs p ON p.id = prr.pr_id
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE prr.user_id = ANY($1) AND p.status = 'OPEN'
+ORDER BY p.created_at ASC;`
+
+	// Переназначение набора ревью одним запросом: снимаем старых ревьюеров с OPEN PR
+	// и назначаем замену только там, где снятие прошло. Замена лида PR становится лидом
+	applyReassignmentsQuery = `
+WITH plan AS (
+    SELECT *
+    FROM unnest($1::text[], $2::text[], $3::text[]) AS t(pr_id, old_user_id, new_user_id)
+),
+removed AS (
+    DELETE FROM pr_reviewers prr
+    USING plan, prs p
+    WHERE prr.pr_id = plan.pr_id
+      AND prr.user_id = plan.old_user_id
+      AND p.id = prr.pr_id
+      AND p.status = 'OPEN'
+    RETURNING prr.pr_id, prr.user_id
+),
+inserted AS (
+    INSERT INTO pr_reviewers (user_id, pr_id)
+    SELECT plan.new_user_id, plan.pr_id
+    FROM plan
+    JOIN removed ON removed.pr_id = plan.pr_id AND removed.user_id = plan.old_user_id
+    ON CONFLICT (user_id, pr_id) DO NOTHING
+),
+lead AS (
+    UPDATE prs
+    SET lead_reviewer_id = plan.new_user_id
+    FROM plan
+    JOIN removed ON removed.pr_id = plan.pr_id AND removed.user_id = plan.old_user_id
+    WHERE prs.id = plan.pr_id
+      AND prs.lead_reviewer_id = plan.old_user_id
+)
+SELECT pr_id, user_id FROM removed;`
+
+	// Запись набора событий журнала назначений одним запросом
+	insertAssignmentEventsQuery = `
+INSERT INTO assignment_events (pr_id, event_type, user_id, previous_user_id, reason, actor, request_id)
+SELECT e.pr_id, e.event_type::assignment_event_type, e.user_id, NULLIF(e.previous_user_id, ''), e.reason, $6, $7
+FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[])
+    AS e(pr_id, event_type, user_id, previous_user_id, reason);`
+
+	selectAssignmentEventsQuery = `
+SELECT id, pr_id, event_type, user_id, COALESCE(previous_user_id, ''), actor, reason, request_id, created_at
+FROM assignment_events
+WHERE pr_id = $1
+ORDER BY id ASC;`
+
 	selectPrQuery = `
-SELECT id, name, author_id, status, created_at, merged_at FROM prs
-WHERE id = $1;`
+SELECT p.id, p.name, p.author_id, p.status, p.created_at, p.merged_at, p.closed_at, p.changed_paths, p.required_tags,
+    COALESCE(t.name, ''), p.requires_lead_review, COALESCE(p.lead_reviewer_id, '')
+FROM prs p
+LEFT JOIN teams t ON t.id = p.team_id
+WHERE p.id = $1;`
+
+	selectCodeOwnersQuery = `
+SELECT pattern, owner_ids
+FROM team_code_owners
+WHERE team_id = $1
+ORDER BY position ASC;`
 )
 
 type PrRepository struct {
+	reviewerPools
 	db  *pgxpool.Pool
 	log *zap.Logger
 }
 
 func NewPrRepository(db *pgxpool.Pool, log *zap.Logger) *PrRepository {
 	return &PrRepository{
-		db:  db,
-		log: log,
+		reviewerPools: reviewerPools{db: db, log: log},
+		db:            db,
+		log:           log,
 	}
 }
 
@@ -109,16 +375,29 @@ func (r *PrRepository) Create(ctx context.Context, d *dto.CreatPrDTO, prReviewer
 	defer tx.Rollback(ctx)
 
 	prRes := &result.PrResult{}
-	var mergedAt sql.NullTime
+	var mergedAt, closedAt sql.NullTime
 
 	// Создаем PR и читаем его состояние
-	err = tx.QueryRow(ctx, insertPrQuery, d.PrId, d.PrName, d.AuthorId).Scan(
+	changedPaths, requiredTags := d.ChangedPaths, d.RequiredTags
+	if changedPaths == nil {
+		changedPaths = []string{}
+	}
+	if requiredTags == nil {
+		requiredTags = []string{}
+	}
+	err = tx.QueryRow(ctx, insertPrQuery, d.PrId, d.PrName, d.AuthorId, d.Status, changedPaths, requiredTags, d.TeamName, d.RequiresLeadReview, d.LeadReviewerId).Scan(
 		&prRes.Id,
 		&prRes.Name,
 		&prRes.AuthorId,
 		&prRes.Status,
 		&prRes.CreatedAt,
 		&mergedAt,
+		&closedAt,
+		&prRes.ChangedPaths,
+		&prRes.RequiredTags,
+		&prRes.TeamName,
+		&prRes.RequiresLeadReview,
+		&prRes.LeadReviewerId,
 	)
 	if err != nil {
 		r.log.Error("failed to insert PR",
@@ -127,25 +406,49 @@ func (r *PrRepository) Create(ctx context.Context, d *dto.CreatPrDTO, prReviewer
 		)
 		return nil, handleDBError(err)
 	}
+	// Черновик создается без подбора ревьюеров, поэтому членство автора в команде PR проверяется здесь
+	if d.TeamName != "" && prRes.TeamName == "" {
+		r.log.Warn("author is not a member of PR team",
+			zap.String("pr_id", d.PrId),
+			zap.String("author_id", d.AuthorId),
+			zap.String("team_name", d.TeamName),
+		)
+		return nil, ErrNotTeamMember
+	}
 	if mergedAt.Valid {
 		prRes.MergedAt = &mergedAt.Time
 	}
+	if closedAt.Valid {
+		prRes.ClosedAt = &closedAt.Time
+	}
 
-	assignedReviewers := make([]string, 0, len(prReviewers))
 	// Записываем назначенных ревьюеров
-	for _, prMemberId := range prReviewers {
-		if prMemberId == "" {
-			continue
-		}
-		if _, err := tx.Exec(ctx, insertPrReviewerQuery, prMemberId, prRes.Id); err != nil {
-			r.log.Error("failed to insert PR reviewer",
-				zap.String("pr_id", d.PrId),
-				zap.String("reviewer_id", prMemberId),
-				zap.Error(err),
-			)
-			return nil, handleDBError(err)
-		}
-		assignedReviewers = append(assignedReviewers, prMemberId)
+	assignedReviewers, err := insertReviewers(ctx, tx, prRes.Id, prReviewers)
+	if err != nil {
+		r.log.Error("failed to insert PR reviewers",
+			zap.String("pr_id", d.PrId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+	prRes.AssignedReviewers = assignedReviewers
+
+	// Публикуем pr.created до событий назначения ревьюеров
+	createdEvent, err := prOutboxEvent(ctx, domain.EventPrCreated, prRes)
+	if err == nil {
+		err = enqueueOutboxEvents(ctx, tx, []*domain.OutboxEvent{createdEvent})
+	}
+	if err != nil {
+		r.log.Error("failed to enqueue outbox events", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if err := recordAssignmentEvents(ctx, tx, assignEvents(prRes.Id, assignedReviewers, domain.AssignReasonPrCreated)); err != nil {
+		r.log.Error("failed to record assignment events", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if err := recordExplanations(ctx, tx, prRes.Id, d.Explanation); err != nil {
+		r.log.Error("failed to record assignment explanation", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -153,8 +456,6 @@ func (r *PrRepository) Create(ctx context.Context, d *dto.CreatPrDTO, prReviewer
 		return nil, handleDBError(err)
 	}
 
-	prRes.AssignedReviewers = assignedReviewers
-
 	r.log.Info("PR created",
 		zap.String("pr_id", prRes.Id),
 		zap.Int("assigned_reviewers", len(prRes.AssignedReviewers)),
@@ -163,7 +464,10 @@ func (r *PrRepository) Create(ctx context.Context, d *dto.CreatPrDTO, prReviewer
 	return prRes, nil
 }
 
-func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.PrResult, error) {
+// Merge сливает открытый PR. Без SkipApprovals одобрения считаются в той же транзакции
+// под блокировкой PR, поэтому вердикт не может смениться между проверкой и слиянием.
+// Если одобрений не хватает, возвращается ErrNotApproved
+func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.MergeResult, error) {
 	r.log.Info("merge PR started", zap.String("pr_id", d.PrId))
 
 	tx, err := r.db.Begin(ctx)
@@ -172,6 +476,36 @@ func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.Pr
 	}
 	defer tx.Rollback(ctx)
 
+	// Блокируем PR, вердикты по нему ждут окончания слияния
+	var status string
+	if err := tx.QueryRow(ctx, lockPrQuery, d.PrId).Scan(&status); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to lock PR before merge", zap.String("pr_id", d.PrId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	// Открытый PR можно смержить, только набрав требуемое число одобрений
+	if status == domain.PrStatusOpen && !d.SkipApprovals {
+		var requiredApprovals, approvals int
+		if err := tx.QueryRow(ctx, selectRequiredApprovalsQuery, d.PrId).Scan(&requiredApprovals); err != nil {
+			r.log.Error("failed to read required approvals", zap.String("pr_id", d.PrId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		if err := tx.QueryRow(ctx, countApprovalsQuery, d.PrId).Scan(&approvals); err != nil {
+			r.log.Error("failed to count PR approvals", zap.String("pr_id", d.PrId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		if approvals < requiredApprovals {
+			r.log.Warn("merge rejected: not enough approvals",
+				zap.String("pr_id", d.PrId),
+				zap.Int("approvals", approvals),
+				zap.Int("required_approvals", requiredApprovals),
+			)
+			return nil, fmt.Errorf("%w: %d of %d approvals", ErrNotApproved, approvals, requiredApprovals)
+		}
+	}
+
 	// Получаем текущее состояние pr
 	prRes, err := readPr(ctx, tx, d.PrId)
 	if err != nil {
@@ -180,6 +514,7 @@ func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.Pr
 	}
 
 	// Меняем статус, если PR еще не merged
+	merged := false
 	if prRes.Status != "MERGED" {
 		cmdTag, err := tx.Exec(ctx, mergePrQuery, d.PrId)
 		if err != nil {
@@ -191,7 +526,7 @@ func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.Pr
 		}
 
 		if cmdTag.RowsAffected() == 0 {
-			// PR уже в состоянии MERGED, перечитываем состояние
+			// PR не в статусе OPEN, перечитываем состояние
 			prRes, err = readPr(ctx, tx, d.PrId)
 			if err != nil {
 				r.log.Error("failed to reload merged PR state",
@@ -200,11 +535,23 @@ func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.Pr
 				)
 				return nil, handleDBError(err)
 			}
+			// Сливать можно только открытый PR
+			if prRes.Status != domain.PrStatusMerged {
+				r.log.Warn("PR is not open, merge rejected",
+					zap.String("pr_id", d.PrId),
+					zap.String("status", prRes.Status),
+				)
+				if prRes.Status == domain.PrStatusClosed {
+					return nil, ErrPrClosedStatus
+				}
+				return nil, ErrPrStatusConflict
+			}
 		} else {
 			prRes, err = readPr(ctx, tx, d.PrId)
 			if err != nil {
 				return nil, handleDBError(err)
 			}
+			merged = true
 		}
 	}
 
@@ -219,6 +566,32 @@ func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.Pr
 	}
 	prRes.AssignedReviewers = prReviewers
 
+	// Слияние завершает назначения ревьюеров, повторный merge журнал и события не меняет
+	if merged {
+		mergedEvent, err := prOutboxEvent(ctx, domain.EventPrMerged, prRes)
+		if err == nil {
+			err = enqueueOutboxEvents(ctx, tx, []*domain.OutboxEvent{mergedEvent})
+		}
+		if err != nil {
+			r.log.Error("failed to enqueue outbox events", zap.String("pr_id", d.PrId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+
+		events := make([]*domain.AssignmentEvent, 0, len(prReviewers))
+		for _, reviewerId := range prReviewers {
+			events = append(events, &domain.AssignmentEvent{
+				PrId:   d.PrId,
+				Type:   domain.AssignmentEventUnassign,
+				UserId: reviewerId,
+				Reason: domain.AssignReasonPrMerged,
+			})
+		}
+		if err := recordAssignmentEvents(ctx, tx, events); err != nil {
+			r.log.Error("failed to record assignment events", zap.String("pr_id", d.PrId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		r.log.Error("failed to commit merge transaction",
 			zap.String("pr_id", d.PrId),
@@ -232,7 +605,10 @@ func (r *PrRepository) Merge(ctx context.Context, d *dto.MergePrDTO) (*result.Pr
 		zap.String("status", prRes.Status),
 	)
 	// Ответ
-	return prRes, nil
+	return &result.MergeResult{
+		Pr:     prRes,
+		Merged: merged,
+	}, nil
 }
 
 func (r *PrRepository) Reassign(ctx context.Context, d *dto.ReassignPrDTO) (*result.ReassignResult, error) {
@@ -258,10 +634,13 @@ func (r *PrRepository) Reassign(ctx context.Context, d *dto.ReassignPrDTO) (*res
 		return nil, handleDBError(err)
 	}
 
-	// Не даем переназначать ревьюеров после MERGED
-	if prRes.Status == "MERGED" {
+	// Не даем переназначать ревьюеров после MERGED и на закрытых PR
+	if prRes.Status == domain.PrStatusMerged {
 		return nil, ErrPrMergedStatus
 	}
+	if prRes.Status == domain.PrStatusClosed {
+		return nil, ErrPrClosedStatus
+	}
 
 	// Удалить старого ревьюера из таблицы pr_reviewers
 	cmdTag, err := tx.Exec(ctx, deletePrReviewerQuery, d.PrId, d.OldReviewerId)
@@ -287,6 +666,23 @@ func (r *PrRepository) Reassign(ctx context.Context, d *dto.ReassignPrDTO) (*res
 			return nil, handleDBError(err)
 		}
 	}
+	if _, err := tx.Exec(ctx, replaceLeadReviewerQuery, d.PrId, d.OldReviewerId, d.ReplacedBy); err != nil {
+		r.log.Error("failed to replace PR lead reviewer", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if prRes.LeadReviewerId == d.OldReviewerId {
+		prRes.LeadReviewerId = d.ReplacedBy
+	}
+
+	// Фиксируем замену в журнале назначений
+	if err := recordAssignmentEvents(ctx, tx, []*domain.AssignmentEvent{reassignEvent(d, domain.AssignReasonManualReassign)}); err != nil {
+		r.log.Error("failed to record assignment events", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if err := recordExplanations(ctx, tx, d.PrId, d.Explanation); err != nil {
+		r.log.Error("failed to record assignment explanation", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
 
 	// Чтение всех ревьюеров для этого pr
 	prReviewers, err := readReviewers(ctx, tx, d.PrId)
@@ -319,54 +715,261 @@ func (r *PrRepository) Reassign(ctx context.Context, d *dto.ReassignPrDTO) (*res
 	}, nil
 }
 
-// вспомогательная функция для поиска возможных ревьюеров, вызывается в сервисном слое для выбора ревьеров для pr
-func (r *PrRepository) SelectPotentialReviewers(ctx context.Context, userId string) ([]*domain.User, error) {
-	r.log.Debug("select potential reviewers", zap.String("user_id", userId))
+func (r *PrRepository) Get(ctx context.Context, prId string) (*result.PrResult, error) {
+	r.log.Debug("get PR", zap.String("pr_id", prId))
 
-	// Чтение команды пользователя
-	var teamId string
-	err := r.db.QueryRow(ctx, selectTeamQuery, userId).Scan(&teamId)
+	prRes, err := readPr(ctx, r.db, prId)
 	if err != nil {
-		r.log.Error("failed to load team for user",
-			zap.String("user_id", userId),
-			zap.Error(err),
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to load PR", zap.String("pr_id", prId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	// Чтение всех ревьюеров этого pr
+	prRes.AssignedReviewers, err = readReviewers(ctx, r.db, prId)
+	if err != nil {
+		r.log.Error("failed to read PR reviewers", zap.String("pr_id", prId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return prRes, nil
+}
+
+// ChangeStatus переводит PR в новый статус, только если он все еще в ожидаемом статусе
+func (r *PrRepository) ChangeStatus(ctx context.Context, d *dto.ChangePrStatusDTO) (*result.PrResult, error) {
+	r.log.Info("change PR status started",
+		zap.String("pr_id", d.PrId),
+		zap.String("from", d.FromStatus),
+		zap.String("to", d.ToStatus),
+		zap.Int("reviewers_requested", len(d.Reviewers)),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmdTag, err := tx.Exec(ctx, changePrStatusQuery, d.PrId, d.FromStatus, d.ToStatus, d.LeadReviewerId, d.TeamName)
+	if err != nil {
+		r.log.Error("failed to change PR status", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		// Отличаем отсутствующий PR от изменения статуса параллельным запросом
+		if _, err := readPr(ctx, tx, d.PrId); err != nil {
+			return nil, handleDBError(err)
+		}
+		r.log.Warn("PR status changed concurrently",
+			zap.String("pr_id", d.PrId),
+			zap.String("expected", d.FromStatus),
 		)
+		return nil, ErrPrStatusConflict
+	}
+
+	// Назначаем ревьюеров, если они подобраны для нового статуса
+	assigned, err := insertReviewers(ctx, tx, d.PrId, d.Reviewers)
+	if err != nil {
+		r.log.Error("failed to insert PR reviewers", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if err := recordAssignmentEvents(ctx, tx, assignEvents(d.PrId, assigned, d.Reason)); err != nil {
+		r.log.Error("failed to record assignment events", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if err := recordExplanations(ctx, tx, d.PrId, d.Explanation); err != nil {
+		r.log.Error("failed to record assignment explanation", zap.String("pr_id", d.PrId), zap.Error(err))
 		return nil, handleDBError(err)
 	}
 
-	// Чтение всех участников команды
-	rows, err := r.db.Query(ctx, selectTeamMembersQuery, teamId)
+	prRes, err := readPr(ctx, tx, d.PrId)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	prRes.AssignedReviewers, err = readReviewers(ctx, tx, d.PrId)
+	if err != nil {
+		r.log.Error("failed to read PR reviewers after status change", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit PR status change", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("PR status changed",
+		zap.String("pr_id", prRes.Id),
+		zap.String("status", prRes.Status),
+		zap.Int("assigned_reviewers", len(prRes.AssignedReviewers)),
+	)
+	// Ответ
+	return prRes, nil
+}
+
+// GetHistory возвращает журнал назначений PR в порядке записи
+func (r *PrRepository) GetHistory(ctx context.Context, prId string) ([]*domain.AssignmentEvent, error) {
+	r.log.Debug("get assignment history", zap.String("pr_id", prId))
+
+	// Убедимся, что PR существует
+	if _, err := readPr(ctx, r.db, prId); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to load PR", zap.String("pr_id", prId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	events, err := readAssignmentEvents(ctx, r.db, prId)
+	if err != nil {
+		r.log.Error("failed to read assignment events", zap.String("pr_id", prId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return events, nil
+}
+
+// GetAssignmentExplanations возвращает объяснения подборов ревьюеров PR в порядке записи
+func (r *PrRepository) GetAssignmentExplanations(ctx context.Context, prId string) ([]*domain.AssignmentExplanation, error) {
+	r.log.Debug("get assignment explanations", zap.String("pr_id", prId))
+
+	// Убедимся, что PR существует
+	if _, err := readPr(ctx, r.db, prId); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to load PR", zap.String("pr_id", prId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	explanations, err := readExplanations(ctx, r.db, prId)
 	if err != nil {
-		r.log.Error("failed to load team members",
-			zap.String("team_id", teamId),
+		r.log.Error("failed to read assignment explanations", zap.String("pr_id", prId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return explanations, nil
+}
+
+// AddReview сохраняет вердикт ревьюера, назначенного на открытый PR
+func (r *PrRepository) AddReview(ctx context.Context, d *dto.AddReviewDTO) (*domain.Review, error) {
+	r.log.Info("add review started",
+		zap.String("pr_id", d.PrId),
+		zap.String("reviewer_id", d.ReviewerId),
+		zap.String("verdict", d.Verdict),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокируем PR, чтобы вердикт не разошелся с одновременным слиянием
+	var status string
+	if err := tx.QueryRow(ctx, lockPrQuery, d.PrId).Scan(&status); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to lock PR before review", zap.String("pr_id", d.PrId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	prRes, err := readPr(ctx, tx, d.PrId)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to load PR before review", zap.String("pr_id", d.PrId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	// Вердикты принимаются только по открытым PR
+	if prRes.Status == domain.PrStatusMerged {
+		return nil, ErrPrMergedStatus
+	}
+	if prRes.Status == domain.PrStatusClosed {
+		return nil, ErrPrClosedStatus
+	}
+
+	// Вердикт может оставить только назначенный ревьюер
+	var exists int
+	err = tx.QueryRow(ctx, checkReviewerAssignedQuery, d.PrId, d.ReviewerId).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReviewerNotAssigned
+		}
+		r.log.Error("failed to check reviewer assignment",
+			zap.String("pr_id", d.PrId),
+			zap.String("reviewer_id", d.ReviewerId),
 			zap.Error(err),
 		)
 		return nil, handleDBError(err)
 	}
-	defer rows.Close()
 
-	var users []*domain.User
-	for rows.Next() {
-		member := &domain.User{}
-		err = rows.Scan(
-			&member.Id,
-			&member.Name,
-			&member.TeamName,
-			&member.IsActive,
-			&member.CreatedAt,
-		)
-		if err != nil {
-			return nil, handleDBError(err)
-		}
-		users = append(users, member)
+	review := &domain.Review{}
+	err = tx.QueryRow(ctx, insertReviewQuery, d.PrId, d.ReviewerId, d.Verdict, d.Comment).Scan(
+		&review.Id,
+		&review.PrId,
+		&review.ReviewerId,
+		&review.Verdict,
+		&review.Comment,
+		&review.CreatedAt,
+	)
+	if err != nil {
+		r.log.Error("failed to insert review", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit review", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
 	}
 
-	r.log.Debug("potential reviewers loaded",
-		zap.String("team_id", teamId),
-		zap.Int("members", len(users)),
+	r.log.Info("review added",
+		zap.String("pr_id", review.PrId),
+		zap.String("reviewer_id", review.ReviewerId),
+		zap.Int64("review_id", review.Id),
 	)
 	// Ответ
-	return users, nil
+	return review, nil
+}
+
+// GetReviewState читает PR, его ревьюеров, историю вердиктов и требуемое число одобрений
+func (r *PrRepository) GetReviewState(ctx context.Context, prId string) (*result.ReviewStateResult, error) {
+	r.log.Debug("get review state", zap.String("pr_id", prId))
+
+	prRes, err := readPr(ctx, r.db, prId)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to load PR", zap.String("pr_id", prId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	prRes.AssignedReviewers, err = readReviewers(ctx, r.db, prId)
+	if err != nil {
+		r.log.Error("failed to read PR reviewers", zap.String("pr_id", prId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	var requiredApprovals int
+	if err := r.db.QueryRow(ctx, selectRequiredApprovalsQuery, prId).Scan(&requiredApprovals); err != nil {
+		r.log.Error("failed to read required approvals", zap.String("pr_id", prId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	reviews, err := readReviews(ctx, r.db, prId)
+	if err != nil {
+		r.log.Error("failed to read PR reviews", zap.String("pr_id", prId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return &result.ReviewStateResult{
+		Pr:                prRes,
+		RequiredApprovals: requiredApprovals,
+		Reviews:           reviews,
+	}, nil
 }
 
 func (r *PrRepository) CheckReviewerAssigned(ctx context.Context, prId, reviewerId string) (bool, error) {
@@ -403,50 +1006,357 @@ func (r *PrRepository) CheckReviewerAssigned(ctx context.Context, prId, reviewer
 	return true, nil
 }
 
-func (r *PrRepository) CheckReviewerAssignedWithPR(ctx context.Context, prId, reviewerId string) (bool, string, error) {
+func (r *PrRepository) CheckReviewerAssignedWithPR(ctx context.Context, prId, reviewerId string) (bool, *result.PrResult, error) {
 	r.log.Debug("check reviewer assigned with PR",
 		zap.String("pr_id", prId),
 		zap.String("reviewer_id", reviewerId),
 	)
 
-	// Проверяем что PR существует и получаем authorId
+	// Проверяем что PR существует и получаем его вместе с ревьюерами
 	prRes, err := readPr(ctx, r.db, prId)
 	if err != nil {
 		r.log.Error("failed to load PR for reviewer check",
 			zap.String("pr_id", prId),
 			zap.Error(err),
 		)
-		return false, "", handleDBError(err)
+		return false, nil, handleDBError(err)
 	}
 
-	// Не даем переназначать ревьюеров после MERGED
-	if prRes.Status == "MERGED" {
-		return false, "", ErrPrMergedStatus
+	// Не даем переназначать ревьюеров после MERGED и на закрытых PR
+	if prRes.Status == domain.PrStatusMerged {
+		return false, nil, ErrPrMergedStatus
+	}
+	if prRes.Status == domain.PrStatusClosed {
+		return false, nil, ErrPrClosedStatus
 	}
 
-	// Проверяем что ревьюер назначен на PR
-	var exists int
-	err = r.db.QueryRow(ctx, checkReviewerAssignedQuery, prId, reviewerId).Scan(&exists)
+	prRes.AssignedReviewers, err = readReviewers(ctx, r.db, prId)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return false, prRes.AuthorId, nil
-		}
-		r.log.Error("failed to check reviewer assignment",
+		r.log.Error("failed to read PR reviewers for reviewer check",
 			zap.String("pr_id", prId),
-			zap.String("reviewer_id", reviewerId),
 			zap.Error(err),
 		)
-		return false, "", handleDBError(err)
+		return false, nil, handleDBError(err)
 	}
 
-	return true, prRes.AuthorId, nil
+	// Проверяем что ревьюер назначен на PR
+	return slices.Contains(prRes.AssignedReviewers, reviewerId), prRes, nil
 }
 
 type queryExecutor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+// вспомогательная функция для чтения команды пользователя, ее настроек и участников с их нагрузкой.
+// Пустой memberOf означает основную команду пользователя
+func readPotentialReviewers(ctx context.Context, exec queryExecutor, userId, memberOf string) (*result.PotentialReviewersResult, error) {
+	// Чтение команды пользователя и ее настроек
+	var teamId, teamName, parentId string
+	settings := &teamSettingsRow{}
+	row := exec.QueryRow(ctx, selectTeamQuery, userId)
+	if memberOf != "" {
+		row = exec.QueryRow(ctx, selectMemberTeamQuery, userId, memberOf)
+	}
+	err := row.Scan(append([]any{&teamId, &teamName, &parentId}, settings.dest()...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Чтение всех участников команды
+	candidates, err := readTeamCandidates(ctx, exec, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Чтение правил CODEOWNERS команды
+	codeOwners, err := readCodeOwners(ctx, exec, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.PotentialReviewersResult{
+		TeamId:     teamId,
+		TeamName:   teamName,
+		ParentId:   parentId,
+		Settings:   settings.toDomain(teamId),
+		Candidates: candidates,
+		CodeOwners: codeOwners,
+	}, nil
+}
+
+// вспомогательная функция для чтения правил CODEOWNERS команды в порядке файла
+func readCodeOwners(ctx context.Context, exec queryExecutor, teamId string) ([]*domain.CodeOwnerRule, error) {
+	rows, err := exec.Query(ctx, selectCodeOwnersQuery, teamId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.CodeOwnerRule
+	for rows.Next() {
+		rule := &domain.CodeOwnerRule{}
+		if err := rows.Scan(&rule.Pattern, &rule.Owners); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// вспомогательная функция для чтения резервных команд и их участников
+func readFallbackPools(ctx context.Context, exec queryExecutor, teamId string) ([]*result.PotentialReviewersResult, error) {
+	return readPools(ctx, exec, selectFallbackTeamsQuery, teamId)
+}
+
+// вспомогательная функция для чтения команд с настройками и их участников в порядке запроса
+func readPools(ctx context.Context, exec queryExecutor, query, teamId string) ([]*result.PotentialReviewersResult, error) {
+	rows, err := exec.Query(ctx, query, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []*result.PotentialReviewersResult
+	for rows.Next() {
+		pool := &result.PotentialReviewersResult{}
+		settings := &teamSettingsRow{}
+		if err := rows.Scan(append([]any{&pool.TeamId, &pool.TeamName}, settings.dest()...)...); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		pool.Settings = settings.toDomain(pool.TeamId)
+		pools = append(pools, pool)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Участников читаем после закрытия курсора, чтобы не держать два запроса на одном соединении
+	for _, pool := range pools {
+		pool.Candidates, err = readTeamCandidates(ctx, exec, pool.TeamId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pools, nil
+}
+
+// вспомогательная функция для чтения участников команды вместе с количеством их открытых ревью
+func readTeamCandidates(ctx context.Context, exec queryExecutor, teamId string) ([]*result.ReviewerCandidate, error) {
+	rows, err := exec.Query(ctx, selectTeamMembersQuery, teamId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*result.ReviewerCandidate
+	for rows.Next() {
+		member := &domain.User{}
+		candidate := &result.ReviewerCandidate{User: member}
+		err = rows.Scan(
+			&member.Id,
+			&member.Name,
+			&member.TeamName,
+			&member.IsActive,
+			&member.CreatedAt,
+			&member.MaxOpenReviews,
+			&candidate.OpenReviews,
+			&candidate.Unavailable,
+			&member.Tags,
+			&candidate.IsLead,
+		)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
+}
+
+// вспомогательная функция для чтения открытых PR, на которые назначены переданные ревьюеры
+func readOpenReviews(ctx context.Context, exec queryExecutor, userIds []string) ([]*result.OpenReviewResult, error) {
+	rows, err := exec.Query(ctx, selectOpenReviewsQuery, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []*result.OpenReviewResult
+	for rows.Next() {
+		review := &result.OpenReviewResult{}
+		err = rows.Scan(
+			&review.PrId,
+			&review.AuthorId,
+			&review.ReviewerId,
+			&review.Reviewers,
+			&review.TeamName,
+			&review.RequiresLeadReview,
+			&review.LeadReviewerId,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// вспомогательная функция для переназначения набора ревью одним запросом.
+// Возвращает только примененные переназначения: уже снятые ревью и не OPEN PR пропускаются
+func applyReassignments(ctx context.Context, exec queryExecutor, reassignments []*dto.ReassignPrDTO) ([]*dto.ReassignPrDTO, error) {
+	if len(reassignments) == 0 {
+		return nil, nil
+	}
+
+	prIds := make([]string, 0, len(reassignments))
+	oldIds := make([]string, 0, len(reassignments))
+	newIds := make([]string, 0, len(reassignments))
+	for _, reassignment := range reassignments {
+		prIds = append(prIds, reassignment.PrId)
+		oldIds = append(oldIds, reassignment.OldReviewerId)
+		newIds = append(newIds, reassignment.ReplacedBy)
+	}
+
+	rows, err := exec.Query(ctx, applyReassignmentsQuery, prIds, oldIds, newIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type key struct{ prId, userId string }
+	removed := make(map[key]struct{}, len(reassignments))
+	for rows.Next() {
+		var k key
+		if err := rows.Scan(&k.prId, &k.userId); err != nil {
+			return nil, err
+		}
+		removed[k] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	applied := make([]*dto.ReassignPrDTO, 0, len(removed))
+	events := make([]*domain.AssignmentEvent, 0, len(removed))
+	for _, reassignment := range reassignments {
+		if _, ok := removed[key{reassignment.PrId, reassignment.OldReviewerId}]; ok {
+			applied = append(applied, reassignment)
+			events = append(events, reassignEvent(reassignment, domain.AssignReasonUserDeactivated))
+		}
+	}
+
+	// Фиксируем примененные замены в журнале назначений вместе с объяснениями подбора
+	if err := recordAssignmentEvents(ctx, exec, events); err != nil {
+		return nil, err
+	}
+	for _, reassignment := range applied {
+		if err := recordExplanations(ctx, exec, reassignment.PrId, reassignment.Explanation); err != nil {
+			return nil, err
+		}
+	}
+	return applied, nil
+}
+
+// assignEvents события назначения ревьюеров на PR
+func assignEvents(prId string, reviewers []string, reason string) []*domain.AssignmentEvent {
+	events := make([]*domain.AssignmentEvent, 0, len(reviewers))
+	for _, reviewerId := range reviewers {
+		events = append(events, &domain.AssignmentEvent{
+			PrId:   prId,
+			Type:   domain.AssignmentEventAssign,
+			UserId: reviewerId,
+			Reason: reason,
+		})
+	}
+	return events
+}
+
+// reassignEvent событие замены ревьюера, без замены - снятие
+func reassignEvent(d *dto.ReassignPrDTO, defaultReason string) *domain.AssignmentEvent {
+	reason := d.Reason
+	if reason == "" {
+		reason = defaultReason
+	}
+	if d.ReplacedBy == "" {
+		return &domain.AssignmentEvent{
+			PrId:   d.PrId,
+			Type:   domain.AssignmentEventUnassign,
+			UserId: d.OldReviewerId,
+			Reason: reason,
+		}
+	}
+	return &domain.AssignmentEvent{
+		PrId:           d.PrId,
+		Type:           domain.AssignmentEventReassign,
+		UserId:         d.ReplacedBy,
+		PreviousUserId: d.OldReviewerId,
+		Reason:         reason,
+	}
+}
+
+// вспомогательная функция для записи событий в журнал назначений одним запросом.
+// Автор изменения и идентификатор запроса берутся из контекста. Изменения назначений
+// публикуются в outbox для вебхуков в той же транзакции
+func recordAssignmentEvents(ctx context.Context, exec queryExecutor, events []*domain.AssignmentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	prIds := make([]string, 0, len(events))
+	types := make([]string, 0, len(events))
+	userIds := make([]string, 0, len(events))
+	previousIds := make([]string, 0, len(events))
+	reasons := make([]string, 0, len(events))
+	for _, event := range events {
+		prIds = append(prIds, event.PrId)
+		types = append(types, event.Type)
+		userIds = append(userIds, event.UserId)
+		previousIds = append(previousIds, event.PreviousUserId)
+		reasons = append(reasons, event.Reason)
+	}
+
+	audit := domain.AuditInfoFrom(ctx)
+	if _, err := exec.Exec(ctx, insertAssignmentEventsQuery, prIds, types, userIds, previousIds, reasons, audit.Actor, audit.RequestId); err != nil {
+		return err
+	}
+
+	outbox, err := reviewerOutboxEvents(events, audit.Actor)
+	if err != nil {
+		return err
+	}
+	return enqueueOutboxEvents(ctx, exec, outbox)
+}
+
+func readAssignmentEvents(ctx context.Context, exec queryExecutor, prId string) ([]*domain.AssignmentEvent, error) {
+	rows, err := exec.Query(ctx, selectAssignmentEventsQuery, prId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domain.AssignmentEvent, 0)
+	for rows.Next() {
+		event := &domain.AssignmentEvent{}
+		if err := rows.Scan(
+			&event.Id,
+			&event.PrId,
+			&event.Type,
+			&event.UserId,
+			&event.PreviousUserId,
+			&event.Actor,
+			&event.Reason,
+			&event.RequestId,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
 // вспомогательная функция для чтения всех ревьюеров для pr
 func readReviewers(ctx context.Context, exec queryExecutor, prId string) ([]string, error) {
 	rows, err := exec.Query(ctx, selectPrReviewerQuery, prId)
@@ -466,10 +1376,49 @@ func readReviewers(ctx context.Context, exec queryExecutor, prId string) ([]stri
 	return prReviewers, nil
 }
 
-// вспомогательная функция для чтения данных для pr
+// вспомогательная функция для назначения ревьюеров на PR, пустые идентификаторы пропускаются
+func insertReviewers(ctx context.Context, tx pgx.Tx, prId string, reviewers []string) ([]string, error) {
+	assigned := make([]string, 0, len(reviewers))
+	for _, reviewerId := range reviewers {
+		if reviewerId == "" {
+			continue
+		}
+		if _, err := tx.Exec(ctx, insertPrReviewerQuery, reviewerId, prId); err != nil {
+			return nil, err
+		}
+		assigned = append(assigned, reviewerId)
+	}
+	return assigned, nil
+}
+
+func readReviews(ctx context.Context, exec queryExecutor, prId string) ([]*domain.Review, error) {
+	rows, err := exec.Query(ctx, selectReviewsQuery, prId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := make([]*domain.Review, 0)
+	for rows.Next() {
+		review := &domain.Review{}
+		if err := rows.Scan(
+			&review.Id,
+			&review.PrId,
+			&review.ReviewerId,
+			&review.Verdict,
+			&review.Comment,
+			&review.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
 func readPr(ctx context.Context, exec queryExecutor, prId string) (*result.PrResult, error) {
 	prRes := &result.PrResult{}
-	var mergedAt sql.NullTime
+	var mergedAt, closedAt sql.NullTime
 	err := exec.QueryRow(ctx, selectPrQuery, prId).Scan(
 		&prRes.Id,
 		&prRes.Name,
@@ -477,6 +1426,12 @@ func readPr(ctx context.Context, exec queryExecutor, prId string) (*result.PrRes
 		&prRes.Status,
 		&prRes.CreatedAt,
 		&mergedAt,
+		&closedAt,
+		&prRes.ChangedPaths,
+		&prRes.RequiredTags,
+		&prRes.TeamName,
+		&prRes.RequiresLeadReview,
+		&prRes.LeadReviewerId,
 	)
 	if err != nil {
 		return nil, err
@@ -484,6 +1439,9 @@ func readPr(ctx context.Context, exec queryExecutor, prId string) (*result.PrRes
 	if mergedAt.Valid {
 		prRes.MergedAt = &mergedAt.Time
 	}
+	if closedAt.Valid {
+		prRes.ClosedAt = &closedAt.Time
+	}
 
 	return prRes, nil
 }
@@ -494,6 +1452,8 @@ func (r *PrRepository) GetStats(ctx context.Context) (*result.StatsResult, error
 	stats := &result.StatsResult{
 		Users: make([]result.UserStats, 0),
 		PRs:   make([]result.PrStats, 0),
+		Pairs: make([]result.PairStats, 0),
+		Teams: make([]*result.TeamNodeResult, 0),
 	}
 
 	// Получаем статистику по пользователям
@@ -506,7 +1466,13 @@ func (r *PrRepository) GetStats(ctx context.Context) (*result.StatsResult, error
 
 	for userRows.Next() {
 		var userStat result.UserStats
-		if err := userRows.Scan(&userStat.UserId, &userStat.Username, &userStat.Assignments); err != nil {
+		if err := userRows.Scan(
+			&userStat.UserId,
+			&userStat.Username,
+			&userStat.Assignments,
+			&userStat.OpenReviews,
+			&userStat.MaxOpenReviews,
+		); err != nil {
 			r.log.Error("failed to scan user statistics", zap.Error(err))
 			return nil, handleDBError(err)
 		}
@@ -540,9 +1506,41 @@ func (r *PrRepository) GetStats(ctx context.Context) (*result.StatsResult, error
 		return nil, handleDBError(err)
 	}
 
+	// Получаем матрицу пар автор -> ревьюер
+	pairRows, err := r.db.Query(ctx, selectPairStatsQuery)
+	if err != nil {
+		r.log.Error("failed to get pair statistics", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer pairRows.Close()
+
+	for pairRows.Next() {
+		var pairStat result.PairStats
+		if err := pairRows.Scan(&pairStat.AuthorId, &pairStat.ReviewerId, &pairStat.Reviews); err != nil {
+			r.log.Error("failed to scan pair statistics", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		stats.Pairs = append(stats.Pairs, pairStat)
+	}
+
+	if err := pairRows.Err(); err != nil {
+		r.log.Error("error iterating pair statistics", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Получаем команды с участниками для сводки по поддеревьям
+	teams, err := readTeamNodes(ctx, r.db, selectTeamNodesQuery)
+	if err != nil {
+		r.log.Error("failed to get team hierarchy", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	stats.Teams = append(stats.Teams, teams...)
+
 	r.log.Info("statistics retrieved",
 		zap.Int("users_count", len(stats.Users)),
 		zap.Int("prs_count", len(stats.PRs)),
+		zap.Int("pairs_count", len(stats.Pairs)),
+		zap.Int("teams_count", len(stats.Teams)),
 	)
 
 	return stats, nil
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"go.uber.org/zap"
+)
+
+// reviewerPools чтение пулов кандидатов для подбора ревьюеров и их замены. Встраивается в репозитории,
+// сервисы которых подбирают ревьюеров: PR, пользователей и команд
+type reviewerPools struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+// вспомогательная функция для поиска возможных ревьюеров, вызывается в сервисном слое для выбора ревьеров для pr.
+// Вместе с каждым участником команды возвращается количество его открытых ревью
+func (r *reviewerPools) SelectPotentialReviewers(ctx context.Context, userId string) (*result.PotentialReviewersResult, error) {
+	r.log.Debug("select potential reviewers", zap.String("user_id", userId))
+
+	pool, err := readPotentialReviewers(ctx, r.db, userId, "")
+	if err != nil {
+		r.log.Error("failed to load potential reviewers",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	r.log.Debug("potential reviewers loaded",
+		zap.String("team_id", pool.TeamId),
+		zap.Int("members", len(pool.Candidates)),
+	)
+	// Ответ
+	return pool, nil
+}
+
+// SelectMemberPool как SelectPotentialReviewers, но читает указанную команду пользователя, а не основную.
+// Если пользователь в команде не состоит, возвращает ErrNotTeamMember
+func (r *reviewerPools) SelectMemberPool(ctx context.Context, userId, teamName string) (*result.PotentialReviewersResult, error) {
+	r.log.Debug("select member pool",
+		zap.String("user_id", userId),
+		zap.String("team_name", teamName),
+	)
+
+	pool, err := readPotentialReviewers(ctx, r.db, userId, teamName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("user is not a team member",
+				zap.String("user_id", userId),
+				zap.String("team_name", teamName),
+			)
+			return nil, ErrNotTeamMember
+		}
+		r.log.Error("failed to load member pool",
+			zap.String("user_id", userId),
+			zap.String("team_name", teamName),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return pool, nil
+}
+
+// SelectRecentPairings возвращает, сколько из последних window PR автора ревьюил каждый пользователь.
+// Пользователи, не ревьюившие эти PR, в ответ не попадают
+func (r *reviewerPools) SelectRecentPairings(ctx context.Context, authorId string, window int) (map[string]int, error) {
+	r.log.Debug("select recent pairings",
+		zap.String("author_id", authorId),
+		zap.Int("window", window),
+	)
+
+	rows, err := r.db.Query(ctx, selectRecentPairingsQuery, authorId, window)
+	if err != nil {
+		r.log.Error("failed to load recent pairings",
+			zap.String("author_id", authorId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	pairings := make(map[string]int)
+	for rows.Next() {
+		var (
+			reviewerId string
+			reviews    int
+		)
+		if err := rows.Scan(&reviewerId, &reviews); err != nil {
+			r.log.Error("failed to scan recent pairing", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		pairings[reviewerId] = reviews
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("error iterating recent pairings", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return pairings, nil
+}
+
+// SelectFallbackPools возвращает кандидатов резервных команд в порядке цепочки
+func (r *reviewerPools) SelectFallbackPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	r.log.Debug("select fallback pools", zap.String("team_id", teamId))
+
+	pools, err := readFallbackPools(ctx, r.db, teamId)
+	if err != nil {
+		r.log.Error("failed to load fallback pools",
+			zap.String("team_id", teamId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	r.log.Debug("fallback pools loaded",
+		zap.String("team_id", teamId),
+		zap.Int("teams", len(pools)),
+	)
+	// Ответ
+	return pools, nil
+}
+
+// SelectParentPools возвращает кандидатов родительских команд от ближайшей к верхней
+func (r *reviewerPools) SelectParentPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	r.log.Debug("select parent pools", zap.String("team_id", teamId))
+
+	pools, err := readPools(ctx, r.db, selectParentTeamsQuery, teamId)
+	if err != nil {
+		r.log.Error("failed to load parent pools",
+			zap.String("team_id", teamId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	r.log.Debug("parent pools loaded",
+		zap.String("team_id", teamId),
+		zap.Int("teams", len(pools)),
+	)
+	// Ответ
+	return pools, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"go.uber.org/zap"
+)
+
+const (
+	// Ревью открытых PR, по которым ревьюер не оставил вердикт после назначения, со сроком ревью
+	// команды PR. Для PR без команды берется основная команда автора
+	selectSlaReviewsQuery = `
+SELECT
+    p.id,
+    p.name,
+    p.author_id,
+    prr.user_id,
+    t.name,
+    prr.assigned_at,
+    prr.escalated_at,
+    ts.review_sla_hours,
+    ts.sla_policy,
+    ARRAY(SELECT r.user_id FROM pr_reviewers r WHERE r.pr_id = p.id) AS reviewers
+FROM pr_reviewers prr
+JOIN prs p ON p.id = prr.pr_id
+JOIN teams t ON t.id = COALESCE(p.team_id, (
+    SELECT tm.team_id FROM team_members tm
+    WHERE tm.user_id = p.author_id AND tm.is_primary
+))
+JOIN team_settings ts ON ts.team_id = t.id
+WHERE p.status = 'OPEN'
+  AND ts.review_sla_hours > 0
+  AND ($1::text = '' OR t.name = $1)
+  AND NOT EXISTS (
+      SELECT 1 FROM pr_reviews rv
+      WHERE rv.pr_id = p.id
+        AND rv.reviewer_id = prr.user_id
+        AND rv.created_at >= prr.assigned_at
+  )
+ORDER BY p.id, prr.user_id;`
+
+	// Ревью отмечается эскалированным один раз и только пока PR открыт
+	markReviewEscalatedQuery = `
+UPDATE pr_reviewers prr
+SET escalated_at = CURRENT_TIMESTAMP
+FROM prs p
+WHERE prr.pr_id = $1
+  AND prr.user_id = $2
+  AND prr.escalated_at IS NULL
+  AND p.id = prr.pr_id
+  AND p.status = 'OPEN';`
+)
+
+// SelectSlaReviews возвращает ревью открытых PR без вердикта для команд со сроком ревью.
+// Непустой teamName оставляет только PR этой команды
+func (r *PrRepository) SelectSlaReviews(ctx context.Context, teamName string) ([]*result.SlaReviewResult, error) {
+	r.log.Debug("select SLA reviews", zap.String("team_name", teamName))
+
+	rows, err := r.db.Query(ctx, selectSlaReviewsQuery, teamName)
+	if err != nil {
+		r.log.Error("failed to select SLA reviews", zap.String("team_name", teamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	var reviews []*result.SlaReviewResult
+	for rows.Next() {
+		review := &result.SlaReviewResult{}
+		err = rows.Scan(
+			&review.PrId,
+			&review.PrName,
+			&review.AuthorId,
+			&review.ReviewerId,
+			&review.TeamName,
+			&review.AssignedAt,
+			&review.EscalatedAt,
+			&review.SlaHours,
+			&review.SlaPolicy,
+			&review.Reviewers,
+		)
+		if err != nil {
+			r.log.Error("failed to scan SLA review", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return reviews, nil
+}
+
+// EscalateReview отмечает просроченное ревью эскалированным, записывает событие review.overdue в outbox
+// и назначает дополнительного ревьюера, если он передан. ErrReviewerNotAssigned означает, что ревью
+// уже эскалировано, ревьюер снят или PR перестал быть открытым
+func (r *PrRepository) EscalateReview(ctx context.Context, d *dto.EscalateReviewDTO) (*result.PrResult, error) {
+	r.log.Info("escalate review started",
+		zap.String("pr_id", d.PrId),
+		zap.String("reviewer_id", d.ReviewerId),
+		zap.String("added_reviewer", d.AddedReviewer),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmdTag, err := tx.Exec(ctx, markReviewEscalatedQuery, d.PrId, d.ReviewerId)
+	if err != nil {
+		r.log.Error("failed to mark review escalated", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Info("review is no longer escalatable",
+			zap.String("pr_id", d.PrId),
+			zap.String("reviewer_id", d.ReviewerId),
+		)
+		return nil, ErrReviewerNotAssigned
+	}
+
+	// Назначаем дополнительного ревьюера
+	if d.AddedReviewer != "" {
+		if _, err := tx.Exec(ctx, insertPrReviewerQuery, d.AddedReviewer, d.PrId); err != nil {
+			r.log.Error("failed to add reviewer", zap.String("pr_id", d.PrId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		events := assignEvents(d.PrId, []string{d.AddedReviewer}, domain.AssignReasonReviewOverdue)
+		if err := recordAssignmentEvents(ctx, tx, events); err != nil {
+			r.log.Error("failed to record assignment events", zap.String("pr_id", d.PrId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		if err := recordExplanations(ctx, tx, d.PrId, d.Explanation); err != nil {
+			r.log.Error("failed to record assignment explanation", zap.String("pr_id", d.PrId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
+	overdue, err := newOutboxEvent(domain.EventReviewOverdue, d.Overdue)
+	if err != nil {
+		return nil, err
+	}
+	if err := enqueueOutboxEvents(ctx, tx, []*domain.OutboxEvent{overdue}); err != nil {
+		r.log.Error("failed to enqueue overdue event", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	prRes, err := readPr(ctx, tx, d.PrId)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	prRes.AssignedReviewers, err = readReviewers(ctx, tx, d.PrId)
+	if err != nil {
+		r.log.Error("failed to read PR reviewers after escalation", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit review escalation", zap.String("pr_id", d.PrId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("review escalated",
+		zap.String("pr_id", d.PrId),
+		zap.String("reviewer_id", d.ReviewerId),
+		zap.Strings("assigned_reviewers", prRes.AssignedReviewers),
+	)
+	// Ответ
+	return prRes, nil
+}
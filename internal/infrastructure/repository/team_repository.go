@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,23 +23,31 @@ const (
 SELECT id FROM teams
 WHERE name = $1;`
 
+	// Существующий пользователь не меняется: имя и активность правятся только через /users
 	insertUserQuery = `
 INSERT INTO users (id, name, team_name, is_active)
 VALUES ($1, $2, $3, $4)
-ON CONFLICT (id) DO UPDATE
-	SET name = EXCLUDED.name,
-	    team_name = EXCLUDED.team_name,
-	    is_active = EXCLUDED.is_active
-RETURNING id, name, team_name, is_active, created_at;`
+ON CONFLICT (id) DO NOTHING;`
+
+	// Пользователи из списка, уже состоящие в какой-либо команде
+	selectMembersOfTeamsQuery = `
+SELECT DISTINCT user_id
+FROM team_members
+WHERE user_id = ANY($1)
+ORDER BY user_id;`
 
 	insertTeamQuery = `
 INSERT INTO teams (id, name) 
 VALUES ($1, $2) 
 RETURNING id, name, created_at;`
 
+	// Первая команда пользователя становится основной
 	insertTeamMemberQuery = `
-INSERT INTO team_members (team_id, user_id)
-VALUES ($1, $2)
+INSERT INTO team_members (team_id, user_id, is_primary)
+VALUES ($1, $2, NOT EXISTS (
+    SELECT 1 FROM team_members
+    WHERE user_id = $2 AND is_primary
+))
 ON CONFLICT (team_id, user_id) DO UPDATE
 	SET joined_at = CURRENT_TIMESTAMP;`
 
@@ -46,23 +58,250 @@ SELECT
     u.name        AS username,
     u.team_name   AS user_team_name,
     u.is_active   AS user_is_active,
-    u.created_at  AS user_created_at
+    u.created_at  AS user_created_at,
+    tm.role       AS member_role,
+    ARRAY(
+        SELECT ut.name
+        FROM team_members utm
+        JOIN teams ut ON ut.id = utm.team_id
+        WHERE utm.user_id = u.id
+        ORDER BY utm.is_primary DESC, ut.name ASC
+    )             AS user_teams
 FROM teams t
 LEFT JOIN team_members tm ON tm.team_id = t.id
 LEFT JOIN users u ON u.id = tm.user_id
 WHERE t.name = $1
 ORDER BY u.created_at ASC;`
+
+	selectTeamSettingsQuery = `
+SELECT
+    t.id,
+    COALESCE(t.parent_id, ''),
+    ts.reviewer_count,
+    ts.strategy,
+    ts.lead_user_id,
+    ts.require_lead,
+    ts.required_approvals,
+    ts.require_tags,
+    ts.review_sla_hours,
+    ts.sla_policy,
+    ts.updated_at
+FROM teams t
+LEFT JOIN team_settings ts ON ts.team_id = t.id
+WHERE t.name = $1;`
+
+	upsertTeamSettingsQuery = `
+INSERT INTO team_settings (team_id, reviewer_count, strategy, lead_user_id, require_lead, required_approvals, require_tags, review_sla_hours, sla_policy)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (team_id) DO UPDATE
+	SET reviewer_count = EXCLUDED.reviewer_count,
+	    strategy = EXCLUDED.strategy,
+	    lead_user_id = EXCLUDED.lead_user_id,
+	    require_lead = EXCLUDED.require_lead,
+	    required_approvals = EXCLUDED.required_approvals,
+	    require_tags = EXCLUDED.require_tags,
+	    review_sla_hours = EXCLUDED.review_sla_hours,
+	    sla_policy = EXCLUDED.sla_policy,
+	    updated_at = CURRENT_TIMESTAMP
+RETURNING reviewer_count, strategy, lead_user_id, require_lead, required_approvals, require_tags, review_sla_hours, sla_policy, updated_at;`
+
+	checkTeamMemberQuery = `
+SELECT 1 FROM team_members
+WHERE team_id = $1 AND user_id = $2;`
+
+	selectFallbackTeamNamesQuery = `
+SELECT t.name
+FROM team_fallbacks tf
+JOIN teams t ON t.id = tf.fallback_team_id
+WHERE tf.team_id = $1
+ORDER BY tf.position ASC;`
+
+	deleteTeamFallbacksQuery = `
+DELETE FROM team_fallbacks
+WHERE team_id = $1;`
+
+	insertTeamFallbacksQuery = `
+INSERT INTO team_fallbacks (team_id, fallback_team_id, position)
+SELECT $1, t.id, f.position
+FROM unnest($2::text[]) WITH ORDINALITY AS f(name, position)
+JOIN teams t ON t.name = f.name;`
+
+	deleteCodeOwnersQuery = `
+DELETE FROM team_code_owners
+WHERE team_id = $1;`
+
+	insertCodeOwnerQuery = `
+INSERT INTO team_code_owners (team_id, position, pattern, owner_ids)
+VALUES ($1, $2, $3, $4);`
+
+	// Возвращает владельцев из переданного списка, которые не состоят в команде
+	selectNonMembersQuery = `
+SELECT u.id
+FROM unnest($2::text[]) AS u(id)
+WHERE NOT EXISTS (
+    SELECT 1 FROM team_members tm
+    WHERE tm.team_id = $1 AND tm.user_id = u.id
+);`
+
+	removeTeamMemberQuery = `
+DELETE FROM team_members
+WHERE team_id = $1 AND user_id = $2
+RETURNING user_id;`
+
+	setTeamMemberRoleQuery = `
+UPDATE team_members
+SET role = $3
+WHERE team_id = $1 AND user_id = $2;`
+
+	// Лид из настроек команды всегда имеет роль LEAD, но проверяются оба признака
+	checkTeamLeadQuery = `
+SELECT 1
+FROM team_members tm
+LEFT JOIN team_settings ts ON ts.team_id = tm.team_id
+WHERE tm.team_id = $1 AND tm.user_id = $2
+  AND (tm.role = 'LEAD' OR ts.lead_user_id = tm.user_id);`
+
+	checkSettingsLeadQuery = `
+SELECT 1 FROM team_settings
+WHERE team_id = $1 AND lead_user_id = $2;`
+
+	promoteTeamLeadQuery = `
+UPDATE team_members
+SET role = 'LEAD'
+WHERE team_id = $1 AND user_id = $2;`
+
+	removeCodeOwnerQuery = `
+UPDATE team_code_owners
+SET owner_ids = array_remove(owner_ids, $2)
+WHERE team_id = $1 AND $2 = ANY(owner_ids);`
+
+	// Пользователи, потерявшие основную команду, получают основной ту, в которую вступили последней
+	promotePrimaryTeamQuery = `
+UPDATE team_members tm
+SET is_primary = TRUE
+FROM (
+    SELECT DISTINCT ON (user_id) user_id, team_id
+    FROM team_members
+    WHERE user_id = ANY($1)
+    ORDER BY user_id, joined_at DESC, team_id ASC
+) latest
+WHERE tm.user_id = latest.user_id
+  AND tm.team_id = latest.team_id
+  AND NOT EXISTS (
+      SELECT 1 FROM team_members p
+      WHERE p.user_id = tm.user_id AND p.is_primary
+  );`
+
+	// users.team_name повторяет основную команду или становится NULL, если команд не осталось
+	syncUsersTeamNameQuery = `
+UPDATE users u
+SET team_name = (
+    SELECT t.name
+    FROM team_members tm
+    JOIN teams t ON t.id = tm.team_id
+    WHERE tm.user_id = u.id AND tm.is_primary
+)
+WHERE u.id = ANY($1);`
+
+	renameTeamQuery = `
+UPDATE teams
+SET name = $2
+WHERE name = $1
+RETURNING id;`
+
+	renameUsersTeamQuery = `
+UPDATE users
+SET team_name = $2
+WHERE team_name = $1;`
+
+	selectTeamMemberIdsQuery = `
+SELECT user_id FROM team_members
+WHERE team_id = $1
+ORDER BY user_id ASC;`
+
+	// Участники, настройки, резервные цепочки и CODEOWNERS команды удаляются каскадно
+	deleteTeamQuery = `
+DELETE FROM teams
+WHERE id = $1;`
+
+	// Команда вместе с названием родительской команды
+	selectTeamParentQuery = `
+SELECT t.id, COALESCE(p.name, '')
+FROM teams t
+LEFT JOIN teams p ON p.id = t.parent_id
+WHERE t.name = $1;`
+
+	// Команда $1 и все ее подкоманды с прямыми участниками
+	selectTeamSubtreeQuery = `
+WITH RECURSIVE subtree AS (
+    SELECT id FROM teams WHERE id = $1
+    UNION
+    SELECT t.id
+    FROM teams t
+    JOIN subtree s ON t.parent_id = s.id
+)
+SELECT
+    t.id,
+    t.name,
+    COALESCE(t.parent_id, ''),
+    ARRAY(SELECT tm.user_id FROM team_members tm WHERE tm.team_id = t.id ORDER BY tm.user_id)
+FROM subtree s
+JOIN teams t ON t.id = s.id
+ORDER BY t.name ASC;`
+
+	// Все команды с прямыми участниками
+	selectTeamNodesQuery = `
+SELECT
+    t.id,
+    t.name,
+    COALESCE(t.parent_id, ''),
+    ARRAY(SELECT tm.user_id FROM team_members tm WHERE tm.team_id = t.id ORDER BY tm.user_id)
+FROM teams t
+ORDER BY t.name ASC;`
+
+	// Изменения иерархии команд выполняются по одному, иначе параллельные запросы могут вместе замкнуть цикл
+	lockTeamHierarchyQuery = `
+SELECT pg_advisory_xact_lock(hashtext('team_hierarchy'));`
+
+	// Есть ли команда $1 среди предков будущего родителя $2, включая его самого
+	checkTeamCycleQuery = `
+WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id FROM teams WHERE id = $2
+    UNION
+    SELECT t.id, t.parent_id
+    FROM teams t
+    JOIN ancestors a ON t.id = a.parent_id
+)
+SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $1);`
+
+	setTeamParentQuery = `
+UPDATE teams
+SET parent_id = NULLIF($2, '')
+WHERE id = $1;`
+
+	// Вместе с идентификатором возвращается прежнее значение is_active
+	deactivateTeamMembersQuery = `
+UPDATE users u
+SET is_active = FALSE
+FROM team_members tm, (SELECT id, is_active FROM users WHERE id = ANY($2) FOR UPDATE) prev
+WHERE tm.user_id = u.id
+  AND tm.team_id = $1
+  AND u.id = ANY($2)
+  AND prev.id = u.id
+RETURNING u.id, prev.is_active;`
 )
 
 type TeamRepository struct {
+	reviewerPools
 	db  *pgxpool.Pool
 	log *zap.Logger
 }
 
 func NewTeamRepository(db *pgxpool.Pool, log *zap.Logger) *TeamRepository {
 	return &TeamRepository{
-		db:  db,
-		log: log,
+		reviewerPools: reviewerPools{db: db, log: log},
+		db:            db,
+		log:           log,
 	}
 }
 
@@ -87,6 +326,22 @@ func (r *TeamRepository) Add(ctx context.Context, d *dto.AddTeamDTO) (*result.Ad
 		return nil, handleDBError(err)
 	}
 
+	// Без allow_multi_team команда не забирает пользователей из других команд
+	if !d.AllowMultiTeam {
+		busy, err := selectMembersOfTeams(ctx, tx, d.Members)
+		if err != nil {
+			r.log.Error("failed to check members of other teams", zap.String("team_name", d.TeamName), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		if len(busy) > 0 {
+			r.log.Warn("team members already belong to other teams",
+				zap.String("team_name", d.TeamName),
+				zap.Strings("user_ids", busy),
+			)
+			return nil, fmt.Errorf("%w: %s", ErrMemberOfAnotherTeam, strings.Join(busy, ", "))
+		}
+	}
+
 	var (
 		teamId    string
 		teamName  string
@@ -105,58 +360,270 @@ func (r *TeamRepository) Add(ctx context.Context, d *dto.AddTeamDTO) (*result.Ad
 		return nil, handleDBError(err)
 	}
 
-	// Добавляем пользователей или обновляем данные колонок, если такие уже существуют
-	for _, member := range d.Members {
-		if member == nil {
-			continue
+	// Добавляем новых пользователей, существующие только вступают в команду
+	ignored, err := r.upsertMembers(ctx, tx, teamId, d.TeamName, d.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit add team tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team added",
+		zap.String("team_name", teamName),
+		zap.Int("members", len(d.Members)),
+		zap.Strings("ignored_user_ids", ignored),
+	)
+	// Ответ
+	return &result.AddTeamResult{
+		TeamName:       teamName,
+		Members:        d.Members,
+		IgnoredUserIds: ignored,
+	}, nil
+}
+
+func (r *TeamRepository) Get(ctx context.Context, d *dto.GetTeamDTO) (*result.GetTeamResult, error) {
+	r.log.Info("get team started", zap.String("team_name", d.TeamName))
+
+	// Чтение команды, всех участников и дерева подкоманд
+	team, err := readTeamHierarchy(ctx, r.db, d.TeamName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+			return nil, ErrNotFound
 		}
-		member.TeamName = d.TeamName
+		r.log.Error("failed to read team", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
 
-		err := tx.QueryRow(ctx, insertUserQuery, member.Id, member.Name, member.TeamName, member.IsActive).Scan(
-			&member.Id,
-			&member.Name,
-			&member.TeamName,
-			&member.IsActive,
-			&member.CreatedAt,
-		)
+	r.log.Info("team loaded",
+		zap.String("team_name", d.TeamName),
+		zap.Int("members", len(team.Members)),
+		zap.Int("subtree_teams", len(team.Subtree)),
+	)
+	// Ответ
+	return team, nil
+}
+
+// SetParent меняет родительскую команду. Родителем не может стать сама команда или ее подкоманда
+func (r *TeamRepository) SetParent(ctx context.Context, d *dto.SetTeamParentDTO) (*result.GetTeamResult, error) {
+	r.log.Info("set team parent started",
+		zap.String("team_name", d.TeamName),
+		zap.String("parent_team_name", d.ParentTeamName),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, lockTeamHierarchyQuery); err != nil {
+		r.log.Error("failed to lock team hierarchy", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	var teamId string
+	err = tx.QueryRow(ctx, teamExistsQuery, d.TeamName).Scan(&teamId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+		} else {
+			r.log.Error("failed to check team existence", zap.String("team_name", d.TeamName), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	var parentId string
+	if d.ParentTeamName != "" {
+		err = tx.QueryRow(ctx, teamExistsQuery, d.ParentTeamName).Scan(&parentId)
 		if err != nil {
-			r.log.Error("failed to upsert user for team",
+			if errors.Is(err, pgx.ErrNoRows) {
+				r.log.Warn("parent team not found", zap.String("parent_team_name", d.ParentTeamName))
+			} else {
+				r.log.Error("failed to check parent team existence", zap.String("parent_team_name", d.ParentTeamName), zap.Error(err))
+			}
+			return nil, handleDBError(err)
+		}
+
+		// Команда не должна оказаться среди предков своего родителя
+		var cycle bool
+		if err = tx.QueryRow(ctx, checkTeamCycleQuery, teamId, parentId).Scan(&cycle); err != nil {
+			r.log.Error("failed to check team hierarchy", zap.String("team_name", d.TeamName), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		if cycle {
+			r.log.Warn("team hierarchy cycle",
 				zap.String("team_name", d.TeamName),
-				zap.String("user_id", member.Id),
-				zap.Error(err),
+				zap.String("parent_team_name", d.ParentTeamName),
 			)
+			return nil, ErrTeamCycle
+		}
+	}
+
+	if _, err = tx.Exec(ctx, setTeamParentQuery, teamId, parentId); err != nil {
+		r.log.Error("failed to set team parent", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	team, err := readTeamHierarchy(ctx, tx, d.TeamName)
+	if err != nil {
+		r.log.Error("failed to read team", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit set team parent tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team parent set",
+		zap.String("team_name", d.TeamName),
+		zap.String("parent_team_name", d.ParentTeamName),
+	)
+	// Ответ
+	return team, nil
+}
+
+func (r *TeamRepository) GetSettings(ctx context.Context, d *dto.GetTeamDTO) (*result.TeamSettingsResult, error) {
+	r.log.Info("get team settings started", zap.String("team_name", d.TeamName))
+
+	// Читаем команду вместе с настройками, если они заданы
+	var teamId, parentId string
+	row := &teamSettingsRow{}
+	err := r.db.QueryRow(ctx, selectTeamSettingsQuery, d.TeamName).Scan(append([]any{&teamId, &parentId}, row.dest()...)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+			return nil, ErrNotFound
+		}
+		r.log.Error("failed to read team settings", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Читаем цепочку резервных команд
+	fallbackTeams, err := readFallbackTeamNames(ctx, r.db, teamId)
+	if err != nil {
+		r.log.Error("failed to read fallback teams", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return &result.TeamSettingsResult{
+		TeamId:        teamId,
+		TeamName:      d.TeamName,
+		Settings:      row.toDomain(teamId),
+		FallbackTeams: fallbackTeams,
+	}, nil
+}
+
+func (r *TeamRepository) UpdateSettings(ctx context.Context, d *dto.UpdateTeamSettingsDTO) (*result.TeamSettingsResult, error) {
+	r.log.Info("update team settings started",
+		zap.String("team_name", d.TeamName),
+		zap.Int("reviewer_count", d.ReviewerCount),
+		zap.String("strategy", d.Strategy),
+		zap.Bool("require_lead", d.RequireLead),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Проверяем существование команды
+	var teamId string
+	err = tx.QueryRow(ctx, teamExistsQuery, d.TeamName).Scan(&teamId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+			return nil, ErrNotFound
+		}
+		r.log.Error("failed to check team existence", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Лидом может быть только участник команды
+	if d.LeadUserId != "" {
+		var exists int
+		err = tx.QueryRow(ctx, checkTeamMemberQuery, teamId, d.LeadUserId).Scan(&exists)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				r.log.Warn("lead is not a team member",
+					zap.String("team_name", d.TeamName),
+					zap.String("lead_user_id", d.LeadUserId),
+				)
+				return nil, ErrNotTeamMember
+			}
 			return nil, handleDBError(err)
 		}
+	}
 
-		// Добавляем пользователя в команду
-		if _, err = tx.Exec(ctx, insertTeamMemberQuery, teamId, member.Id); err != nil {
-			r.log.Error("failed to add team member",
+	row := &teamSettingsRow{}
+	err = tx.QueryRow(ctx, upsertTeamSettingsQuery,
+		teamId,
+		d.ReviewerCount,
+		nullString(d.Strategy),
+		nullString(d.LeadUserId),
+		d.RequireLead,
+		d.RequiredApprovals,
+		d.RequireTags,
+		d.ReviewSlaHours,
+		d.SlaPolicy,
+	).Scan(row.dest()...)
+	if err != nil {
+		r.log.Error("failed to upsert team settings", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Лид из настроек получает роль LEAD
+	if d.LeadUserId != "" {
+		if _, err = tx.Exec(ctx, promoteTeamLeadQuery, teamId, d.LeadUserId); err != nil {
+			r.log.Error("failed to promote team lead", zap.String("team_name", d.TeamName), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
+	// Заменяем цепочку резервных команд целиком, сохраняя переданный порядок
+	if _, err = tx.Exec(ctx, deleteTeamFallbacksQuery, teamId); err != nil {
+		r.log.Error("failed to clear fallback teams", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if len(d.FallbackTeams) > 0 {
+		cmdTag, err := tx.Exec(ctx, insertTeamFallbacksQuery, teamId, d.FallbackTeams)
+		if err != nil {
+			r.log.Error("failed to insert fallback teams", zap.String("team_name", d.TeamName), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		if int(cmdTag.RowsAffected()) != len(d.FallbackTeams) {
+			r.log.Warn("some fallback teams not found",
 				zap.String("team_name", d.TeamName),
-				zap.String("user_id", member.Id),
-				zap.Error(err),
+				zap.Strings("fallback_teams", d.FallbackTeams),
 			)
-			return nil, handleDBError(err)
+			return nil, ErrFallbackTeamMissing
 		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		r.log.Error("failed to commit add team tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		r.log.Error("failed to commit team settings tx", zap.String("team_name", d.TeamName), zap.Error(err))
 		return nil, handleDBError(err)
 	}
 
-	r.log.Info("team added",
-		zap.String("team_name", teamName),
-		zap.Int("members", len(d.Members)),
-	)
+	r.log.Info("team settings updated", zap.String("team_name", d.TeamName))
 	// Ответ
-	return &result.AddTeamResult{
-		TeamName: teamName,
-		Members:  d.Members,
+	return &result.TeamSettingsResult{
+		TeamId:        teamId,
+		TeamName:      d.TeamName,
+		Settings:      row.toDomain(teamId),
+		FallbackTeams: d.FallbackTeams,
 	}, nil
 }
 
-func (r *TeamRepository) Get(ctx context.Context, d *dto.GetTeamDTO) (*result.GetTeamResult, error) {
-	r.log.Info("get team started", zap.String("team_name", d.TeamName))
+// GetCodeOwners возвращает правила CODEOWNERS команды, пустой список, если они не загружены
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, d *dto.GetTeamDTO) (*result.CodeOwnersResult, error) {
+	r.log.Info("get code owners started", zap.String("team_name", d.TeamName))
 
 	// Проверяем существование команды
 	var teamId string
@@ -170,42 +637,824 @@ func (r *TeamRepository) Get(ctx context.Context, d *dto.GetTeamDTO) (*result.Ge
 		return nil, handleDBError(err)
 	}
 
-	// Чтение команды и всех участников
-	rows, err := r.db.Query(ctx, getTeamQuery, d.TeamName)
+	rules, err := readCodeOwners(ctx, r.db, teamId)
 	if err != nil {
-		r.log.Error("failed to read team", zap.String("team_name", d.TeamName), zap.Error(err))
+		r.log.Error("failed to read code owners", zap.String("team_name", d.TeamName), zap.Error(err))
 		return nil, handleDBError(err)
 	}
-	defer rows.Close()
 
-	var members []*domain.User
-	for rows.Next() {
-		member := &domain.User{}
-		err := rows.Scan(
-			&d.TeamName,
-			&member.Id,
-			&member.Name,
-			&member.TeamName,
-			&member.IsActive,
-			&member.CreatedAt,
-		)
+	// Ответ
+	return &result.CodeOwnersResult{
+		TeamId:   teamId,
+		TeamName: d.TeamName,
+		Rules:    rules,
+	}, nil
+}
+
+// SetCodeOwners заменяет правила CODEOWNERS команды целиком. Владельцами могут быть только участники команды
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, d *dto.SetCodeOwnersDTO) (*result.CodeOwnersResult, error) {
+	r.log.Info("set code owners started",
+		zap.String("team_name", d.TeamName),
+		zap.Int("rules", len(d.Rules)),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Проверяем существование команды
+	var teamId string
+	err = tx.QueryRow(ctx, teamExistsQuery, d.TeamName).Scan(&teamId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+			return nil, ErrNotFound
+		}
+		r.log.Error("failed to check team existence", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Проверяем, что все владельцы состоят в команде
+	var owners []string
+	for _, rule := range d.Rules {
+		for _, owner := range rule.Owners {
+			if !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	if len(owners) > 0 {
+		nonMembers, err := readStrings(ctx, tx, selectNonMembersQuery, teamId, owners)
 		if err != nil {
-			r.log.Error("failed to scan team member",
+			r.log.Error("failed to check code owners membership", zap.String("team_name", d.TeamName), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		if len(nonMembers) > 0 {
+			r.log.Warn("code owners are not team members",
+				zap.String("team_name", d.TeamName),
+				zap.Strings("user_ids", nonMembers),
+			)
+			return nil, fmt.Errorf("%w: %s", ErrNotTeamMember, strings.Join(nonMembers, ", "))
+		}
+	}
+
+	// Заменяем правила целиком, сохраняя порядок файла
+	if _, err = tx.Exec(ctx, deleteCodeOwnersQuery, teamId); err != nil {
+		r.log.Error("failed to clear code owners", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	for position, rule := range d.Rules {
+		if _, err = tx.Exec(ctx, insertCodeOwnerQuery, teamId, position+1, rule.Pattern, rule.Owners); err != nil {
+			r.log.Error("failed to insert code owner rule",
 				zap.String("team_name", d.TeamName),
+				zap.String("pattern", rule.Pattern),
 				zap.Error(err),
 			)
 			return nil, handleDBError(err)
 		}
-		members = append(members, member)
 	}
 
-	r.log.Info("team loaded",
-		zap.String("team_name", d.TeamName),
-		zap.Int("members", len(members)),
-	)
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit code owners tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("code owners updated", zap.String("team_name", d.TeamName), zap.Int("rules", len(d.Rules)))
 	// Ответ
-	return &result.GetTeamResult{
+	return &result.CodeOwnersResult{
+		TeamId:   teamId,
 		TeamName: d.TeamName,
-		Members:  members,
+		Rules:    d.Rules,
 	}, nil
 }
+
+func (r *TeamRepository) SelectTeamPool(ctx context.Context, d *dto.GetTeamDTO) (*result.PotentialReviewersResult, error) {
+	// Читаем команду вместе с настройками
+	var teamId, parentId string
+	row := &teamSettingsRow{}
+	err := r.db.QueryRow(ctx, selectTeamSettingsQuery, d.TeamName).Scan(append([]any{&teamId, &parentId}, row.dest()...)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+			return nil, ErrNotFound
+		}
+		r.log.Error("failed to read team", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Читаем участников команды с их нагрузкой
+	candidates, err := readTeamCandidates(ctx, r.db, teamId)
+	if err != nil {
+		r.log.Error("failed to read team members", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return &result.PotentialReviewersResult{
+		TeamId:     teamId,
+		TeamName:   d.TeamName,
+		ParentId:   parentId,
+		Settings:   row.toDomain(teamId),
+		Candidates: candidates,
+	}, nil
+}
+
+func (r *TeamRepository) SelectOpenReviews(ctx context.Context, userIds []string) ([]*result.OpenReviewResult, error) {
+	reviews, err := readOpenReviews(ctx, r.db, userIds)
+	if err != nil {
+		r.log.Error("failed to load open reviews",
+			zap.Strings("user_ids", userIds),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return reviews, nil
+}
+
+// DeactivateMembers выключает участников команды и в той же транзакции применяет переназначения их ревью
+func (r *TeamRepository) DeactivateMembers(ctx context.Context, d *dto.DeactivateMembersDTO) (*result.DeactivateMembersResult, error) {
+	r.log.Info("deactivate team members started",
+		zap.String("team_name", d.TeamName),
+		zap.Int("users", len(d.UserIds)),
+		zap.Int("reassignments", len(d.Reassignments)),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Выключаем всех пользователей одним запросом, затрагивая только участников команды
+	rows, err := tx.Query(ctx, deactivateTeamMembersQuery, d.TeamId, d.UserIds)
+	if err != nil {
+		r.log.Error("failed to deactivate team members", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	var deactivated, wereActive []string
+	for rows.Next() {
+		var userId string
+		var wasActive bool
+		if err := rows.Scan(&userId, &wasActive); err != nil {
+			rows.Close()
+			return nil, handleDBError(err)
+		}
+		deactivated = append(deactivated, userId)
+		if wasActive {
+			wereActive = append(wereActive, userId)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.log.Error("failed to deactivate team members", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Состав команды мог измениться после планирования
+	if len(deactivated) != len(d.UserIds) {
+		r.log.Warn("some users are not team members",
+			zap.String("team_name", d.TeamName),
+			zap.Strings("user_ids", d.UserIds),
+			zap.Strings("deactivated", deactivated),
+		)
+		return nil, ErrNotTeamMember
+	}
+
+	// Публикуем user.deactivated для фактически выключенных пользователей
+	events, err := userDeactivatedOutboxEvents(ctx, d.TeamName, wereActive)
+	if err == nil {
+		err = enqueueOutboxEvents(ctx, tx, events)
+	}
+	if err != nil {
+		r.log.Error("failed to enqueue outbox events", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Переназначаем открытые ревью
+	reassigned, err := applyReassignments(ctx, tx, d.Reassignments)
+	if err != nil {
+		r.log.Error("failed to reassign reviews of deactivated members",
+			zap.String("team_name", d.TeamName),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit deactivate team members tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team members deactivated",
+		zap.String("team_name", d.TeamName),
+		zap.Int("users", len(deactivated)),
+		zap.Int("reassigned", len(reassigned)),
+	)
+	// Ответ
+	return &result.DeactivateMembersResult{
+		DeactivatedIds: deactivated,
+		Reassigned:     reassigned,
+	}, nil
+}
+
+// AddMembers добавляет участников в существующую команду и возвращает ее полный состав
+func (r *TeamRepository) AddMembers(ctx context.Context, d *dto.AddTeamMembersDTO) (*result.GetTeamResult, error) {
+	r.log.Info("add team members started",
+		zap.String("team_name", d.TeamName),
+		zap.Int("members", len(d.Members)),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Проверяем существование команды
+	var teamId string
+	err = tx.QueryRow(ctx, teamExistsQuery, d.TeamName).Scan(&teamId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+			return nil, ErrNotFound
+		}
+		r.log.Error("failed to check team existence", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	ignored, err := r.upsertMembers(ctx, tx, teamId, d.TeamName, d.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := readTeamMembers(ctx, tx, d.TeamName)
+	if err != nil {
+		r.log.Error("failed to read team", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit add team members tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team members added",
+		zap.String("team_name", d.TeamName),
+		zap.Int("members", len(members)),
+		zap.Strings("ignored_user_ids", ignored),
+	)
+	// Ответ
+	return &result.GetTeamResult{
+		TeamName:       d.TeamName,
+		Members:        members,
+		IgnoredUserIds: ignored,
+	}, nil
+}
+
+// SetMemberRole меняет роль участника команды и возвращает ее полный состав.
+// Лида из настроек команды понизить нельзя, возвращается ErrTeamLead
+func (r *TeamRepository) SetMemberRole(ctx context.Context, d *dto.SetTeamMemberRoleDTO) (*result.GetTeamResult, error) {
+	r.log.Info("set team member role started",
+		zap.String("team_name", d.TeamName),
+		zap.String("user_id", d.UserId),
+		zap.String("role", d.Role),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Проверяем существование команды
+	var teamId string
+	err = tx.QueryRow(ctx, teamExistsQuery, d.TeamName).Scan(&teamId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+			return nil, ErrNotFound
+		}
+		r.log.Error("failed to check team existence", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Лид из настроек команды остается лидом, пока его не сменят в настройках
+	if d.Role != domain.TeamRoleLead {
+		var isLead int
+		err = tx.QueryRow(ctx, checkSettingsLeadQuery, teamId, d.UserId).Scan(&isLead)
+		if err == nil {
+			r.log.Warn("settings lead cannot be demoted",
+				zap.String("team_name", d.TeamName),
+				zap.String("user_id", d.UserId),
+			)
+			return nil, ErrTeamLead
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.log.Error("failed to check team lead", zap.String("team_name", d.TeamName), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
+	cmdTag, err := tx.Exec(ctx, setTeamMemberRoleQuery, teamId, d.UserId, d.Role)
+	if err != nil {
+		r.log.Error("failed to set team member role", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Warn("user is not a team member",
+			zap.String("team_name", d.TeamName),
+			zap.String("user_id", d.UserId),
+		)
+		return nil, ErrNotTeamMember
+	}
+
+	members, err := readTeamMembers(ctx, tx, d.TeamName)
+	if err != nil {
+		r.log.Error("failed to read team", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit set team member role tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team member role set",
+		zap.String("team_name", d.TeamName),
+		zap.String("user_id", d.UserId),
+		zap.String("role", d.Role),
+	)
+	// Ответ
+	return &result.GetTeamResult{
+		TeamName: d.TeamName,
+		Members:  members,
+	}, nil
+}
+
+// RemoveMember исключает пользователя из команды и в той же транзакции применяет переназначения его ревью.
+// Лида команды исключить нельзя, из правил CODEOWNERS команды пользователь удаляется
+func (r *TeamRepository) RemoveMember(ctx context.Context, d *dto.RemoveTeamMemberDTO) (*result.RemoveMembersResult, error) {
+	r.log.Info("remove team member started",
+		zap.String("team_name", d.TeamName),
+		zap.String("user_id", d.UserId),
+		zap.Int("reassignments", len(d.Reassignments)),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Лид мог быть назначен после планирования
+	var isLead int
+	err = tx.QueryRow(ctx, checkTeamLeadQuery, d.TeamId, d.UserId).Scan(&isLead)
+	if err == nil {
+		r.log.Warn("team lead cannot be removed",
+			zap.String("team_name", d.TeamName),
+			zap.String("user_id", d.UserId),
+		)
+		return nil, ErrTeamLead
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		r.log.Error("failed to check team lead", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	var removedId string
+	err = tx.QueryRow(ctx, removeTeamMemberQuery, d.TeamId, d.UserId).Scan(&removedId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("user is not a team member",
+				zap.String("team_name", d.TeamName),
+				zap.String("user_id", d.UserId),
+			)
+			return nil, ErrNotTeamMember
+		}
+		r.log.Error("failed to remove team member", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if _, err = tx.Exec(ctx, removeCodeOwnerQuery, d.TeamId, d.UserId); err != nil {
+		r.log.Error("failed to remove user from code owners", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if err = syncPrimaryTeams(ctx, tx, []string{d.UserId}); err != nil {
+		r.log.Error("failed to update user primary team", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Переназначаем открытые ревью
+	reassigned, err := applyReassignments(ctx, tx, d.Reassignments)
+	if err != nil {
+		r.log.Error("failed to reassign reviews of removed member",
+			zap.String("team_name", d.TeamName),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit remove team member tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team member removed",
+		zap.String("team_name", d.TeamName),
+		zap.String("user_id", d.UserId),
+		zap.Int("reassigned", len(reassigned)),
+	)
+	// Ответ
+	return &result.RemoveMembersResult{
+		RemovedIds: []string{removedId},
+		Reassigned: reassigned,
+	}, nil
+}
+
+// Rename меняет название команды вместе с users.team_name ее участников
+func (r *TeamRepository) Rename(ctx context.Context, d *dto.RenameTeamDTO) (*result.GetTeamResult, error) {
+	r.log.Info("rename team started",
+		zap.String("team_name", d.TeamName),
+		zap.String("new_team_name", d.NewTeamName),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Занятое название дает ошибку уникальности
+	var teamId string
+	err = tx.QueryRow(ctx, renameTeamQuery, d.TeamName, d.NewTeamName).Scan(&teamId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+		} else {
+			r.log.Error("failed to rename team", zap.String("team_name", d.TeamName), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	if _, err = tx.Exec(ctx, renameUsersTeamQuery, d.TeamName, d.NewTeamName); err != nil {
+		r.log.Error("failed to rename users team", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	members, err := readTeamMembers(ctx, tx, d.NewTeamName)
+	if err != nil {
+		r.log.Error("failed to read team", zap.String("team_name", d.NewTeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit rename team tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team renamed",
+		zap.String("team_name", d.TeamName),
+		zap.String("new_team_name", d.NewTeamName),
+	)
+	// Ответ
+	return &result.GetTeamResult{
+		TeamName: d.NewTeamName,
+		Members:  members,
+	}, nil
+}
+
+// Delete удаляет команду и в той же транзакции применяет переназначения открытых ревью ее участников.
+// Пользователи остаются, users.team_name переходит на другую их команду
+func (r *TeamRepository) Delete(ctx context.Context, d *dto.DeleteTeamDTO) (*result.RemoveMembersResult, error) {
+	r.log.Info("delete team started",
+		zap.String("team_name", d.TeamName),
+		zap.Int("reassignments", len(d.Reassignments)),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	memberIds, err := readStrings(ctx, tx, selectTeamMemberIdsQuery, d.TeamId)
+	if err != nil {
+		r.log.Error("failed to read team members", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	cmdTag, err := tx.Exec(ctx, deleteTeamQuery, d.TeamId)
+	if err != nil {
+		r.log.Error("failed to delete team", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Warn("team not found", zap.String("team_name", d.TeamName))
+		return nil, ErrNotFound
+	}
+
+	if len(memberIds) > 0 {
+		if err = syncPrimaryTeams(ctx, tx, memberIds); err != nil {
+			r.log.Error("failed to update users primary team", zap.String("team_name", d.TeamName), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
+	// Переназначаем открытые ревью
+	reassigned, err := applyReassignments(ctx, tx, d.Reassignments)
+	if err != nil {
+		r.log.Error("failed to reassign reviews of deleted team",
+			zap.String("team_name", d.TeamName),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit delete team tx", zap.String("team_name", d.TeamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("team deleted",
+		zap.String("team_name", d.TeamName),
+		zap.Int("members", len(memberIds)),
+		zap.Int("reassigned", len(reassigned)),
+	)
+	// Ответ
+	return &result.RemoveMembersResult{
+		RemovedIds: memberIds,
+		Reassigned: reassigned,
+	}, nil
+}
+
+// upsertMembers создает новых пользователей и добавляет их в команду. Существующие пользователи
+// только добавляются в команду, их имя и активность не меняются. Участники заполняются данными из бд,
+// возвращаются существующие пользователи, у которых username или is_active из запроса отличались
+// от сохраненных и были проигнорированы
+func (r *TeamRepository) upsertMembers(ctx context.Context, exec queryExecutor, teamId, teamName string, members []*domain.User) ([]string, error) {
+	userIds := make([]string, 0, len(members))
+	for _, member := range members {
+		if member == nil {
+			continue
+		}
+
+		if _, err := exec.Exec(ctx, insertUserQuery, member.Id, member.Name, teamName, member.IsActive); err != nil {
+			r.log.Error("failed to insert user for team",
+				zap.String("team_name", teamName),
+				zap.String("user_id", member.Id),
+				zap.Error(err),
+			)
+			return nil, handleDBError(err)
+		}
+
+		// Добавляем пользователя в команду
+		if _, err := exec.Exec(ctx, insertTeamMemberQuery, teamId, member.Id); err != nil {
+			r.log.Error("failed to add team member",
+				zap.String("team_name", teamName),
+				zap.String("user_id", member.Id),
+				zap.Error(err),
+			)
+			return nil, handleDBError(err)
+		}
+		userIds = append(userIds, member.Id)
+	}
+
+	// Команда становится основной только для пользователей, у которых еще нет команды,
+	// переносим ее в users.team_name
+	if err := syncPrimaryTeams(ctx, exec, userIds); err != nil {
+		r.log.Error("failed to sync users team name", zap.String("team_name", teamName), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Возвращаем сохраненные данные, в том числе не измененных существующих пользователей
+	var ignored []string
+	for _, member := range members {
+		if member == nil {
+			continue
+		}
+		user, err := readUser(ctx, exec, member.Id)
+		if err != nil {
+			r.log.Error("failed to read team member",
+				zap.String("team_name", teamName),
+				zap.String("user_id", member.Id),
+				zap.Error(err),
+			)
+			return nil, handleDBError(err)
+		}
+		if user.Name != member.Name || user.IsActive != member.IsActive {
+			ignored = append(ignored, member.Id)
+		}
+		member.Name = user.Name
+		member.TeamName = user.TeamName
+		member.IsActive = user.IsActive
+		member.CreatedAt = user.CreatedAt
+	}
+	return ignored, nil
+}
+
+// вспомогательная функция для поиска участников, которые уже состоят в командах
+func selectMembersOfTeams(ctx context.Context, exec queryExecutor, members []*domain.User) ([]string, error) {
+	userIds := make([]string, 0, len(members))
+	for _, member := range members {
+		if member != nil {
+			userIds = append(userIds, member.Id)
+		}
+	}
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+
+	rows, err := exec.Query(ctx, selectMembersOfTeamsQuery, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var busy []string
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		busy = append(busy, userId)
+	}
+	return busy, rows.Err()
+}
+
+// вспомогательная функция для чтения участников команды в порядке создания пользователей
+func readTeamMembers(ctx context.Context, exec queryExecutor, teamName string) ([]*domain.User, error) {
+	rows, err := exec.Query(ctx, getTeamQuery, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*domain.User
+	for rows.Next() {
+		member := &domain.User{}
+		var id, name, userTeamName, role sql.NullString
+		var isActive sql.NullBool
+		var createdAt sql.NullTime
+		if err := rows.Scan(new(string), &id, &name, &userTeamName, &isActive, &createdAt, &role, &member.Teams); err != nil {
+			return nil, err
+		}
+		// Команда без участников дает одну строку с NULL из LEFT JOIN
+		if !id.Valid {
+			continue
+		}
+		member.Id = id.String
+		member.Name = name.String
+		member.TeamName = userTeamName.String
+		member.IsActive = isActive.Bool
+		member.CreatedAt = createdAt.Time
+		member.Role = role.String
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// вспомогательная функция для чтения команды с участниками, родительской командой и деревом подкоманд
+func readTeamHierarchy(ctx context.Context, exec queryExecutor, teamName string) (*result.GetTeamResult, error) {
+	var teamId, parentName string
+	if err := exec.QueryRow(ctx, selectTeamParentQuery, teamName).Scan(&teamId, &parentName); err != nil {
+		return nil, err
+	}
+
+	members, err := readTeamMembers(ctx, exec, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	subtree, err := readTeamNodes(ctx, exec, selectTeamSubtreeQuery, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.GetTeamResult{
+		TeamName:       teamName,
+		Members:        members,
+		ParentTeamName: parentName,
+		Subtree:        subtree,
+	}, nil
+}
+
+// вспомогательная функция для чтения команд иерархии с идентификаторами их прямых участников
+func readTeamNodes(ctx context.Context, exec queryExecutor, query string, args ...any) ([]*result.TeamNodeResult, error) {
+	rows, err := exec.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []*result.TeamNodeResult
+	for rows.Next() {
+		node := &result.TeamNodeResult{}
+		if err := rows.Scan(&node.TeamId, &node.TeamName, &node.ParentId, &node.MemberIds); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, rows.Err()
+}
+
+// вспомогательная функция для выбора новой основной команды пользователей, потерявших основную,
+// и синхронизации users.team_name
+func syncPrimaryTeams(ctx context.Context, exec queryExecutor, userIds []string) error {
+	if _, err := exec.Exec(ctx, promotePrimaryTeamQuery, userIds); err != nil {
+		return err
+	}
+	_, err := exec.Exec(ctx, syncUsersTeamNameQuery, userIds)
+	return err
+}
+
+// вспомогательная функция для чтения названий резервных команд в порядке цепочки
+func readFallbackTeamNames(ctx context.Context, exec queryExecutor, teamId string) ([]string, error) {
+	rows, err := exec.Query(ctx, selectFallbackTeamNamesQuery, teamId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// вспомогательная функция для чтения запроса с одной текстовой колонкой
+func readStrings(ctx context.Context, exec queryExecutor, query string, args ...any) ([]string, error) {
+	rows, err := exec.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// teamSettingsRow колонки настроек команды, которые при LEFT JOIN могут быть NULL
+type teamSettingsRow struct {
+	reviewerCount sql.NullInt32
+	strategy      sql.NullString
+	leadUserId    sql.NullString
+	requireLead   sql.NullBool
+	approvals     sql.NullInt32
+	requireTags   sql.NullBool
+	slaHours      sql.NullInt32
+	slaPolicy     sql.NullString
+	updatedAt     sql.NullTime
+}
+
+func (r *teamSettingsRow) dest() []any {
+	return []any{
+		&r.reviewerCount,
+		&r.strategy,
+		&r.leadUserId,
+		&r.requireLead,
+		&r.approvals,
+		&r.requireTags,
+		&r.slaHours,
+		&r.slaPolicy,
+		&r.updatedAt,
+	}
+}
+
+// toDomain возвращает nil, если настройки для команды не заданы
+func (r *teamSettingsRow) toDomain(teamId string) *domain.TeamSettings {
+	if !r.reviewerCount.Valid {
+		return nil
+	}
+	return &domain.TeamSettings{
+		TeamId:            teamId,
+		ReviewerCount:     int(r.reviewerCount.Int32),
+		Strategy:          r.strategy.String,
+		LeadUserId:        r.leadUserId.String,
+		RequireLead:       r.requireLead.Bool,
+		RequiredApprovals: int(r.approvals.Int32),
+		RequireTags:       r.requireTags.Bool,
+		ReviewSlaHours:    int(r.slaHours.Int32),
+		SlaPolicy:         r.slaPolicy.String,
+		UpdatedAt:         r.updatedAt.Time,
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"go.uber.org/zap"
+)
+
+const (
+	// Существующий пользователь не перезаписывается, конфликт распознается по пустому RETURNING
+	createUserQuery = `
+INSERT INTO users (id, name, team_name, is_active)
+VALUES ($1, $2, NULLIF($3, ''), $4)
+ON CONFLICT (id) DO NOTHING
+RETURNING id;`
+
+	// Незаданные поля сохраняют прежние значения
+	updateUserQuery = `
+UPDATE users u
+SET name = COALESCE($2, u.name),
+    is_active = COALESCE($3, u.is_active)
+FROM (SELECT id, is_active FROM users WHERE id = $1 FOR UPDATE) prev
+WHERE u.id = prev.id
+RETURNING prev.is_active;`
+
+	listUsersQuery = `
+SELECT
+    u.id,
+    u.name,
+    COALESCE(u.team_name, ''),
+    u.is_active,
+    u.created_at,
+    ARRAY(
+        SELECT t.name
+        FROM team_members tm
+        JOIN teams t ON t.id = tm.team_id
+        WHERE tm.user_id = u.id
+        ORDER BY tm.is_primary DESC, t.name ASC
+    )
+FROM users u
+WHERE ($1::text = '' OR EXISTS (
+        SELECT 1
+        FROM team_members tm
+        JOIN teams t ON t.id = tm.team_id
+        WHERE tm.user_id = u.id AND t.name = $1
+    ))
+  AND ($2::boolean IS NULL OR u.is_active = $2)
+ORDER BY u.id ASC;`
+)
+
+// Create создает пользователя и, если задана команда, делает ее основной.
+// Возвращает ErrAlreadyExists, если пользователь уже есть, и ErrNotFound, если нет команды
+func (r *UserRepository) Create(ctx context.Context, d *dto.CreateUserDTO) (*domain.User, error) {
+	r.log.Info("create user started",
+		zap.String("user_id", d.UserId),
+		zap.String("team_name", d.TeamName),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Проверяем существование команды
+	var teamId string
+	if d.TeamName != "" {
+		err = tx.QueryRow(ctx, teamExistsQuery, d.TeamName).Scan(&teamId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				r.log.Warn("team not found while creating user", zap.String("team_name", d.TeamName))
+			} else {
+				r.log.Error("failed to check team existence", zap.String("team_name", d.TeamName), zap.Error(err))
+			}
+			return nil, handleDBError(err)
+		}
+	}
+
+	var userId string
+	err = tx.QueryRow(ctx, createUserQuery, d.UserId, d.Username, d.TeamName, d.IsActive).Scan(&userId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Warn("user already exists", zap.String("user_id", d.UserId))
+		return nil, ErrAlreadyExists
+	}
+	if err != nil {
+		r.log.Error("failed to insert user", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Первая команда пользователя становится основной
+	if teamId != "" {
+		if _, err = tx.Exec(ctx, insertTeamMemberQuery, teamId, d.UserId); err != nil {
+			r.log.Error("failed to add user to team",
+				zap.String("user_id", d.UserId),
+				zap.String("team_name", d.TeamName),
+				zap.Error(err),
+			)
+			return nil, handleDBError(err)
+		}
+	}
+
+	user, err := readUser(ctx, tx, d.UserId)
+	if err != nil {
+		r.log.Error("failed to read created user", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit create user tx", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("user created", zap.String("user_id", user.Id), zap.String("team_name", user.TeamName))
+	// Ответ
+	return user, nil
+}
+
+// Get пользователь со всеми командами
+func (r *UserRepository) Get(ctx context.Context, userId string) (*domain.User, error) {
+	r.log.Info("get user started", zap.String("user_id", userId))
+
+	user, err := readUser(ctx, r.db, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("user not found", zap.String("user_id", userId))
+		} else {
+			r.log.Error("failed to read user", zap.String("user_id", userId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("user retrieved", zap.String("user_id", user.Id))
+	// Ответ
+	return user, nil
+}
+
+// Update меняет имя и активность пользователя. При выключении публикуется user.deactivated,
+// открытые ревью пользователя не переназначаются
+func (r *UserRepository) Update(ctx context.Context, d *dto.UpdateUserDTO) (*domain.User, error) {
+	r.log.Info("update user started", zap.String("user_id", d.UserId))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var wasActive bool
+	err = tx.QueryRow(ctx, updateUserQuery, d.UserId, d.Username, d.IsActive).Scan(&wasActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Warn("user not found while updating", zap.String("user_id", d.UserId))
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		r.log.Error("update user failed", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Читаем пользователя повторно, чтобы вернуть актуальные данные
+	user, err := readUser(ctx, tx, d.UserId)
+	if err != nil {
+		r.log.Error("failed to read user after update", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Публикуем user.deactivated только при фактическом выключении
+	if wasActive && !user.IsActive {
+		if err := enqueueUserDeactivated(ctx, tx, user); err != nil {
+			r.log.Error("failed to enqueue outbox events", zap.String("user_id", d.UserId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit update user tx", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("user updated", zap.String("user_id", user.Id), zap.Bool("is_active", user.IsActive))
+	// Ответ
+	return user, nil
+}
+
+// List пользователи по фильтрам в порядке id. Если задана несуществующая команда, возвращает ErrNotFound
+func (r *UserRepository) List(ctx context.Context, d *dto.ListUsersDTO) ([]*domain.User, error) {
+	r.log.Info("list users started", zap.String("team_name", d.TeamName))
+
+	if d.TeamName != "" {
+		var teamId string
+		err := r.db.QueryRow(ctx, teamExistsQuery, d.TeamName).Scan(&teamId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				r.log.Warn("team not found while listing users", zap.String("team_name", d.TeamName))
+			} else {
+				r.log.Error("failed to check team existence", zap.String("team_name", d.TeamName), zap.Error(err))
+			}
+			return nil, handleDBError(err)
+		}
+	}
+
+	rows, err := r.db.Query(ctx, listUsersQuery, d.TeamName, d.IsActive)
+	if err != nil {
+		r.log.Error("failed to list users", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	users := make([]*domain.User, 0)
+	for rows.Next() {
+		user := &domain.User{}
+		if err := rows.Scan(&user.Id, &user.Name, &user.TeamName, &user.IsActive, &user.CreatedAt, &user.Teams); err != nil {
+			r.log.Error("failed to scan user", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("failed to iterate users", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("users listed", zap.Int("count", len(users)))
+	// Ответ
+	return users, nil
+}
+
+// вспомогательная функция для чтения пользователя со всеми командами
+func readUser(ctx context.Context, exec queryExecutor, userId string) (*domain.User, error) {
+	user := &domain.User{}
+	err := exec.QueryRow(ctx, selectUserQuery, userId).Scan(
+		&user.Id,
+		&user.Name,
+		&user.TeamName,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.Teams,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
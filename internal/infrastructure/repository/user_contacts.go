@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"go.uber.org/zap"
+)
+
+const (
+	selectUserContactsQuery = `
+SELECT email, chat_handle, digest_enabled, updated_at
+FROM user_contacts
+WHERE user_id = $1;`
+
+	upsertUserContactsQuery = `
+INSERT INTO user_contacts (user_id, email, chat_handle, digest_enabled, updated_at)
+VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+ON CONFLICT (user_id) DO UPDATE
+SET email = EXCLUDED.email,
+    chat_handle = EXCLUDED.chat_handle,
+    digest_enabled = EXCLUDED.digest_enabled,
+    updated_at = EXCLUDED.updated_at
+RETURNING email, chat_handle, digest_enabled, updated_at;`
+
+	// Те же назначения, что отдает getReviewQuery, но только открытые PR активных пользователей,
+	// не отказавшихся от сводки. Пользователь без контактов получает сводку по умолчанию
+	selectReviewDigestsQuery = `
+SELECT
+    u.id,
+    u.name,
+    COALESCE(c.email, ''),
+    COALESCE(c.chat_handle, ''),
+    p.id,
+    p.name,
+    p.author_id,
+    p.status,
+    p.created_at
+FROM pr_reviewers prr
+JOIN prs p ON prr.pr_id = p.id
+JOIN users u ON u.id = prr.user_id
+LEFT JOIN user_contacts c ON c.user_id = u.id
+WHERE p.status = 'OPEN'
+  AND u.is_active = TRUE
+  AND COALESCE(c.digest_enabled, TRUE)
+ORDER BY u.id, p.created_at DESC;`
+)
+
+// GetContacts возвращает контакты пользователя. Если контакты не задавались, возвращаются пустые
+// контакты с включенной сводкой
+func (r *UserRepository) GetContacts(ctx context.Context, userId string) (*domain.UserContact, error) {
+	r.log.Debug("get user contacts", zap.String("user_id", userId))
+
+	// Проверяем существование пользователя
+	exists, err := r.CheckUserExists(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		r.log.Warn("user not found while reading contacts", zap.String("user_id", userId))
+		return nil, ErrNotFound
+	}
+
+	contact := &domain.UserContact{UserId: userId}
+	err = r.db.QueryRow(ctx, selectUserContactsQuery, userId).Scan(
+		&contact.Email,
+		&contact.ChatHandle,
+		&contact.DigestEnabled,
+		&contact.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			contact.DigestEnabled = true
+			return contact, nil
+		}
+		r.log.Error("failed to read user contacts", zap.String("user_id", userId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return contact, nil
+}
+
+// SetContacts заменяет контакты пользователя целиком
+func (r *UserRepository) SetContacts(ctx context.Context, d *dto.SetUserContactsDTO) (*domain.UserContact, error) {
+	r.log.Info("set user contacts",
+		zap.String("user_id", d.UserId),
+		zap.Bool("digest_enabled", d.DigestEnabled),
+	)
+
+	// Несуществующий пользователь дает ошибку внешнего ключа
+	contact := &domain.UserContact{UserId: d.UserId}
+	err := r.db.QueryRow(ctx, upsertUserContactsQuery, d.UserId, d.Email, d.ChatHandle, d.DigestEnabled).Scan(
+		&contact.Email,
+		&contact.ChatHandle,
+		&contact.DigestEnabled,
+		&contact.UpdatedAt,
+	)
+	if err != nil {
+		r.log.Error("failed to upsert user contacts", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("user contacts updated", zap.String("user_id", d.UserId))
+	// Ответ
+	return contact, nil
+}
+
+// SelectReviewDigests собирает для каждого активного ревьюера открытые PR, на которые он назначен.
+// Пользователи без открытых ревью в результат не попадают
+func (r *UserRepository) SelectReviewDigests(ctx context.Context) ([]*domain.ReviewDigest, error) {
+	r.log.Debug("select review digests")
+
+	rows, err := r.db.Query(ctx, selectReviewDigestsQuery)
+	if err != nil {
+		r.log.Error("failed to load review digests", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	// Строки отсортированы по пользователю, поэтому сводка собирается одним проходом
+	var digests []*domain.ReviewDigest
+	var current *domain.ReviewDigest
+	for rows.Next() {
+		var userId, username, email, chatHandle string
+		pr := &domain.Pr{}
+		err = rows.Scan(
+			&userId,
+			&username,
+			&email,
+			&chatHandle,
+			&pr.Id,
+			&pr.Name,
+			&pr.AuthorId,
+			&pr.Status,
+			&pr.CreatedAt,
+		)
+		if err != nil {
+			return nil, handleDBError(err)
+		}
+		if current == nil || current.UserId != userId {
+			current = &domain.ReviewDigest{
+				UserId:     userId,
+				Username:   username,
+				Email:      email,
+				ChatHandle: chatHandle,
+			}
+			digests = append(digests, current)
+		}
+		current.Prs = append(current.Prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("failed to read review digests", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return digests, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/niklvrr/AvitoInternship2025/internal/domain"
@@ -14,15 +15,31 @@ import (
 )
 
 const (
+	// Возвращает прежнее значение is_active, чтобы отличить выключение от повторного запроса
 	setIsActiveQuery = `
-UPDATE users
+UPDATE users u
 SET is_active = $1
-WHERE id = $2`
+FROM (SELECT id, is_active FROM users WHERE id = $2 FOR UPDATE) prev
+WHERE u.id = prev.id
+RETURNING prev.is_active;`
 
+	// Вместе с основной командой читаются все команды пользователя, основная первой
 	selectUserQuery = `
-SELECT id, name, team_name, is_active, created_at
-FROM users
-WHERE id = $1`
+SELECT
+    u.id,
+    u.name,
+    COALESCE(u.team_name, ''),
+    u.is_active,
+    u.created_at,
+    ARRAY(
+        SELECT t.name
+        FROM team_members tm
+        JOIN teams t ON t.id = tm.team_id
+        WHERE tm.user_id = u.id
+        ORDER BY tm.is_primary DESC, t.name ASC
+    )
+FROM users u
+WHERE u.id = $1`
 
 	getReviewQuery = `
 SELECT
@@ -36,17 +53,66 @@ FROM pr_reviewers prr
 JOIN prs p ON prr.pr_id = p.id
 WHERE prr.user_id = $1
 ORDER BY p.created_at DESC;`
+
+	setMaxOpenReviewsQuery = `
+UPDATE users u
+SET max_open_reviews = $1
+WHERE u.id = $2
+RETURNING
+    u.id,
+    u.name,
+    COALESCE(u.team_name, ''),
+    u.is_active,
+    u.created_at,
+    u.max_open_reviews,
+    ARRAY(
+        SELECT t.name
+        FROM team_members tm
+        JOIN teams t ON t.id = tm.team_id
+        WHERE tm.user_id = u.id
+        ORDER BY tm.is_primary DESC, t.name ASC
+    );`
+
+	insertAvailabilityQuery = `
+INSERT INTO availability (id, user_id, starts_at, ends_at, reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, starts_at, ends_at, reason, created_at;`
+
+	selectAvailabilityQuery = `
+SELECT id, user_id, starts_at, ends_at, reason, created_at
+FROM availability
+WHERE user_id = $1
+ORDER BY starts_at ASC;`
+
+	deleteAvailabilityQuery = `
+DELETE FROM availability
+WHERE id = $1;`
+
+	selectUserTagsQuery = `
+SELECT tag FROM user_tags
+WHERE user_id = $1
+ORDER BY tag ASC;`
+
+	deleteUserTagsQuery = `
+DELETE FROM user_tags
+WHERE user_id = $1;`
+
+	insertUserTagsQuery = `
+INSERT INTO user_tags (user_id, tag)
+SELECT $1, unnest($2::text[]);`
 )
 
 type UserRepository struct {
+	reviewerPools
 	db  *pgxpool.Pool
 	log *zap.Logger
 }
 
 func NewUserRepository(db *pgxpool.Pool, log *zap.Logger) *UserRepository {
 	return &UserRepository{
-		db:  db,
-		log: log,
+		reviewerPools: reviewerPools{db: db, log: log},
+		db:            db,
+		log:           log,
 	}
 }
 
@@ -56,8 +122,19 @@ func (r *UserRepository) SetIsActive(ctx context.Context, d *dto.SetIsActiveDTO)
 		zap.Bool("is_active", d.IsActive),
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
 	// Изменение поле is_active
-	cmdTag, err := r.db.Exec(ctx, setIsActiveQuery, d.IsActive, d.UserId)
+	var wasActive bool
+	err = tx.QueryRow(ctx, setIsActiveQuery, d.IsActive, d.UserId).Scan(&wasActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Warn("user not found while updating activity", zap.String("user_id", d.UserId))
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		r.log.Error("set user activity failed",
 			zap.String("user_id", d.UserId),
@@ -66,19 +143,15 @@ func (r *UserRepository) SetIsActive(ctx context.Context, d *dto.SetIsActiveDTO)
 		return nil, handleDBError(err)
 	}
 
-	if cmdTag.RowsAffected() == 0 {
-		r.log.Warn("user not found while updating activity", zap.String("user_id", d.UserId))
-		return nil, ErrNotFound
-	}
-
 	// Читаем пользователя повторно, чтобы вернуть актуальные данные
 	user := &domain.User{}
-	err = r.db.QueryRow(ctx, selectUserQuery, d.UserId).Scan(
+	err = tx.QueryRow(ctx, selectUserQuery, d.UserId).Scan(
 		&user.Id,
 		&user.Name,
 		&user.TeamName,
 		&user.IsActive,
 		&user.CreatedAt,
+		&user.Teams,
 	)
 	if err != nil {
 		r.log.Error("failed to read user after activity update",
@@ -88,6 +161,19 @@ func (r *UserRepository) SetIsActive(ctx context.Context, d *dto.SetIsActiveDTO)
 		return nil, handleDBError(err)
 	}
 
+	// Публикуем user.deactivated только при фактическом выключении
+	if wasActive && !user.IsActive {
+		if err := enqueueUserDeactivated(ctx, tx, user); err != nil {
+			r.log.Error("failed to enqueue outbox events", zap.String("user_id", d.UserId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit user activity update", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
 	r.log.Info("user activity updated",
 		zap.String("user_id", user.Id),
 		zap.Bool("is_active", user.IsActive),
@@ -96,6 +182,107 @@ func (r *UserRepository) SetIsActive(ctx context.Context, d *dto.SetIsActiveDTO)
 	return user, nil
 }
 
+// SelectOpenReviews читает открытые PR, на которые назначены переданные ревьюеры
+func (r *UserRepository) SelectOpenReviews(ctx context.Context, userIds []string) ([]*result.OpenReviewResult, error) {
+	r.log.Debug("select open reviews", zap.Strings("user_ids", userIds))
+
+	reviews, err := readOpenReviews(ctx, r.db, userIds)
+	if err != nil {
+		r.log.Error("failed to load open reviews",
+			zap.Strings("user_ids", userIds),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return reviews, nil
+}
+
+// Deactivate выключает пользователя и в той же транзакции переназначает его открытые ревью по плану из сервиса.
+// Ревью, которые к моменту записи уже сняты или PR перестал быть OPEN, пропускаются
+func (r *UserRepository) Deactivate(ctx context.Context, d *dto.DeactivateUserDTO) (*result.DeactivateUserResult, error) {
+	r.log.Info("deactivate user started",
+		zap.String("user_id", d.UserId),
+		zap.Int("planned_reassignments", len(d.Reassignments)),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Выключаем пользователя
+	var wasActive bool
+	err = tx.QueryRow(ctx, setIsActiveQuery, false, d.UserId).Scan(&wasActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Warn("user not found while deactivating", zap.String("user_id", d.UserId))
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		r.log.Error("deactivate user failed", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Переназначаем открытые ревью
+	reassigned, err := applyReassignments(ctx, tx, d.Reassignments)
+	if err != nil {
+		r.log.Error("failed to reassign reviews of deactivated user",
+			zap.String("user_id", d.UserId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+	if len(reassigned) < len(d.Reassignments) {
+		r.log.Warn("some reviews are no longer open, skipped reassignment",
+			zap.String("user_id", d.UserId),
+			zap.Int("planned", len(d.Reassignments)),
+			zap.Int("reassigned", len(reassigned)),
+		)
+	}
+
+	// Читаем пользователя повторно, чтобы вернуть актуальные данные
+	user := &domain.User{}
+	err = tx.QueryRow(ctx, selectUserQuery, d.UserId).Scan(
+		&user.Id,
+		&user.Name,
+		&user.TeamName,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.Teams,
+	)
+	if err != nil {
+		r.log.Error("failed to read user after deactivation",
+			zap.String("user_id", d.UserId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	if wasActive {
+		if err := enqueueUserDeactivated(ctx, tx, user); err != nil {
+			r.log.Error("failed to enqueue outbox events", zap.String("user_id", d.UserId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit deactivation", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("user deactivated",
+		zap.String("user_id", user.Id),
+		zap.Int("reassigned_reviews", len(reassigned)),
+	)
+	// Ответ
+	return &result.DeactivateUserResult{
+		User:       user,
+		Reassigned: reassigned,
+	}, nil
+}
+
 func (r *UserRepository) GetReview(ctx context.Context, d *dto.GetReviewDTO) (*result.GetReviewResult, error) {
 	r.log.Info("get user reviews", zap.String("user_id", d.UserId))
 
@@ -146,7 +333,7 @@ func (r *UserRepository) CheckUserExists(ctx context.Context, userId string) (bo
 	r.log.Debug("check user exists", zap.String("user_id", userId))
 
 	var id string
-	err := r.db.QueryRow(ctx, selectUserQuery, userId).Scan(&id, new(string), new(string), new(bool), new(sql.NullTime))
+	err := r.db.QueryRow(ctx, selectUserQuery, userId).Scan(&id, new(string), new(string), new(bool), new(sql.NullTime), new([]string))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return false, nil
@@ -160,3 +347,215 @@ func (r *UserRepository) CheckUserExists(ctx context.Context, userId string) (bo
 
 	return true, nil
 }
+
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, d *dto.SetMaxOpenReviewsDTO) (*domain.User, error) {
+	r.log.Info("set user max open reviews",
+		zap.String("user_id", d.UserId),
+		zap.Any("max_open_reviews", d.MaxOpenReviews),
+	)
+
+	// Изменяем предел и сразу возвращаем актуальные данные
+	user := &domain.User{}
+	err := r.db.QueryRow(ctx, setMaxOpenReviewsQuery, d.MaxOpenReviews, d.UserId).Scan(
+		&user.Id,
+		&user.Name,
+		&user.TeamName,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.MaxOpenReviews,
+		&user.Teams,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("user not found while updating max open reviews", zap.String("user_id", d.UserId))
+			return nil, ErrNotFound
+		}
+		r.log.Error("set user max open reviews failed",
+			zap.String("user_id", d.UserId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("user max open reviews updated", zap.String("user_id", user.Id))
+	// Ответ
+	return user, nil
+}
+
+func (r *UserRepository) AddAvailability(ctx context.Context, d *dto.AddAvailabilityDTO) (*domain.Availability, error) {
+	r.log.Info("add availability period",
+		zap.String("user_id", d.UserId),
+		zap.Time("starts_at", d.StartsAt),
+		zap.Time("ends_at", d.EndsAt),
+	)
+
+	// Создаем период, несуществующий пользователь дает ошибку внешнего ключа
+	period := &domain.Availability{}
+	err := r.db.QueryRow(ctx, insertAvailabilityQuery, uuid.NewString(), d.UserId, d.StartsAt, d.EndsAt, d.Reason).Scan(
+		&period.Id,
+		&period.UserId,
+		&period.StartsAt,
+		&period.EndsAt,
+		&period.Reason,
+		&period.CreatedAt,
+	)
+	if err != nil {
+		r.log.Error("failed to add availability period",
+			zap.String("user_id", d.UserId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("availability period added",
+		zap.String("user_id", period.UserId),
+		zap.String("availability_id", period.Id),
+	)
+	// Ответ
+	return period, nil
+}
+
+func (r *UserRepository) ListAvailability(ctx context.Context, d *dto.ListAvailabilityDTO) ([]*domain.Availability, error) {
+	r.log.Info("list availability periods", zap.String("user_id", d.UserId))
+
+	rows, err := r.db.Query(ctx, selectAvailabilityQuery, d.UserId)
+	if err != nil {
+		r.log.Error("failed to load availability periods",
+			zap.String("user_id", d.UserId),
+			zap.Error(err),
+		)
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	periods := make([]*domain.Availability, 0)
+	for rows.Next() {
+		period := &domain.Availability{}
+		err := rows.Scan(
+			&period.Id,
+			&period.UserId,
+			&period.StartsAt,
+			&period.EndsAt,
+			&period.Reason,
+			&period.CreatedAt,
+		)
+		if err != nil {
+			r.log.Error("failed to scan availability period",
+				zap.String("user_id", d.UserId),
+				zap.Error(err),
+			)
+			return nil, handleDBError(err)
+		}
+		periods = append(periods, period)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return periods, nil
+}
+
+func (r *UserRepository) DeleteAvailability(ctx context.Context, d *dto.DeleteAvailabilityDTO) error {
+	r.log.Info("delete availability period", zap.String("availability_id", d.AvailabilityId))
+
+	cmdTag, err := r.db.Exec(ctx, deleteAvailabilityQuery, d.AvailabilityId)
+	if err != nil {
+		r.log.Error("failed to delete availability period",
+			zap.String("availability_id", d.AvailabilityId),
+			zap.Error(err),
+		)
+		return handleDBError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Warn("availability period not found", zap.String("availability_id", d.AvailabilityId))
+		return ErrNotFound
+	}
+
+	r.log.Info("availability period deleted", zap.String("availability_id", d.AvailabilityId))
+	return nil
+}
+
+// GetTags возвращает теги пользователя по алфавиту
+func (r *UserRepository) GetTags(ctx context.Context, userId string) ([]string, error) {
+	r.log.Debug("get user tags", zap.String("user_id", userId))
+
+	// Проверяем существование пользователя
+	exists, err := r.CheckUserExists(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		r.log.Warn("user not found while reading tags", zap.String("user_id", userId))
+		return nil, ErrNotFound
+	}
+
+	tags, err := readStrings(ctx, r.db, selectUserTagsQuery, userId)
+	if err != nil {
+		r.log.Error("failed to read user tags", zap.String("user_id", userId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return tags, nil
+}
+
+// SetTags заменяет теги пользователя целиком и возвращает их по алфавиту
+func (r *UserRepository) SetTags(ctx context.Context, d *dto.SetUserTagsDTO) ([]string, error) {
+	r.log.Info("set user tags",
+		zap.String("user_id", d.UserId),
+		zap.Strings("tags", d.Tags),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, deleteUserTagsQuery, d.UserId); err != nil {
+		r.log.Error("failed to clear user tags", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	// Несуществующий пользователь дает ошибку внешнего ключа
+	if len(d.Tags) > 0 {
+		if _, err := tx.Exec(ctx, insertUserTagsQuery, d.UserId, d.Tags); err != nil {
+			r.log.Error("failed to insert user tags", zap.String("user_id", d.UserId), zap.Error(err))
+			return nil, handleDBError(err)
+		}
+	} else {
+		// Без тегов проверяем пользователя отдельно
+		var id string
+		err := tx.QueryRow(ctx, selectUserQuery, d.UserId).Scan(&id, new(string), new(string), new(bool), new(sql.NullTime), new([]string))
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				r.log.Error("failed to check user existence", zap.String("user_id", d.UserId), zap.Error(err))
+			}
+			return nil, handleDBError(err)
+		}
+	}
+
+	tags, err := readStrings(ctx, tx, selectUserTagsQuery, d.UserId)
+	if err != nil {
+		r.log.Error("failed to read user tags", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit user tags tx", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("user tags updated", zap.String("user_id", d.UserId), zap.Int("tags", len(tags)))
+	// Ответ
+	return tags, nil
+}
+
+// вспомогательная функция для публикации события user.deactivated
+func enqueueUserDeactivated(ctx context.Context, exec queryExecutor, user *domain.User) error {
+	events, err := userDeactivatedOutboxEvents(ctx, user.TeamName, []string{user.Id})
+	if err != nil {
+		return err
+	}
+	return enqueueOutboxEvents(ctx, exec, events)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"go.uber.org/zap"
+)
+
+const (
+	// Строка членства блокируется, чтобы команду нельзя было покинуть до смены основной
+	selectMembershipQuery = `
+SELECT tm.team_id
+FROM team_members tm
+JOIN teams t ON t.id = tm.team_id
+WHERE tm.user_id = $1 AND t.name = $2
+FOR UPDATE OF tm;`
+
+	// Прежняя основная команда снимается первой: у пользователя не может быть двух основных команд
+	clearPrimaryTeamQuery = `
+UPDATE team_members
+SET is_primary = FALSE
+WHERE user_id = $1 AND is_primary AND team_id <> $2;`
+
+	setPrimaryTeamQuery = `
+UPDATE team_members
+SET is_primary = TRUE
+WHERE user_id = $1 AND team_id = $2;`
+)
+
+// SetPrimaryTeam делает одну из команд пользователя основной и возвращает пользователя со всеми командами.
+// Если пользователь в команде не состоит, возвращает ErrNotTeamMember
+func (r *UserRepository) SetPrimaryTeam(ctx context.Context, d *dto.SetPrimaryTeamDTO) (*domain.User, error) {
+	r.log.Info("set primary team started",
+		zap.String("user_id", d.UserId),
+		zap.String("team_name", d.TeamName),
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, handleDBError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Проверяем существование пользователя
+	var userId string
+	err = tx.QueryRow(ctx, selectUserQuery, d.UserId).Scan(&userId, new(string), new(string), new(bool), new(sql.NullTime), new([]string))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("user not found while setting primary team", zap.String("user_id", d.UserId))
+		} else {
+			r.log.Error("failed to check user existence", zap.String("user_id", d.UserId), zap.Error(err))
+		}
+		return nil, handleDBError(err)
+	}
+
+	var teamId string
+	err = tx.QueryRow(ctx, selectMembershipQuery, d.UserId, d.TeamName).Scan(&teamId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Warn("user is not a team member",
+				zap.String("user_id", d.UserId),
+				zap.String("team_name", d.TeamName),
+			)
+			return nil, ErrNotTeamMember
+		}
+		r.log.Error("failed to read team membership", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if _, err = tx.Exec(ctx, clearPrimaryTeamQuery, d.UserId, teamId); err != nil {
+		r.log.Error("failed to clear primary team", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if _, err = tx.Exec(ctx, setPrimaryTeamQuery, d.UserId, teamId); err != nil {
+		r.log.Error("failed to set primary team", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	if _, err = tx.Exec(ctx, syncUsersTeamNameQuery, []string{d.UserId}); err != nil {
+		r.log.Error("failed to update user team name", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	// Читаем пользователя повторно, чтобы вернуть актуальные данные
+	user := &domain.User{}
+	err = tx.QueryRow(ctx, selectUserQuery, d.UserId).Scan(
+		&user.Id,
+		&user.Name,
+		&user.TeamName,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.Teams,
+	)
+	if err != nil {
+		r.log.Error("failed to read user after primary team update", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("failed to commit primary team tx", zap.String("user_id", d.UserId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("primary team updated",
+		zap.String("user_id", d.UserId),
+		zap.String("team_name", user.TeamName),
+	)
+	// Ответ
+	return user, nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"go.uber.org/zap"
+)
+
+const (
+	insertWebhookQuery = `
+INSERT INTO webhooks (id, url, secret, event_types)
+VALUES ($1, $2, $3, $4)
+RETURNING id, url, secret, event_types, created_at;`
+
+	selectWebhooksQuery = `
+SELECT id, url, secret, event_types, created_at
+FROM webhooks
+ORDER BY created_at ASC, id ASC;`
+
+	deleteWebhookQuery = `
+DELETE FROM webhooks
+WHERE id = $1;`
+
+	selectDeliveriesQuery = `
+SELECT d.id, d.webhook_id, d.event_id, e.event_type, d.status, d.attempts,
+       d.next_attempt_at, d.last_error, d.delivered_at, d.created_at
+FROM webhook_deliveries d
+JOIN outbox_events e ON e.id = d.event_id
+WHERE ($1 = '' OR d.webhook_id = $1)
+  AND ($2 = '' OR d.status::text = $2)
+ORDER BY d.id DESC
+LIMIT $3;`
+
+	// Повторная отправка: доставка снова в очереди с нулевым счетчиком попыток
+	redeliverQuery = `
+UPDATE webhook_deliveries d
+SET status = 'PENDING', attempts = 0, next_attempt_at = NOW(), last_error = '', delivered_at = NULL
+FROM outbox_events e
+WHERE d.id = $1 AND e.id = d.event_id
+RETURNING d.id, d.webhook_id, d.event_id, e.event_type, d.status, d.attempts,
+          d.next_attempt_at, d.last_error, d.delivered_at, d.created_at;`
+
+	// Захват доставок, готовых к отправке. next_attempt_at сдвигается на время аренды,
+	// чтобы другие экземпляры сервиса не взяли ту же доставку, пока идет отправка
+	claimDeliveriesQuery = `
+WITH due AS (
+    SELECT id
+    FROM webhook_deliveries
+    WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at ASC, id ASC
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET attempts = d.attempts + 1,
+    next_attempt_at = NOW() + make_interval(secs => $2)
+FROM due, outbox_events e, webhooks w
+WHERE d.id = due.id AND e.id = d.event_id AND w.id = d.webhook_id
+RETURNING d.id, d.webhook_id, w.url, w.secret, d.attempts, e.id, e.event_type, e.payload, e.created_at;`
+
+	markDeliveredQuery = `
+UPDATE webhook_deliveries
+SET status = 'DELIVERED', delivered_at = NOW(), last_error = ''
+WHERE id = $1;`
+
+	markFailedQuery = `
+UPDATE webhook_deliveries
+SET status = $2::text::webhook_delivery_status,
+    next_attempt_at = NOW() + make_interval(secs => $3),
+    last_error = $4
+WHERE id = $1;`
+)
+
+type WebhookRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewWebhookRepository(db *pgxpool.Pool, log *zap.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, d *dto.CreateWebhookDTO) (*domain.Webhook, error) {
+	r.log.Info("create webhook",
+		zap.String("url", d.Url),
+		zap.Strings("event_types", d.EventTypes),
+	)
+
+	webhook := &domain.Webhook{}
+	err := r.db.QueryRow(ctx, insertWebhookQuery, uuid.NewString(), d.Url, d.Secret, d.EventTypes).Scan(
+		&webhook.Id,
+		&webhook.Url,
+		&webhook.Secret,
+		&webhook.EventTypes,
+		&webhook.CreatedAt,
+	)
+	if err != nil {
+		r.log.Error("failed to create webhook", zap.String("url", d.Url), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("webhook created", zap.String("webhook_id", webhook.Id))
+	// Ответ
+	return webhook, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	r.log.Debug("list webhooks")
+
+	rows, err := r.db.Query(ctx, selectWebhooksQuery)
+	if err != nil {
+		r.log.Error("failed to load webhooks", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*domain.Webhook, 0)
+	for rows.Next() {
+		webhook := &domain.Webhook{}
+		if err := rows.Scan(
+			&webhook.Id,
+			&webhook.Url,
+			&webhook.Secret,
+			&webhook.EventTypes,
+			&webhook.CreatedAt,
+		); err != nil {
+			r.log.Error("failed to scan webhook", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return webhooks, nil
+}
+
+// Delete удаляет вебхук вместе со всеми его доставками
+func (r *WebhookRepository) Delete(ctx context.Context, d *dto.DeleteWebhookDTO) error {
+	r.log.Info("delete webhook", zap.String("webhook_id", d.WebhookId))
+
+	cmdTag, err := r.db.Exec(ctx, deleteWebhookQuery, d.WebhookId)
+	if err != nil {
+		r.log.Error("failed to delete webhook", zap.String("webhook_id", d.WebhookId), zap.Error(err))
+		return handleDBError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Warn("webhook not found", zap.String("webhook_id", d.WebhookId))
+		return ErrNotFound
+	}
+
+	r.log.Info("webhook deleted", zap.String("webhook_id", d.WebhookId))
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, d *dto.ListDeliveriesDTO) ([]*domain.WebhookDelivery, error) {
+	r.log.Debug("list webhook deliveries",
+		zap.String("webhook_id", d.WebhookId),
+		zap.String("status", d.Status),
+	)
+
+	rows, err := r.db.Query(ctx, selectDeliveriesQuery, d.WebhookId, d.Status, d.Limit)
+	if err != nil {
+		r.log.Error("failed to load webhook deliveries", zap.String("webhook_id", d.WebhookId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*domain.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			r.log.Error("failed to scan webhook delivery", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return deliveries, nil
+}
+
+// Redeliver возвращает доставку в очередь, в том числе из dead-letter
+func (r *WebhookRepository) Redeliver(ctx context.Context, d *dto.RedeliverDTO) (*domain.WebhookDelivery, error) {
+	r.log.Info("redeliver webhook event", zap.Int64("delivery_id", d.DeliveryId))
+
+	delivery, err := scanDelivery(r.db.QueryRow(ctx, redeliverQuery, d.DeliveryId))
+	if err != nil {
+		r.log.Error("failed to requeue webhook delivery", zap.Int64("delivery_id", d.DeliveryId), zap.Error(err))
+		return nil, handleDBError(err)
+	}
+
+	r.log.Info("webhook delivery requeued",
+		zap.Int64("delivery_id", delivery.Id),
+		zap.String("webhook_id", delivery.WebhookId),
+	)
+	// Ответ
+	return delivery, nil
+}
+
+// ClaimDeliveries захватывает до limit доставок, готовых к отправке, на время lease
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*result.DeliveryJob, error) {
+	rows, err := r.db.Query(ctx, claimDeliveriesQuery, limit, lease.Seconds())
+	if err != nil {
+		r.log.Error("failed to claim webhook deliveries", zap.Error(err))
+		return nil, handleDBError(err)
+	}
+	defer rows.Close()
+
+	var jobs []*result.DeliveryJob
+	for rows.Next() {
+		job := &result.DeliveryJob{Event: &domain.OutboxEvent{}}
+		if err := rows.Scan(
+			&job.DeliveryId,
+			&job.WebhookId,
+			&job.Url,
+			&job.Secret,
+			&job.Attempt,
+			&job.Event.Id,
+			&job.Event.Type,
+			&job.Event.Payload,
+			&job.Event.CreatedAt,
+		); err != nil {
+			r.log.Error("failed to scan webhook delivery job", zap.Error(err))
+			return nil, handleDBError(err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleDBError(err)
+	}
+
+	// Ответ
+	return jobs, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, deliveryId int64) error {
+	if _, err := r.db.Exec(ctx, markDeliveredQuery, deliveryId); err != nil {
+		r.log.Error("failed to mark webhook delivery delivered", zap.Int64("delivery_id", deliveryId), zap.Error(err))
+		return handleDBError(err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) MarkFailed(ctx context.Context, d *dto.DeliveryFailureDTO) error {
+	_, err := r.db.Exec(ctx, markFailedQuery, d.DeliveryId, d.Status, d.RetryIn.Seconds(), d.Error)
+	if err != nil {
+		r.log.Error("failed to record webhook delivery failure", zap.Int64("delivery_id", d.DeliveryId), zap.Error(err))
+		return handleDBError(err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	delivery := &domain.WebhookDelivery{}
+	var deliveredAt sql.NullTime
+	err := row.Scan(
+		&delivery.Id,
+		&delivery.WebhookId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastError,
+		&deliveredAt,
+		&delivery.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return delivery, nil
+}
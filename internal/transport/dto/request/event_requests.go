@@ -0,0 +1,9 @@
+package request
+
+// EventStreamRequest фильтры потока событий необязательны, заданные фильтры объединяются по И.
+// LastEventId - идентификатор последнего полученного события для продолжения потока
+type EventStreamRequest struct {
+	TeamName    string `json:"team_name"`
+	UserId      string `json:"user_id"`
+	LastEventId string `json:"last_event_id"`
+}
@@ -0,0 +1,27 @@
+package request
+
+// SetIntegrationUserRequest external_id нужен только для GitLab: его API назначает ревьюеров по числовым id
+type SetIntegrationUserRequest struct {
+	Provider      string `json:"provider"`
+	ExternalLogin string `json:"external_login"`
+	ExternalId    string `json:"external_id"`
+	UserId        string `json:"user_id"`
+}
+
+// ListIntegrationUsersRequest пустой provider - сопоставления всех провайдеров
+type ListIntegrationUsersRequest struct {
+	Provider string `json:"provider"`
+}
+
+type DeleteIntegrationUserRequest struct {
+	Provider      string `json:"provider"`
+	ExternalLogin string `json:"external_login"`
+}
+
+// ProviderEventRequest событие от GitHub или GitLab. Тело передается без разбора,
+// потому что подпись считается по исходным байтам
+type ProviderEventRequest struct {
+	Event     string
+	Signature string
+	Body      []byte
+}
@@ -4,13 +4,66 @@ type CreateRequest struct {
 	PrId     string `json:"pull_request_id"`
 	PrName   string `json:"pull_request_name"`
 	AuthorId string `json:"author_id"`
+	// Черновик создается без ревьюеров
+	Draft bool `json:"draft"`
+	// Необязательные пути измененных файлов: один из ревьюеров выбирается из их владельцев по CODEOWNERS команды
+	ChangedPaths []string `json:"changed_paths"`
+	// Необязательные теги PR: предпочтение получают ревьюеры, у которых есть все эти теги
+	RequiredTags []string `json:"required_tags"`
+	// Необязательная команда автора, из которой подбираются ревьюеры. По умолчанию - основная команда
+	TeamName string `json:"team_name"`
+	// Среди ревьюеров обязательно должен быть лид команды автора
+	RequiresLeadReview bool `json:"requires_lead_review"`
 }
 
+// MergeRequest External выставляет интеграция с GitHub/GitLab: PR уже слит у провайдера,
+// поэтому проверка одобрений не применяется. Через API флаг не передается
 type MergeRequest struct {
-	PrId string `json:"pull_request_id"`
+	PrId     string `json:"pull_request_id"`
+	External bool   `json:"-"`
 }
 
 type ReassignRequest struct {
 	PrId      string `json:"pull_request_id"`
 	OldUserId string `json:"old_user_id"`
+	// Необязательная причина для журнала назначений
+	Reason string `json:"reason"`
+}
+
+type ClosePrRequest struct {
+	PrId string `json:"pull_request_id"`
+}
+
+type ReopenPrRequest struct {
+	PrId string `json:"pull_request_id"`
+}
+
+type MarkReadyRequest struct {
+	PrId string `json:"pull_request_id"`
+}
+
+type ReviewRequest struct {
+	PrId       string `json:"pull_request_id"`
+	ReviewerId string `json:"reviewer_id"`
+	Verdict    string `json:"verdict"`
+	Comment    string `json:"comment"`
+}
+
+type GetReviewsRequest struct {
+	PrId string `json:"pull_request_id"`
+}
+
+type GetHistoryRequest struct {
+	PrId string `json:"pull_request_id"`
+}
+
+// GetAssignmentExplainRequest UserId необязателен и оставляет подборы, в которых пользователь был кандидатом
+type GetAssignmentExplainRequest struct {
+	PrId   string `json:"pull_request_id"`
+	UserId string `json:"user_id"`
+}
+
+// GetOverdueRequest TeamName необязателен и оставляет PR авторов из этой команды
+type GetOverdueRequest struct {
+	TeamName string `json:"team_name"`
 }
@@ -2,11 +2,87 @@ package request
 
 import "github.com/niklvrr/AvitoInternship2025/internal/domain"
 
+// AddTeamRequest создает команду. Пользователи, уже состоящие в других командах, добавляются
+// только с allow_multi_team
 type AddTeamRequest struct {
+	TeamName       string         `json:"team_name"`
+	Members        []*domain.User `json:"members"`
+	AllowMultiTeam bool           `json:"allow_multi_team"`
+}
+
+type GetTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+type GetTeamSettingsRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+// UpdateTeamSettingsRequest частичное обновление: не переданные поля сохраняют текущее значение.
+// Пустые strategy и lead_user_id сбрасывают значение, пустой fallback_teams очищает цепочку
+type UpdateTeamSettingsRequest struct {
+	TeamName      string    `json:"team_name"`
+	ReviewerCount *int      `json:"reviewer_count"`
+	Strategy      *string   `json:"strategy"`
+	LeadUserId    *string   `json:"lead_user_id"`
+	RequireLead   *bool     `json:"require_lead"`
+	FallbackTeams *[]string `json:"fallback_teams"`
+	// Число одобрений, без которого PR нельзя смержить
+	RequiredApprovals *int `json:"required_approvals"`
+	// Назначать только ревьюеров, покрывающих теги PR, вместо предпочтения
+	RequireTags *bool `json:"require_tags"`
+	// Срок ревью в рабочих часах, 0 отключает отслеживание просрочки
+	ReviewSlaHours *int `json:"review_sla_hours"`
+	// notify, add_reviewer или reassign, пустое значение возвращает notify
+	SlaPolicy *string `json:"sla_policy"`
+}
+
+type GetCodeOwnersRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+// SetCodeOwnersRequest заменяет правила CODEOWNERS команды целиком. Content в формате файла CODEOWNERS,
+// владельцы указываются идентификаторами пользователей. Пустой Content удаляет все правила
+type SetCodeOwnersRequest struct {
+	TeamName string `json:"team_name"`
+	Content  string `json:"content"`
+}
+
+type DeactivateMembersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIds  []string `json:"user_ids"`
+}
+
+// AddTeamMembersRequest добавляет участников в существующую команду. Данные уже существующих
+// пользователей обновляются так же, как в /team/add
+type AddTeamMembersRequest struct {
 	TeamName string         `json:"team_name"`
 	Members  []*domain.User `json:"members"`
 }
 
-type GetTeamRequest struct {
+type RemoveTeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// SetTeamParentRequest пустой parent_team_name делает команду командой верхнего уровня
+type SetTeamParentRequest struct {
+	TeamName       string `json:"team_name"`
+	ParentTeamName string `json:"parent_team_name"`
+}
+
+// SetTeamMemberRoleRequest role - MEMBER или LEAD
+type SetTeamMemberRoleRequest struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+	Role     string `json:"role"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type DeleteTeamRequest struct {
 	TeamName string `json:"team_name"`
 }
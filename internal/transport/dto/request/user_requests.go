@@ -3,8 +3,86 @@ package request
 type SetIsActiveRequest struct {
 	UserId   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
+	// При деактивации переназначить открытые ревью пользователя на активных участников команды
+	ReassignReviews bool `json:"reassign_reviews"`
 }
 
 type GetReviewRequest struct {
 	UserId string `json:"user_id"`
 }
+
+// SetPrimaryTeamRequest делает одну из команд пользователя основной
+type SetPrimaryTeamRequest struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+// SetMaxOpenReviewsRequest null в max_open_reviews снимает ограничение
+type SetMaxOpenReviewsRequest struct {
+	UserId         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+// SetTagsRequest заменяет теги пользователя целиком, пустой список удаляет все теги
+type SetTagsRequest struct {
+	UserId string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+type GetTagsRequest struct {
+	UserId string `json:"user_id"`
+}
+
+// SetContactsRequest заменяет контакты пользователя целиком, пустая строка удаляет контакт.
+// Без digest_enabled сводка включена
+type SetContactsRequest struct {
+	UserId        string `json:"user_id"`
+	Email         string `json:"email"`
+	ChatHandle    string `json:"chat_handle"`
+	DigestEnabled *bool  `json:"digest_enabled"`
+}
+
+type GetContactsRequest struct {
+	UserId string `json:"user_id"`
+}
+
+// AddAvailabilityRequest период недоступности, время в формате RFC3339
+type AddAvailabilityRequest struct {
+	UserId   string `json:"user_id"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+	Reason   string `json:"reason"`
+}
+
+type ListAvailabilityRequest struct {
+	UserId string `json:"user_id"`
+}
+
+type DeleteAvailabilityRequest struct {
+	AvailabilityId string `json:"availability_id"`
+}
+
+// CreateUserRequest создает пользователя. is_active по умолчанию true, team_name необязателен
+type CreateUserRequest struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive *bool  `json:"is_active"`
+}
+
+type GetUserRequest struct {
+	UserId string `json:"user_id"`
+}
+
+// UpdateUserRequest меняет имя и/или активность пользователя, отсутствующие поля не меняются
+type UpdateUserRequest struct {
+	UserId   string  `json:"user_id"`
+	Username *string `json:"username"`
+	IsActive *bool   `json:"is_active"`
+}
+
+// ListUsersRequest фильтры списка пользователей, пустые значения не фильтруют
+type ListUsersRequest struct {
+	TeamName string `json:"team_name"`
+	IsActive string `json:"is_active"`
+}
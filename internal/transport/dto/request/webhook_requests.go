@@ -0,0 +1,24 @@
+package request
+
+// CreateWebhookRequest пустой secret - сервис сгенерирует его сам,
+// пустой event_types - подписка на все события
+type CreateWebhookRequest struct {
+	Url        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+type DeleteWebhookRequest struct {
+	WebhookId string `json:"webhook_id"`
+}
+
+// ListDeliveriesRequest все параметры необязательны, status=DEAD - dead-letter очередь
+type ListDeliveriesRequest struct {
+	WebhookId string `json:"webhook_id"`
+	Status    string `json:"status"`
+	Limit     string `json:"limit"`
+}
+
+type RedeliverRequest struct {
+	DeliveryId int64 `json:"delivery_id"`
+}
@@ -0,0 +1,21 @@
+package response
+
+type IntegrationUserResponse struct {
+	Provider      string `json:"provider"`
+	ExternalLogin string `json:"external_login"`
+	ExternalId    string `json:"external_id,omitempty"`
+	UserId        string `json:"user_id"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type ListIntegrationUsersResponse struct {
+	Users []IntegrationUserResponse `json:"users"`
+}
+
+// ProviderEventResponse результат обработки события провайдера. Status - processed или ignored,
+// ignored возвращается для неподдерживаемых событий и повторных доставок
+type ProviderEventResponse struct {
+	Status string `json:"status"`
+	Action string `json:"action,omitempty"`
+	PrId   string `json:"pull_request_id,omitempty"`
+}
@@ -8,6 +8,17 @@ type CreateResponse struct {
 	AssignedReviewers []string `json:"assigned_reviewers"`
 	CreatedAt         string   `json:"createdAt"`
 	MergedAt          *string  `json:"mergedAt,omitempty"`
+	RequiredTags      []string `json:"required_tags,omitempty"`
+	// PR требует ревью лида, LeadReviewer - назначенный лид
+	RequiresLeadReview bool   `json:"requires_lead_review"`
+	LeadReviewer       string `json:"lead_reviewer,omitempty"`
+	// Ревьюеры взяты из резервной команды, так как в команде автора кандидатов нет
+	CrossTeam    bool   `json:"cross_team"`
+	FallbackTeam string `json:"fallback_team,omitempty"`
+	// Команды, из которых добраны ревьюеры, когда в команде автора их не хватило
+	ParentTeams []string `json:"parent_teams,omitempty"`
+	// Предупреждения подбора, например если теги PR не покрыл ни один ревьюер
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 type MergeResponse struct {
@@ -29,4 +40,126 @@ type ReassignResponse struct {
 	ReplacedBy        string   `json:"replaced_by"`
 	CreatedAt         string   `json:"createdAt"`
 	MergedAt          *string  `json:"mergedAt,omitempty"`
+	// Лид PR с обязательным ревью лида после замены
+	LeadReviewer string `json:"lead_reviewer,omitempty"`
+	// Замена взята из резервной команды
+	CrossTeam    bool   `json:"cross_team"`
+	FallbackTeam string `json:"fallback_team,omitempty"`
+	// Замена взята из родительской команды
+	ParentTeam string `json:"parent_team,omitempty"`
+}
+
+// StatusChangeResponse ответ на close, reopen и markReady
+type StatusChangeResponse struct {
+	PrId              string   `json:"pull_request_id"`
+	PrName            string   `json:"pull_request_name"`
+	AuthorId          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	CreatedAt         string   `json:"createdAt"`
+	MergedAt          *string  `json:"mergedAt,omitempty"`
+	ClosedAt          *string  `json:"closedAt,omitempty"`
+	// Назначенный лид PR с обязательным ревью лида
+	LeadReviewer string `json:"lead_reviewer,omitempty"`
+	// Ревьюеры, назначенные при переводе в OPEN, взяты из резервной команды
+	CrossTeam    bool   `json:"cross_team"`
+	FallbackTeam string `json:"fallback_team,omitempty"`
+	// Команды, из которых добраны ревьюеры при переводе в OPEN
+	ParentTeams []string `json:"parent_teams,omitempty"`
+	// Предупреждения подбора ревьюеров при переводе в OPEN
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type ReviewResponse struct {
+	ReviewId   int64  `json:"review_id"`
+	PrId       string `json:"pull_request_id"`
+	ReviewerId string `json:"reviewer_id"`
+	Verdict    string `json:"verdict"`
+	Comment    string `json:"comment,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// ReviewStateResponse состояние ревью PR. Учитываются последние решающие вердикты
+// ревьюеров, назначенных на PR сейчас, а в reviews отдается вся история
+type ReviewStateResponse struct {
+	PrId               string           `json:"pull_request_id"`
+	Status             string           `json:"status"`
+	Approvals          int              `json:"approvals"`
+	RequiredApprovals  int              `json:"required_approvals"`
+	Approved           bool             `json:"approved"`
+	ChangesRequestedBy []string         `json:"changes_requested_by"`
+	Reviews            []ReviewResponse `json:"reviews"`
+}
+
+type SubmitReviewResponse struct {
+	Review *ReviewResponse      `json:"review"`
+	State  *ReviewStateResponse `json:"state"`
+}
+
+type AssignmentEventResponse struct {
+	EventId        int64  `json:"event_id"`
+	Type           string `json:"type"`
+	UserId         string `json:"user_id"`
+	PreviousUserId string `json:"previous_user_id,omitempty"`
+	Actor          string `json:"actor"`
+	Reason         string `json:"reason"`
+	RequestId      string `json:"request_id,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+}
+
+type HistoryResponse struct {
+	PrId   string                    `json:"pull_request_id"`
+	Events []AssignmentEventResponse `json:"events"`
+}
+
+type ExplainedCandidateResponse struct {
+	UserId         string `json:"user_id"`
+	OpenReviews    int    `json:"open_reviews"`
+	RecentPairings int    `json:"recent_pairings"`
+	Lead           bool   `json:"lead,omitempty"`
+	CodeOwner      bool   `json:"code_owner,omitempty"`
+	CoversTags     bool   `json:"covers_tags,omitempty"`
+	Excluded       string `json:"excluded,omitempty"`
+	Selected       bool   `json:"selected"`
+}
+
+type ExplainedPoolResponse struct {
+	TeamName   string                       `json:"team_name"`
+	Strategy   string                       `json:"strategy"`
+	Candidates []ExplainedCandidateResponse `json:"candidates"`
+}
+
+// AssignmentExplanationResponse seed передается строкой: int64 не помещается в число JavaScript
+type AssignmentExplanationResponse struct {
+	ExplanationId int64                   `json:"explanation_id"`
+	Reason        string                  `json:"reason"`
+	TeamName      string                  `json:"team_name,omitempty"`
+	Seed          int64                   `json:"seed,string"`
+	Reviewers     []string                `json:"reviewers"`
+	Pools         []ExplainedPoolResponse `json:"pools"`
+	CreatedAt     string                  `json:"createdAt"`
+}
+
+type AssignmentExplainResponse struct {
+	PrId         string                          `json:"pull_request_id"`
+	Explanations []AssignmentExplanationResponse `json:"explanations"`
+}
+
+// OverdueReviewResponse ревью, по которому ревьюер не оставил вердикт до dueAt.
+// escalatedAt заполнен, если просрочка уже эскалирована по политике команды
+type OverdueReviewResponse struct {
+	PrId        string  `json:"pull_request_id"`
+	PrName      string  `json:"pull_request_name"`
+	AuthorId    string  `json:"author_id"`
+	ReviewerId  string  `json:"reviewer_id"`
+	TeamName    string  `json:"team_name"`
+	SlaHours    int     `json:"review_sla_hours"`
+	SlaPolicy   string  `json:"sla_policy"`
+	AssignedAt  string  `json:"assignedAt"`
+	DueAt       string  `json:"dueAt"`
+	EscalatedAt *string `json:"escalatedAt,omitempty"`
+}
+
+type OverdueResponse struct {
+	Reviews []OverdueReviewResponse `json:"reviews"`
 }
@@ -4,6 +4,10 @@ type UserStat struct {
 	UserId      string `json:"user_id"`
 	Username    string `json:"username"`
 	Assignments int    `json:"assignments"`
+	// Текущая нагрузка относительно предела, max_open_reviews равен null без ограничения
+	OpenReviews    int  `json:"open_reviews"`
+	MaxOpenReviews *int `json:"max_open_reviews"`
+	AtCapacity     bool `json:"at_capacity"`
 }
 
 type PrStat struct {
@@ -12,8 +16,27 @@ type PrStat struct {
 	ReviewersCount int    `json:"reviewers_count"`
 }
 
+// PairStat ячейка матрицы автор -> ревьюер: сколько PR автора ревьюил ревьюер
+type PairStat struct {
+	AuthorId   string `json:"author_id"`
+	ReviewerId string `json:"reviewer_id"`
+	Reviews    int    `json:"reviews"`
+}
+
+// TeamStat сводка по команде вместе со всеми ее подкомандами. Пользователь из нескольких команд
+// поддерева учитывается один раз, member_count считает только прямых участников
+type TeamStat struct {
+	TeamName         string `json:"team_name"`
+	ParentTeamName   string `json:"parent_team_name,omitempty"`
+	MemberCount      int    `json:"member_count"`
+	TotalMemberCount int    `json:"total_member_count"`
+	Assignments      int    `json:"assignments"`
+	OpenReviews      int    `json:"open_reviews"`
+}
+
 type StatsResponse struct {
 	Users []UserStat `json:"users"`
 	PRs   []PrStat   `json:"prs"`
+	Pairs []PairStat `json:"pairs"`
+	Teams []TeamStat `json:"teams"`
 }
-
@@ -2,12 +2,89 @@ package response
 
 import "github.com/niklvrr/AvitoInternship2025/internal/domain"
 
+// AddTeamResponse созданная команда. IgnoredUserIds - существующие пользователи, чьи username
+// и is_active из запроса не применены
 type AddTeamResponse struct {
-	TeamName string         `json:"team_name"`
-	Members  []*domain.User `json:"members"`
+	TeamName       string         `json:"team_name"`
+	Members        []*domain.User `json:"members"`
+	IgnoredUserIds []string       `json:"ignored_user_ids"`
 }
 
+// GetTeamResponse команда с участниками. ParentTeamName и Tree заполняются только при чтении
+// команды вместе с иерархией, пустой ParentTeamName - команда верхнего уровня
+// IgnoredUserIds заполняется только в ответе /team/addMembers
 type GetTeamResponse struct {
-	TeamName string         `json:"team_name"`
-	Members  []*domain.User `json:"members"`
+	TeamName       string         `json:"team_name"`
+	Members        []*domain.User `json:"members"`
+	ParentTeamName string         `json:"parent_team_name,omitempty"`
+	Tree           *TeamTreeNode  `json:"tree,omitempty"`
+	IgnoredUserIds []string       `json:"ignored_user_ids,omitempty"`
+}
+
+// TeamTreeNode команда в дереве подкоманд. В total_member_count входят участники всего поддерева,
+// пользователь из нескольких его команд учитывается один раз
+type TeamTreeNode struct {
+	TeamName         string          `json:"team_name"`
+	MemberCount      int             `json:"member_count"`
+	TotalMemberCount int             `json:"total_member_count"`
+	Subteams         []*TeamTreeNode `json:"subteams"`
+}
+
+type TeamSettingsResponse struct {
+	TeamName      string `json:"team_name"`
+	ReviewerCount int    `json:"reviewer_count"`
+	Strategy      string `json:"strategy,omitempty"`
+	LeadUserId    string `json:"lead_user_id,omitempty"`
+	RequireLead   bool   `json:"require_lead"`
+	// Команды, из которых берутся ревьюеры, если в своей команде кандидатов нет
+	FallbackTeams []string `json:"fallback_teams"`
+	// Число одобрений, без которого PR нельзя смержить
+	RequiredApprovals int `json:"required_approvals"`
+	// Ревьюеры, покрывающие теги PR, обязательны, а не предпочтительны
+	RequireTags bool `json:"require_tags"`
+	// Срок ревью в рабочих часах, 0 - без срока
+	ReviewSlaHours int `json:"review_sla_hours"`
+	// Что делать с просроченным ревью
+	SlaPolicy string `json:"sla_policy"`
+}
+
+type CodeOwnerRuleResponse struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+// CodeOwnersResponse правила CODEOWNERS команды в порядке файла
+type CodeOwnersResponse struct {
+	TeamName string                  `json:"team_name"`
+	Rules    []CodeOwnerRuleResponse `json:"rules"`
+}
+
+// Итог обработки открытого ревью при массовой деактивации
+const (
+	ReviewOutcomeReassigned  = "REASSIGNED"
+	ReviewOutcomeNoCandidate = "NO_CANDIDATE"
+	ReviewOutcomeSkipped     = "SKIPPED"
+)
+
+type DeactivateMembersResponse struct {
+	TeamName       string          `json:"team_name"`
+	DeactivatedIds []string        `json:"deactivated_user_ids"`
+	Reviews        []ReviewOutcome `json:"reviews"`
+}
+
+// ReviewOutcome результат для одного ревью выключенного пользователя.
+// SKIPPED означает, что PR перестал быть открытым или ревьюер был снят до применения
+type ReviewOutcome struct {
+	PrId          string `json:"pull_request_id"`
+	OldReviewerId string `json:"old_reviewer_id"`
+	Status        string `json:"status"`
+	ReplacedBy    string `json:"replaced_by,omitempty"`
+}
+
+// RemoveTeamMembersResponse итог исключения участников: пользователи, покинувшие команду,
+// и переназначение их открытых ревью
+type RemoveTeamMembersResponse struct {
+	TeamName   string          `json:"team_name"`
+	RemovedIds []string        `json:"removed_user_ids"`
+	Reviews    []ReviewOutcome `json:"reviews"`
 }
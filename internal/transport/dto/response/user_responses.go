@@ -6,10 +6,71 @@ type SetIsActiveResponse struct {
 	UserId   string `json:"user_id"`
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	// Все команды пользователя, основная первой
+	Teams    []string `json:"teams"`
+	IsActive bool     `json:"is_active"`
+	// Результат переназначения ревью, заполняется только при деактивации с reassign_reviews
+	Reassigned     []ReviewReassignment `json:"-"`
+	NoCandidatePrs []string             `json:"-"`
+}
+
+type ReviewReassignment struct {
+	PrId       string `json:"pull_request_id"`
+	ReplacedBy string `json:"replaced_by"`
 }
 
 type GetReviewResponse struct {
 	UserId string       `json:"user_id"`
 	Prs    []*domain.Pr `json:"pull_requests"`
 }
+
+type SetMaxOpenReviewsResponse struct {
+	UserId         string   `json:"user_id"`
+	Username       string   `json:"username"`
+	TeamName       string   `json:"team_name"`
+	Teams          []string `json:"teams"`
+	IsActive       bool     `json:"is_active"`
+	MaxOpenReviews *int     `json:"max_open_reviews"`
+}
+
+// UserResponse пользователь с основной командой в team_name и всеми командами в teams
+type UserResponse struct {
+	UserId   string   `json:"user_id"`
+	Username string   `json:"username"`
+	TeamName string   `json:"team_name"`
+	Teams    []string `json:"teams"`
+	IsActive bool     `json:"is_active"`
+}
+
+type UserTagsResponse struct {
+	UserId string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+type UserContactsResponse struct {
+	UserId        string `json:"user_id"`
+	Email         string `json:"email"`
+	ChatHandle    string `json:"chat_handle"`
+	DigestEnabled bool   `json:"digest_enabled"`
+	// Пусто, если контакты не задавались
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+type AvailabilityResponse struct {
+	AvailabilityId string `json:"availability_id"`
+	UserId         string `json:"user_id"`
+	StartsAt       string `json:"starts_at"`
+	EndsAt         string `json:"ends_at"`
+	Reason         string `json:"reason"`
+	// Период идет прямо сейчас, пользователь не назначается ревьюером
+	Current bool `json:"current"`
+}
+
+type ListAvailabilityResponse struct {
+	UserId  string                 `json:"user_id"`
+	Periods []AvailabilityResponse `json:"periods"`
+}
+
+type ListUsersResponse struct {
+	Users []*UserResponse `json:"users"`
+}
@@ -0,0 +1,31 @@
+package response
+
+// WebhookResponse секрет возвращается только при создании вебхука
+type WebhookResponse struct {
+	WebhookId  string   `json:"webhook_id"`
+	Url        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+	CreatedAt  string   `json:"createdAt"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type WebhookDeliveryResponse struct {
+	DeliveryId    int64   `json:"delivery_id"`
+	WebhookId     string  `json:"webhook_id"`
+	EventId       int64   `json:"event_id"`
+	EventType     string  `json:"event_type"`
+	Status        string  `json:"status"`
+	Attempts      int     `json:"attempts"`
+	NextAttemptAt string  `json:"next_attempt_at"`
+	LastError     string  `json:"last_error"`
+	DeliveredAt   *string `json:"delivered_at"`
+	CreatedAt     string  `json:"createdAt"`
+}
+
+type ListDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
@@ -48,16 +48,30 @@ func mapErrorCodeToHTTPStatus(code string) int {
 	switch code {
 	case "TEAM_EXISTS":
 		return http.StatusBadRequest // 400
+	case "INVALID_INPUT":
+		return http.StatusBadRequest // 400
 	case "PR_EXISTS":
 		return http.StatusConflict // 409
+	case "USER_EXISTS":
+		return http.StatusConflict // 409
+	case "USER_IN_TEAM":
+		return http.StatusConflict // 409
 	case "PR_MERGED":
 		return http.StatusConflict // 409
+	case "NOT_APPROVED":
+		return http.StatusConflict // 409
+	case "PR_CLOSED":
+		return http.StatusConflict // 409
+	case "INVALID_TRANSITION":
+		return http.StatusConflict // 409
 	case "NOT_ASSIGNED":
 		return http.StatusConflict // 409
 	case "NO_CANDIDATE":
 		return http.StatusConflict // 409
 	case "NOT_FOUND":
 		return http.StatusNotFound // 404
+	case "UNAUTHORIZED":
+		return http.StatusUnauthorized // 401
 	default:
 		return http.StatusInternalServerError // 500
 	}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/usecase/service"
+	"go.uber.org/zap"
+)
+
+const (
+	// Интервал комментариев-пингов, чтобы прокси не закрывали простаивающее соединение
+	defaultHeartbeatInterval = 15 * time.Second
+	// Пауза перед переподключением, которую рекомендуем клиенту
+	eventStreamRetry = 3 * time.Second
+	// Событие для клиента: часть пропущенных событий потеряна, состояние нужно перечитать
+	eventStreamReset = "reset"
+)
+
+type EventStreamService interface {
+	Subscribe(ctx context.Context, req *request.EventStreamRequest) (*service.EventStream, error)
+}
+
+type EventsHandler struct {
+	svc       EventStreamService
+	heartbeat time.Duration
+	log       *zap.Logger
+}
+
+func NewEventsHandler(svc EventStreamService, log *zap.Logger) *EventsHandler {
+	return &EventsHandler{
+		svc:       svc,
+		heartbeat: defaultHeartbeatInterval,
+		log:       log,
+	}
+}
+
+// Stream отдает события о назначениях по SSE до отключения клиента или остановки сервера
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("eventStream request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Браузерный EventSource передает Last-Event-ID заголовком, query-параметр - для остальных клиентов
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = r.URL.Query().Get("last_event_id")
+	}
+	req := request.EventStreamRequest{
+		TeamName:    r.URL.Query().Get("team_name"),
+		UserId:      r.URL.Query().Get("user_id"),
+		LastEventId: lastEventId,
+	}
+
+	// Вызов сервиса
+	stream, err := h.svc.Subscribe(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to subscribe to events", zap.String("team_name", req.TeamName), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+	defer stream.Close()
+
+	// Поток бессрочный: снимаем дедлайн записи сервера
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.log.Debug("write deadline is not supported", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", eventStreamRetry.Milliseconds())
+	if !stream.Complete {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventStreamReset)
+	}
+	for _, event := range stream.Replay {
+		if err := writeEvent(w, event); err != nil {
+			h.log.Error("failed to write event", zap.Uint64("event_id", event.Id), zap.Error(err))
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		h.log.Error("failed to flush event stream", zap.Error(err))
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			h.log.Info("event stream closed by client")
+			return
+		case event, ok := <-stream.Events():
+			if !ok {
+				// Подписка закрыта шиной: сервер останавливается или клиент не успевал читать
+				h.log.Info("event stream closed by server")
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				h.log.Error("failed to write event", zap.Uint64("event_id", event.Id), zap.Error(err))
+				return
+			}
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event *domain.ActivityEvent) error {
+	data, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", strconv.FormatUint(event.Id, 10), event.Type, data)
+	return err
+}
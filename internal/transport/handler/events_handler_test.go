@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/usecase/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stubTeamMembers состав команд для тестов потока событий
+type stubTeamMembers map[string][]string
+
+func (s stubTeamMembers) Get(ctx context.Context, d *dto.GetTeamDTO) (*result.GetTeamResult, error) {
+	ids, ok := s[d.TeamName]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	res := &result.GetTeamResult{TeamName: d.TeamName}
+	for _, id := range ids {
+		res.Members = append(res.Members, &domain.User{Id: id})
+	}
+	return res, nil
+}
+
+func newTestEventsHandler(bus *service.EventBus) *EventsHandler {
+	svc := service.NewEventStreamService(bus, stubTeamMembers{"backend": {"u1", "u2"}}, zap.NewNop())
+	return NewEventsHandler(svc, zap.NewNop())
+}
+
+func TestEventsHandler_Stream_ReplayAndLive(t *testing.T) {
+	bus := service.NewEventBus(10, 10, zap.NewNop())
+	handler := newTestEventsHandler(bus)
+
+	first := &domain.ActivityEvent{Type: domain.EventPrCreated, UserIds: []string{"u1"}}
+	bus.Publish(first)
+	bus.Publish(&domain.ActivityEvent{
+		Type:    domain.EventReviewerAssigned,
+		UserIds: []string{"u1", "u2"},
+		Payload: &domain.ReviewerEventPayload{PrId: "pr1", UserId: "u2"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events/stream?team_name=backend", nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(first.Id, 10))
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.Stream(w, req)
+	}()
+
+	// Живое событие другой команды не приходит, затем шина останавливается
+	time.Sleep(50 * time.Millisecond)
+	bus.Publish(&domain.ActivityEvent{Type: domain.EventPrCreated, UserIds: []string{"u9"}})
+	bus.Close()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream did not stop after bus close")
+	}
+
+	body := w.Body.String()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, body, "id: "+strconv.FormatUint(first.Id+1, 10)+"\nevent: reviewer.assigned\n")
+	assert.Contains(t, body, `"user_id":"u2"`)
+	assert.NotContains(t, body, "event: reset")
+	assert.Equal(t, 1, strings.Count(body, "event: "))
+}
+
+func TestEventsHandler_Stream_ResetOnGap(t *testing.T) {
+	bus := service.NewEventBus(10, 10, zap.NewNop())
+	handler := newTestEventsHandler(bus)
+	bus.Publish(&domain.ActivityEvent{Type: domain.EventPrCreated, UserIds: []string{"u1"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/events/stream?last_event_id=1", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.Stream(w, req)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Contains(t, w.Body.String(), "event: reset\n")
+}
+
+func TestEventsHandler_Stream_TeamNotFound(t *testing.T) {
+	handler := newTestEventsHandler(service.NewEventBus(10, 10, zap.NewNop()))
+
+	req := httptest.NewRequest(http.MethodGet, "/events/stream?team_name=missing", nil)
+	w := httptest.NewRecorder()
+
+	handler.Stream(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
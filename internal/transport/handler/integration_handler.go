@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+// Ограничение тела события провайдера
+const maxProviderEventSize = 5 << 20
+
+type IntegrationService interface {
+	SetUser(ctx context.Context, req *request.SetIntegrationUserRequest) (*response.IntegrationUserResponse, error)
+	ListUsers(ctx context.Context, req *request.ListIntegrationUsersRequest) (*response.ListIntegrationUsersResponse, error)
+	DeleteUser(ctx context.Context, req *request.DeleteIntegrationUserRequest) error
+	HandleGitHub(ctx context.Context, req *request.ProviderEventRequest) (*response.ProviderEventResponse, error)
+	HandleGitLab(ctx context.Context, req *request.ProviderEventRequest) (*response.ProviderEventResponse, error)
+}
+
+type IntegrationHandler struct {
+	svc IntegrationService
+	log *zap.Logger
+}
+
+func NewIntegrationHandler(svc IntegrationService, log *zap.Logger) *IntegrationHandler {
+	return &IntegrationHandler{
+		svc: svc,
+		log: log,
+	}
+}
+
+func (h *IntegrationHandler) SetUser(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setIntegrationUser request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetIntegrationUserRequest
+	var req request.SetIntegrationUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetUser(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set integration user",
+			zap.String("provider", req.Provider),
+			zap.String("external_login", req.ExternalLogin),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"user": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *IntegrationHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("listIntegrationUsers request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	req := request.ListIntegrationUsersRequest{
+		Provider: r.URL.Query().Get("provider"),
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.ListUsers(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to list integration users", zap.String("provider", req.Provider), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *IntegrationHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("deleteIntegrationUser request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель DeleteIntegrationUserRequest
+	var req request.DeleteIntegrationUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	if err := h.svc.DeleteUser(r.Context(), &req); err != nil {
+		h.log.Error("failed to delete integration user", zap.String("external_login", req.ExternalLogin), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"provider":       req.Provider,
+		"external_login": req.ExternalLogin,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GitHubWebhook принимает события GitHub, подпись в заголовке X-Hub-Signature-256
+func (h *IntegrationHandler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	h.providerEvent(w, r, "X-GitHub-Event", "X-Hub-Signature-256", h.svc.HandleGitHub)
+}
+
+// GitLabWebhook принимает события GitLab, секрет в заголовке X-Gitlab-Token
+func (h *IntegrationHandler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	h.providerEvent(w, r, "X-Gitlab-Event", "X-Gitlab-Token", h.svc.HandleGitLab)
+}
+
+func (h *IntegrationHandler) providerEvent(
+	w http.ResponseWriter,
+	r *http.Request,
+	eventHeader, signatureHeader string,
+	handle func(context.Context, *request.ProviderEventRequest) (*response.ProviderEventResponse, error),
+) {
+	h.log.Info("provider event received",
+		zap.String("path", r.URL.Path),
+		zap.String("event", r.Header.Get(eventHeader)),
+	)
+
+	// Читаем тело целиком: подпись считается по исходным байтам
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxProviderEventSize))
+	if err != nil {
+		h.log.Error("failed to read provider event body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := handle(r.Context(), &request.ProviderEventRequest{
+		Event:     r.Header.Get(eventHeader),
+		Signature: r.Header.Get(signatureHeader),
+		Body:      body,
+	})
+	if err != nil {
+		h.log.Error("failed to handle provider event", zap.String("path", r.URL.Path), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("provider event handled",
+		zap.String("status", resp.Status),
+		zap.String("pr_id", resp.PrId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"github.com/niklvrr/AvitoInternship2025/internal/usecase/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockIntegrationService мок сервиса для тестов
+type MockIntegrationService struct {
+	mock.Mock
+}
+
+func (m *MockIntegrationService) SetUser(ctx context.Context, req *request.SetIntegrationUserRequest) (*response.IntegrationUserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.IntegrationUserResponse), args.Error(1)
+}
+
+func (m *MockIntegrationService) ListUsers(ctx context.Context, req *request.ListIntegrationUsersRequest) (*response.ListIntegrationUsersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ListIntegrationUsersResponse), args.Error(1)
+}
+
+func (m *MockIntegrationService) DeleteUser(ctx context.Context, req *request.DeleteIntegrationUserRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockIntegrationService) HandleGitHub(ctx context.Context, req *request.ProviderEventRequest) (*response.ProviderEventResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ProviderEventResponse), args.Error(1)
+}
+
+func (m *MockIntegrationService) HandleGitLab(ctx context.Context, req *request.ProviderEventRequest) (*response.ProviderEventResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ProviderEventResponse), args.Error(1)
+}
+
+func TestIntegrationHandler_GitHubWebhook_PassesRawBody(t *testing.T) {
+	mockService := new(MockIntegrationService)
+	handler := NewIntegrationHandler(mockService, zap.NewNop())
+
+	body := `{"action":"opened"}`
+	mockService.On("HandleGitHub", mock.Anything, &request.ProviderEventRequest{
+		Event:     "pull_request",
+		Signature: "sha256=abc",
+		Body:      []byte(body),
+	}).Return(&response.ProviderEventResponse{Status: "processed", Action: "open", PrId: "github:acme/api#1"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/integrations/github/webhook", bytes.NewBufferString(body))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature-256", "sha256=abc")
+	w := httptest.NewRecorder()
+
+	handler.GitHubWebhook(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp response.ProviderEventResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "github:acme/api#1", resp.PrId)
+	mockService.AssertExpectations(t)
+}
+
+func TestIntegrationHandler_GitLabWebhook_InvalidToken(t *testing.T) {
+	mockService := new(MockIntegrationService)
+	handler := NewIntegrationHandler(mockService, zap.NewNop())
+
+	mockService.On("HandleGitLab", mock.Anything, mock.MatchedBy(func(req *request.ProviderEventRequest) bool {
+		return req.Signature == "wrong" && req.Event == "Merge Request Hook"
+	})).Return(nil, service.ErrInvalidSignature)
+
+	req := httptest.NewRequest(http.MethodPost, "/integrations/gitlab/webhook", bytes.NewBufferString(`{}`))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", "wrong")
+	w := httptest.NewRecorder()
+
+	handler.GitLabWebhook(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
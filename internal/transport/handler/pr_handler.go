@@ -14,6 +14,14 @@ type PrService interface {
 	Create(ctx context.Context, req *request.CreateRequest) (*response.CreateResponse, error)
 	Merge(ctx context.Context, req *request.MergeRequest) (*response.MergeResponse, error)
 	Reassign(ctx context.Context, req *request.ReassignRequest) (*response.ReassignResponse, error)
+	Close(ctx context.Context, req *request.ClosePrRequest) (*response.StatusChangeResponse, error)
+	Reopen(ctx context.Context, req *request.ReopenPrRequest) (*response.StatusChangeResponse, error)
+	MarkReady(ctx context.Context, req *request.MarkReadyRequest) (*response.StatusChangeResponse, error)
+	Review(ctx context.Context, req *request.ReviewRequest) (*response.SubmitReviewResponse, error)
+	GetReviews(ctx context.Context, req *request.GetReviewsRequest) (*response.ReviewStateResponse, error)
+	GetHistory(ctx context.Context, req *request.GetHistoryRequest) (*response.HistoryResponse, error)
+	GetAssignmentExplain(ctx context.Context, req *request.GetAssignmentExplainRequest) (*response.AssignmentExplainResponse, error)
+	GetOverdue(ctx context.Context, req *request.GetOverdueRequest) (*response.OverdueResponse, error)
 	GetStats(ctx context.Context) (*response.StatsResponse, error)
 }
 
@@ -157,3 +165,297 @@ func (h *PrHandler) ReassignPr(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+func (h *PrHandler) ClosePr(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("closePr request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель ClosePrRequest
+	var req request.ClosePrRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Close(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to close PR",
+			zap.String("pr_id", req.PrId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("PR closed successfully",
+		zap.String("pr_id", resp.PrId),
+		zap.String("status", resp.Status),
+	)
+
+	response := map[string]interface{}{
+		"pr": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PrHandler) ReopenPr(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("reopenPr request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель ReopenPrRequest
+	var req request.ReopenPrRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Reopen(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to reopen PR",
+			zap.String("pr_id", req.PrId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("PR reopened successfully",
+		zap.String("pr_id", resp.PrId),
+		zap.String("status", resp.Status),
+	)
+
+	response := map[string]interface{}{
+		"pr": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PrHandler) MarkReadyPr(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("markReadyPr request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель MarkReadyRequest
+	var req request.MarkReadyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.MarkReady(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to mark ready PR",
+			zap.String("pr_id", req.PrId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("PR marked ready successfully",
+		zap.String("pr_id", resp.PrId),
+		zap.String("status", resp.Status),
+	)
+
+	response := map[string]interface{}{
+		"pr": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PrHandler) ReviewPr(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("reviewPr request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель ReviewRequest
+	var req request.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Review(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to review PR",
+			zap.String("pr_id", req.PrId),
+			zap.String("reviewer_id", req.ReviewerId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("PR review submitted successfully",
+		zap.String("pr_id", resp.Review.PrId),
+		zap.String("verdict", resp.Review.Verdict),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *PrHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getReviews request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем pull_request_id из query параметров
+	req := request.GetReviewsRequest{
+		PrId: r.URL.Query().Get("pull_request_id"),
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.GetReviews(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get PR reviews",
+			zap.String("pr_id", req.PrId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("PR reviews retrieved",
+		zap.String("pr_id", resp.PrId),
+		zap.Int("reviews_count", len(resp.Reviews)),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *PrHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getHistory request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем pull_request_id из query параметров
+	req := request.GetHistoryRequest{
+		PrId: r.URL.Query().Get("pull_request_id"),
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.GetHistory(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get PR history",
+			zap.String("pr_id", req.PrId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("PR history retrieved",
+		zap.String("pr_id", resp.PrId),
+		zap.Int("events_count", len(resp.Events)),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *PrHandler) GetAssignmentExplain(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getAssignmentExplain request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем pull_request_id и необязательный user_id из query параметров
+	req := request.GetAssignmentExplainRequest{
+		PrId:   r.URL.Query().Get("pull_request_id"),
+		UserId: r.URL.Query().Get("user_id"),
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.GetAssignmentExplain(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get assignment explanations",
+			zap.String("pr_id", req.PrId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("assignment explanations retrieved",
+		zap.String("pr_id", resp.PrId),
+		zap.Int("explanations_count", len(resp.Explanations)),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *PrHandler) GetOverdue(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getOverdue request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем необязательный team_name из query параметров
+	req := request.GetOverdueRequest{
+		TeamName: r.URL.Query().Get("team_name"),
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.GetOverdue(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get overdue reviews",
+			zap.String("team_name", req.TeamName),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("overdue reviews retrieved",
+		zap.String("team_name", req.TeamName),
+		zap.Int("reviews_count", len(resp.Reviews)),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
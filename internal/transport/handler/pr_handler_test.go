@@ -46,6 +46,70 @@ func (m *MockPrService) Reassign(ctx context.Context, req *request.ReassignReque
 	return args.Get(0).(*response.ReassignResponse), args.Error(1)
 }
 
+func (m *MockPrService) Close(ctx context.Context, req *request.ClosePrRequest) (*response.StatusChangeResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.StatusChangeResponse), args.Error(1)
+}
+
+func (m *MockPrService) Reopen(ctx context.Context, req *request.ReopenPrRequest) (*response.StatusChangeResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.StatusChangeResponse), args.Error(1)
+}
+
+func (m *MockPrService) MarkReady(ctx context.Context, req *request.MarkReadyRequest) (*response.StatusChangeResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.StatusChangeResponse), args.Error(1)
+}
+
+func (m *MockPrService) Review(ctx context.Context, req *request.ReviewRequest) (*response.SubmitReviewResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.SubmitReviewResponse), args.Error(1)
+}
+
+func (m *MockPrService) GetReviews(ctx context.Context, req *request.GetReviewsRequest) (*response.ReviewStateResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ReviewStateResponse), args.Error(1)
+}
+
+func (m *MockPrService) GetHistory(ctx context.Context, req *request.GetHistoryRequest) (*response.HistoryResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.HistoryResponse), args.Error(1)
+}
+
+func (m *MockPrService) GetAssignmentExplain(ctx context.Context, req *request.GetAssignmentExplainRequest) (*response.AssignmentExplainResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.AssignmentExplainResponse), args.Error(1)
+}
+
+func (m *MockPrService) GetOverdue(ctx context.Context, req *request.GetOverdueRequest) (*response.OverdueResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.OverdueResponse), args.Error(1)
+}
+
 func (m *MockPrService) GetStats(ctx context.Context) (*response.StatsResponse, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -146,6 +210,31 @@ func TestPrHandler_CreatePr_PrExists(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestPrHandler_CreatePr_NoLeadCandidate(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(r *request.CreateRequest) bool {
+		return r.RequiresLeadReview
+	})).Return(nil, service.WrapError(service.ErrNoLeadCandidate, nil))
+
+	body := []byte(`{"pull_request_id":"pr1","pull_request_name":"Test PR","author_id":"author1","requires_lead_review":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreatePr(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	errorObj := result["error"].(map[string]interface{})
+	assert.Equal(t, "NO_CANDIDATE", errorObj["code"])
+	mockService.AssertExpectations(t)
+}
+
 func TestPrHandler_MergePr_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockService := new(MockPrService)
@@ -382,3 +471,360 @@ func TestPrHandler_ReassignPr_NotFound(t *testing.T) {
 	assert.Contains(t, result, "error")
 	mockService.AssertExpectations(t)
 }
+
+func TestPrHandler_ClosePr_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	closedAt := time.Now().Format(time.RFC3339)
+	expectedResp := &response.StatusChangeResponse{
+		PrId:              "pr1",
+		PrName:            "Test PR",
+		AuthorId:          "author1",
+		Status:            "CLOSED",
+		AssignedReviewers: []string{"reviewer1"},
+		CreatedAt:         time.Now().Format(time.RFC3339),
+		ClosedAt:          &closedAt,
+	}
+
+	mockService.On("Close", mock.Anything, mock.MatchedBy(func(r *request.ClosePrRequest) bool {
+		return r.PrId == "pr1"
+	})).Return(expectedResp, nil)
+
+	body, _ := json.Marshal(request.ClosePrRequest{PrId: "pr1"})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ClosePr(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	pr := result["pr"].(map[string]interface{})
+	assert.Equal(t, "CLOSED", pr["status"])
+	assert.Equal(t, closedAt, pr["closedAt"])
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_ReopenPr_InvalidTransition(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	mockService.On("Reopen", mock.Anything, mock.Anything).Return(nil, service.WrapError(service.ErrInvalidTransition, nil))
+
+	body, _ := json.Marshal(request.ReopenPrRequest{PrId: "pr1"})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/reopen", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ReopenPr(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var result ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "INVALID_TRANSITION", result.Error.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_MarkReadyPr_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	expectedResp := &response.StatusChangeResponse{
+		PrId:              "pr1",
+		PrName:            "Test PR",
+		AuthorId:          "author1",
+		Status:            "OPEN",
+		AssignedReviewers: []string{"reviewer1", "reviewer2"},
+		CreatedAt:         time.Now().Format(time.RFC3339),
+	}
+
+	mockService.On("MarkReady", mock.Anything, mock.Anything).Return(expectedResp, nil)
+
+	body, _ := json.Marshal(request.MarkReadyRequest{PrId: "pr1"})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/markReady", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.MarkReadyPr(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	pr := result["pr"].(map[string]interface{})
+	assert.Equal(t, "OPEN", pr["status"])
+	assert.Len(t, pr["assigned_reviewers"], 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_ReviewPr_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	expectedResp := &response.SubmitReviewResponse{
+		Review: &response.ReviewResponse{
+			ReviewId:   1,
+			PrId:       "pr1",
+			ReviewerId: "reviewer1",
+			Verdict:    "APPROVED",
+			CreatedAt:  time.Now().Format(time.RFC3339),
+		},
+		State: &response.ReviewStateResponse{
+			PrId:               "pr1",
+			Status:             "OPEN",
+			Approvals:          1,
+			RequiredApprovals:  1,
+			Approved:           true,
+			ChangesRequestedBy: []string{},
+		},
+	}
+
+	mockService.On("Review", mock.Anything, mock.MatchedBy(func(r *request.ReviewRequest) bool {
+		return r.PrId == "pr1" && r.ReviewerId == "reviewer1" && r.Verdict == "APPROVED"
+	})).Return(expectedResp, nil)
+
+	body, _ := json.Marshal(request.ReviewRequest{PrId: "pr1", ReviewerId: "reviewer1", Verdict: "APPROVED"})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ReviewPr(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "APPROVED", result["review"].(map[string]interface{})["verdict"])
+	assert.Equal(t, true, result["state"].(map[string]interface{})["approved"])
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_ReviewPr_NotAssigned(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	mockService.On("Review", mock.Anything, mock.Anything).Return(nil, service.WrapError(service.ErrReviewerNotAssigned, nil))
+
+	body, _ := json.Marshal(request.ReviewRequest{PrId: "pr1", ReviewerId: "stranger", Verdict: "APPROVED"})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ReviewPr(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_GetReviews_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	expectedResp := &response.ReviewStateResponse{
+		PrId:               "pr1",
+		Status:             "OPEN",
+		RequiredApprovals:  2,
+		ChangesRequestedBy: []string{"reviewer2"},
+		Reviews: []response.ReviewResponse{
+			{ReviewId: 1, PrId: "pr1", ReviewerId: "reviewer2", Verdict: "CHANGES_REQUESTED"},
+		},
+	}
+
+	mockService.On("GetReviews", mock.Anything, mock.MatchedBy(func(r *request.GetReviewsRequest) bool {
+		return r.PrId == "pr1"
+	})).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/reviews?pull_request_id=pr1", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetReviews(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result response.ReviewStateResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Reviews, 1)
+	assert.Equal(t, []string{"reviewer2"}, result.ChangesRequestedBy)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_MergePr_NotApproved(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	mockService.On("Merge", mock.Anything, mock.Anything).Return(nil, service.WrapError(service.ErrNotApproved, nil))
+
+	body, _ := json.Marshal(request.MergeRequest{PrId: "pr1"})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.MergePr(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var result ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT_APPROVED", result.Error.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_GetHistory_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	expectedResp := &response.HistoryResponse{
+		PrId: "pr1",
+		Events: []response.AssignmentEventResponse{
+			{EventId: 1, Type: "ASSIGN", UserId: "r1", Actor: "system", Reason: "pr_created"},
+			{EventId: 2, Type: "REASSIGN", UserId: "r2", PreviousUserId: "r1", Actor: "manager", Reason: "manual_reassign"},
+		},
+	}
+
+	mockService.On("GetHistory", mock.Anything, mock.MatchedBy(func(r *request.GetHistoryRequest) bool {
+		return r.PrId == "pr1"
+	})).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr1", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetHistory(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result response.HistoryResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Events, 2)
+	assert.Equal(t, "r1", result.Events[1].PreviousUserId)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_GetHistory_NotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	mockService.On("GetHistory", mock.Anything, mock.Anything).Return(nil, service.WrapError(service.ErrPrNotFound, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=missing", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetHistory(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_GetAssignmentExplain_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	expectedResp := &response.AssignmentExplainResponse{
+		PrId: "pr1",
+		Explanations: []response.AssignmentExplanationResponse{
+			{
+				ExplanationId: 1,
+				Reason:        "pr_created",
+				TeamName:      "backend",
+				Seed:          1700000000123456789,
+				Reviewers:     []string{"r1"},
+				Pools: []response.ExplainedPoolResponse{
+					{
+						TeamName: "backend",
+						Strategy: "least_loaded",
+						Candidates: []response.ExplainedCandidateResponse{
+							{UserId: "author", Excluded: "author"},
+							{UserId: "r1", Selected: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mockService.On("GetAssignmentExplain", mock.Anything, mock.MatchedBy(func(r *request.GetAssignmentExplainRequest) bool {
+		return r.PrId == "pr1" && r.UserId == "r1"
+	})).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/assignmentExplain?pull_request_id=pr1&user_id=r1", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAssignmentExplain(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// seed передается строкой, чтобы не терять точность
+	assert.Contains(t, w.Body.String(), `"seed":"1700000000123456789"`)
+	var result response.AssignmentExplainResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResp.Explanations, result.Explanations)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_GetAssignmentExplain_NotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	mockService.On("GetAssignmentExplain", mock.Anything, mock.Anything).Return(nil, service.WrapError(service.ErrPrNotFound, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/assignmentExplain?pull_request_id=missing", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAssignmentExplain(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPrHandler_GetOverdue_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockPrService)
+	handler := NewPrHandler(mockService, logger)
+
+	expectedResp := &response.OverdueResponse{
+		Reviews: []response.OverdueReviewResponse{
+			{
+				PrId:       "pr1",
+				PrName:     "Fix",
+				AuthorId:   "author",
+				ReviewerId: "r1",
+				TeamName:   "backend",
+				SlaHours:   24,
+				SlaPolicy:  "notify",
+				AssignedAt: "2025-01-06T09:00:00Z",
+				DueAt:      "2025-01-08T15:00:00Z",
+			},
+		},
+	}
+
+	mockService.On("GetOverdue", mock.Anything, mock.MatchedBy(func(r *request.GetOverdueRequest) bool {
+		return r.TeamName == "backend"
+	})).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/overdue?team_name=backend", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetOverdue(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "escalatedAt")
+	var result response.OverdueResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResp.Reviews, result.Reviews)
+	mockService.AssertExpectations(t)
+}
@@ -13,6 +13,17 @@ import (
 type TeamService interface {
 	Add(ctx context.Context, req *request.AddTeamRequest) (*response.AddTeamResponse, error)
 	Get(ctx context.Context, req *request.GetTeamRequest) (*response.GetTeamResponse, error)
+	GetSettings(ctx context.Context, req *request.GetTeamSettingsRequest) (*response.TeamSettingsResponse, error)
+	UpdateSettings(ctx context.Context, req *request.UpdateTeamSettingsRequest) (*response.TeamSettingsResponse, error)
+	DeactivateMembers(ctx context.Context, req *request.DeactivateMembersRequest) (*response.DeactivateMembersResponse, error)
+	GetCodeOwners(ctx context.Context, req *request.GetCodeOwnersRequest) (*response.CodeOwnersResponse, error)
+	SetCodeOwners(ctx context.Context, req *request.SetCodeOwnersRequest) (*response.CodeOwnersResponse, error)
+	AddMembers(ctx context.Context, req *request.AddTeamMembersRequest) (*response.GetTeamResponse, error)
+	RemoveMember(ctx context.Context, req *request.RemoveTeamMemberRequest) (*response.RemoveTeamMembersResponse, error)
+	Rename(ctx context.Context, req *request.RenameTeamRequest) (*response.GetTeamResponse, error)
+	Delete(ctx context.Context, req *request.DeleteTeamRequest) (*response.RemoveTeamMembersResponse, error)
+	SetParent(ctx context.Context, req *request.SetTeamParentRequest) (*response.GetTeamResponse, error)
+	SetMemberRole(ctx context.Context, req *request.SetTeamMemberRoleRequest) (*response.GetTeamResponse, error)
 }
 
 type TeamHandler struct {
@@ -60,7 +71,8 @@ func (h *TeamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 		zap.Int("members_count", len(resp.Members)),
 	)
 
-	// Формируем ответ по формату openapi
+	// Формируем ответ по формату openapi, существующие пользователи с проигнорированными
+	// username и is_active перечисляются в ignored_user_ids
 	members := make([]map[string]interface{}, 0, len(resp.Members))
 	for _, member := range resp.Members {
 		members = append(members, map[string]interface{}{
@@ -71,8 +83,9 @@ func (h *TeamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 	}
 	response := map[string]interface{}{
 		"team": map[string]interface{}{
-			"team_name": resp.TeamName,
-			"members":   members,
+			"team_name":        resp.TeamName,
+			"members":          members,
+			"ignored_user_ids": ignoredUserIds(resp.IgnoredUserIds),
 		},
 	}
 
@@ -113,20 +126,480 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Формируем ответ по формату openapi
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(teamResponse(resp))
+}
+
+func (h *TeamHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getTeamSettings request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем team_name из query параметров
+	teamName := r.URL.Query().Get("team_name")
+
+	// Формируем запрос
+	req := request.GetTeamSettingsRequest{
+		TeamName: teamName,
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.GetSettings(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get team settings",
+			zap.String("team_name", teamName),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team settings retrieved successfully", zap.String("team_name", resp.TeamName))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"settings": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TeamHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("updateTeamSettings request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель UpdateTeamSettingsRequest
+	var req request.UpdateTeamSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.UpdateSettings(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to update team settings",
+			zap.String("team_name", req.TeamName),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team settings updated successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.Int("reviewer_count", resp.ReviewerCount),
+	)
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"settings": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TeamHandler) DeactivateMembers(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("deactivateMembers request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель DeactivateMembersRequest
+	var req request.DeactivateMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.DeactivateMembers(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to deactivate team members",
+			zap.String("team_name", req.TeamName),
+			zap.Int("users_count", len(req.UserIds)),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team members deactivated successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.Int("users_count", len(resp.DeactivatedIds)),
+		zap.Int("reviews_count", len(resp.Reviews)),
+	)
+
+	// Формируем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *TeamHandler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getCodeOwners request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем team_name из query параметров
+	req := request.GetCodeOwnersRequest{
+		TeamName: r.URL.Query().Get("team_name"),
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.GetCodeOwners(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get code owners", zap.String("team_name", req.TeamName), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"codeowners": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TeamHandler) SetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setCodeOwners request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetCodeOwnersRequest
+	var req request.SetCodeOwnersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetCodeOwners(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set code owners", zap.String("team_name", req.TeamName), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("code owners updated successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.Int("rules", len(resp.Rules)),
+	)
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"codeowners": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TeamHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("addTeamMembers request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель AddTeamMembersRequest
+	var req request.AddTeamMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.AddMembers(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to add team members",
+			zap.String("team_name", req.TeamName),
+			zap.Int("members_count", len(req.Members)),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team members added successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.Int("members_count", len(resp.Members)),
+	)
+
+	// Формируем ответ в формате /team/get вместе с ignored_user_ids
+	team := teamResponse(resp)
+	team["ignored_user_ids"] = ignoredUserIds(resp.IgnoredUserIds)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(team)
+}
+
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("removeTeamMember request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель RemoveTeamMemberRequest
+	var req request.RemoveTeamMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.RemoveMember(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to remove team member",
+			zap.String("team_name", req.TeamName),
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team member removed successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.String("user_id", req.UserId),
+		zap.Int("reviews_count", len(resp.Reviews)),
+	)
+
+	// Формируем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *TeamHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("renameTeam request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель RenameTeamRequest
+	var req request.RenameTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Rename(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to rename team",
+			zap.String("team_name", req.TeamName),
+			zap.String("new_team_name", req.NewTeamName),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team renamed successfully",
+		zap.String("team_name", req.TeamName),
+		zap.String("new_team_name", resp.TeamName),
+	)
+
+	// Формируем ответ в формате /team/get
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(teamResponse(resp))
+}
+
+func (h *TeamHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("deleteTeam request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель DeleteTeamRequest
+	var req request.DeleteTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Delete(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to delete team", zap.String("team_name", req.TeamName), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team deleted successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.Int("users_count", len(resp.RemovedIds)),
+		zap.Int("reviews_count", len(resp.Reviews)),
+	)
+
+	// Формируем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *TeamHandler) SetParent(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setTeamParent request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetTeamParentRequest
+	var req request.SetTeamParentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetParent(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set team parent",
+			zap.String("team_name", req.TeamName),
+			zap.String("parent_team_name", req.ParentTeamName),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team parent set successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.String("parent_team_name", resp.ParentTeamName),
+	)
+
+	// Формируем ответ в формате /team/get
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(teamResponse(resp))
+}
+
+func (h *TeamHandler) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setTeamMemberRole request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetTeamMemberRoleRequest
+	var req request.SetTeamMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetMemberRole(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set team member role",
+			zap.String("team_name", req.TeamName),
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("team member role set successfully",
+		zap.String("team_name", resp.TeamName),
+		zap.String("user_id", req.UserId),
+	)
+
+	// Формируем ответ в формате /team/get
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(teamResponse(resp))
+}
+
+// teamResponse команда в формате ответа /team/get. Для каждого участника кроме полей openapi
+// возвращаются основная команда в team_name, все его команды в teams и роль в команде в role. Если прочитана иерархия,
+// добавляются родительская команда (null у команды верхнего уровня), число участников
+// и дерево подкоманд
+func teamResponse(resp *response.GetTeamResponse) map[string]interface{} {
 	members := make([]map[string]interface{}, 0, len(resp.Members))
 	for _, member := range resp.Members {
-		members = append(members, map[string]interface{}{
+		teams := member.Teams
+		if teams == nil {
+			teams = []string{}
+		}
+		m := map[string]interface{}{
 			"user_id":   member.Id,
 			"username":  member.Name,
 			"is_active": member.IsActive,
-		})
+			"team_name": member.TeamName,
+			"teams":     teams,
+		}
+		if member.Role != "" {
+			m["role"] = member.Role
+		}
+		members = append(members, m)
 	}
-	response := map[string]interface{}{
+	team := map[string]interface{}{
 		"team_name": resp.TeamName,
 		"members":   members,
 	}
+	if resp.Tree != nil {
+		var parent interface{}
+		if resp.ParentTeamName != "" {
+			parent = resp.ParentTeamName
+		}
+		team["parent_team_name"] = parent
+		team["member_count"] = resp.Tree.MemberCount
+		team["total_member_count"] = resp.Tree.TotalMemberCount
+		team["subteams"] = resp.Tree.Subteams
+	}
+	return team
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+// ignoredUserIds существующие пользователи, чьи username и is_active из запроса не применены,
+// пустой список вместо null
+func ignoredUserIds(userIds []string) []string {
+	if userIds == nil {
+		return []string{}
+	}
+	return userIds
 }
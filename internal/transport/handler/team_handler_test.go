@@ -39,6 +39,94 @@ func (m *MockTeamService) Get(ctx context.Context, req *request.GetTeamRequest)
 	return args.Get(0).(*response.GetTeamResponse), args.Error(1)
 }
 
+func (m *MockTeamService) GetSettings(ctx context.Context, req *request.GetTeamSettingsRequest) (*response.TeamSettingsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.TeamSettingsResponse), args.Error(1)
+}
+
+func (m *MockTeamService) UpdateSettings(ctx context.Context, req *request.UpdateTeamSettingsRequest) (*response.TeamSettingsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.TeamSettingsResponse), args.Error(1)
+}
+
+func (m *MockTeamService) DeactivateMembers(ctx context.Context, req *request.DeactivateMembersRequest) (*response.DeactivateMembersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.DeactivateMembersResponse), args.Error(1)
+}
+
+func (m *MockTeamService) GetCodeOwners(ctx context.Context, req *request.GetCodeOwnersRequest) (*response.CodeOwnersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.CodeOwnersResponse), args.Error(1)
+}
+
+func (m *MockTeamService) SetCodeOwners(ctx context.Context, req *request.SetCodeOwnersRequest) (*response.CodeOwnersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.CodeOwnersResponse), args.Error(1)
+}
+
+func (m *MockTeamService) AddMembers(ctx context.Context, req *request.AddTeamMembersRequest) (*response.GetTeamResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.GetTeamResponse), args.Error(1)
+}
+
+func (m *MockTeamService) RemoveMember(ctx context.Context, req *request.RemoveTeamMemberRequest) (*response.RemoveTeamMembersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.RemoveTeamMembersResponse), args.Error(1)
+}
+
+func (m *MockTeamService) Rename(ctx context.Context, req *request.RenameTeamRequest) (*response.GetTeamResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.GetTeamResponse), args.Error(1)
+}
+
+func (m *MockTeamService) Delete(ctx context.Context, req *request.DeleteTeamRequest) (*response.RemoveTeamMembersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.RemoveTeamMembersResponse), args.Error(1)
+}
+
+func (m *MockTeamService) SetParent(ctx context.Context, req *request.SetTeamParentRequest) (*response.GetTeamResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.GetTeamResponse), args.Error(1)
+}
+
+func (m *MockTeamService) SetMemberRole(ctx context.Context, req *request.SetTeamMemberRoleRequest) (*response.GetTeamResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.GetTeamResponse), args.Error(1)
+}
+
 func TestTeamHandler_AddTeam_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockService := new(MockTeamService)
@@ -80,6 +168,7 @@ func TestTeamHandler_AddTeam_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &result)
 	assert.NoError(t, err)
 	assert.Contains(t, result, "team")
+	assert.Equal(t, []interface{}{}, result["team"].(map[string]interface{})["ignored_user_ids"])
 	mockService.AssertExpectations(t)
 }
 
@@ -142,9 +231,10 @@ func TestTeamHandler_GetTeam_Success(t *testing.T) {
 		{
 			Id:        "user1",
 			Name:      "User 1",
-			TeamName:  "team1",
+			TeamName:  "team2",
 			IsActive:  true,
 			CreatedAt: time.Now(),
+			Teams:     []string{"team2", "team1"},
 		},
 	}
 
@@ -167,7 +257,11 @@ func TestTeamHandler_GetTeam_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &result)
 	assert.NoError(t, err)
 	assert.Equal(t, "team1", result["team_name"])
-	assert.Contains(t, result, "members")
+	if assert.Len(t, result["members"], 1) {
+		member := result["members"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "team2", member["team_name"])
+		assert.Equal(t, []interface{}{"team2", "team1"}, member["teams"])
+	}
 	mockService.AssertExpectations(t)
 }
 
@@ -207,3 +301,377 @@ func TestTeamHandler_GetTeam_TeamNotFound(t *testing.T) {
 	assert.Contains(t, result, "error")
 	mockService.AssertExpectations(t)
 }
+
+func TestTeamHandler_GetSettings_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("GetSettings", mock.Anything, mock.MatchedBy(func(r *request.GetTeamSettingsRequest) bool {
+		return r.TeamName == "team1"
+	})).Return(&response.TeamSettingsResponse{TeamName: "team1", ReviewerCount: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/settings?team_name=team1", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetSettings(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), result["settings"]["reviewer_count"])
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_UpdateSettings_Invalid(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("UpdateSettings", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidTeamSettings)
+
+	body := []byte(`{"team_name":"team1","reviewer_count":-1}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.UpdateSettings(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_DeactivateMembers_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("DeactivateMembers", mock.Anything, mock.MatchedBy(func(r *request.DeactivateMembersRequest) bool {
+		return r.TeamName == "team1" && len(r.UserIds) == 2
+	})).Return(&response.DeactivateMembersResponse{
+		TeamName:       "team1",
+		DeactivatedIds: []string{"u1", "u2"},
+		Reviews: []response.ReviewOutcome{
+			{PrId: "pr1", OldReviewerId: "u1", Status: response.ReviewOutcomeReassigned, ReplacedBy: "u3"},
+			{PrId: "pr2", OldReviewerId: "u2", Status: response.ReviewOutcomeNoCandidate},
+		},
+	}, nil)
+
+	body := []byte(`{"team_name":"team1","user_ids":["u1","u2"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/deactivateMembers", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.DeactivateMembers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result response.DeactivateMembersResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, result.DeactivatedIds)
+	assert.Len(t, result.Reviews, 2)
+	assert.Equal(t, "u3", result.Reviews[0].ReplacedBy)
+	assert.Equal(t, response.ReviewOutcomeNoCandidate, result.Reviews[1].Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_DeactivateMembers_NotMembers(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("DeactivateMembers", mock.Anything, mock.Anything).Return(nil, service.ErrUsersNotTeamMembers)
+
+	body := []byte(`{"team_name":"team1","user_ids":["stranger"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/deactivateMembers", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.DeactivateMembers(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_AddMembers_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("AddMembers", mock.Anything, mock.MatchedBy(func(r *request.AddTeamMembersRequest) bool {
+		return r.TeamName == "team1" && len(r.Members) == 1 && r.Members[0].Id == "u3"
+	})).Return(&response.GetTeamResponse{
+		TeamName: "team1",
+		Members: []*domain.User{
+			{Id: "u1", Name: "User 1", IsActive: true},
+			{Id: "u3", Name: "User 3", IsActive: true},
+		},
+	}, nil)
+
+	body := []byte(`{"team_name":"team1","members":[{"user_id":"u3","username":"User 3","is_active":true}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/addMembers", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.AddMembers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "team1", result["team_name"])
+	assert.Len(t, result["members"], 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_AddMembers_ReportsIgnoredUsers(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	// u1 уже существует выключенным, is_active из запроса не применяется
+	mockService.On("AddMembers", mock.Anything, mock.Anything).Return(&response.GetTeamResponse{
+		TeamName: "team1",
+		Members: []*domain.User{
+			{Id: "u1", Name: "User 1", IsActive: false},
+		},
+		IgnoredUserIds: []string{"u1"},
+	}, nil)
+
+	body := []byte(`{"team_name":"team1","members":[{"user_id":"u1","username":"User 1","is_active":true}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/addMembers", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.AddMembers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"u1"}, result["ignored_user_ids"])
+	members := result["members"].([]interface{})
+	assert.Equal(t, false, members[0].(map[string]interface{})["is_active"])
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_RemoveMember_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("RemoveMember", mock.Anything, &request.RemoveTeamMemberRequest{TeamName: "team1", UserId: "u1"}).
+		Return(&response.RemoveTeamMembersResponse{
+			TeamName:   "team1",
+			RemovedIds: []string{"u1"},
+			Reviews: []response.ReviewOutcome{
+				{PrId: "pr1", OldReviewerId: "u1", Status: response.ReviewOutcomeReassigned, ReplacedBy: "u3"},
+			},
+		}, nil)
+
+	body := []byte(`{"team_name":"team1","user_id":"u1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/removeMember", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.RemoveMember(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result response.RemoveTeamMembersResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, result.RemovedIds)
+	assert.Equal(t, "u3", result.Reviews[0].ReplacedBy)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_RemoveMember_NoCandidate(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("RemoveMember", mock.Anything, mock.Anything).Return(nil, service.ErrOpenReviewsNoCandidate)
+
+	body := []byte(`{"team_name":"team1","user_id":"u1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/removeMember", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.RemoveMember(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_Rename_TeamExists(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("Rename", mock.Anything, &request.RenameTeamRequest{TeamName: "team1", NewTeamName: "team2"}).
+		Return(nil, service.ErrTeamExists)
+
+	body := []byte(`{"team_name":"team1","new_team_name":"team2"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/rename", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Rename(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_Delete_TeamNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("Delete", mock.Anything, &request.DeleteTeamRequest{TeamName: "ghost"}).Return(nil, service.ErrTeamNotFound)
+
+	body := []byte(`{"team_name":"ghost"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/delete", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Delete(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_SetParent_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("SetParent", mock.Anything, &request.SetTeamParentRequest{TeamName: "backend", ParentTeamName: ""}).
+		Return(&response.GetTeamResponse{
+			TeamName: "backend",
+			Members:  []*domain.User{{Id: "u1", Name: "User 1", IsActive: true}},
+			Tree: &response.TeamTreeNode{
+				TeamName:         "backend",
+				MemberCount:      1,
+				TotalMemberCount: 2,
+				Subteams: []*response.TeamTreeNode{
+					{TeamName: "backend-payments", MemberCount: 1, TotalMemberCount: 1, Subteams: []*response.TeamTreeNode{}},
+				},
+			},
+		}, nil)
+
+	body := []byte(`{"team_name":"backend","parent_team_name":""}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setParent", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetParent(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	// Команда верхнего уровня отдает parent_team_name как null
+	assert.Contains(t, result, "parent_team_name")
+	assert.Nil(t, result["parent_team_name"])
+	assert.Equal(t, float64(1), result["member_count"])
+	assert.Equal(t, float64(2), result["total_member_count"])
+	if assert.Len(t, result["subteams"], 1) {
+		subteam := result["subteams"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "backend-payments", subteam["team_name"])
+		assert.Equal(t, []interface{}{}, subteam["subteams"])
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_SetParent_Cycle(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("SetParent", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidParentTeam)
+
+	body := []byte(`{"team_name":"backend","parent_team_name":"backend-payments"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setParent", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetParent(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_SetMemberRole_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("SetMemberRole", mock.Anything, &request.SetTeamMemberRoleRequest{TeamName: "backend", UserId: "u1", Role: "LEAD"}).
+		Return(&response.GetTeamResponse{
+			TeamName: "backend",
+			Members:  []*domain.User{{Id: "u1", Name: "User 1", IsActive: true, Role: "LEAD"}},
+		}, nil)
+
+	body := []byte(`{"team_name":"backend","user_id":"u1","role":"LEAD"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setMemberRole", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetMemberRole(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	member := result["members"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "LEAD", member["role"])
+	// Без иерархии в ответе нет дерева подкоманд
+	assert.NotContains(t, result, "subteams")
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_SetCodeOwners_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("SetCodeOwners", mock.Anything, mock.MatchedBy(func(r *request.SetCodeOwnersRequest) bool {
+		return r.TeamName == "team1" && r.Content == "*.go u1"
+	})).Return(&response.CodeOwnersResponse{
+		TeamName: "team1",
+		Rules:    []response.CodeOwnerRuleResponse{{Pattern: "*.go", Owners: []string{"u1"}}},
+	}, nil)
+
+	body := []byte(`{"team_name":"team1","content":"*.go u1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/codeowners", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetCodeOwners(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]response.CodeOwnersResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "*.go", result["codeowners"].Rules[0].Pattern)
+	mockService.AssertExpectations(t)
+}
+
+func TestTeamHandler_GetCodeOwners_TeamNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockTeamService)
+	handler := NewTeamHandler(mockService, logger)
+
+	mockService.On("GetCodeOwners", mock.Anything, mock.MatchedBy(func(r *request.GetCodeOwnersRequest) bool {
+		return r.TeamName == "missing"
+	})).Return(nil, service.ErrTeamNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/codeowners?team_name=missing", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetCodeOwners(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -13,6 +13,19 @@ import (
 type UserService interface {
 	SetIsActive(ctx context.Context, req *request.SetIsActiveRequest) (*response.SetIsActiveResponse, error)
 	GetReview(ctx context.Context, req *request.GetReviewRequest) (*response.GetReviewResponse, error)
+	SetMaxOpenReviews(ctx context.Context, req *request.SetMaxOpenReviewsRequest) (*response.SetMaxOpenReviewsResponse, error)
+	AddAvailability(ctx context.Context, req *request.AddAvailabilityRequest) (*response.AvailabilityResponse, error)
+	ListAvailability(ctx context.Context, req *request.ListAvailabilityRequest) (*response.ListAvailabilityResponse, error)
+	DeleteAvailability(ctx context.Context, req *request.DeleteAvailabilityRequest) error
+	GetTags(ctx context.Context, req *request.GetTagsRequest) (*response.UserTagsResponse, error)
+	SetTags(ctx context.Context, req *request.SetTagsRequest) (*response.UserTagsResponse, error)
+	GetContacts(ctx context.Context, req *request.GetContactsRequest) (*response.UserContactsResponse, error)
+	SetContacts(ctx context.Context, req *request.SetContactsRequest) (*response.UserContactsResponse, error)
+	SetPrimaryTeam(ctx context.Context, req *request.SetPrimaryTeamRequest) (*response.UserResponse, error)
+	Create(ctx context.Context, req *request.CreateUserRequest) (*response.UserResponse, error)
+	Get(ctx context.Context, req *request.GetUserRequest) (*response.UserResponse, error)
+	Update(ctx context.Context, req *request.UpdateUserRequest) (*response.UserResponse, error)
+	List(ctx context.Context, req *request.ListUsersRequest) (*response.ListUsersResponse, error)
 }
 
 type UserHandler struct {
@@ -64,6 +77,10 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
 		"user": resp,
 	}
+	if !req.IsActive && req.ReassignReviews {
+		response["reassigned"] = resp.Reassigned
+		response["no_candidate"] = resp.NoCandidatePrs
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -120,3 +137,466 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+func (h *UserHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setMaxOpenReviews request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetMaxOpenReviewsRequest
+	var req request.SetMaxOpenReviewsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetMaxOpenReviews(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set user max open reviews",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("user max open reviews updated", zap.String("user_id", resp.UserId))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"user": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) AddAvailability(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("addAvailability request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель AddAvailabilityRequest
+	var req request.AddAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.AddAvailability(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to add availability period",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("availability period added",
+		zap.String("user_id", resp.UserId),
+		zap.String("availability_id", resp.AvailabilityId),
+	)
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"availability": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) ListAvailability(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getAvailability request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем user_id из query параметров
+	userId := r.URL.Query().Get("user_id")
+
+	// Формируем запрос
+	req := request.ListAvailabilityRequest{
+		UserId: userId,
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.ListAvailability(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to list availability periods",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("availability periods retrieved",
+		zap.String("user_id", resp.UserId),
+		zap.Int("periods_count", len(resp.Periods)),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *UserHandler) DeleteAvailability(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("deleteAvailability request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель DeleteAvailabilityRequest
+	var req request.DeleteAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	if err := h.svc.DeleteAvailability(r.Context(), &req); err != nil {
+		h.log.Error("failed to delete availability period",
+			zap.String("availability_id", req.AvailabilityId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("availability period deleted", zap.String("availability_id", req.AvailabilityId))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"availability_id": req.AvailabilityId,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) SetTags(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setTags request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetTagsRequest
+	var req request.SetTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetTags(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set user tags",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("user tags updated", zap.String("user_id", resp.UserId))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *UserHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getTags request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем user_id из query параметров
+	req := request.GetTagsRequest{
+		UserId: r.URL.Query().Get("user_id"),
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.GetTags(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get user tags",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *UserHandler) SetContacts(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setContacts request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetContactsRequest
+	var req request.SetContactsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetContacts(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set user contacts",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("user contacts updated", zap.String("user_id", resp.UserId))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *UserHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getContacts request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем user_id из query параметров
+	req := request.GetContactsRequest{
+		UserId: r.URL.Query().Get("user_id"),
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.GetContacts(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get user contacts",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *UserHandler) SetPrimaryTeam(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("setPrimaryTeam request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель SetPrimaryTeamRequest
+	var req request.SetPrimaryTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.SetPrimaryTeam(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to set primary team",
+			zap.String("user_id", req.UserId),
+			zap.String("team_name", req.TeamName),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("primary team updated",
+		zap.String("user_id", resp.UserId),
+		zap.String("team_name", resp.TeamName),
+	)
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"user": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("createUser request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель CreateUserRequest
+	var req request.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Create(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to create user",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("user created", zap.String("user_id", resp.UserId))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"user": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("getUser request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем user_id из query параметров
+	req := request.GetUserRequest{
+		UserId: r.URL.Query().Get("user_id"),
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.Get(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to get user",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"user": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("updateUser request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель UpdateUserRequest
+	var req request.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Update(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to update user",
+			zap.String("user_id", req.UserId),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("user updated", zap.String("user_id", resp.UserId))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"user": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("listUsers request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем фильтры из query параметров
+	query := r.URL.Query()
+	req := request.ListUsersRequest{
+		TeamName: query.Get("team_name"),
+		IsActive: query.Get("is_active"),
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.List(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to list users",
+			zap.String("team_name", req.TeamName),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -39,6 +39,107 @@ func (m *MockUserService) GetReview(ctx context.Context, req *request.GetReviewR
 	return args.Get(0).(*response.GetReviewResponse), args.Error(1)
 }
 
+func (m *MockUserService) SetMaxOpenReviews(ctx context.Context, req *request.SetMaxOpenReviewsRequest) (*response.SetMaxOpenReviewsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.SetMaxOpenReviewsResponse), args.Error(1)
+}
+
+func (m *MockUserService) AddAvailability(ctx context.Context, req *request.AddAvailabilityRequest) (*response.AvailabilityResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.AvailabilityResponse), args.Error(1)
+}
+
+func (m *MockUserService) ListAvailability(ctx context.Context, req *request.ListAvailabilityRequest) (*response.ListAvailabilityResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ListAvailabilityResponse), args.Error(1)
+}
+
+func (m *MockUserService) DeleteAvailability(ctx context.Context, req *request.DeleteAvailabilityRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) GetTags(ctx context.Context, req *request.GetTagsRequest) (*response.UserTagsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserTagsResponse), args.Error(1)
+}
+
+func (m *MockUserService) SetTags(ctx context.Context, req *request.SetTagsRequest) (*response.UserTagsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserTagsResponse), args.Error(1)
+}
+
+func (m *MockUserService) GetContacts(ctx context.Context, req *request.GetContactsRequest) (*response.UserContactsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserContactsResponse), args.Error(1)
+}
+
+func (m *MockUserService) SetContacts(ctx context.Context, req *request.SetContactsRequest) (*response.UserContactsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserContactsResponse), args.Error(1)
+}
+
+func (m *MockUserService) SetPrimaryTeam(ctx context.Context, req *request.SetPrimaryTeamRequest) (*response.UserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) Create(ctx context.Context, req *request.CreateUserRequest) (*response.UserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) Get(ctx context.Context, req *request.GetUserRequest) (*response.UserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) Update(ctx context.Context, req *request.UpdateUserRequest) (*response.UserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) List(ctx context.Context, req *request.ListUsersRequest) (*response.ListUsersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ListUsersResponse), args.Error(1)
+}
+
 func TestUserHandler_SetIsActive_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockService := new(MockUserService)
@@ -199,3 +300,365 @@ func TestUserHandler_GetReview_UserNotFound(t *testing.T) {
 	assert.Contains(t, result, "error")
 	mockService.AssertExpectations(t)
 }
+
+func TestUserHandler_SetIsActive_DeactivateWithReassign(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("SetIsActive", mock.Anything, mock.MatchedBy(func(r *request.SetIsActiveRequest) bool {
+		return r.UserId == "user1" && !r.IsActive && r.ReassignReviews
+	})).Return(&response.SetIsActiveResponse{
+		UserId:         "user1",
+		IsActive:       false,
+		Reassigned:     []response.ReviewReassignment{{PrId: "pr1", ReplacedBy: "user2"}},
+		NoCandidatePrs: []string{"pr2"},
+	}, nil)
+
+	body := []byte(`{"user_id":"user1","is_active":false,"reassign_reviews":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setIsActive", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetIsActive(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "user")
+	assert.Len(t, result["reassigned"], 1)
+	assert.Equal(t, []interface{}{"pr2"}, result["no_candidate"])
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_AddAvailability_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("AddAvailability", mock.Anything, mock.MatchedBy(func(r *request.AddAvailabilityRequest) bool {
+		return r.UserId == "user1" && r.StartsAt == "2030-01-01T00:00:00Z" && r.Reason == "vacation"
+	})).Return(&response.AvailabilityResponse{
+		AvailabilityId: "period1",
+		UserId:         "user1",
+		StartsAt:       "2030-01-01T00:00:00Z",
+		EndsAt:         "2030-01-15T00:00:00Z",
+		Reason:         "vacation",
+	}, nil)
+
+	body := []byte(`{"user_id":"user1","starts_at":"2030-01-01T00:00:00Z","ends_at":"2030-01-15T00:00:00Z","reason":"vacation"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/addAvailability", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.AddAvailability(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var result map[string]map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "period1", result["availability"]["availability_id"])
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_AddAvailability_InvalidPeriod(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("AddAvailability", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidAvailability)
+
+	body := []byte(`{"user_id":"user1","starts_at":"soon","ends_at":"later"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/addAvailability", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.AddAvailability(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_ListAvailability_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("ListAvailability", mock.Anything, &request.ListAvailabilityRequest{UserId: "user1"}).Return(&response.ListAvailabilityResponse{
+		UserId: "user1",
+		Periods: []response.AvailabilityResponse{
+			{AvailabilityId: "period1", UserId: "user1", Current: true},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/getAvailability?user_id=user1", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListAvailability(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result response.ListAvailabilityResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Periods, 1)
+	assert.True(t, result.Periods[0].Current)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_DeleteAvailability_NotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("DeleteAvailability", mock.Anything, &request.DeleteAvailabilityRequest{AvailabilityId: "missing"}).Return(service.ErrAvailabilityNotFound)
+
+	body := []byte(`{"availability_id":"missing"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/deleteAvailability", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.DeleteAvailability(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetMaxOpenReviews_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	limit := 4
+	mockService.On("SetMaxOpenReviews", mock.Anything, mock.MatchedBy(func(r *request.SetMaxOpenReviewsRequest) bool {
+		return r.UserId == "user1" && r.MaxOpenReviews != nil && *r.MaxOpenReviews == 4
+	})).Return(&response.SetMaxOpenReviewsResponse{
+		UserId:         "user1",
+		Username:       "User 1",
+		IsActive:       true,
+		MaxOpenReviews: &limit,
+	}, nil)
+
+	body := []byte(`{"user_id":"user1","max_open_reviews":4}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setMaxOpenReviews", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetMaxOpenReviews(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result map[string]map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4), result["user"]["max_open_reviews"])
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetTags_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("SetTags", mock.Anything, mock.MatchedBy(func(r *request.SetTagsRequest) bool {
+		return r.UserId == "u1" && len(r.Tags) == 2
+	})).Return(&response.UserTagsResponse{UserId: "u1", Tags: []string{"go", "postgres"}}, nil)
+
+	body := []byte(`{"user_id":"u1","tags":["go","postgres"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setTags", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetTags(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp response.UserTagsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "postgres"}, resp.Tags)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetTags_Invalid(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("SetTags", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidTags)
+
+	body := []byte(`{"user_id":"u1","tags":["front end"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setTags", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetTags(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetContacts_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("SetContacts", mock.Anything, mock.MatchedBy(func(r *request.SetContactsRequest) bool {
+		return r.UserId == "u1" && r.Email == "alice@example.com" && r.DigestEnabled != nil && !*r.DigestEnabled
+	})).Return(&response.UserContactsResponse{UserId: "u1", Email: "alice@example.com"}, nil)
+
+	body := []byte(`{"user_id":"u1","email":"alice@example.com","digest_enabled":false}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setContacts", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetContacts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp response.UserContactsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice@example.com", resp.Email)
+	assert.False(t, resp.DigestEnabled)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetPrimaryTeam_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("SetPrimaryTeam", mock.Anything, &request.SetPrimaryTeamRequest{UserId: "u1", TeamName: "payments"}).
+		Return(&response.UserResponse{
+			UserId:   "u1",
+			Username: "Alice",
+			TeamName: "payments",
+			Teams:    []string{"payments", "backend"},
+			IsActive: true,
+		}, nil)
+
+	body := []byte(`{"user_id":"u1","team_name":"payments"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setPrimaryTeam", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetPrimaryTeam(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result struct {
+		User response.UserResponse `json:"user"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", result.User.TeamName)
+	assert.Equal(t, []string{"payments", "backend"}, result.User.Teams)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetPrimaryTeam_NotMember(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("SetPrimaryTeam", mock.Anything, mock.Anything).Return(nil, service.ErrNotTeamMember)
+
+	body := []byte(`{"user_id":"u1","team_name":"docs"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setPrimaryTeam", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetPrimaryTeam(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_CreateUser_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("Create", mock.Anything, &request.CreateUserRequest{UserId: "u1", Username: "Alice"}).
+		Return(&response.UserResponse{
+			UserId:   "u1",
+			Username: "Alice",
+			Teams:    []string{},
+			IsActive: true,
+		}, nil)
+
+	body := []byte(`{"user_id":"u1","username":"Alice"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/create", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateUser(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var result struct {
+		User response.UserResponse `json:"user"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", result.User.UserId)
+	assert.True(t, result.User.IsActive)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_CreateUser_Exists(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("Create", mock.Anything, mock.Anything).Return(nil, service.ErrUserExists)
+
+	body := []byte(`{"user_id":"u1","username":"Alice"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/create", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateUser(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var errResp ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "USER_EXISTS", errResp.Error.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_GetUser_NotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("Get", mock.Anything, &request.GetUserRequest{UserId: "ghost"}).Return(nil, service.ErrUserNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/get?user_id=ghost", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetUser(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_ListUsers_PassesFilters(t *testing.T) {
+	logger := zap.NewNop()
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger)
+
+	mockService.On("List", mock.Anything, &request.ListUsersRequest{TeamName: "backend", IsActive: "false"}).
+		Return(&response.ListUsersResponse{Users: []*response.UserResponse{
+			{UserId: "u2", Username: "Bob", TeamName: "backend", Teams: []string{"backend"}},
+		}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/list?team_name=backend&is_active=false", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListUsers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result response.ListUsersResponse
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Users, 1)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, req *request.CreateWebhookRequest) (*response.WebhookResponse, error)
+	List(ctx context.Context) (*response.ListWebhooksResponse, error)
+	Delete(ctx context.Context, req *request.DeleteWebhookRequest) error
+	ListDeliveries(ctx context.Context, req *request.ListDeliveriesRequest) (*response.ListDeliveriesResponse, error)
+	Redeliver(ctx context.Context, req *request.RedeliverRequest) (*response.WebhookDeliveryResponse, error)
+}
+
+type WebhookHandler struct {
+	svc WebhookService
+	log *zap.Logger
+}
+
+func NewWebhookHandler(svc WebhookService, log *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		svc: svc,
+		log: log,
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("createWebhook request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель CreateWebhookRequest
+	var req request.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Create(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to create webhook", zap.String("url", req.Url), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("webhook created", zap.String("webhook_id", resp.WebhookId))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"webhook": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("listWebhooks request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Вызываем сервис
+	resp, err := h.svc.List(r.Context())
+	if err != nil {
+		h.log.Error("failed to list webhooks", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("webhooks retrieved", zap.Int("webhooks_count", len(resp.Webhooks)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("deleteWebhook request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель DeleteWebhookRequest
+	var req request.DeleteWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	if err := h.svc.Delete(r.Context(), &req); err != nil {
+		h.log.Error("failed to delete webhook", zap.String("webhook_id", req.WebhookId), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("webhook deleted", zap.String("webhook_id", req.WebhookId))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"webhook_id": req.WebhookId,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("listDeliveries request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Получаем фильтры из query параметров
+	query := r.URL.Query()
+	req := request.ListDeliveriesRequest{
+		WebhookId: query.Get("webhook_id"),
+		Status:    query.Get("status"),
+		Limit:     query.Get("limit"),
+	}
+
+	// Вызываем сервис
+	resp, err := h.svc.ListDeliveries(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to list webhook deliveries",
+			zap.String("webhook_id", req.WebhookId),
+			zap.String("status", req.Status),
+			zap.Error(err),
+		)
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("webhook deliveries retrieved", zap.Int("deliveries_count", len(resp.Deliveries)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	h.log.Info("redeliver request received",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	// Парсим json в модель RedeliverRequest
+	var req request.RedeliverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("failed to decode request body", zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	// Вызов сервиса
+	resp, err := h.svc.Redeliver(r.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to requeue webhook delivery", zap.Int64("delivery_id", req.DeliveryId), zap.Error(err))
+		statusCode, errResp := HandleError(err)
+		WriteError(w, statusCode, errResp)
+		return
+	}
+
+	h.log.Info("webhook delivery requeued", zap.Int64("delivery_id", resp.DeliveryId))
+
+	// Формируем ответ
+	response := map[string]interface{}{
+		"delivery": resp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
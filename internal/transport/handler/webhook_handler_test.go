@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"github.com/niklvrr/AvitoInternship2025/internal/usecase/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockWebhookService мок сервиса для тестов
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Create(ctx context.Context, req *request.CreateWebhookRequest) (*response.WebhookResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) List(ctx context.Context) (*response.ListWebhooksResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ListWebhooksResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) Delete(ctx context.Context, req *request.DeleteWebhookRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, req *request.ListDeliveriesRequest) (*response.ListDeliveriesResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.ListDeliveriesResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, req *request.RedeliverRequest) (*response.WebhookDeliveryResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.WebhookDeliveryResponse), args.Error(1)
+}
+
+func TestWebhookHandler_CreateWebhook_Success(t *testing.T) {
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService, zap.NewNop())
+
+	reqBody := request.CreateWebhookRequest{
+		Url:        "https://bot.example.com/hook",
+		EventTypes: []string{"reviewer.assigned"},
+	}
+	mockService.On("Create", mock.Anything, &reqBody).Return(&response.WebhookResponse{
+		WebhookId:  "hook1",
+		Url:        reqBody.Url,
+		Secret:     "secret",
+		EventTypes: reqBody.EventTypes,
+	}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/create", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateWebhook(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "hook1", resp["webhook"]["webhook_id"])
+	assert.Equal(t, "secret", resp["webhook"]["secret"])
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_CreateWebhook_InvalidInput(t *testing.T) {
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService, zap.NewNop())
+
+	mockService.On("Create", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidWebhook)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/create", bytes.NewBufferString(`{"url":"ftp://x"}`))
+	w := httptest.NewRecorder()
+
+	handler.CreateWebhook(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookHandler_ListDeliveries_QueryParams(t *testing.T) {
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService, zap.NewNop())
+
+	mockService.On("ListDeliveries", mock.Anything, &request.ListDeliveriesRequest{
+		WebhookId: "hook1",
+		Status:    "DEAD",
+		Limit:     "10",
+	}).Return(&response.ListDeliveriesResponse{Deliveries: []response.WebhookDeliveryResponse{
+		{DeliveryId: 1, WebhookId: "hook1", Status: "DEAD", Attempts: 8},
+	}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?webhook_id=hook1&status=DEAD&limit=10", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListDeliveries(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp response.ListDeliveriesResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp.Deliveries, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_DeleteWebhook_NotFound(t *testing.T) {
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService, zap.NewNop())
+
+	mockService.On("Delete", mock.Anything, &request.DeleteWebhookRequest{WebhookId: "missing"}).Return(service.ErrWebhookNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/delete", bytes.NewBufferString(`{"webhook_id":"missing"}`))
+	w := httptest.NewRecorder()
+
+	handler.DeleteWebhook(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
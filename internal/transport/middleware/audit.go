@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+const (
+	// ActorHeader заголовок, в котором клиент передает автора изменения
+	ActorHeader = "X-Actor"
+	// Длина колонки actor в журнале назначений
+	maxActorLength = 255
+)
+
+// Audit кладет в контекст автора изменения и идентификатор запроса для журнала назначений.
+// Должен стоять после middleware.RequestID
+func Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := []rune(strings.TrimSpace(r.Header.Get(ActorHeader)))
+		if len(actor) > maxActorLength {
+			actor = actor[:maxActorLength]
+		}
+		ctx := domain.WithAuditInfo(r.Context(), domain.AuditInfo{
+			Actor:     string(actor),
+			RequestId: middleware.GetReqID(r.Context()),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
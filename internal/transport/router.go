@@ -16,6 +16,9 @@ func NewRouter(
 	teamHandler *handler.TeamHandler,
 	prHandler *handler.PrHandler,
 	statsHandler *handler.StatsHandler,
+	webhookHandler *handler.WebhookHandler,
+	eventsHandler *handler.EventsHandler,
+	integrationHandler *handler.IntegrationHandler,
 	healthHandler *handler.HealthHandler,
 	log *zap.Logger,
 ) *chi.Mux {
@@ -27,36 +30,106 @@ func NewRouter(
 	// RequestID для трейсинга запросов
 	router.Use(middleware.RequestID)
 
+	// Audit для журнала назначений: автор изменения и RequestID
+	router.Use(transportMiddleware.Audit)
+
 	// Logging для структурированного логирования всех запросов
 	router.Use(transportMiddleware.Logging(log))
 
-	// Timeout для контроля времени выполнения запросов (500ms для соблюдения SLI 300ms)
-	router.Use(transportMiddleware.Timeout(500*time.Millisecond, log))
+	// Поток событий живет дольше любого таймаута запроса, поэтому подключен вне группы с Timeout
+	router.With(transportMiddleware.Metrics).Get("/events/stream", eventsHandler.Stream)
+
+	router.Group(func(router chi.Router) {
+		// Timeout для контроля времени выполнения запросов (500ms для соблюдения SLI 300ms)
+		router.Use(transportMiddleware.Timeout(500*time.Millisecond, log))
 
-	// Metrics для сбора метрик производительности
-	router.Use(transportMiddleware.Metrics)
+		// Metrics для сбора метрик производительности
+		router.Use(transportMiddleware.Metrics)
 
+		registerRoutes(router, userHandler, teamHandler, prHandler, statsHandler, webhookHandler, integrationHandler, healthHandler)
+	})
+
+	return router
+}
+
+func registerRoutes(
+	router chi.Router,
+	userHandler *handler.UserHandler,
+	teamHandler *handler.TeamHandler,
+	prHandler *handler.PrHandler,
+	statsHandler *handler.StatsHandler,
+	webhookHandler *handler.WebhookHandler,
+	integrationHandler *handler.IntegrationHandler,
+	healthHandler *handler.HealthHandler,
+) {
 	// Эндпоинт для Prometheus метрик
 	router.Handle("/metrics", promhttp.Handler())
 
 	router.Route("/users", func(r chi.Router) {
 		r.Post("/setIsActive", userHandler.SetIsActive)
 		r.Get("/getReview", userHandler.GetReview)
+		r.Post("/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
+		r.Post("/addAvailability", userHandler.AddAvailability)
+		r.Get("/getAvailability", userHandler.ListAvailability)
+		r.Post("/deleteAvailability", userHandler.DeleteAvailability)
+		r.Post("/setTags", userHandler.SetTags)
+		r.Get("/getTags", userHandler.GetTags)
+		r.Post("/setContacts", userHandler.SetContacts)
+		r.Get("/getContacts", userHandler.GetContacts)
+		r.Post("/setPrimaryTeam", userHandler.SetPrimaryTeam)
+		r.Post("/create", userHandler.CreateUser)
+		r.Get("/get", userHandler.GetUser)
+		r.Post("/update", userHandler.UpdateUser)
+		r.Get("/list", userHandler.ListUsers)
 	})
 
 	router.Route("/team", func(r chi.Router) {
 		r.Post("/add", teamHandler.AddTeam)
 		r.Get("/get", teamHandler.GetTeam)
+		r.Get("/settings", teamHandler.GetSettings)
+		r.Post("/settings", teamHandler.UpdateSettings)
+		r.Post("/deactivateMembers", teamHandler.DeactivateMembers)
+		r.Get("/codeowners", teamHandler.GetCodeOwners)
+		r.Post("/codeowners", teamHandler.SetCodeOwners)
+		r.Post("/addMembers", teamHandler.AddMembers)
+		r.Post("/removeMember", teamHandler.RemoveMember)
+		r.Post("/rename", teamHandler.Rename)
+		r.Post("/delete", teamHandler.Delete)
+		r.Post("/setParent", teamHandler.SetParent)
+		r.Post("/setMemberRole", teamHandler.SetMemberRole)
 	})
 
 	router.Route("/pullRequest", func(r chi.Router) {
 		r.Post("/create", prHandler.CreatePr)
 		r.Post("/merge", prHandler.MergePr)
 		r.Post("/reassign", prHandler.ReassignPr)
+		r.Post("/close", prHandler.ClosePr)
+		r.Post("/reopen", prHandler.ReopenPr)
+		r.Post("/markReady", prHandler.MarkReadyPr)
+		r.Post("/review", prHandler.ReviewPr)
+		r.Get("/reviews", prHandler.GetReviews)
+		r.Get("/history", prHandler.GetHistory)
+		r.Get("/assignmentExplain", prHandler.GetAssignmentExplain)
+		r.Get("/overdue", prHandler.GetOverdue)
 	})
 
 	router.Get("/stats", statsHandler.GetStats)
 
+	router.Route("/webhooks", func(r chi.Router) {
+		r.Post("/create", webhookHandler.CreateWebhook)
+		r.Get("/list", webhookHandler.ListWebhooks)
+		r.Post("/delete", webhookHandler.DeleteWebhook)
+		r.Get("/deliveries", webhookHandler.ListDeliveries)
+		r.Post("/redeliver", webhookHandler.Redeliver)
+	})
+
+	router.Route("/integrations", func(r chi.Router) {
+		r.Post("/github/webhook", integrationHandler.GitHubWebhook)
+		r.Post("/gitlab/webhook", integrationHandler.GitLabWebhook)
+		r.Post("/users/set", integrationHandler.SetUser)
+		r.Get("/users/list", integrationHandler.ListUsers)
+		r.Post("/users/delete", integrationHandler.DeleteUser)
+	})
+
 	router.Get("/health", healthHandler.HealthCheck)
-	return router
 }
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"math/rand"
+	"slices"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+)
+
+// assignmentExplainer ведет один подбор ревьюеров: выдает стратегии команд, которые берут случайность
+// из генератора с записанным seed, и собирает объяснение - какие пулы рассматривались и кто из кандидатов
+// не мог быть выбран и почему
+type assignmentExplainer struct {
+	selectors   *ReviewerSelectors
+	rnd         *rand.Rand
+	explanation *domain.AssignmentExplanation
+	// Пользователи, исключенные вызывающей стороной, с причиной исключения
+	excluded map[string]string
+	// Лид при обязательном ревью лида, владельцы измененных файлов и теги PR
+	lead         string
+	owners       []string
+	requiredTags []string
+}
+
+func newAssignmentExplainer(selectors *ReviewerSelectors, reason string) *assignmentExplainer {
+	seed := selectors.NextSeed()
+	return &assignmentExplainer{
+		selectors: selectors,
+		rnd:       rand.New(rand.NewSource(seed)),
+		explanation: &domain.AssignmentExplanation{
+			Reason: reason,
+			Seed:   seed,
+		},
+		excluded: make(map[string]string),
+	}
+}
+
+// exclude помечает пользователей исключенными по причине reason. Первая причина сохраняется
+func (e *assignmentExplainer) exclude(reason string, userIds ...string) {
+	for _, userId := range userIds {
+		if _, ok := e.excluded[userId]; !ok {
+			e.excluded[userId] = reason
+		}
+	}
+}
+
+// selectorFor возвращает стратегию команды пула и записывает пул в объяснение
+// в том виде, в котором его увидит стратегия
+func (e *assignmentExplainer) selectorFor(pool *result.PotentialReviewersResult) ReviewerSelector {
+	settings := effectiveTeamSettings(pool.TeamId, pool.Settings)
+	strategy := e.selectors.StrategyFor(pool.TeamName, settings.Strategy)
+
+	explained := domain.ExplainedPool{
+		TeamName:   pool.TeamName,
+		Strategy:   strategy,
+		Candidates: make([]domain.ExplainedCandidate, 0, len(pool.Candidates)),
+	}
+	for _, candidate := range pool.Candidates {
+		if candidate == nil || candidate.User == nil {
+			continue
+		}
+		explained.Candidates = append(explained.Candidates, domain.ExplainedCandidate{
+			UserId:         candidate.User.Id,
+			OpenReviews:    candidate.OpenReviews,
+			RecentPairings: candidate.RecentPairings,
+			Lead:           e.lead != "" && candidate.User.Id == e.lead,
+			CodeOwner:      slices.Contains(e.owners, candidate.User.Id),
+			CoversTags:     len(e.requiredTags) > 0 && coversTags(candidate, e.requiredTags),
+			Excluded:       e.exclusionOf(candidate),
+		})
+	}
+	e.explanation.Pools = append(e.explanation.Pools, explained)
+
+	return e.selectors.WithRand(strategy, e.rnd)
+}
+
+// exclusionOf возвращает причину, по которой кандидата нельзя выбрать, или пустую строку
+func (e *assignmentExplainer) exclusionOf(candidate *result.ReviewerCandidate) string {
+	if reason, ok := e.excluded[candidate.User.Id]; ok {
+		return reason
+	}
+	switch {
+	case !candidate.User.IsActive:
+		return domain.ExclusionInactive
+	case candidate.Unavailable:
+		return domain.ExclusionUnavailable
+	case atCapacity(candidate.OpenReviews, candidate.User.MaxOpenReviews):
+		return domain.ExclusionAtCapacity
+	}
+	return ""
+}
+
+// finish записывает выбранных ревьюеров и команду, из пула которой они взяты, и возвращает объяснение.
+// Выбранные отмечаются во всех рассмотренных пулах: ревьюеры одного подбора могут быть взяты
+// из команды автора, родительских и резервных команд
+func (e *assignmentExplainer) finish(teamName string, reviewers []string) *domain.AssignmentExplanation {
+	e.explanation.Reviewers = reviewers
+	if len(reviewers) == 0 {
+		return e.explanation
+	}
+
+	e.explanation.TeamName = teamName
+	for _, pool := range e.explanation.Pools {
+		for j := range pool.Candidates {
+			pool.Candidates[j].Selected = slices.Contains(reviewers, pool.Candidates[j].UserId)
+		}
+	}
+	return e.explanation
+}
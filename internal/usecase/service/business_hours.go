@@ -0,0 +1,51 @@
+package service
+
+import "time"
+
+// BusinessHours рабочее время, в котором отсчитывается срок ревью:
+// с Start до End часов по будням в часовом поясе Location
+type BusinessHours struct {
+	Location *time.Location
+	Start    int
+	End      int
+}
+
+// DefaultBusinessHours рабочий день с 9 до 18 по UTC
+func DefaultBusinessHours() BusinessHours {
+	return BusinessHours{
+		Location: time.UTC,
+		Start:    9,
+		End:      18,
+	}
+}
+
+// Deadline возвращает момент, когда с from пройдет hours рабочих часов.
+// Время вне рабочего дня и выходные не считаются
+func (b BusinessHours) Deadline(from time.Time, hours int) time.Time {
+	if hours <= 0 {
+		return from
+	}
+
+	remaining := time.Duration(hours) * time.Hour
+	t := from.In(b.Location)
+	for {
+		dayStart := time.Date(t.Year(), t.Month(), t.Day(), b.Start, 0, 0, 0, b.Location)
+		dayEnd := time.Date(t.Year(), t.Month(), t.Day(), b.End, 0, 0, 0, b.Location)
+		if isWorkday(t.Weekday()) && t.Before(dayEnd) {
+			if t.Before(dayStart) {
+				t = dayStart
+			}
+			left := dayEnd.Sub(t)
+			if remaining <= left {
+				return t.Add(remaining)
+			}
+			remaining -= left
+		}
+		// Переходим к началу следующего дня
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, b.Location)
+	}
+}
+
+func isWorkday(day time.Weekday) bool {
+	return day != time.Saturday && day != time.Sunday
+}
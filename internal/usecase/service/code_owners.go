@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+)
+
+const (
+	// Ограничения правил CODEOWNERS и списка измененных файлов PR
+	maxCodeOwnerRules      = 500
+	maxCodeOwnerPatternLen = 255
+	maxChangedPaths        = 1000
+)
+
+// parseCodeOwners разбирает содержимое в формате CODEOWNERS: одна строка - шаблон и владельцы
+// через пробел, "#" начинает комментарий. Владельцы указываются идентификаторами пользователей,
+// ведущий "@" допускается. Строка без владельцев снимает владельцев с подходящих файлов
+func parseCodeOwners(content string) ([]*domain.CodeOwnerRule, error) {
+	var rules []*domain.CodeOwnerRule
+	for number, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		// Отрезаем комментарий
+		for i, field := range fields {
+			if strings.HasPrefix(field, "#") {
+				fields = fields[:i]
+				break
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := fields[0]
+		if err := validateCodeOwnerPattern(pattern); err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+
+		owners := make([]string, 0, len(fields)-1)
+		for _, field := range fields[1:] {
+			owner := strings.TrimPrefix(field, "@")
+			if owner == "" {
+				return nil, fmt.Errorf("line %d: owner is empty", number+1)
+			}
+			if !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+
+		rules = append(rules, &domain.CodeOwnerRule{
+			Pattern: pattern,
+			Owners:  owners,
+		})
+		if len(rules) > maxCodeOwnerRules {
+			return nil, fmt.Errorf("too many rules, at most %d allowed", maxCodeOwnerRules)
+		}
+	}
+	return rules, nil
+}
+
+func validateCodeOwnerPattern(pattern string) error {
+	if len(pattern) > maxCodeOwnerPatternLen {
+		return fmt.Errorf("pattern is longer than %d characters", maxCodeOwnerPatternLen)
+	}
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return fmt.Errorf("pattern %q matches nothing", pattern)
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment == "" {
+			return fmt.Errorf("pattern %q has an empty path segment", pattern)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// normalizeChangedPaths приводит пути измененных файлов к виду относительно корня репозитория
+// и убирает повторы
+func normalizeChangedPaths(raw []string) ([]string, error) {
+	if len(raw) > maxChangedPaths {
+		return nil, fmt.Errorf("too many changed paths, at most %d allowed", maxChangedPaths)
+	}
+
+	paths := make([]string, 0, len(raw))
+	for _, rawPath := range raw {
+		filePath := strings.TrimSpace(rawPath)
+		if filePath == "" {
+			return nil, errors.New("changed path is empty")
+		}
+		filePath = strings.TrimPrefix(path.Clean("/"+filePath), "/")
+		if filePath == "" {
+			return nil, fmt.Errorf("changed path %q is not a file", rawPath)
+		}
+		if !slices.Contains(paths, filePath) {
+			paths = append(paths, filePath)
+		}
+	}
+	return paths, nil
+}
+
+// matchCodeOwners возвращает владельцев измененных файлов без повторов. Для каждого файла,
+// как и в CODEOWNERS, действует последнее подходящее правило
+func matchCodeOwners(rules []*domain.CodeOwnerRule, changedPaths []string) []string {
+	var owners []string
+	for _, filePath := range changedPaths {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if !matchCodeOwnerPattern(rules[i].Pattern, filePath) {
+				continue
+			}
+			for _, owner := range rules[i].Owners {
+				if !slices.Contains(owners, owner) {
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// matchCodeOwnerPattern сверяет путь с шаблоном по правилам gitignore: шаблон со "/" в начале
+// или в середине привязан к корню, иначе совпадает на любой глубине. "*" и "?" не выходят
+// за пределы каталога, "**" совпадает с любым числом каталогов. Шаблон, совпавший с каталогом,
+// покрывает все файлы внутри него
+func matchCodeOwnerPattern(pattern, filePath string) bool {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	patternSegments := strings.Split(strings.TrimPrefix(trimmed, "/"), "/")
+	if !anchored {
+		patternSegments = append([]string{"**"}, patternSegments...)
+	}
+	return matchPathSegments(patternSegments, strings.Split(filePath, "/"))
+}
+
+func matchPathSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		// Шаблон исчерпан: совпал сам файл или один из его каталогов
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPathSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchPathSegments(pattern[1:], segments[1:])
+}
+
+// ownersPool возвращает копию пула, в которой остались только владельцы кода
+func ownersPool(pool *result.PotentialReviewersResult, owners []string) *result.PotentialReviewersResult {
+	filtered := *pool
+	filtered.Candidates = nil
+	for _, candidate := range pool.Candidates {
+		if candidate != nil && candidate.User != nil && slices.Contains(owners, candidate.User.Id) {
+			filtered.Candidates = append(filtered.Candidates, candidate)
+		}
+	}
+	return &filtered
+}
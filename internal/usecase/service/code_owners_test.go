@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestMatchCodeOwnerPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "internal/service/pr.go", true},
+		{"*.go", "internal/service/pr.go", true},
+		{"*.go", "README.md", false},
+		{"docs/", "docs/api/openapi.yml", true},
+		{"docs", "internal/docs/readme.md", true},
+		{"/docs/", "internal/docs/readme.md", false},
+		{"/migrations/*.sql", "migrations/0001_init.up.sql", true},
+		{"/migrations/*.sql", "tools/migrations/0001_init.up.sql", false},
+		{"internal/service/", "internal/service/pr.go", true},
+		{"internal/service/", "cmd/internal/service/pr.go", false},
+		{"internal/**/handler", "internal/transport/http/handler/pr.go", true},
+		{"**/handler", "handler/pr.go", true},
+		{"pr_?.go", "pr_1.go", true},
+		{"pr_?.go", "pr_10.go", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchCodeOwnerPattern(tt.pattern, tt.path), "%s ~ %s", tt.pattern, tt.path)
+	}
+}
+
+func TestMatchCodeOwners_LastRuleWins(t *testing.T) {
+	rules := []*domain.CodeOwnerRule{
+		{Pattern: "*", Owners: []string{"default"}},
+		{Pattern: "*.go", Owners: []string{"gopher1", "gopher2"}},
+		{Pattern: "/docs/", Owners: []string{"writer"}},
+		{Pattern: "/docs/generated/", Owners: []string{}},
+	}
+
+	assert.Equal(t, []string{"gopher1", "gopher2"}, matchCodeOwners(rules, []string{"internal/pr.go"}))
+	assert.Equal(t, []string{"writer", "default"}, matchCodeOwners(rules, []string{"docs/a.md", "Makefile"}))
+	assert.Empty(t, matchCodeOwners(rules, []string{"docs/generated/api.md"}))
+	assert.Empty(t, matchCodeOwners(nil, []string{"internal/pr.go"}))
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	rules, err := parseCodeOwners("# comment\n\n*.go @u1 u2 u1\n/docs/ u3 # inline\n/generated/\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.CodeOwnerRule{
+		{Pattern: "*.go", Owners: []string{"u1", "u2"}},
+		{Pattern: "/docs/", Owners: []string{"u3"}},
+		{Pattern: "/generated/", Owners: []string{}},
+	}, rules)
+
+	rules, err = parseCodeOwners("")
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, content := range []string{"/ u1", "docs//api u1", "[a u1", "*.go @"} {
+		_, err := parseCodeOwners(content)
+		assert.Error(t, err, content)
+	}
+}
+
+func TestNormalizeChangedPaths(t *testing.T) {
+	paths, err := normalizeChangedPaths([]string{" /internal/pr.go", "./internal/pr.go", "docs/../README.md"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"internal/pr.go", "README.md"}, paths)
+
+	_, err = normalizeChangedPaths([]string{"  "})
+	assert.Error(t, err)
+	_, err = normalizeChangedPaths([]string{"/"})
+	assert.Error(t, err)
+}
+
+func TestSelectCreateReviewers_PicksCodeOwner(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "owner1", IsActive: true},
+		{Id: "owner2", IsActive: false},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+	})
+	settings := &domain.TeamSettings{ReviewerCount: 2}
+
+	for i := 0; i < 20; i++ {
+		reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, []string{"owner1", "owner2"}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, reviewers, 2)
+		assert.Equal(t, "owner1", reviewers[0])
+	}
+}
+
+func TestSelectCreateReviewers_LeadIsCodeOwner(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "lead", IsActive: true},
+		{Id: "owner1", IsActive: true},
+		{Id: "user1", IsActive: true},
+	})
+	settings := &domain.TeamSettings{ReviewerCount: 1, LeadUserId: "lead", RequireLead: true}
+
+	// Лид уже владеет файлами - отдельный владелец не добавляется
+	reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, []string{"lead", "owner1"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lead"}, reviewers)
+}
+
+func TestSelectCreateReviewers_NoEligibleCodeOwner(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "user1", IsActive: true},
+	})
+	settings := &domain.TeamSettings{ReviewerCount: 1}
+
+	// Единственный владелец - сам автор, место заполняет обычная стратегия
+	reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, []string{"author"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user1"}, reviewers)
+}
+
+func TestPrService_Create_AssignsCodeOwner(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "dba", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+	})
+	pool.Settings = &domain.TeamSettings{TeamId: pool.TeamId, ReviewerCount: 1}
+	pool.CodeOwners = []*domain.CodeOwnerRule{
+		{Pattern: "*", Owners: []string{"user1"}},
+		{Pattern: "/migrations/", Owners: []string{"dba"}},
+	}
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
+		return assert.ObjectsAreEqual([]string{"migrations/0013_add.up.sql"}, d.ChangedPaths)
+	}), []string{"dba"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"dba"},
+		ChangedPaths:      []string{"migrations/0013_add.up.sql"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:         "pr1",
+		PrName:       "Add migration",
+		AuthorId:     "author1",
+		ChangedPaths: []string{"/migrations/0013_add.up.sql"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dba"}, resp.AssignedReviewers)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_MarkReady_UsesStoredChangedPaths(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "frontend", IsActive: true},
+		{Id: "user1", IsActive: true},
+	})
+	pool.Settings = &domain.TeamSettings{TeamId: pool.TeamId, ReviewerCount: 1}
+	pool.CodeOwners = []*domain.CodeOwnerRule{{Pattern: "*.tsx", Owners: []string{"frontend"}}}
+
+	mockRepo.On("Get", mock.Anything, "pr1").Return(&result.PrResult{
+		Id:           "pr1",
+		AuthorId:     "author1",
+		Status:       domain.PrStatusDraft,
+		ChangedPaths: []string{"web/app.tsx"},
+	}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(d *dto.ChangePrStatusDTO) bool {
+		return assert.ObjectsAreEqual([]string{"frontend"}, d.Reviewers)
+	})).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"frontend"},
+	}, nil)
+
+	resp, err := service.MarkReady(context.Background(), &request.MarkReadyRequest{PrId: "pr1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"frontend"}, resp.AssignedReviewers)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/notifier"
+	"go.uber.org/zap"
+)
+
+var sendDigestsError = errors.New("send review digests error")
+
+type DigestRepository interface {
+	SelectReviewDigests(ctx context.Context) ([]*domain.ReviewDigest, error)
+}
+
+// Notifier доставляет сводку пользователю: письмом, в чат, в файл.
+// notifier.ErrNoRecipient означает, что у пользователя нет нужного контакта
+type Notifier interface {
+	Notify(ctx context.Context, digest *domain.ReviewDigest) error
+}
+
+// DigestService рассылает ревьюерам ежедневную сводку открытых PR, на которые они назначены
+type DigestService struct {
+	repo     DigestRepository
+	notifier Notifier
+	log      *zap.Logger
+	now      func() time.Time
+}
+
+func NewDigestService(repo DigestRepository, notifier Notifier, log *zap.Logger) *DigestService {
+	return &DigestService{
+		repo:     repo,
+		notifier: notifier,
+		log:      log,
+		now:      time.Now,
+	}
+}
+
+// SendDigests задача планировщика: отправляет сводку каждому ревьюеру с открытыми PR.
+// Ошибка доставки одной сводки не мешает отправить остальные
+func (s *DigestService) SendDigests(ctx context.Context) error {
+	digests, err := s.repo.SelectReviewDigests(ctx)
+	if err != nil {
+		s.log.Error("failed to load review digests", zap.Error(err))
+		return fmt.Errorf("%w: %w", sendDigestsError, err)
+	}
+
+	generatedAt := s.now()
+	var errs []error
+	sent, skipped := 0, 0
+	for _, digest := range digests {
+		digest.GeneratedAt = generatedAt
+		err := s.notifier.Notify(ctx, digest)
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, notifier.ErrNoRecipient):
+			s.log.Debug("review digest skipped, no contact", zap.String("user_id", digest.UserId))
+			skipped++
+		default:
+			s.log.Error("failed to send review digest",
+				zap.String("user_id", digest.UserId),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("user %s: %w", digest.UserId, err))
+		}
+	}
+
+	s.log.Info("review digests sent",
+		zap.Int("sent", sent),
+		zap.Int("skipped", skipped),
+		zap.Int("failed", len(errs)),
+	)
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", sendDigestsError, errors.Join(errs...))
+	}
+	return nil
+}
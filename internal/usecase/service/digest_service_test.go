@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockDigestRepository struct {
+	mock.Mock
+}
+
+func (m *MockDigestRepository) SelectReviewDigests(ctx context.Context) ([]*domain.ReviewDigest, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ReviewDigest), args.Error(1)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, digest *domain.ReviewDigest) error {
+	args := m.Called(ctx, digest)
+	return args.Error(0)
+}
+
+func digestFor(userId string) *domain.ReviewDigest {
+	return &domain.ReviewDigest{
+		UserId:   userId,
+		Username: userId,
+		Prs:      []*domain.Pr{{Id: "pr-" + userId, AuthorId: "author", Status: domain.PrStatusOpen}},
+	}
+}
+
+func TestDigestService_SendDigests(t *testing.T) {
+	mockRepo := new(MockDigestRepository)
+	mockNotifier := new(MockNotifier)
+	service := NewDigestService(mockRepo, mockNotifier, zap.NewNop())
+	now := time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	mockRepo.On("SelectReviewDigests", mock.Anything).Return([]*domain.ReviewDigest{digestFor("u1"), digestFor("u2")}, nil)
+	mockNotifier.On("Notify", mock.Anything, mock.MatchedBy(func(d *domain.ReviewDigest) bool {
+		return d.GeneratedAt.Equal(now)
+	})).Return(nil).Twice()
+
+	assert.NoError(t, service.SendDigests(context.Background()))
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestDigestService_SkipsWithoutContactAndContinuesAfterError(t *testing.T) {
+	mockRepo := new(MockDigestRepository)
+	mockNotifier := new(MockNotifier)
+	service := NewDigestService(mockRepo, mockNotifier, zap.NewNop())
+
+	mockRepo.On("SelectReviewDigests", mock.Anything).Return([]*domain.ReviewDigest{digestFor("u1"), digestFor("u2"), digestFor("u3")}, nil)
+	byUser := func(userId string) any {
+		return mock.MatchedBy(func(d *domain.ReviewDigest) bool { return d.UserId == userId })
+	}
+	mockNotifier.On("Notify", mock.Anything, byUser("u1")).Return(notifier.ErrNoRecipient)
+	mockNotifier.On("Notify", mock.Anything, byUser("u2")).Return(errors.New("smtp down"))
+	mockNotifier.On("Notify", mock.Anything, byUser("u3")).Return(nil)
+
+	err := service.SendDigests(context.Background())
+
+	// Пропуск без контакта не считается ошибкой, сбой доставки не мешает следующим сводкам
+	assert.ErrorIs(t, err, sendDigestsError)
+	assert.ErrorContains(t, err, "user u2: smtp down")
+	assert.NotContains(t, err.Error(), "u1")
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestDigestService_RepositoryError(t *testing.T) {
+	mockRepo := new(MockDigestRepository)
+	mockNotifier := new(MockNotifier)
+	service := NewDigestService(mockRepo, mockNotifier, zap.NewNop())
+
+	dbErr := errors.New("db down")
+	mockRepo.On("SelectReviewDigests", mock.Anything).Return(nil, dbErr)
+
+	err := service.SendDigests(context.Background())
+
+	assert.ErrorIs(t, err, dbErr)
+	mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
@@ -48,6 +48,30 @@ var (
 		Code:    "NOT_FOUND",
 		Message: "pull request not found",
 	}
+	ErrFallbackTeamNotFound = &DomainError{
+		Code:    "NOT_FOUND",
+		Message: "fallback team not found",
+	}
+	ErrAvailabilityNotFound = &DomainError{
+		Code:    "NOT_FOUND",
+		Message: "availability period not found",
+	}
+	ErrWebhookNotFound = &DomainError{
+		Code:    "NOT_FOUND",
+		Message: "webhook not found",
+	}
+	ErrIntegrationUserNotFound = &DomainError{
+		Code:    "NOT_FOUND",
+		Message: "provider user is not mapped to a service user",
+	}
+	ErrDeliveryNotFound = &DomainError{
+		Code:    "NOT_FOUND",
+		Message: "webhook delivery not found",
+	}
+	ErrNotTeamMember = &DomainError{
+		Code:    "NOT_FOUND",
+		Message: "user is not a member of the team",
+	}
 
 	// TEAM_EXISTS
 	ErrTeamExists = &DomainError{
@@ -55,6 +79,18 @@ var (
 		Message: "team_name already exists",
 	}
 
+	// USER_EXISTS
+	ErrUserExists = &DomainError{
+		Code:    "USER_EXISTS",
+		Message: "user_id already exists",
+	}
+
+	// USER_IN_TEAM
+	ErrUserInAnotherTeam = &DomainError{
+		Code:    "USER_IN_TEAM",
+		Message: "user already belongs to another team",
+	}
+
 	// PR_EXISTS
 	ErrPrExists = &DomainError{
 		Code:    "PR_EXISTS",
@@ -67,15 +103,158 @@ var (
 		Message: "cannot reassign on merged PR",
 	}
 
+	ErrReviewOnMergedPr = &DomainError{
+		Code:    "PR_MERGED",
+		Message: "cannot review merged PR",
+	}
+
+	// NOT_APPROVED
+	ErrNotApproved = &DomainError{
+		Code:    "NOT_APPROVED",
+		Message: "PR does not have enough approvals to be merged",
+	}
+
+	// PR_CLOSED
+	ErrPrClosed = &DomainError{
+		Code:    "PR_CLOSED",
+		Message: "PR is closed",
+	}
+
+	// INVALID_TRANSITION
+	ErrInvalidTransition = &DomainError{
+		Code:    "INVALID_TRANSITION",
+		Message: "PR status transition is not allowed",
+	}
+
 	// NOT_ASSIGNED
 	ErrReviewerNotAssigned = &DomainError{
 		Code:    "NOT_ASSIGNED",
 		Message: "reviewer is not assigned to this PR",
 	}
 
+	// INVALID_INPUT
+	ErrInvalidTeamSettings = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid team settings",
+	}
+	ErrLeadNotTeamMember = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "team lead must be a member of the team",
+	}
+	ErrEmptyUserIds = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "user_ids must not be empty",
+	}
+	ErrUsersNotTeamMembers = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "all users must be members of the team",
+	}
+	ErrEmptyMembers = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "members must not be empty",
+	}
+	ErrInvalidTeamName = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid team name",
+	}
+	ErrRemoveTeamLead = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "team lead cannot be removed, change lead_user_id or role first",
+	}
+	ErrDemoteTeamLead = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "team lead from settings cannot be demoted, change lead_user_id first",
+	}
+	ErrInvalidParentTeam = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "parent team cannot be the team itself or one of its subteams",
+	}
+	ErrInvalidTeamRole = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "role must be MEMBER or LEAD",
+	}
+	ErrInvalidMaxOpenReviews = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "max_open_reviews must be positive or null",
+	}
+	ErrInvalidAvailability = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid availability period",
+	}
+	ErrInvalidReason = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid reassignment reason",
+	}
+	ErrInvalidVerdict = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "verdict must be one of APPROVED, CHANGES_REQUESTED, COMMENTED",
+	}
+	ErrInvalidWebhook = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid webhook",
+	}
+	ErrInvalidDeliveryFilter = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid webhook deliveries filter",
+	}
+	ErrInvalidIntegrationUser = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid integration user mapping",
+	}
+	ErrInvalidProviderEvent = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "malformed provider event",
+	}
+	ErrInvalidCodeOwners = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid CODEOWNERS ruleset",
+	}
+	ErrCodeOwnersNotTeamMembers = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "code owners must be members of the team",
+	}
+	ErrInvalidChangedPaths = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid changed file paths",
+	}
+	ErrInvalidTags = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid tags",
+	}
+	ErrInvalidContacts = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid user contacts",
+	}
+	ErrInvalidEventFilter = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid event stream filter",
+	}
+	ErrInvalidUser = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "invalid user",
+	}
+	ErrEmptyUserUpdate = &DomainError{
+		Code:    "INVALID_INPUT",
+		Message: "no user fields to update",
+	}
+
+	// UNAUTHORIZED
+	ErrInvalidSignature = &DomainError{
+		Code:    "UNAUTHORIZED",
+		Message: "invalid provider signature",
+	}
+
 	// NO_CANDIDATE
 	ErrNoCandidate = &DomainError{
 		Code:    "NO_CANDIDATE",
 		Message: "no active replacement candidate in team",
 	}
+	ErrOpenReviewsNoCandidate = &DomainError{
+		Code:    "NO_CANDIDATE",
+		Message: "open reviews of leaving members cannot be reassigned",
+	}
+	ErrNoLeadCandidate = &DomainError{
+		Code:    "NO_CANDIDATE",
+		Message: "no available team lead to review the PR",
+	}
 )
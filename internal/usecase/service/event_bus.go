@@ -0,0 +1,170 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultEventHistorySize = 1000
+	defaultSubscriberBuffer = 64
+)
+
+// EventPublisher получает события о назначениях после того, как изменение записано в бд
+type EventPublisher interface {
+	Publish(event *domain.ActivityEvent)
+}
+
+// EventBus внутрипроцессная шина событий для живых подписчиков. Хранит последние события,
+// чтобы переподключившийся клиент получил пропущенное по Last-Event-ID.
+// События живут только в памяти экземпляра: после перезапуска история начинается заново
+type EventBus struct {
+	mu          sync.Mutex
+	lastId      uint64
+	history     []*domain.ActivityEvent
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+	log         *zap.Logger
+}
+
+// Subscription подписка на шину. Канал Events закрывается при отписке, остановке шины
+// или если подписчик не успевает читать события
+type Subscription struct {
+	events chan *domain.ActivityEvent
+	match  func(*domain.ActivityEvent) bool
+	bus    *EventBus
+}
+
+func NewEventBus(historySize, bufferSize int, log *zap.Logger) *EventBus {
+	if historySize <= 0 {
+		historySize = defaultEventHistorySize
+	}
+	if bufferSize <= 0 {
+		bufferSize = defaultSubscriberBuffer
+	}
+	return &EventBus{
+		// Идентификаторы начинаются с времени запуска в микросекундах, поэтому растут и между
+		// перезапусками: старый Last-Event-ID распознается как разрыв, а не как событие из будущего
+		lastId:      uint64(time.Now().UnixMicro()),
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+		log:         log,
+	}
+}
+
+// Publish присваивает событию идентификатор и рассылает его подписчикам. Не блокируется
+func (b *EventBus) Publish(event *domain.ActivityEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.lastId++
+	event.Id = b.lastId
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = append(b.history[:0], b.history[len(b.history)-b.historySize:]...)
+	}
+
+	for sub := range b.subscribers {
+		if sub.match != nil && !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Подписчик не успевает читать - отключаем его, клиент переподключится с Last-Event-ID
+			b.log.Warn("event subscriber is too slow, dropping it", zap.Uint64("event_id", event.Id))
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe регистрирует подписчика и возвращает события после lastEventId, подходящие под match.
+// Для нового подключения (resume = false) пропущенные события не возвращаются.
+// complete = false, если часть пропущенных событий уже вытеснена из истории
+func (b *EventBus) Subscribe(lastEventId uint64, resume bool, match func(*domain.ActivityEvent) bool) (*Subscription, []*domain.ActivityEvent, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		events: make(chan *domain.ActivityEvent, b.bufferSize),
+		match:  match,
+		bus:    b,
+	}
+	if b.closed {
+		close(sub.events)
+		return sub, nil, true
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if !resume {
+		return sub, nil, true
+	}
+
+	// Идентификатор из будущего или старше начала истории - продолжить без потерь нельзя
+	complete := lastEventId <= b.lastId
+	if len(b.history) > 0 && lastEventId < b.history[0].Id-1 {
+		complete = false
+	}
+	if len(b.history) == 0 && lastEventId != b.lastId {
+		complete = false
+	}
+
+	var replay []*domain.ActivityEvent
+	for _, event := range b.history {
+		if event.Id <= lastEventId {
+			continue
+		}
+		if match != nil && !match(event) {
+			continue
+		}
+		replay = append(replay, event)
+	}
+
+	// Ответ
+	return sub, replay, complete
+}
+
+// Close останавливает шину и закрывает каналы всех подписчиков
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (s *Subscription) Events() <-chan *domain.ActivityEvent {
+	return s.events
+}
+
+// Close отписывает подписчика. Повторный вызов безопасен
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, ok := s.bus.subscribers[s]; ok {
+		delete(s.bus.subscribers, s)
+		close(s.events)
+	}
+}
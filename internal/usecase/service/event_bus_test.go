@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockTeamMembersRepository мок чтения состава команды для тестов
+type MockTeamMembersRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamMembersRepository) Get(ctx context.Context, d *dto.GetTeamDTO) (*result.GetTeamResult, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.GetTeamResult), args.Error(1)
+}
+
+func activity(eventType string, userIds ...string) *domain.ActivityEvent {
+	return &domain.ActivityEvent{Type: eventType, UserIds: userIds}
+}
+
+func receive(t *testing.T, sub *Subscription) *domain.ActivityEvent {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+		return nil
+	}
+}
+
+func TestEventBus_PublishAssignsIncreasingIds(t *testing.T) {
+	bus := NewEventBus(10, 10, zap.NewNop())
+	sub, replay, complete := bus.Subscribe(0, false, nil)
+	defer sub.Close()
+
+	assert.Empty(t, replay)
+	assert.True(t, complete)
+
+	bus.Publish(activity(domain.EventPrCreated, "u1"))
+	bus.Publish(activity(domain.EventReviewerAssigned, "u1", "u2"))
+
+	first := receive(t, sub)
+	second := receive(t, sub)
+	assert.Equal(t, domain.EventPrCreated, first.Type)
+	assert.Equal(t, first.Id+1, second.Id)
+	assert.False(t, first.CreatedAt.IsZero())
+}
+
+func TestEventBus_SubscribeResumesFromLastEventId(t *testing.T) {
+	bus := NewEventBus(10, 10, zap.NewNop())
+	first := activity(domain.EventPrCreated, "u1")
+	bus.Publish(first)
+	bus.Publish(activity(domain.EventReviewerAssigned, "u1", "u2"))
+	bus.Publish(activity(domain.EventReviewerAssigned, "u3", "u4"))
+
+	onlyU2 := func(event *domain.ActivityEvent) bool {
+		for _, id := range event.UserIds {
+			if id == "u2" {
+				return true
+			}
+		}
+		return false
+	}
+	sub, replay, complete := bus.Subscribe(first.Id, true, onlyU2)
+	defer sub.Close()
+
+	assert.True(t, complete)
+	assert.Len(t, replay, 1)
+	assert.Equal(t, first.Id+1, replay[0].Id)
+}
+
+func TestEventBus_SubscribeDetectsGap(t *testing.T) {
+	bus := NewEventBus(2, 10, zap.NewNop())
+	first := activity(domain.EventPrCreated, "u1")
+	bus.Publish(first)
+	bus.Publish(activity(domain.EventPrMerged, "u1"))
+	bus.Publish(activity(domain.EventPrCreated, "u2"))
+	bus.Publish(activity(domain.EventPrMerged, "u2"))
+
+	// Событие first+1 вытеснено из истории
+	_, replay, complete := bus.Subscribe(first.Id, true, nil)
+	assert.False(t, complete)
+	assert.Len(t, replay, 2)
+
+	// Идентификатор из прошлого запуска
+	_, _, complete = bus.Subscribe(1, true, nil)
+	assert.False(t, complete)
+
+	// Идентификатор из будущего
+	_, replay, complete = bus.Subscribe(first.Id+100, true, nil)
+	assert.False(t, complete)
+	assert.Empty(t, replay)
+
+	// Последнее событие уже получено - пропусков нет
+	_, replay, complete = bus.Subscribe(first.Id+3, true, nil)
+	assert.True(t, complete)
+	assert.Empty(t, replay)
+}
+
+func TestEventBus_DropsSlowSubscriber(t *testing.T) {
+	bus := NewEventBus(10, 1, zap.NewNop())
+	sub, _, _ := bus.Subscribe(0, false, nil)
+
+	bus.Publish(activity(domain.EventPrCreated, "u1"))
+	bus.Publish(activity(domain.EventPrMerged, "u1"))
+
+	receive(t, sub)
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	// Повторная отписка безопасна
+	sub.Close()
+}
+
+func TestEventBus_CloseStopsSubscribers(t *testing.T) {
+	bus := NewEventBus(10, 10, zap.NewNop())
+	sub, _, _ := bus.Subscribe(0, false, nil)
+
+	bus.Close()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+
+	// После остановки подписка сразу закрыта, публикация игнорируется
+	late, _, _ := bus.Subscribe(0, false, nil)
+	bus.Publish(activity(domain.EventPrCreated, "u1"))
+	_, ok = <-late.Events()
+	assert.False(t, ok)
+}
+
+func TestEventStreamService_Subscribe_TeamAndUserFilters(t *testing.T) {
+	bus := NewEventBus(10, 10, zap.NewNop())
+	teams := new(MockTeamMembersRepository)
+	service := NewEventStreamService(bus, teams, zap.NewNop())
+
+	teams.On("Get", mock.Anything, &dto.GetTeamDTO{TeamName: "backend"}).Return(&result.GetTeamResult{
+		TeamName: "backend",
+		Members:  []*domain.User{{Id: "u1"}, {Id: "u2"}},
+	}, nil)
+
+	stream, err := service.Subscribe(context.Background(), &request.EventStreamRequest{TeamName: " backend ", UserId: "u2"})
+	assert.NoError(t, err)
+	defer stream.Close()
+
+	bus.Publish(activity(domain.EventPrCreated, "u1"))
+	bus.Publish(activity(domain.EventReviewerAssigned, "u9", "u2"))
+	bus.Publish(activity(domain.EventReviewerAssigned, "u3", "u4"))
+
+	event := <-stream.Events()
+	assert.Equal(t, []string{"u9", "u2"}, event.UserIds)
+	select {
+	case extra := <-stream.Events():
+		t.Fatalf("unexpected event %v", extra)
+	default:
+	}
+	teams.AssertExpectations(t)
+}
+
+func TestEventStreamService_Subscribe_Errors(t *testing.T) {
+	bus := NewEventBus(10, 10, zap.NewNop())
+	teams := new(MockTeamMembersRepository)
+	service := NewEventStreamService(bus, teams, zap.NewNop())
+
+	teams.On("Get", mock.Anything, &dto.GetTeamDTO{TeamName: "missing"}).Return(nil, repository.ErrNotFound)
+
+	_, err := service.Subscribe(context.Background(), &request.EventStreamRequest{TeamName: "missing"})
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+
+	_, err = service.Subscribe(context.Background(), &request.EventStreamRequest{LastEventId: "abc"})
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+}
+
+func TestPrService_PublishesActivityEvents(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	bus := NewEventBus(10, 10, zap.NewNop())
+	service := NewPrService(mockRepo, testSelectors(t), bus, zap.NewNop())
+	sub, _, _ := bus.Subscribe(0, false, nil)
+	defer sub.Close()
+
+	pr := &result.PrResult{
+		Id:                "pr1",
+		Name:              "Test PR",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"reviewer1"},
+		CreatedAt:         time.Now(),
+	}
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(toPool([]*domain.User{
+		{Id: "reviewer1", IsActive: true},
+	}), nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(pr, nil)
+
+	_, err := service.Create(context.Background(), &request.CreateRequest{PrId: "pr1", PrName: "Test PR", AuthorId: "author1"})
+	assert.NoError(t, err)
+
+	created := receive(t, sub)
+	assert.Equal(t, domain.EventPrCreated, created.Type)
+	assert.Equal(t, []string{"author1", "reviewer1"}, created.UserIds)
+	assigned := receive(t, sub)
+	assert.Equal(t, domain.EventReviewerAssigned, assigned.Type)
+	assert.Equal(t, "reviewer1", assigned.Payload.(*domain.ReviewerEventPayload).UserId)
+
+	// Повторный merge уже смерженного PR событий не порождает
+	merged := &result.PrResult{Id: "pr1", AuthorId: "author1", Status: domain.PrStatusMerged}
+	mockRepo.On("Merge", mock.Anything, mock.Anything).Return(&result.MergeResult{Pr: merged}, nil)
+
+	_, err = service.Merge(context.Background(), &request.MergeRequest{PrId: "pr1"})
+	assert.NoError(t, err)
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("unexpected event %s", event.Type)
+	default:
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"go.uber.org/zap"
+)
+
+var (
+	subscribeEventsError = errors.New("subscribe to events error")
+)
+
+// Интерфейс репозитория
+type TeamMembersRepository interface {
+	Get(ctx context.Context, dto *dto.GetTeamDTO) (*result.GetTeamResult, error)
+}
+
+// EventStream открытая подписка на события. Replay - пропущенные события после Last-Event-ID.
+// Complete = false, если часть пропущенных событий уже недоступна и клиенту нужно перечитать состояние
+type EventStream struct {
+	Replay   []*domain.ActivityEvent
+	Complete bool
+	sub      *Subscription
+}
+
+func (s *EventStream) Events() <-chan *domain.ActivityEvent {
+	return s.sub.Events()
+}
+
+func (s *EventStream) Close() {
+	s.sub.Close()
+}
+
+type EventStreamService struct {
+	bus   *EventBus
+	teams TeamMembersRepository
+	log   *zap.Logger
+}
+
+func NewEventStreamService(bus *EventBus, teams TeamMembersRepository, log *zap.Logger) *EventStreamService {
+	return &EventStreamService{
+		bus:   bus,
+		teams: teams,
+		log:   log,
+	}
+}
+
+// Subscribe открывает подписку с фильтрами. Состав команды фиксируется в момент подключения
+func (s *EventStreamService) Subscribe(ctx context.Context, req *request.EventStreamRequest) (*EventStream, error) {
+	var lastEventId uint64
+	resume := strings.TrimSpace(req.LastEventId) != ""
+	if resume {
+		id, err := strconv.ParseUint(strings.TrimSpace(req.LastEventId), 10, 64)
+		if err != nil {
+			return nil, WrapError(ErrInvalidEventFilter, fmt.Errorf("last event id must be a non-negative integer: %w", err))
+		}
+		lastEventId = id
+	}
+
+	var filters []func(*domain.ActivityEvent) bool
+
+	userId := strings.TrimSpace(req.UserId)
+	if userId != "" {
+		filters = append(filters, func(event *domain.ActivityEvent) bool {
+			return slices.Contains(event.UserIds, userId)
+		})
+	}
+
+	teamName := strings.TrimSpace(req.TeamName)
+	if teamName != "" {
+		// Читаем состав команды
+		team, err := s.teams.Get(ctx, &dto.GetTeamDTO{TeamName: teamName})
+		if err != nil {
+			s.log.Error("failed to load team for event stream", zap.String("team_name", teamName), zap.Error(err))
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, WrapError(ErrTeamNotFound, err)
+			}
+			return nil, fmt.Errorf("%w: %w", subscribeEventsError, err)
+		}
+
+		members := make(map[string]struct{}, len(team.Members))
+		for _, member := range team.Members {
+			members[member.Id] = struct{}{}
+		}
+		filters = append(filters, func(event *domain.ActivityEvent) bool {
+			for _, id := range event.UserIds {
+				if _, ok := members[id]; ok {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	var match func(*domain.ActivityEvent) bool
+	if len(filters) > 0 {
+		match = func(event *domain.ActivityEvent) bool {
+			for _, filter := range filters {
+				if !filter(event) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	sub, replay, complete := s.bus.Subscribe(lastEventId, resume, match)
+	s.log.Info("event stream subscribed",
+		zap.String("team_name", teamName),
+		zap.String("user_id", userId),
+		zap.Bool("resume", resume),
+		zap.Int("replay", len(replay)),
+		zap.Bool("complete", complete),
+	)
+
+	// Ответ
+	return &EventStream{
+		Replay:   replay,
+		Complete: complete,
+		sub:      sub,
+	}, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+)
+
+const (
+	githubPullRequestEvent  = "pull_request"
+	gitlabMergeRequestEvent = "Merge Request Hook"
+	githubSignaturePrefix   = "sha256="
+)
+
+var (
+	integrationDisabledError = errors.New("integration is not configured")
+	signatureMismatchError   = errors.New("signature mismatch")
+)
+
+type githubPullRequestPayload struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+type gitlabMergeRequestPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		Iid            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+// verifyGitHubSignature проверяет заголовок X-Hub-Signature-256: HMAC-SHA256 тела с секретом вебхука
+func verifyGitHubSignature(secret, signature string, body []byte) error {
+	if secret == "" {
+		return integrationDisabledError
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, githubSignaturePrefix))
+	if err != nil || !strings.HasPrefix(signature, githubSignaturePrefix) {
+		return signatureMismatchError
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return signatureMismatchError
+	}
+	return nil
+}
+
+// verifyGitLabToken GitLab не подписывает тело, а передает секрет в заголовке X-Gitlab-Token
+func verifyGitLabToken(secret, token string) error {
+	if secret == "" {
+		return integrationDisabledError
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(token)) != 1 {
+		return signatureMismatchError
+	}
+	return nil
+}
+
+// parseGitHubEvent возвращает nil для событий, которые не меняют PR в сервисе
+func parseGitHubEvent(eventType string, body []byte) (*providerPrEvent, error) {
+	if eventType != githubPullRequestEvent {
+		return nil, nil
+	}
+
+	var payload githubPullRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode github payload: %w", err)
+	}
+
+	var action string
+	switch payload.Action {
+	case "opened":
+		action = prActionOpen
+	case "reopened":
+		action = prActionReopen
+	case "ready_for_review":
+		action = prActionMarkReady
+	case "closed":
+		action = prActionClose
+		if payload.PullRequest.Merged {
+			action = prActionMerge
+		}
+	default:
+		return nil, nil
+	}
+
+	if payload.Repository.FullName == "" || payload.PullRequest.Number <= 0 {
+		return nil, errors.New("repository and pull request number are required")
+	}
+
+	return &providerPrEvent{
+		pr: domain.ExternalPr{
+			Provider:   domain.ProviderGitHub,
+			Repository: payload.Repository.FullName,
+			Number:     payload.PullRequest.Number,
+		},
+		action:      action,
+		title:       payload.PullRequest.Title,
+		authorLogin: payload.PullRequest.User.Login,
+		actorLogin:  payload.Sender.Login,
+		draft:       payload.PullRequest.Draft,
+	}, nil
+}
+
+// parseGitLabEvent возвращает nil для событий, которые не меняют PR в сервисе.
+// В событии открытия GitLab передает только числовой id автора, поэтому автором считается
+// пользователь, открывший merge request
+func parseGitLabEvent(eventType string, body []byte) (*providerPrEvent, error) {
+	if eventType != gitlabMergeRequestEvent {
+		return nil, nil
+	}
+
+	var payload gitlabMergeRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode gitlab payload: %w", err)
+	}
+	attrs := payload.ObjectAttributes
+
+	var action string
+	switch attrs.Action {
+	case "open":
+		action = prActionOpen
+	case "reopen":
+		action = prActionReopen
+	case "close":
+		action = prActionClose
+	case "merge":
+		action = prActionMerge
+	case "update":
+		// Снятие пометки Draft
+		if draft := payload.Changes.Draft; draft != nil && draft.Previous && !draft.Current {
+			action = prActionMarkReady
+		}
+	}
+	if action == "" {
+		return nil, nil
+	}
+
+	if payload.Project.PathWithNamespace == "" || attrs.Iid <= 0 {
+		return nil, errors.New("project and merge request iid are required")
+	}
+
+	return &providerPrEvent{
+		pr: domain.ExternalPr{
+			Provider:   domain.ProviderGitLab,
+			Repository: payload.Project.PathWithNamespace,
+			Number:     attrs.Iid,
+		},
+		action:      action,
+		title:       attrs.Title,
+		authorLogin: payload.User.Username,
+		actorLogin:  payload.User.Username,
+		draft:       attrs.Draft || attrs.WorkInProgress,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	reviewerReportTimeout = 10 * time.Second
+	// Пауза перед повторной подпиской, если шина отключила отстающего подписчика
+	reporterResubscribeDelay = time.Second
+)
+
+// ReviewerClient сообщает провайдеру о назначенных и снятых ревьюерах PR
+type ReviewerClient interface {
+	AddReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error
+	RemoveReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error
+}
+
+// Интерфейс репозитория
+type ExternalUsersRepository interface {
+	FindExternalUsers(ctx context.Context, provider string, userIds []string) ([]*domain.IntegrationUser, error)
+}
+
+// ReviewerReporter передает провайдерам назначения ревьюеров на PR, созданные интеграцией.
+// Читает события из шины, поэтому о назначениях, сделанных во время перезапуска, провайдер не узнает
+type ReviewerReporter struct {
+	bus     *EventBus
+	users   ExternalUsersRepository
+	clients map[string]ReviewerClient
+	log     *zap.Logger
+}
+
+// NewReviewerReporter clients - клиенты по провайдерам, провайдеры без клиента пропускаются
+func NewReviewerReporter(bus *EventBus, users ExternalUsersRepository, clients map[string]ReviewerClient, log *zap.Logger) *ReviewerReporter {
+	return &ReviewerReporter{
+		bus:     bus,
+		users:   users,
+		clients: clients,
+		log:     log,
+	}
+}
+
+// Run обрабатывает события до отмены ctx
+func (r *ReviewerReporter) Run(ctx context.Context) {
+	r.log.Info("reviewer reporter started")
+	defer r.log.Info("reviewer reporter stopped")
+
+	var lastEventId uint64
+	resume := false
+	for {
+		sub, replay, complete := r.bus.Subscribe(lastEventId, resume, r.match)
+		if !complete {
+			r.log.Warn("reviewer reporter missed events", zap.Uint64("last_event_id", lastEventId))
+		}
+		for _, event := range replay {
+			r.Report(ctx, event)
+			lastEventId = event.Id
+		}
+
+	receive:
+		for {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return
+			case event, ok := <-sub.Events():
+				if !ok {
+					break receive
+				}
+				r.Report(ctx, event)
+				lastEventId = event.Id
+			}
+		}
+
+		// Подписку закрыла шина - подписываемся снова с последнего обработанного события
+		resume = true
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reporterResubscribeDelay):
+		}
+	}
+}
+
+func (r *ReviewerReporter) match(event *domain.ActivityEvent) bool {
+	if event.Type != domain.EventReviewerAssigned && event.Type != domain.EventReviewerReassigned {
+		return false
+	}
+	pr, ok := domain.ParseExternalPrId(event.PrId)
+	if !ok {
+		return false
+	}
+	_, ok = r.clients[pr.Provider]
+	return ok
+}
+
+// Report передает провайдеру одно событие. Ошибки провайдера логируются и не повторяются
+func (r *ReviewerReporter) Report(ctx context.Context, event *domain.ActivityEvent) {
+	pr, ok := domain.ParseExternalPrId(event.PrId)
+	payload, isReviewer := event.Payload.(*domain.ReviewerEventPayload)
+	client := r.clients[pr.Provider]
+	if !ok || !isReviewer || client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reviewerReportTimeout)
+	defer cancel()
+
+	added := payload.UserId
+	if event.Type == domain.EventReviewerReassigned {
+		added = payload.NewUserId
+		if payload.OldUserId != "" {
+			r.send(ctx, pr, []string{payload.OldUserId}, client.RemoveReviewers)
+		}
+	}
+	if added != "" {
+		r.send(ctx, pr, []string{added}, client.AddReviewers)
+	}
+}
+
+func (r *ReviewerReporter) send(
+	ctx context.Context,
+	pr domain.ExternalPr,
+	userIds []string,
+	call func(context.Context, domain.ExternalPr, []*domain.IntegrationUser) error,
+) {
+	users, err := r.users.FindExternalUsers(ctx, pr.Provider, userIds)
+	if err != nil {
+		r.log.Error("failed to load provider users", zap.String("provider", pr.Provider), zap.Error(err))
+		return
+	}
+	if len(users) == 0 {
+		r.log.Info("reviewers are not mapped to provider users",
+			zap.String("pr_id", pr.PrId()),
+			zap.Strings("user_ids", userIds),
+		)
+		return
+	}
+
+	if err := call(ctx, pr, users); err != nil {
+		r.log.Error("failed to report reviewers to provider",
+			zap.String("pr_id", pr.PrId()),
+			zap.Strings("user_ids", userIds),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	setIntegrationUserError    = errors.New("set integration user error")
+	listIntegrationUsersError  = errors.New("list integration users error")
+	deleteIntegrationUserError = errors.New("delete integration user error")
+	providerEventError         = errors.New("provider event error")
+)
+
+// Действие над PR из события провайдера в дополнение к действиям жизненного цикла
+const prActionOpen = "open"
+
+// Статусы обработки события провайдера
+const (
+	providerEventProcessed = "processed"
+	providerEventIgnored   = "ignored"
+)
+
+// Интерфейс репозитория
+type IntegrationRepository interface {
+	SetUser(ctx context.Context, dto *dto.SetIntegrationUserDTO) (*domain.IntegrationUser, error)
+	ListUsers(ctx context.Context, provider string) ([]*domain.IntegrationUser, error)
+	DeleteUser(ctx context.Context, dto *dto.DeleteIntegrationUserDTO) error
+	FindUserId(ctx context.Context, provider, login string) (string, error)
+}
+
+// PullRequestActions операции над PR, которые вызывает интеграция
+type PullRequestActions interface {
+	Create(ctx context.Context, req *request.CreateRequest) (*response.CreateResponse, error)
+	Merge(ctx context.Context, req *request.MergeRequest) (*response.MergeResponse, error)
+	Close(ctx context.Context, req *request.ClosePrRequest) (*response.StatusChangeResponse, error)
+	Reopen(ctx context.Context, req *request.ReopenPrRequest) (*response.StatusChangeResponse, error)
+	MarkReady(ctx context.Context, req *request.MarkReadyRequest) (*response.StatusChangeResponse, error)
+}
+
+// IntegrationConfig секреты входящих вебхуков. Пустой секрет отключает прием событий провайдера
+type IntegrationConfig struct {
+	GitHubSecret string
+	GitLabToken  string
+}
+
+// providerPrEvent событие провайдера, приведенное к действию над PR
+type providerPrEvent struct {
+	pr          domain.ExternalPr
+	action      string
+	title       string
+	authorLogin string
+	actorLogin  string
+	draft       bool
+}
+
+type IntegrationService struct {
+	repo IntegrationRepository
+	prs  PullRequestActions
+	cfg  IntegrationConfig
+	log  *zap.Logger
+}
+
+func NewIntegrationService(repo IntegrationRepository, prs PullRequestActions, cfg IntegrationConfig, log *zap.Logger) *IntegrationService {
+	return &IntegrationService{
+		repo: repo,
+		prs:  prs,
+		cfg:  cfg,
+		log:  log,
+	}
+}
+
+func (s *IntegrationService) SetUser(ctx context.Context, req *request.SetIntegrationUserRequest) (*response.IntegrationUserResponse, error) {
+	provider, err := normalizeProvider(req.Provider)
+	if err != nil {
+		return nil, WrapError(ErrInvalidIntegrationUser, err)
+	}
+	login := normalizeLogin(req.ExternalLogin)
+	if login == "" {
+		return nil, WrapError(ErrInvalidIntegrationUser, errors.New("external_login is empty"))
+	}
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrInvalidIntegrationUser, err)
+	}
+	s.log.Info("set integration user request accepted",
+		zap.String("provider", provider),
+		zap.String("external_login", login),
+		zap.String("user_id", userId),
+	)
+
+	// Запрос в бд
+	user, err := s.repo.SetUser(ctx, &dto.SetIntegrationUserDTO{
+		Provider:      provider,
+		ExternalLogin: login,
+		ExternalId:    strings.TrimSpace(req.ExternalId),
+		UserId:        userId,
+	})
+	if err != nil {
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, WrapError(ErrInvalidIntegrationUser, fmt.Errorf("user %s already has a %s login", userId, provider))
+		}
+		return nil, fmt.Errorf("%w: %w", setIntegrationUserError, err)
+	}
+
+	// Ответ
+	res := toIntegrationUserResponse(user)
+	return &res, nil
+}
+
+func (s *IntegrationService) ListUsers(ctx context.Context, req *request.ListIntegrationUsersRequest) (*response.ListIntegrationUsersResponse, error) {
+	var provider string
+	if strings.TrimSpace(req.Provider) != "" {
+		var err error
+		if provider, err = normalizeProvider(req.Provider); err != nil {
+			return nil, WrapError(ErrInvalidIntegrationUser, err)
+		}
+	}
+
+	users, err := s.repo.ListUsers(ctx, provider)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", listIntegrationUsersError, err)
+	}
+
+	// Ответ
+	res := &response.ListIntegrationUsersResponse{Users: make([]response.IntegrationUserResponse, 0, len(users))}
+	for _, user := range users {
+		res.Users = append(res.Users, toIntegrationUserResponse(user))
+	}
+	return res, nil
+}
+
+func (s *IntegrationService) DeleteUser(ctx context.Context, req *request.DeleteIntegrationUserRequest) error {
+	provider, err := normalizeProvider(req.Provider)
+	if err != nil {
+		return WrapError(ErrInvalidIntegrationUser, err)
+	}
+	login := normalizeLogin(req.ExternalLogin)
+	s.log.Info("delete integration user request accepted",
+		zap.String("provider", provider),
+		zap.String("external_login", login),
+	)
+
+	err = s.repo.DeleteUser(ctx, &dto.DeleteIntegrationUserDTO{Provider: provider, ExternalLogin: login})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return WrapError(ErrIntegrationUserNotFound, err)
+		}
+		return fmt.Errorf("%w: %w", deleteIntegrationUserError, err)
+	}
+	return nil
+}
+
+// HandleGitHub принимает событие pull_request от GitHub
+func (s *IntegrationService) HandleGitHub(ctx context.Context, req *request.ProviderEventRequest) (*response.ProviderEventResponse, error) {
+	if err := verifyGitHubSignature(s.cfg.GitHubSecret, req.Signature, req.Body); err != nil {
+		s.log.Warn("github event rejected", zap.Error(err))
+		return nil, WrapError(ErrInvalidSignature, err)
+	}
+
+	event, err := parseGitHubEvent(req.Event, req.Body)
+	if err != nil {
+		return nil, WrapError(ErrInvalidProviderEvent, err)
+	}
+	return s.apply(ctx, domain.ProviderGitHub, event)
+}
+
+// HandleGitLab принимает событие Merge Request Hook от GitLab
+func (s *IntegrationService) HandleGitLab(ctx context.Context, req *request.ProviderEventRequest) (*response.ProviderEventResponse, error) {
+	if err := verifyGitLabToken(s.cfg.GitLabToken, req.Signature); err != nil {
+		s.log.Warn("gitlab event rejected", zap.Error(err))
+		return nil, WrapError(ErrInvalidSignature, err)
+	}
+
+	event, err := parseGitLabEvent(req.Event, req.Body)
+	if err != nil {
+		return nil, WrapError(ErrInvalidProviderEvent, err)
+	}
+	return s.apply(ctx, domain.ProviderGitLab, event)
+}
+
+// apply выполняет действие над PR. Провайдеры доставляют события повторно,
+// поэтому уже созданный PR и действия над неизвестным PR не считаются ошибкой
+func (s *IntegrationService) apply(ctx context.Context, provider string, event *providerPrEvent) (*response.ProviderEventResponse, error) {
+	if event == nil {
+		s.log.Debug("provider event ignored", zap.String("provider", provider))
+		return &response.ProviderEventResponse{Status: providerEventIgnored}, nil
+	}
+
+	prId := event.pr.PrId()
+	s.log.Info("provider event accepted",
+		zap.String("provider", provider),
+		zap.String("pr_id", prId),
+		zap.String("action", event.action),
+		zap.String("actor", event.actorLogin),
+	)
+
+	// В журнал назначений попадает пользователь провайдера, вызвавший событие
+	audit := domain.AuditInfoFrom(ctx)
+	audit.Actor = provider + ":" + event.actorLogin
+	ctx = domain.WithAuditInfo(ctx, audit)
+
+	var err error
+	switch event.action {
+	case prActionOpen:
+		var authorId string
+		authorId, err = s.repo.FindUserId(ctx, provider, normalizeLogin(event.authorLogin))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, WrapError(ErrIntegrationUserNotFound, fmt.Errorf("%s login %q", provider, event.authorLogin))
+			}
+			return nil, fmt.Errorf("%w: %w", providerEventError, err)
+		}
+		_, err = s.prs.Create(ctx, &request.CreateRequest{
+			PrId:     prId,
+			PrName:   event.title,
+			AuthorId: authorId,
+			Draft:    event.draft,
+		})
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			s.log.Info("provider PR already exists", zap.String("pr_id", prId))
+			return &response.ProviderEventResponse{Status: providerEventIgnored, Action: event.action, PrId: prId}, nil
+		}
+	case prActionMerge:
+		_, err = s.prs.Merge(ctx, &request.MergeRequest{PrId: prId, External: true})
+	case prActionClose:
+		_, err = s.prs.Close(ctx, &request.ClosePrRequest{PrId: prId})
+	case prActionReopen:
+		_, err = s.prs.Reopen(ctx, &request.ReopenPrRequest{PrId: prId})
+	case prActionMarkReady:
+		_, err = s.prs.MarkReady(ctx, &request.MarkReadyRequest{PrId: prId})
+	}
+
+	if err != nil {
+		// PR открыт до подключения интеграции - сервис о нем не знает
+		if event.action != prActionOpen && errors.Is(err, repository.ErrNotFound) {
+			s.log.Info("provider PR is unknown", zap.String("pr_id", prId), zap.String("action", event.action))
+			return &response.ProviderEventResponse{Status: providerEventIgnored, Action: event.action, PrId: prId}, nil
+		}
+		s.log.Error("failed to apply provider event",
+			zap.String("pr_id", prId),
+			zap.String("action", event.action),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	// Ответ
+	return &response.ProviderEventResponse{Status: providerEventProcessed, Action: event.action, PrId: prId}, nil
+}
+
+func normalizeProvider(raw string) (string, error) {
+	provider := strings.ToLower(strings.TrimSpace(raw))
+	if !slices.Contains(domain.Providers, provider) {
+		return "", fmt.Errorf("provider must be one of %s", strings.Join(domain.Providers, ", "))
+	}
+	return provider, nil
+}
+
+// normalizeLogin логины GitHub и GitLab не различают регистр
+func normalizeLogin(raw string) string {
+	return strings.ToLower(strings.TrimSpace(raw))
+}
+
+func toIntegrationUserResponse(user *domain.IntegrationUser) response.IntegrationUserResponse {
+	return response.IntegrationUserResponse{
+		Provider:      user.Provider,
+		ExternalLogin: user.ExternalLogin,
+		ExternalId:    user.ExternalId,
+		UserId:        user.UserId,
+		CreatedAt:     formatTime(user.CreatedAt),
+	}
+}
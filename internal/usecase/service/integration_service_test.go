@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+const testGitHubSecret = "github-secret"
+
+// MockIntegrationRepository мок репозитория интеграций для тестов
+type MockIntegrationRepository struct {
+	mock.Mock
+}
+
+func (m *MockIntegrationRepository) SetUser(ctx context.Context, d *dto.SetIntegrationUserDTO) (*domain.IntegrationUser, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IntegrationUser), args.Error(1)
+}
+
+func (m *MockIntegrationRepository) ListUsers(ctx context.Context, provider string) ([]*domain.IntegrationUser, error) {
+	args := m.Called(ctx, provider)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IntegrationUser), args.Error(1)
+}
+
+func (m *MockIntegrationRepository) DeleteUser(ctx context.Context, d *dto.DeleteIntegrationUserDTO) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockIntegrationRepository) FindUserId(ctx context.Context, provider, login string) (string, error) {
+	args := m.Called(ctx, provider, login)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockIntegrationRepository) FindExternalUsers(ctx context.Context, provider string, userIds []string) ([]*domain.IntegrationUser, error) {
+	args := m.Called(ctx, provider, userIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IntegrationUser), args.Error(1)
+}
+
+// MockPullRequestActions мок операций над PR для тестов
+type MockPullRequestActions struct {
+	mock.Mock
+}
+
+func (m *MockPullRequestActions) Create(ctx context.Context, req *request.CreateRequest) (*response.CreateResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.CreateResponse), args.Error(1)
+}
+
+func (m *MockPullRequestActions) Merge(ctx context.Context, req *request.MergeRequest) (*response.MergeResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.MergeResponse), args.Error(1)
+}
+
+func (m *MockPullRequestActions) Close(ctx context.Context, req *request.ClosePrRequest) (*response.StatusChangeResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.StatusChangeResponse), args.Error(1)
+}
+
+func (m *MockPullRequestActions) Reopen(ctx context.Context, req *request.ReopenPrRequest) (*response.StatusChangeResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.StatusChangeResponse), args.Error(1)
+}
+
+func (m *MockPullRequestActions) MarkReady(ctx context.Context, req *request.MarkReadyRequest) (*response.StatusChangeResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.StatusChangeResponse), args.Error(1)
+}
+
+// fakeReviewerClient запоминает вызовы клиента провайдера
+type fakeReviewerClient struct {
+	added   []string
+	removed []string
+}
+
+func (c *fakeReviewerClient) AddReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error {
+	for _, reviewer := range reviewers {
+		c.added = append(c.added, pr.PrId()+" "+reviewer.ExternalLogin)
+	}
+	return nil
+}
+
+func (c *fakeReviewerClient) RemoveReviewers(ctx context.Context, pr domain.ExternalPr, reviewers []*domain.IntegrationUser) error {
+	for _, reviewer := range reviewers {
+		c.removed = append(c.removed, pr.PrId()+" "+reviewer.ExternalLogin)
+	}
+	return nil
+}
+
+func githubEvent(body string) *request.ProviderEventRequest {
+	mac := hmac.New(sha256.New, []byte(testGitHubSecret))
+	mac.Write([]byte(body))
+	return &request.ProviderEventRequest{
+		Event:     "pull_request",
+		Signature: "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+		Body:      []byte(body),
+	}
+}
+
+func newTestIntegrationService() (*IntegrationService, *MockIntegrationRepository, *MockPullRequestActions) {
+	repo := new(MockIntegrationRepository)
+	prs := new(MockPullRequestActions)
+	service := NewIntegrationService(repo, prs, IntegrationConfig{
+		GitHubSecret: testGitHubSecret,
+		GitLabToken:  "gitlab-token",
+	}, zap.NewNop())
+	return service, repo, prs
+}
+
+func TestExternalPrId_RoundTrip(t *testing.T) {
+	github := domain.ExternalPr{Provider: domain.ProviderGitHub, Repository: "acme/api", Number: 12}
+	gitlab := domain.ExternalPr{Provider: domain.ProviderGitLab, Repository: "acme/backend/api", Number: 7}
+
+	assert.Equal(t, "github:acme/api#12", github.PrId())
+	assert.Equal(t, "gitlab:acme/backend/api!7", gitlab.PrId())
+
+	parsed, ok := domain.ParseExternalPrId(github.PrId())
+	assert.True(t, ok)
+	assert.Equal(t, github, parsed)
+	parsed, ok = domain.ParseExternalPrId(gitlab.PrId())
+	assert.True(t, ok)
+	assert.Equal(t, gitlab, parsed)
+
+	for _, prId := range []string{"pr-1", "github:acme/api", "github:acme/api#x", "bitbucket:a#1"} {
+		_, ok := domain.ParseExternalPrId(prId)
+		assert.False(t, ok, prId)
+	}
+}
+
+func TestIntegrationService_HandleGitHub_Opened(t *testing.T) {
+	service, repo, prs := newTestIntegrationService()
+
+	repo.On("FindUserId", mock.Anything, domain.ProviderGitHub, "octocat").Return("u1", nil)
+	prs.On("Create", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.AuditInfoFrom(ctx).Actor == "github:Octocat"
+	}), &request.CreateRequest{
+		PrId:     "github:acme/api#12",
+		PrName:   "Add search",
+		AuthorId: "u1",
+		Draft:    true,
+	}).Return(&response.CreateResponse{PrId: "github:acme/api#12"}, nil)
+
+	resp, err := service.HandleGitHub(context.Background(), githubEvent(`{
+		"action": "opened",
+		"pull_request": {"number": 12, "title": "Add search", "draft": true, "user": {"login": "Octocat"}},
+		"repository": {"full_name": "acme/api"},
+		"sender": {"login": "Octocat"}
+	}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, providerEventProcessed, resp.Status)
+	assert.Equal(t, "github:acme/api#12", resp.PrId)
+	repo.AssertExpectations(t)
+	prs.AssertExpectations(t)
+}
+
+func TestIntegrationService_HandleGitHub_ClosedMerged(t *testing.T) {
+	service, _, prs := newTestIntegrationService()
+
+	prs.On("Merge", mock.Anything, &request.MergeRequest{PrId: "github:acme/api#12", External: true}).
+		Return(&response.MergeResponse{PrId: "github:acme/api#12"}, nil)
+	prs.On("Close", mock.Anything, &request.ClosePrRequest{PrId: "github:acme/api#13"}).
+		Return(nil, WrapError(ErrPrNotFound, repository.ErrNotFound))
+
+	resp, err := service.HandleGitHub(context.Background(), githubEvent(`{"action": "closed",
+		"pull_request": {"number": 12, "merged": true}, "repository": {"full_name": "acme/api"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, prActionMerge, resp.Action)
+
+	// PR открыт до подключения интеграции
+	resp, err = service.HandleGitHub(context.Background(), githubEvent(`{"action": "closed",
+		"pull_request": {"number": 13, "merged": false}, "repository": {"full_name": "acme/api"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, providerEventIgnored, resp.Status)
+	prs.AssertExpectations(t)
+}
+
+func TestIntegrationService_HandleGitHub_Rejected(t *testing.T) {
+	service, repo, prs := newTestIntegrationService()
+
+	event := githubEvent(`{"action": "opened"}`)
+	event.Signature = "sha256=deadbeef"
+	_, err := service.HandleGitHub(context.Background(), event)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "UNAUTHORIZED", domainErr.Code)
+
+	// Интеграция без секрета не принимает события
+	disabled := NewIntegrationService(repo, prs, IntegrationConfig{}, zap.NewNop())
+	_, err = disabled.HandleGitHub(context.Background(), githubEvent(`{}`))
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "UNAUTHORIZED", domainErr.Code)
+
+	// Автор не сопоставлен с пользователем сервиса
+	repo.On("FindUserId", mock.Anything, domain.ProviderGitHub, "stranger").Return("", repository.ErrNotFound)
+	_, err = service.HandleGitHub(context.Background(), githubEvent(`{"action": "opened",
+		"pull_request": {"number": 1, "user": {"login": "stranger"}}, "repository": {"full_name": "acme/api"}}`))
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+	prs.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// Неподдерживаемые события подтверждаются без изменений
+	resp, err := service.HandleGitHub(context.Background(), githubEvent(`{"action": "labeled"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, providerEventIgnored, resp.Status)
+}
+
+func TestIntegrationService_HandleGitLab(t *testing.T) {
+	service, _, prs := newTestIntegrationService()
+
+	prs.On("MarkReady", mock.Anything, &request.MarkReadyRequest{PrId: "gitlab:acme/backend!7"}).
+		Return(&response.StatusChangeResponse{PrId: "gitlab:acme/backend!7"}, nil)
+
+	body := `{
+		"object_kind": "merge_request",
+		"user": {"username": "alice"},
+		"project": {"path_with_namespace": "acme/backend"},
+		"object_attributes": {"iid": 7, "action": "update", "draft": false},
+		"changes": {"draft": {"previous": true, "current": false}}
+	}`
+	resp, err := service.HandleGitLab(context.Background(), &request.ProviderEventRequest{
+		Event:     "Merge Request Hook",
+		Signature: "gitlab-token",
+		Body:      []byte(body),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, prActionMarkReady, resp.Action)
+
+	_, err = service.HandleGitLab(context.Background(), &request.ProviderEventRequest{
+		Event:     "Merge Request Hook",
+		Signature: "wrong",
+		Body:      []byte(body),
+	})
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "UNAUTHORIZED", domainErr.Code)
+	prs.AssertExpectations(t)
+}
+
+func TestIntegrationService_SetUser_Validation(t *testing.T) {
+	service, repo, _ := newTestIntegrationService()
+
+	repo.On("SetUser", mock.Anything, &dto.SetIntegrationUserDTO{
+		Provider:      domain.ProviderGitLab,
+		ExternalLogin: "alice",
+		ExternalId:    "42",
+		UserId:        "u1",
+	}).Return(&domain.IntegrationUser{Provider: domain.ProviderGitLab, ExternalLogin: "alice", ExternalId: "42", UserId: "u1"}, nil)
+
+	resp, err := service.SetUser(context.Background(), &request.SetIntegrationUserRequest{
+		Provider:      "GitLab",
+		ExternalLogin: " Alice ",
+		ExternalId:    "42",
+		UserId:        "u1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", resp.ExternalLogin)
+
+	_, err = service.SetUser(context.Background(), &request.SetIntegrationUserRequest{Provider: "bitbucket", ExternalLogin: "a", UserId: "u1"})
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestReviewerReporter_Report(t *testing.T) {
+	repo := new(MockIntegrationRepository)
+	client := &fakeReviewerClient{}
+	reporter := NewReviewerReporter(NewEventBus(10, 10, zap.NewNop()), repo, map[string]ReviewerClient{
+		domain.ProviderGitHub: client,
+	}, zap.NewNop())
+
+	repo.On("FindExternalUsers", mock.Anything, domain.ProviderGitHub, []string{"u2"}).
+		Return([]*domain.IntegrationUser{{ExternalLogin: "bob", UserId: "u2"}}, nil)
+	repo.On("FindExternalUsers", mock.Anything, domain.ProviderGitHub, []string{"u3"}).
+		Return([]*domain.IntegrationUser{{ExternalLogin: "carol", UserId: "u3"}}, nil)
+
+	reassigned := &domain.ActivityEvent{
+		Type: domain.EventReviewerReassigned,
+		PrId: "github:acme/api#12",
+		Payload: &domain.ReviewerEventPayload{
+			PrId:      "github:acme/api#12",
+			OldUserId: "u2",
+			NewUserId: "u3",
+		},
+	}
+	assert.True(t, reporter.match(reassigned))
+	reporter.Report(context.Background(), reassigned)
+
+	assert.Equal(t, []string{"github:acme/api#12 bob"}, client.removed)
+	assert.Equal(t, []string{"github:acme/api#12 carol"}, client.added)
+
+	// PR, созданные через API, и провайдеры без клиента не сообщаются
+	assert.False(t, reporter.match(&domain.ActivityEvent{Type: domain.EventReviewerAssigned, PrId: "pr-1"}))
+	assert.False(t, reporter.match(&domain.ActivityEvent{Type: domain.EventReviewerAssigned, PrId: "gitlab:acme/api!1"}))
+	assert.False(t, reporter.match(&domain.ActivityEvent{Type: domain.EventPrMerged, PrId: "github:acme/api#12"}))
+}
+
+func TestReviewerReporter_RunReportsPublishedEvents(t *testing.T) {
+	bus := NewEventBus(10, 10, zap.NewNop())
+	repo := new(MockIntegrationRepository)
+	client := &fakeReviewerClient{}
+	reporter := NewReviewerReporter(bus, repo, map[string]ReviewerClient{domain.ProviderGitHub: client}, zap.NewNop())
+
+	reported := make(chan struct{})
+	repo.On("FindExternalUsers", mock.Anything, domain.ProviderGitHub, []string{"u2"}).
+		Run(func(mock.Arguments) { close(reported) }).
+		Return([]*domain.IntegrationUser{{ExternalLogin: "bob", UserId: "u2"}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reporter.Run(ctx)
+	}()
+
+	// Ждем подписки репортера
+	assert.Eventually(t, func() bool {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		return len(bus.subscribers) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	bus.Publish(&domain.ActivityEvent{
+		Type:    domain.EventReviewerAssigned,
+		PrId:    "github:acme/api#12",
+		Payload: &domain.ReviewerEventPayload{PrId: "github:acme/api#12", UserId: "u2"},
+	})
+	<-reported
+	cancel()
+	<-done
+
+	assert.Equal(t, []string{"github:acme/api#12 bob"}, client.added)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	setTeamMemberRoleError = errors.New("set team member role error")
+	noLeadReviewerError    = errors.New("no lead reviewer")
+)
+
+// SetMemberRole назначает участнику команды роль MEMBER или LEAD. Лид из настроек команды
+// остается лидом, пока lead_user_id не сменят
+func (s *TeamService) SetMemberRole(ctx context.Context, req *request.SetTeamMemberRoleRequest) (*response.GetTeamResponse, error) {
+	teamName := strings.TrimSpace(req.TeamName)
+	role := strings.ToUpper(strings.TrimSpace(req.Role))
+	s.log.Info("set team member role request accepted",
+		zap.String("team_name", teamName),
+		zap.String("user_id", req.UserId),
+		zap.String("role", role),
+	)
+
+	// Проверяем корректность параметров
+	if teamName == "" {
+		return nil, WrapError(ErrInvalidTeamName, errors.New("team_name is empty"))
+	}
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+	if role != domain.TeamRoleMember && role != domain.TeamRoleLead {
+		return nil, WrapError(ErrInvalidTeamRole, fmt.Errorf("unknown role %q", req.Role))
+	}
+
+	// Собираем dto
+	dto := &dto.SetTeamMemberRoleDTO{
+		TeamName: teamName,
+		UserId:   userId,
+		Role:     role,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.SetMemberRole(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to set team member role",
+			zap.String("team_name", teamName),
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			return nil, WrapError(ErrNotTeamMember, err)
+		}
+		if errors.Is(err, repository.ErrTeamLead) {
+			return nil, WrapError(ErrDemoteTeamLead, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", setTeamMemberRoleError, err)
+	}
+
+	s.log.Info("team member role set",
+		zap.String("team_name", res.TeamName),
+		zap.String("user_id", userId),
+		zap.String("role", role),
+	)
+	// Ответ
+	return &response.GetTeamResponse{
+		TeamName: res.TeamName,
+		Members:  res.Members,
+	}, nil
+}
+
+// findLeadReviewer подбирает лида для PR с обязательным ревью лида. Сначала лид ищется в команде автора,
+// затем в родительских командах от ближайшей и в резервных командах - так PR автора-лида ревьюит лид
+// команды выше. Возвращает лида и название его команды
+func (r *reviewerReplacer) findLeadReviewer(ctx context.Context, explainer *assignmentExplainer, pool *result.PotentialReviewersResult, excludedIds []string, pairings map[string]int) (string, string, error) {
+	leads := leadsPool(pool)
+	if lead, err := findReviewers(explainer.selectorFor(leads), leads, excludedIds, 1); err == nil {
+		return lead[0], pool.TeamName, nil
+	}
+
+	if pool.ParentId != "" {
+		parents, err := r.repo.SelectParentPools(ctx, pool.TeamId)
+		if err != nil {
+			r.log.Error("failed to load parent pools", zap.String("team_id", pool.TeamId), zap.Error(err))
+			return "", "", err
+		}
+		if lead, team, ok := r.findPoolsLead(explainer, pool.TeamId, parents, excludedIds, pairings); ok {
+			return lead, team, nil
+		}
+	}
+
+	fallbacks, err := r.repo.SelectFallbackPools(ctx, pool.TeamId)
+	if err != nil {
+		r.log.Error("failed to load fallback pools", zap.String("team_id", pool.TeamId), zap.Error(err))
+		return "", "", err
+	}
+	if lead, team, ok := r.findPoolsLead(explainer, pool.TeamId, fallbacks, excludedIds, pairings); ok {
+		return lead, team, nil
+	}
+	return "", "", noLeadReviewerError
+}
+
+// findPoolsLead лид из первого пула по порядку, в котором нашелся доступный лид
+func (r *reviewerReplacer) findPoolsLead(explainer *assignmentExplainer, teamId string, pools []*result.PotentialReviewersResult, excludedIds []string, pairings map[string]int) (string, string, bool) {
+	for _, pool := range pools {
+		applyPairings(pool, pairings)
+		leads := leadsPool(pool)
+		if lead, err := findReviewers(explainer.selectorFor(leads), leads, excludedIds, 1); err == nil {
+			r.log.Info("lead reviewer taken from another team",
+				zap.String("team_id", teamId),
+				zap.String("lead_team", pool.TeamName),
+				zap.String("lead_reviewer", lead[0]),
+			)
+			return lead[0], pool.TeamName, true
+		}
+	}
+	return "", "", false
+}
+
+// authorPool пул команды, из которой подбирались ревьюеры PR. Если автор покинул эту команду,
+// используется его основная команда
+func (r *reviewerReplacer) authorPool(ctx context.Context, pr *result.PrResult) (*result.PotentialReviewersResult, error) {
+	if pr.TeamName != "" {
+		pool, err := r.repo.SelectMemberPool(ctx, pr.AuthorId, pr.TeamName)
+		if !errors.Is(err, repository.ErrNotTeamMember) {
+			return pool, err
+		}
+	}
+	return r.repo.SelectPotentialReviewers(ctx, pr.AuthorId)
+}
+
+// leadsPool копия пула только с лидами команды. Лид из настроек команды считается лидом
+// независимо от роли
+func leadsPool(pool *result.PotentialReviewersResult) *result.PotentialReviewersResult {
+	filtered := *pool
+	filtered.Candidates = nil
+	for _, candidate := range pool.Candidates {
+		if candidate != nil && candidate.User != nil && isTeamLead(pool, candidate) {
+			filtered.Candidates = append(filtered.Candidates, candidate)
+		}
+	}
+	return &filtered
+}
+
+func isTeamLead(pool *result.PotentialReviewersResult, candidate *result.ReviewerCandidate) bool {
+	return candidate.IsLead || (pool.Settings != nil && pool.Settings.LeadUserId != "" && pool.Settings.LeadUserId == candidate.User.Id)
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// markLeads отмечает лидами пула переданных пользователей
+func markLeads(pool *result.PotentialReviewersResult, leadIds ...string) *result.PotentialReviewersResult {
+	for _, leadId := range leadIds {
+		findCandidate(pool, leadId).IsLead = true
+	}
+	return pool
+}
+
+func TestPrService_Create_RequiresLeadReview_PicksTeamLeadFirst(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(markLeads(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "lead1", IsActive: true},
+	}), "lead1"), nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
+		return d.RequiresLeadReview && d.LeadReviewerId == "lead1"
+	}), []string{"lead1", "user1"}).Return(&result.PrResult{
+		Id:                 "pr1",
+		AuthorId:           "author1",
+		Status:             domain.PrStatusOpen,
+		AssignedReviewers:  []string{"lead1", "user1"},
+		RequiresLeadReview: true,
+		LeadReviewerId:     "lead1",
+		CreatedAt:          time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:               "pr1",
+		PrName:             "Test PR",
+		AuthorId:           "author1",
+		RequiresLeadReview: true,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.RequiresLeadReview)
+	assert.Equal(t, "lead1", resp.LeadReviewer)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Create_AuthorIsLead_TakesParentLead(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	// Единственный лид команды - сам автор
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(markLeads(subteamPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+	}), "author1"), nil)
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		markLeads(fallbackPool("backend", []*domain.User{{Id: "p1", IsActive: true}, {Id: "plead", IsActive: true}}), "plead"),
+	}, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"plead", "user1"}).Return(&result.PrResult{
+		Id:                 "pr1",
+		AuthorId:           "author1",
+		Status:             domain.PrStatusOpen,
+		AssignedReviewers:  []string{"plead", "user1"},
+		RequiresLeadReview: true,
+		LeadReviewerId:     "plead",
+		CreatedAt:          time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:               "pr1",
+		PrName:             "Test PR",
+		AuthorId:           "author1",
+		RequiresLeadReview: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "plead", resp.LeadReviewer)
+	mockRepo.AssertNotCalled(t, "SelectFallbackPools", mock.Anything, mock.Anything)
+
+	d := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(1).(*dto.CreatPrDTO)
+	assert.Equal(t, "plead", d.LeadReviewerId)
+}
+
+func TestPrService_Create_RequiresLeadReview_NoLead(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	// Лид в отпуске, в резервной команде лидов нет
+	pool := markLeads(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "lead1", IsActive: true},
+	}), "lead1")
+	findCandidate(pool, "lead1").Unavailable = true
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backup", []*domain.User{{Id: "b1", IsActive: true}}),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:               "pr1",
+		PrName:             "Test PR",
+		AuthorId:           "author1",
+		RequiresLeadReview: true,
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrNoLeadCandidate.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "SelectParentPools", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPrService_Create_SettingsLeadCountsAsLead(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "lead1", IsActive: true},
+	})
+	pool.Settings = &domain.TeamSettings{ReviewerCount: 1, LeadUserId: "lead1"}
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"lead1"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"lead1"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	_, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:               "pr1",
+		PrName:             "Test PR",
+		AuthorId:           "author1",
+		RequiresLeadReview: true,
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Reassign_LeadReplacedByLead(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "lead1").Return(true, &result.PrResult{
+		Id:                 "pr1",
+		AuthorId:           "author1",
+		AssignedReviewers:  []string{"lead1", "user1"},
+		RequiresLeadReview: true,
+		LeadReviewerId:     "lead1",
+	}, nil)
+	// user1 тоже лид, но уже ревьюит PR, а user2 не лид - замену получает lead2
+	authorTeam := markLeads(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "lead1", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+		{Id: "lead2", IsActive: true},
+	}), "lead1", "user1", "lead2")
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "lead1").Return(authorTeam, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(authorTeam, nil)
+	mockRepo.On("Reassign", mock.Anything, mock.MatchedBy(func(d *dto.ReassignPrDTO) bool {
+		return d.OldReviewerId == "lead1" && d.ReplacedBy == "lead2"
+	})).Return(&result.ReassignResult{
+		Pr: &result.PrResult{
+			Id:                 "pr1",
+			AuthorId:           "author1",
+			Status:             domain.PrStatusOpen,
+			AssignedReviewers:  []string{"user1", "lead2"},
+			RequiresLeadReview: true,
+			LeadReviewerId:     "lead2",
+			CreatedAt:          time.Now(),
+		},
+		ReplacedBy: "lead2",
+	}, nil)
+
+	resp, err := service.Reassign(context.Background(), &request.ReassignRequest{
+		PrId:      "pr1",
+		OldUserId: "lead1",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "lead2", resp.ReplacedBy)
+	assert.Equal(t, "lead2", resp.LeadReviewer)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Reassign_LeadWithoutReplacementLead(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "lead1").Return(true, &result.PrResult{
+		Id:                 "pr1",
+		AuthorId:           "author1",
+		TeamName:           "team1",
+		AssignedReviewers:  []string{"lead1"},
+		RequiresLeadReview: true,
+		LeadReviewerId:     "lead1",
+	}, nil)
+	authorTeam := markLeads(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "lead1", IsActive: true},
+		{Id: "user1", IsActive: true},
+	}), "lead1")
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "lead1").Return(authorTeam, nil)
+	mockRepo.On("SelectMemberPool", mock.Anything, "author1", "team1").Return(authorTeam, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{}, nil)
+
+	resp, err := service.Reassign(context.Background(), &request.ReassignRequest{
+		PrId:      "pr1",
+		OldUserId: "lead1",
+	})
+
+	// Обычный участник user1 свободен, но лида он не заменяет
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrNoLeadCandidate.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "Reassign", mock.Anything, mock.Anything)
+}
+
+func TestUserService_DeactivateWithReassign_LeadReplacedByLead(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author1", ReviewerId: "lead1", Reviewers: []string{"lead1", "user1"},
+			TeamName: "team1", RequiresLeadReview: true, LeadReviewerId: "lead1"},
+		{PrId: "pr2", AuthorId: "author1", ReviewerId: "lead1", Reviewers: []string{"lead1", "user1"}, TeamName: "team1"},
+	}
+	// user2 наименее загружен, но лидом не является
+	team := markLeads(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "lead1", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+		{Id: "lead2", IsActive: true},
+	}), "lead1", "lead2")
+	findCandidate(team, "lead2").OpenReviews = 3
+
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"lead1"}).Return(reviews, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "lead1").Return(team, nil)
+	mockRepo.On("SelectMemberPool", mock.Anything, "author1", "team1").Return(team, nil)
+	mockRepo.On("Deactivate", mock.Anything, mock.MatchedBy(func(d *dto.DeactivateUserDTO) bool {
+		// Лида PR с обязательным ревью лида заменяет лид, обычное ревью - участник
+		return len(d.Reassignments) == 2 &&
+			d.Reassignments[0].ReplacedBy == "lead2" && d.Reassignments[1].ReplacedBy == "user2"
+	})).Return(&result.DeactivateUserResult{User: &domain.User{Id: "lead1"}}, nil)
+
+	_, err := service.SetIsActive(context.Background(), &request.SetIsActiveRequest{
+		UserId:          "lead1",
+		IsActive:        false,
+		ReassignReviews: true,
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_DeactivateMembers_LeadWithoutReplacementLead(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	team := markLeads(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "lead1", IsActive: true},
+		{Id: "user1", IsActive: true},
+	}), "lead1")
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author1", ReviewerId: "lead1", Reviewers: []string{"lead1"},
+			TeamName: "team1", RequiresLeadReview: true, LeadReviewerId: "lead1"},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, &dto.GetTeamDTO{TeamName: "team1"}).Return(team, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"lead1"}).Return(reviews, nil)
+	mockRepo.On("SelectMemberPool", mock.Anything, "author1", "team1").Return(team, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{}, nil)
+
+	_, err := service.DeactivateMembers(context.Background(), &request.DeactivateMembersRequest{
+		TeamName: "team1",
+		UserIds:  []string{"lead1"},
+	})
+
+	// Свободный участник user1 лида не заменяет, никто не выключается
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrNoLeadCandidate.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "DeactivateMembers", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetMemberRole(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("SetMemberRole", mock.Anything, &dto.SetTeamMemberRoleDTO{
+		TeamName: "backend",
+		UserId:   "u1",
+		Role:     domain.TeamRoleLead,
+	}).Return(&result.GetTeamResult{
+		TeamName: "backend",
+		Members:  []*domain.User{{Id: "u1", IsActive: true, Role: domain.TeamRoleLead}},
+	}, nil)
+
+	resp, err := service.SetMemberRole(context.Background(), &request.SetTeamMemberRoleRequest{
+		TeamName: "backend",
+		UserId:   "u1",
+		Role:     " lead ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.TeamRoleLead, resp.Members[0].Role)
+}
+
+func TestTeamService_SetMemberRole_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		repoErr  error
+		wantCode string
+	}{
+		{name: "unknown role", role: "owner", wantCode: "INVALID_INPUT"},
+		{name: "not a member", role: "MEMBER", repoErr: repository.ErrNotTeamMember, wantCode: "NOT_FOUND"},
+		{name: "team not found", role: "MEMBER", repoErr: repository.ErrNotFound, wantCode: "NOT_FOUND"},
+		{name: "settings lead demoted", role: "MEMBER", repoErr: repository.ErrTeamLead, wantCode: "INVALID_INPUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTeamRepository)
+			service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+			mockRepo.On("SetMemberRole", mock.Anything, mock.Anything).Return(nil, tt.repoErr)
+
+			_, err := service.SetMemberRole(context.Background(), &request.SetTeamMemberRoleRequest{
+				TeamName: "backend",
+				UserId:   "u1",
+				Role:     tt.role,
+			})
+
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, tt.wantCode, domainErr.Code)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+)
+
+// publishEvent отправляет событие живым подписчикам, если шина подключена
+func publishEvent(events EventPublisher, event *domain.ActivityEvent) {
+	if events == nil {
+		return
+	}
+	events.Publish(event)
+}
+
+func (s *PrService) publish(event *domain.ActivityEvent) {
+	publishEvent(s.events, event)
+}
+
+// publishPrEvent событие pr.created или pr.merged с текущим состоянием PR
+func (s *PrService) publishPrEvent(ctx context.Context, eventType string, pr *result.PrResult) {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	s.publish(&domain.ActivityEvent{
+		Type:    eventType,
+		PrId:    pr.Id,
+		UserIds: append([]string{pr.AuthorId}, reviewers...),
+		Payload: &domain.PrEventPayload{
+			PrId:              pr.Id,
+			PrName:            pr.Name,
+			AuthorId:          pr.AuthorId,
+			Status:            pr.Status,
+			AssignedReviewers: reviewers,
+			MergedAt:          pr.MergedAt,
+			Actor:             domain.AuditInfoFrom(ctx).Actor,
+		},
+	})
+}
+
+// publishAssigned по событию reviewer.assigned на каждого назначенного ревьюера
+func (s *PrService) publishAssigned(ctx context.Context, pr *result.PrResult, reviewers []string, reason string) {
+	actor := domain.AuditInfoFrom(ctx).Actor
+	for _, reviewerId := range reviewers {
+		s.publish(&domain.ActivityEvent{
+			Type:    domain.EventReviewerAssigned,
+			PrId:    pr.Id,
+			UserIds: []string{pr.AuthorId, reviewerId},
+			Payload: &domain.ReviewerEventPayload{
+				PrId:   pr.Id,
+				UserId: reviewerId,
+				Reason: reason,
+				Actor:  actor,
+			},
+		})
+	}
+}
+
+// publishReassigned событие reviewer.reassigned о замене ревьюера
+func (s *PrService) publishReassigned(ctx context.Context, pr *result.PrResult, oldReviewerId, newReviewerId, reason string) {
+	s.publish(reassignedEvent(ctx, pr.Id, pr.AuthorId, oldReviewerId, newReviewerId, reason))
+}
+
+// publishReassignments по событию reviewer.reassigned на каждую примененную замену ревьюера,
+// выбывшего при деактивации или изменении состава команды. Автор PR берется из открытых ревью
+func publishReassignments(ctx context.Context, events EventPublisher, reviews []*result.OpenReviewResult, applied []*dto.ReassignPrDTO) {
+	authors := make(map[string]string, len(reviews))
+	for _, review := range reviews {
+		authors[review.PrId] = review.AuthorId
+	}
+	for _, r := range applied {
+		publishEvent(events, reassignedEvent(ctx, r.PrId, authors[r.PrId], r.OldReviewerId, r.ReplacedBy, r.Reason))
+	}
+}
+
+func reassignedEvent(ctx context.Context, prId, authorId, oldReviewerId, newReviewerId, reason string) *domain.ActivityEvent {
+	return &domain.ActivityEvent{
+		Type:    domain.EventReviewerReassigned,
+		PrId:    prId,
+		UserIds: []string{authorId, oldReviewerId, newReviewerId},
+		Payload: &domain.ReviewerEventPayload{
+			PrId:      prId,
+			OldUserId: oldReviewerId,
+			NewUserId: newReviewerId,
+			Reason:    reason,
+			Actor:     domain.AuditInfoFrom(ctx).Actor,
+		},
+	}
+}
+
+// publishOverdue событие review.overdue о просроченном ревью
+func (s *PrService) publishOverdue(pr *result.PrResult, overdue *domain.ReviewOverduePayload) {
+	userIds := []string{pr.AuthorId, overdue.UserId}
+	if overdue.AddedUserId != "" {
+		userIds = append(userIds, overdue.AddedUserId)
+	}
+	s.publish(&domain.ActivityEvent{
+		Type:    domain.EventReviewOverdue,
+		PrId:    pr.Id,
+		UserIds: userIds,
+		Payload: overdue,
+	})
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	changeStatusError = errors.New("change pull request status error")
+)
+
+// Действия жизненного цикла PR
+const (
+	prActionMerge     = "merge"
+	prActionClose     = "close"
+	prActionReopen    = "reopen"
+	prActionMarkReady = "markReady"
+)
+
+// prTransitions допустимые переходы: статус -> действие -> новый статус
+var prTransitions = map[string]map[string]string{
+	domain.PrStatusDraft: {
+		prActionMarkReady: domain.PrStatusOpen,
+		prActionClose:     domain.PrStatusClosed,
+	},
+	domain.PrStatusOpen: {
+		prActionMerge: domain.PrStatusMerged,
+		prActionClose: domain.PrStatusClosed,
+	},
+	domain.PrStatusClosed: {
+		prActionReopen: domain.PrStatusOpen,
+	},
+}
+
+// prActionTargets статус, в который переводит действие. Повтор действия над PR,
+// уже находящимся в этом статусе, идемпотентен
+var prActionTargets = map[string]string{
+	prActionMerge:     domain.PrStatusMerged,
+	prActionClose:     domain.PrStatusClosed,
+	prActionReopen:    domain.PrStatusOpen,
+	prActionMarkReady: domain.PrStatusOpen,
+}
+
+// prActionReasons причина назначения ревьюеров при переходе в OPEN для журнала назначений
+var prActionReasons = map[string]string{
+	prActionReopen:    domain.AssignReasonPrReopened,
+	prActionMarkReady: domain.AssignReasonPrReady,
+}
+
+// nextPrStatus возвращает новый статус PR после действия.
+// changed = false, если PR уже в целевом статусе и менять ничего не нужно
+func nextPrStatus(current, action string) (string, bool, error) {
+	if next, ok := prTransitions[current][action]; ok {
+		return next, true, nil
+	}
+	if prActionTargets[action] == current {
+		return current, false, nil
+	}
+	return "", false, fmt.Errorf("%s is not allowed for %s PR", action, current)
+}
+
+func (s *PrService) Close(ctx context.Context, req *request.ClosePrRequest) (*response.StatusChangeResponse, error) {
+	return s.changeStatus(ctx, req.PrId, prActionClose)
+}
+
+func (s *PrService) Reopen(ctx context.Context, req *request.ReopenPrRequest) (*response.StatusChangeResponse, error) {
+	return s.changeStatus(ctx, req.PrId, prActionReopen)
+}
+
+func (s *PrService) MarkReady(ctx context.Context, req *request.MarkReadyRequest) (*response.StatusChangeResponse, error) {
+	return s.changeStatus(ctx, req.PrId, prActionMarkReady)
+}
+
+func (s *PrService) changeStatus(ctx context.Context, rawPrId, action string) (*response.StatusChangeResponse, error) {
+	prId, err := normalizeID(rawPrId, "pull_request_id")
+	if err != nil {
+		return nil, WrapError(ErrPrNotFound, err)
+	}
+	s.log.Info("change PR status request accepted",
+		zap.String("pr_id", prId),
+		zap.String("action", action),
+	)
+
+	// Получаем текущее состояние PR
+	pr, err := s.repo.Get(ctx, prId)
+	if err != nil {
+		s.log.Error("failed to load PR", zap.String("pr_id", prId), zap.Error(err))
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrPrNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", changeStatusError, err)
+	}
+
+	// Проверяем переход по машине состояний
+	next, changed, err := nextPrStatus(pr.Status, action)
+	if err != nil {
+		s.log.Warn("PR status transition rejected",
+			zap.String("pr_id", prId),
+			zap.String("status", pr.Status),
+			zap.String("action", action),
+		)
+		return nil, WrapError(ErrInvalidTransition, err)
+	}
+	if !changed {
+		s.log.Info("PR already in target status", zap.String("pr_id", prId), zap.String("status", pr.Status))
+		return toStatusChangeResponse(pr, nil), nil
+	}
+
+	// PR впервые становится открытым - подбираем ревьюеров так же, как при создании
+	pick := &reviewerPick{}
+	var teamName string
+	if next == domain.PrStatusOpen && len(pr.AssignedReviewers) == 0 {
+		pick, err = s.pickReviewers(ctx, pr.AuthorId, pr.TeamName, pr.ChangedPaths, pr.RequiredTags, pr.RequiresLeadReview, prActionReasons[action])
+		// Автор успел покинуть команду PR - ревьюеры подбираются из его основной команды,
+		// и она становится командой PR вместе с ее настройками
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			s.log.Warn("author left PR team, using primary team",
+				zap.String("pr_id", prId),
+				zap.String("team_name", pr.TeamName),
+			)
+			pick, err = s.pickReviewers(ctx, pr.AuthorId, "", pr.ChangedPaths, pr.RequiredTags, pr.RequiresLeadReview, prActionReasons[action])
+			if err == nil {
+				teamName = pick.TeamName
+			}
+		}
+		if err != nil {
+			if errors.Is(err, noLeadReviewerError) {
+				return nil, WrapError(ErrNoLeadCandidate, err)
+			}
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, WrapError(ErrPrNotFound, err)
+			}
+			return nil, fmt.Errorf("%w: %w", changeStatusError, err)
+		}
+	}
+
+	dto := &dto.ChangePrStatusDTO{
+		PrId:        prId,
+		FromStatus:  pr.Status,
+		ToStatus:    next,
+		Reviewers:   pick.Reviewers,
+		Reason:      prActionReasons[action],
+		TeamName:    teamName,
+		Explanation: pick.Explanation,
+
+		LeadReviewerId: pick.LeadReviewer,
+	}
+
+	// Запрос в бд на изменение статуса
+	res, err := s.repo.ChangeStatus(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to change PR status",
+			zap.String("pr_id", prId),
+			zap.String("to", next),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrPrNotFound, err)
+		}
+		if errors.Is(err, repository.ErrPrStatusConflict) {
+			return nil, WrapError(ErrInvalidTransition, err)
+		}
+		return nil, fmt.Errorf("%w: %w", changeStatusError, err)
+	}
+
+	s.log.Info("PR status changed",
+		zap.String("pr_id", res.Id),
+		zap.String("status", res.Status),
+		zap.Strings("assigned_reviewers", res.AssignedReviewers),
+	)
+	s.publishAssigned(ctx, res, pick.Reviewers, prActionReasons[action])
+
+	return toStatusChangeResponse(res, pick), nil
+}
+
+// toStatusChangeResponse pick равен nil, если ревьюеры при переходе не подбирались
+func toStatusChangeResponse(pr *result.PrResult, pick *reviewerPick) *response.StatusChangeResponse {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	if pick == nil {
+		pick = &reviewerPick{}
+	}
+	return &response.StatusChangeResponse{
+		PrId:              pr.Id,
+		PrName:            pr.Name,
+		AuthorId:          pr.AuthorId,
+		Status:            pr.Status,
+		AssignedReviewers: reviewers,
+		CreatedAt:         formatTime(pr.CreatedAt),
+		MergedAt:          formatTimePtr(pr.MergedAt),
+		ClosedAt:          formatTimePtr(pr.ClosedAt),
+		LeadReviewer:      pr.LeadReviewerId,
+		CrossTeam:         pick.FallbackTeam != "",
+		FallbackTeam:      pick.FallbackTeam,
+		ParentTeams:       pick.ParentTeams,
+		Warnings:          pick.Warnings,
+	}
+}
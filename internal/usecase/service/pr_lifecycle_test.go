@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestNextPrStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		action  string
+		next    string
+		changed bool
+		wantErr bool
+	}{
+		{"draft mark ready", domain.PrStatusDraft, prActionMarkReady, domain.PrStatusOpen, true, false},
+		{"draft close", domain.PrStatusDraft, prActionClose, domain.PrStatusClosed, true, false},
+		{"draft merge", domain.PrStatusDraft, prActionMerge, "", false, true},
+		{"draft reopen", domain.PrStatusDraft, prActionReopen, "", false, true},
+		{"open merge", domain.PrStatusOpen, prActionMerge, domain.PrStatusMerged, true, false},
+		{"open close", domain.PrStatusOpen, prActionClose, domain.PrStatusClosed, true, false},
+		{"open reopen is noop", domain.PrStatusOpen, prActionReopen, domain.PrStatusOpen, false, false},
+		{"open mark ready is noop", domain.PrStatusOpen, prActionMarkReady, domain.PrStatusOpen, false, false},
+		{"closed reopen", domain.PrStatusClosed, prActionReopen, domain.PrStatusOpen, true, false},
+		{"closed close is noop", domain.PrStatusClosed, prActionClose, domain.PrStatusClosed, false, false},
+		{"closed merge", domain.PrStatusClosed, prActionMerge, "", false, true},
+		{"closed mark ready", domain.PrStatusClosed, prActionMarkReady, "", false, true},
+		{"merged merge is noop", domain.PrStatusMerged, prActionMerge, domain.PrStatusMerged, false, false},
+		{"merged close", domain.PrStatusMerged, prActionClose, "", false, true},
+		{"merged reopen", domain.PrStatusMerged, prActionReopen, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, changed, err := nextPrStatus(tt.current, tt.action)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.next, next)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestPrService_Create_Draft(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	expectedPrResult := &result.PrResult{
+		Id:                "pr1",
+		Name:              "Draft PR",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusDraft,
+		AssignedReviewers: []string{},
+		CreatedAt:         time.Now(),
+	}
+
+	// Ревьюеры для черновика не подбираются
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
+		return d.PrId == "pr1" && d.Status == domain.PrStatusDraft
+	}), []string{}).Return(expectedPrResult, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "Draft PR",
+		AuthorId: "author1",
+		Draft:    true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PrStatusDraft, resp.Status)
+	assert.Empty(t, resp.AssignedReviewers)
+	mockRepo.AssertNotCalled(t, "SelectPotentialReviewers", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_MarkReady_AssignsReviewers(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	draft := &result.PrResult{
+		Id:                "pr1",
+		Name:              "Draft PR",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusDraft,
+		AssignedReviewers: []string{},
+		CreatedAt:         time.Now(),
+	}
+	opened := &result.PrResult{
+		Id:                "pr1",
+		Name:              "Draft PR",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"reviewer1", "reviewer2"},
+		CreatedAt:         draft.CreatedAt,
+	}
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "reviewer1", IsActive: true},
+		{Id: "reviewer2", IsActive: true},
+	})
+
+	mockRepo.On("Get", mock.Anything, "pr1").Return(draft, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(d *dto.ChangePrStatusDTO) bool {
+		return d.PrId == "pr1" &&
+			d.FromStatus == domain.PrStatusDraft &&
+			d.ToStatus == domain.PrStatusOpen &&
+			assert.ElementsMatch(t, []string{"reviewer1", "reviewer2"}, d.Reviewers)
+	})).Return(opened, nil)
+
+	resp, err := service.MarkReady(context.Background(), &request.MarkReadyRequest{PrId: "pr1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PrStatusOpen, resp.Status)
+	assert.Len(t, resp.AssignedReviewers, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_MarkReady_AuthorLeftPrTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	draft := &result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusDraft,
+		AssignedReviewers: []string{},
+		TeamName:          "payments",
+	}
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "reviewer1", IsActive: true},
+	})
+
+	mockRepo.On("Get", mock.Anything, "pr1").Return(draft, nil)
+	mockRepo.On("SelectMemberPool", mock.Anything, "author1", "payments").Return(nil, repository.ErrNotTeamMember)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	// Основная команда автора становится командой PR
+	mockRepo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(d *dto.ChangePrStatusDTO) bool {
+		return assert.Equal(t, []string{"reviewer1"}, d.Reviewers) && assert.Equal(t, "team1", d.TeamName)
+	})).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"reviewer1"},
+	}, nil)
+
+	resp, err := service.MarkReady(context.Background(), &request.MarkReadyRequest{PrId: "pr1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"reviewer1"}, resp.AssignedReviewers)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Close_Open(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	closedAt := time.Now()
+	open := &result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"reviewer1"},
+		CreatedAt:         time.Now(),
+	}
+	closed := &result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusClosed,
+		AssignedReviewers: []string{"reviewer1"},
+		CreatedAt:         open.CreatedAt,
+		ClosedAt:          &closedAt,
+	}
+
+	mockRepo.On("Get", mock.Anything, "pr1").Return(open, nil)
+	mockRepo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(d *dto.ChangePrStatusDTO) bool {
+		return d.FromStatus == domain.PrStatusOpen && d.ToStatus == domain.PrStatusClosed && len(d.Reviewers) == 0
+	})).Return(closed, nil)
+
+	resp, err := service.Close(context.Background(), &request.ClosePrRequest{PrId: "pr1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PrStatusClosed, resp.Status)
+	assert.NotNil(t, resp.ClosedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Close_AlreadyClosedIsIdempotent(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	closedAt := time.Now()
+	mockRepo.On("Get", mock.Anything, "pr1").Return(&result.PrResult{
+		Id:        "pr1",
+		Status:    domain.PrStatusClosed,
+		CreatedAt: time.Now(),
+		ClosedAt:  &closedAt,
+	}, nil)
+
+	resp, err := service.Close(context.Background(), &request.ClosePrRequest{PrId: "pr1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PrStatusClosed, resp.Status)
+	assert.Equal(t, []string{}, resp.AssignedReviewers)
+	mockRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything)
+}
+
+func TestPrService_Reopen_MergedRejected(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("Get", mock.Anything, "pr1").Return(&result.PrResult{
+		Id:     "pr1",
+		Status: domain.PrStatusMerged,
+	}, nil)
+
+	resp, err := service.Reopen(context.Background(), &request.ReopenPrRequest{PrId: "pr1"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_TRANSITION", domainErr.Code)
+	mockRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything)
+}
+
+func TestPrService_Reopen_ConcurrentChange(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("Get", mock.Anything, "pr1").Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusClosed,
+		AssignedReviewers: []string{"reviewer1"},
+	}, nil)
+	mockRepo.On("ChangeStatus", mock.Anything, mock.Anything).Return(nil, repository.ErrPrStatusConflict)
+
+	resp, err := service.Reopen(context.Background(), &request.ReopenPrRequest{PrId: "pr1"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_TRANSITION", domainErr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Close_PrNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("Get", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+
+	resp, err := service.Close(context.Background(), &request.ClosePrRequest{PrId: "missing"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	reviewError     = errors.New("review pull request error")
+	getReviewsError = errors.New("get pull request reviews error")
+)
+
+var reviewVerdicts = []string{
+	domain.ReviewVerdictApproved,
+	domain.ReviewVerdictChangesRequested,
+	domain.ReviewVerdictCommented,
+}
+
+func (s *PrService) Review(ctx context.Context, req *request.ReviewRequest) (*response.SubmitReviewResponse, error) {
+	prId, err := normalizeID(req.PrId, "pull_request_id")
+	if err != nil {
+		return nil, WrapError(ErrPrNotFound, err)
+	}
+	reviewerId, err := normalizeID(req.ReviewerId, "reviewer_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+	verdict := strings.ToUpper(strings.TrimSpace(req.Verdict))
+	if !isKnownVerdict(verdict) {
+		return nil, WrapError(ErrInvalidVerdict, fmt.Errorf("unknown verdict %q", req.Verdict))
+	}
+	s.log.Info("review PR request accepted",
+		zap.String("pr_id", prId),
+		zap.String("reviewer_id", reviewerId),
+		zap.String("verdict", verdict),
+	)
+
+	dto := &dto.AddReviewDTO{
+		PrId:       prId,
+		ReviewerId: reviewerId,
+		Verdict:    verdict,
+		Comment:    strings.TrimSpace(req.Comment),
+	}
+
+	// Запрос в бд на сохранение вердикта
+	review, err := s.repo.AddReview(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to add review",
+			zap.String("pr_id", prId),
+			zap.String("reviewer_id", reviewerId),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrPrNotFound, err)
+		}
+		if errors.Is(err, repository.ErrPrMergedStatus) {
+			return nil, WrapError(ErrReviewOnMergedPr, err)
+		}
+		if errors.Is(err, repository.ErrPrClosedStatus) {
+			return nil, WrapError(ErrPrClosed, err)
+		}
+		if errors.Is(err, repository.ErrReviewerNotAssigned) {
+			return nil, WrapError(ErrReviewerNotAssigned, err)
+		}
+		return nil, fmt.Errorf("%w: %w", reviewError, err)
+	}
+
+	// Перечитываем состояние, чтобы клиент сразу видел, можно ли мержить PR
+	state, err := s.repo.GetReviewState(ctx, prId)
+	if err != nil {
+		s.log.Error("failed to load PR review state", zap.String("pr_id", prId), zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", reviewError, err)
+	}
+
+	stateResp := toReviewStateResponse(state)
+	s.log.Info("review added",
+		zap.String("pr_id", prId),
+		zap.String("reviewer_id", reviewerId),
+		zap.Int("approvals", stateResp.Approvals),
+		zap.Int("required_approvals", stateResp.RequiredApprovals),
+	)
+
+	return &response.SubmitReviewResponse{
+		Review: toReviewResponse(review),
+		State:  stateResp,
+	}, nil
+}
+
+func (s *PrService) GetReviews(ctx context.Context, req *request.GetReviewsRequest) (*response.ReviewStateResponse, error) {
+	prId, err := normalizeID(req.PrId, "pull_request_id")
+	if err != nil {
+		return nil, WrapError(ErrPrNotFound, err)
+	}
+	s.log.Info("get PR reviews request accepted", zap.String("pr_id", prId))
+
+	state, err := s.repo.GetReviewState(ctx, prId)
+	if err != nil {
+		s.log.Error("failed to load PR review state", zap.String("pr_id", prId), zap.Error(err))
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrPrNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", getReviewsError, err)
+	}
+
+	return toReviewStateResponse(state), nil
+}
+
+// summarizeVerdicts считает одобрения и запросы изменений по последнему решающему вердикту
+// каждого назначенного ревьюера. COMMENTED не отменяет предыдущий вердикт, а вердикты
+// снятых с PR ревьюеров не учитываются
+func summarizeVerdicts(assigned []string, reviews []*domain.Review) (int, []string) {
+	latest := make(map[string]string, len(assigned))
+	for _, review := range reviews {
+		if review.Verdict == domain.ReviewVerdictCommented {
+			continue
+		}
+		latest[review.ReviewerId] = review.Verdict
+	}
+
+	approvals := 0
+	changesRequestedBy := make([]string, 0)
+	for _, reviewerId := range assigned {
+		switch latest[reviewerId] {
+		case domain.ReviewVerdictApproved:
+			approvals++
+		case domain.ReviewVerdictChangesRequested:
+			changesRequestedBy = append(changesRequestedBy, reviewerId)
+		}
+	}
+	return approvals, changesRequestedBy
+}
+
+func isKnownVerdict(verdict string) bool {
+	return slices.Contains(reviewVerdicts, verdict)
+}
+
+func toReviewResponse(review *domain.Review) *response.ReviewResponse {
+	return &response.ReviewResponse{
+		ReviewId:   review.Id,
+		PrId:       review.PrId,
+		ReviewerId: review.ReviewerId,
+		Verdict:    review.Verdict,
+		Comment:    review.Comment,
+		CreatedAt:  formatTime(review.CreatedAt),
+	}
+}
+
+func toReviewStateResponse(state *result.ReviewStateResult) *response.ReviewStateResponse {
+	approvals, changesRequestedBy := summarizeVerdicts(state.Pr.AssignedReviewers, state.Reviews)
+
+	reviews := make([]response.ReviewResponse, 0, len(state.Reviews))
+	for _, review := range state.Reviews {
+		reviews = append(reviews, *toReviewResponse(review))
+	}
+
+	return &response.ReviewStateResponse{
+		PrId:               state.Pr.Id,
+		Status:             state.Pr.Status,
+		Approvals:          approvals,
+		RequiredApprovals:  state.RequiredApprovals,
+		Approved:           approvals >= state.RequiredApprovals,
+		ChangesRequestedBy: changesRequestedBy,
+		Reviews:            reviews,
+	}
+}
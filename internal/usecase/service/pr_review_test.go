@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func review(reviewerId, verdict string) *domain.Review {
+	return &domain.Review{PrId: "pr1", ReviewerId: reviewerId, Verdict: verdict, CreatedAt: time.Now()}
+}
+
+func TestSummarizeVerdicts(t *testing.T) {
+	reviews := []*domain.Review{
+		review("r1", domain.ReviewVerdictChangesRequested),
+		review("r1", domain.ReviewVerdictApproved),
+		review("r1", domain.ReviewVerdictCommented), // комментарий не отменяет одобрение
+		review("r2", domain.ReviewVerdictApproved),
+		review("r2", domain.ReviewVerdictChangesRequested),
+		review("old", domain.ReviewVerdictApproved), // снят с PR, не учитывается
+	}
+
+	approvals, changesRequestedBy := summarizeVerdicts([]string{"r1", "r2", "r3"}, reviews)
+
+	assert.Equal(t, 1, approvals)
+	assert.Equal(t, []string{"r2"}, changesRequestedBy)
+}
+
+func TestPrService_Review_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	added := &domain.Review{Id: 7, PrId: "pr1", ReviewerId: "r1", Verdict: domain.ReviewVerdictApproved, CreatedAt: time.Now()}
+	state := &result.ReviewStateResult{
+		Pr: &result.PrResult{
+			Id:                "pr1",
+			Status:            domain.PrStatusOpen,
+			AssignedReviewers: []string{"r1", "r2"},
+		},
+		RequiredApprovals: 2,
+		Reviews:           []*domain.Review{added},
+	}
+
+	mockRepo.On("AddReview", mock.Anything, mock.MatchedBy(func(d *dto.AddReviewDTO) bool {
+		return d.PrId == "pr1" && d.ReviewerId == "r1" && d.Verdict == domain.ReviewVerdictApproved && d.Comment == "lgtm"
+	})).Return(added, nil)
+	mockRepo.On("GetReviewState", mock.Anything, "pr1").Return(state, nil)
+
+	resp, err := service.Review(context.Background(), &request.ReviewRequest{
+		PrId:       "pr1",
+		ReviewerId: "r1",
+		Verdict:    "approved",
+		Comment:    " lgtm ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), resp.Review.ReviewId)
+	assert.Equal(t, 1, resp.State.Approvals)
+	assert.Equal(t, 2, resp.State.RequiredApprovals)
+	assert.False(t, resp.State.Approved)
+	assert.Len(t, resp.State.Reviews, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Review_InvalidVerdict(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	resp, err := service.Review(context.Background(), &request.ReviewRequest{
+		PrId:       "pr1",
+		ReviewerId: "r1",
+		Verdict:    "LGTM",
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+	mockRepo.AssertNotCalled(t, "AddReview", mock.Anything, mock.Anything)
+}
+
+func TestPrService_Review_RepositoryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		wantCode string
+	}{
+		{"not assigned", repository.ErrReviewerNotAssigned, "NOT_ASSIGNED"},
+		{"merged", repository.ErrPrMergedStatus, "PR_MERGED"},
+		{"closed", repository.ErrPrClosedStatus, "PR_CLOSED"},
+		{"not found", repository.ErrNotFound, "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPrRepository)
+			service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+			mockRepo.On("AddReview", mock.Anything, mock.Anything).Return(nil, tt.repoErr)
+
+			resp, err := service.Review(context.Background(), &request.ReviewRequest{
+				PrId:       "pr1",
+				ReviewerId: "r1",
+				Verdict:    domain.ReviewVerdictCommented,
+			})
+
+			assert.Nil(t, resp)
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, tt.wantCode, domainErr.Code)
+		})
+	}
+}
+
+func TestPrService_Merge_NotEnoughApprovals(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("Merge", mock.Anything, mock.MatchedBy(func(d *dto.MergePrDTO) bool {
+		return d.PrId == "pr1" && !d.SkipApprovals
+	})).Return(nil, fmt.Errorf("%w: 1 of 2 approvals", repository.ErrNotApproved))
+
+	resp, err := service.Merge(context.Background(), &request.MergeRequest{PrId: "pr1"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_APPROVED", domainErr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Merge_ExternalSkipsApprovals(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mergedAt := time.Now()
+	mockRepo.On("Merge", mock.Anything, mock.MatchedBy(func(d *dto.MergePrDTO) bool {
+		return d.PrId == "pr1" && d.SkipApprovals
+	})).Return(&result.MergeResult{
+		Pr: &result.PrResult{
+			Id:        "pr1",
+			Status:    domain.PrStatusMerged,
+			CreatedAt: time.Now(),
+			MergedAt:  &mergedAt,
+		},
+		Merged: true,
+	}, nil)
+
+	resp, err := service.Merge(context.Background(), &request.MergeRequest{PrId: "pr1", External: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PrStatusMerged, resp.Status)
+	mockRepo.AssertExpectations(t)
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,33 +23,59 @@ var (
 	mergeError               = errors.New("merge pull request error")
 	reassignError            = errors.New("reassigning pull request reviewer error")
 	noPotentialReviewerError = errors.New("no active reviewer available")
+	getHistoryError          = errors.New("get pull request history error")
+	getExplanationsError     = errors.New("get assignment explanations error")
 )
 
 const (
-	reviewerCountForCreate   = 2
+	// Количество ревьюеров для команд без собственных настроек
+	defaultReviewerCount     = 2
+	maxReviewerCount         = 10
 	reviewerCountForReassign = 1
+	// Длина колонки reason в журнале назначений
+	maxReasonLength = 255
 )
 
 // Интерфейс репозитория
 type PrRepository interface {
 	Create(ctx context.Context, dto *dto.CreatPrDTO, prReviewers []string) (*result.PrResult, error)
-	Merge(ctx context.Context, dto *dto.MergePrDTO) (*result.PrResult, error)
+	Merge(ctx context.Context, dto *dto.MergePrDTO) (*result.MergeResult, error)
 	Reassign(ctx context.Context, dto *dto.ReassignPrDTO) (*result.ReassignResult, error)
-	SelectPotentialReviewers(ctx context.Context, userId string) ([]*domain.User, error)
+	Get(ctx context.Context, prId string) (*result.PrResult, error)
+	ChangeStatus(ctx context.Context, dto *dto.ChangePrStatusDTO) (*result.PrResult, error)
+	AddReview(ctx context.Context, dto *dto.AddReviewDTO) (*domain.Review, error)
+	GetReviewState(ctx context.Context, prId string) (*result.ReviewStateResult, error)
+	GetHistory(ctx context.Context, prId string) ([]*domain.AssignmentEvent, error)
+	GetAssignmentExplanations(ctx context.Context, prId string) ([]*domain.AssignmentExplanation, error)
+	ReviewerPoolRepository
 	CheckReviewerAssigned(ctx context.Context, prId, reviewerId string) (bool, error)
-	CheckReviewerAssignedWithPR(ctx context.Context, prId, reviewerId string) (bool, string, error)
+	CheckReviewerAssignedWithPR(ctx context.Context, prId, reviewerId string) (bool, *result.PrResult, error)
 	GetStats(ctx context.Context) (*result.StatsResult, error)
+	SelectSlaReviews(ctx context.Context, teamName string) ([]*result.SlaReviewResult, error)
+	EscalateReview(ctx context.Context, dto *dto.EscalateReviewDTO) (*result.PrResult, error)
 }
 
 type PrService struct {
-	repo PrRepository
-	log  *zap.Logger
+	repo      PrRepository
+	selectors *ReviewerSelectors
+	replacer  *reviewerReplacer
+	events    EventPublisher
+	// Рабочее время для сроков ревью и текущее время
+	hours BusinessHours
+	now   func() time.Time
+	log   *zap.Logger
 }
 
-func NewPrService(repo PrRepository, log *zap.Logger) *PrService {
+// NewPrService создает сервис PR. events может быть nil - тогда живые события не публикуются
+func NewPrService(repo PrRepository, selectors *ReviewerSelectors, events EventPublisher, log *zap.Logger) *PrService {
 	return &PrService{
-		repo: repo,
-		log:  log,
+		repo:      repo,
+		selectors: selectors,
+		replacer:  newReviewerReplacer(repo, selectors, log),
+		events:    events,
+		hours:     DefaultBusinessHours(),
+		now:       time.Now,
+		log:       log,
 	}
 }
 
@@ -58,43 +84,37 @@ func (s *PrService) Create(ctx context.Context, req *request.CreateRequest) (*re
 	if err != nil {
 		return nil, WrapError(ErrPrNotFound, err)
 	}
+	changedPaths, err := normalizeChangedPaths(req.ChangedPaths)
+	if err != nil {
+		return nil, WrapError(ErrInvalidChangedPaths, err)
+	}
+	requiredTags, err := normalizeTags(req.RequiredTags)
+	if err != nil {
+		return nil, WrapError(ErrInvalidTags, err)
+	}
+	teamName := strings.TrimSpace(req.TeamName)
 	s.log.Info("create PR request accepted",
 		zap.String("pr_id", req.PrId),
 		zap.String("author_id", authorId),
+		zap.String("team_name", teamName),
+		zap.Bool("requires_lead_review", req.RequiresLeadReview),
 	)
 
-	// Читаем всех членов команды автора
-	potentialReviewers, err := s.repo.SelectPotentialReviewers(ctx, authorId)
-	if err != nil {
-		s.log.Error("failed to load potential reviewers",
-			zap.String("author_id", authorId),
-			zap.Error(err),
-		)
-
-		// Маппим ошибки
-		if errors.Is(err, repository.ErrNotFound) {
-			return nil, WrapError(ErrPrNotFound, err)
-		}
-
-		// Неизвестная ошибка
-		return nil, fmt.Errorf("%w: %w", createError, err)
-	}
-
-	// Ищем до двух активных ревьюеров, исключая автора
-	reviewers, err := findReviewers(potentialReviewers, authorId, reviewerCountForCreate)
-	if err != nil {
-		if errors.Is(err, noPotentialReviewerError) {
-			s.log.Info("no reviewers available, creating PR with empty reviewers list",
-				zap.String("author_id", authorId),
-			)
-			reviewers = []string{} // Пустой массив ревьюеров
-		} else {
-			s.log.Warn("error finding reviewers",
-				zap.String("author_id", authorId),
-				zap.Error(err),
-			)
-
-			// Маппим другие ошибки
+	// Черновик создается без ревьюеров, они подбираются при переводе в OPEN
+	status := domain.PrStatusOpen
+	pick := &reviewerPick{Reviewers: []string{}}
+	if req.Draft {
+		status = domain.PrStatusDraft
+	} else {
+		pick, err = s.pickReviewers(ctx, authorId, teamName, changedPaths, requiredTags, req.RequiresLeadReview, domain.AssignReasonPrCreated)
+		if err != nil {
+			// Маппим ошибки
+			if errors.Is(err, noLeadReviewerError) {
+				return nil, WrapError(ErrNoLeadCandidate, err)
+			}
+			if errors.Is(err, repository.ErrNotTeamMember) {
+				return nil, WrapError(ErrNotTeamMember, err)
+			}
 			if errors.Is(err, repository.ErrNotFound) {
 				return nil, WrapError(ErrPrNotFound, err)
 			}
@@ -110,12 +130,20 @@ func (s *PrService) Create(ctx context.Context, req *request.CreateRequest) (*re
 	}
 
 	dto := &dto.CreatPrDTO{
-		PrId:     prId,
-		PrName:   req.PrName,
-		AuthorId: authorId,
+		PrId:         prId,
+		PrName:       req.PrName,
+		AuthorId:     authorId,
+		Status:       status,
+		ChangedPaths: changedPaths,
+		RequiredTags: requiredTags,
+		TeamName:     teamName,
+		Explanation:  pick.Explanation,
+
+		RequiresLeadReview: req.RequiresLeadReview,
+		LeadReviewerId:     pick.LeadReviewer,
 	}
 
-	res, err := s.repo.Create(ctx, dto, reviewers)
+	res, err := s.repo.Create(ctx, dto, pick.Reviewers)
 	if err != nil {
 		s.log.Error("failed to create PR",
 			zap.String("pr_id", prId),
@@ -126,6 +154,9 @@ func (s *PrService) Create(ctx context.Context, req *request.CreateRequest) (*re
 		if errors.Is(err, repository.ErrAlreadyExists) {
 			return nil, WrapError(ErrPrExists, err)
 		}
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			return nil, WrapError(ErrNotTeamMember, err)
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, WrapError(ErrPrNotFound, err)
 		}
@@ -135,7 +166,10 @@ func (s *PrService) Create(ctx context.Context, req *request.CreateRequest) (*re
 	s.log.Info("PR created",
 		zap.String("pr_id", res.Id),
 		zap.Strings("assigned_reviewers", res.AssignedReviewers),
+		zap.String("fallback_team", pick.FallbackTeam),
 	)
+	s.publishPrEvent(ctx, domain.EventPrCreated, res)
+	s.publishAssigned(ctx, res, res.AssignedReviewers, domain.AssignReasonPrCreated)
 
 	return &response.CreateResponse{
 		PrId:              res.Id,
@@ -145,9 +179,294 @@ func (s *PrService) Create(ctx context.Context, req *request.CreateRequest) (*re
 		AssignedReviewers: res.AssignedReviewers,
 		CreatedAt:         formatTime(res.CreatedAt),
 		MergedAt:          formatTimePtr(res.MergedAt),
+		RequiredTags:      requiredTags,
+		CrossTeam:         pick.FallbackTeam != "",
+		FallbackTeam:      pick.FallbackTeam,
+		ParentTeams:       pick.ParentTeams,
+		Warnings:          pick.Warnings,
+
+		RequiresLeadReview: res.RequiresLeadReview,
+		LeadReviewer:       res.LeadReviewerId,
+	}, nil
+}
+
+func (s *PrService) GetHistory(ctx context.Context, req *request.GetHistoryRequest) (*response.HistoryResponse, error) {
+	prId, err := normalizeID(req.PrId, "pull_request_id")
+	if err != nil {
+		return nil, WrapError(ErrPrNotFound, err)
+	}
+	s.log.Info("get PR history request accepted", zap.String("pr_id", prId))
+
+	events, err := s.repo.GetHistory(ctx, prId)
+	if err != nil {
+		s.log.Error("failed to load PR history", zap.String("pr_id", prId), zap.Error(err))
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrPrNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", getHistoryError, err)
+	}
+
+	// Преобразуем журнал в ответ
+	timeline := make([]response.AssignmentEventResponse, 0, len(events))
+	for _, event := range events {
+		timeline = append(timeline, response.AssignmentEventResponse{
+			EventId:        event.Id,
+			Type:           event.Type,
+			UserId:         event.UserId,
+			PreviousUserId: event.PreviousUserId,
+			Actor:          event.Actor,
+			Reason:         event.Reason,
+			RequestId:      event.RequestId,
+			CreatedAt:      formatTime(event.CreatedAt),
+		})
+	}
+
+	return &response.HistoryResponse{
+		PrId:   prId,
+		Events: timeline,
 	}, nil
 }
 
+func (s *PrService) GetAssignmentExplain(ctx context.Context, req *request.GetAssignmentExplainRequest) (*response.AssignmentExplainResponse, error) {
+	prId, err := normalizeID(req.PrId, "pull_request_id")
+	if err != nil {
+		return nil, WrapError(ErrPrNotFound, err)
+	}
+	userId := strings.TrimSpace(req.UserId)
+	s.log.Info("get assignment explanations request accepted",
+		zap.String("pr_id", prId),
+		zap.String("user_id", userId),
+	)
+
+	explanations, err := s.repo.GetAssignmentExplanations(ctx, prId)
+	if err != nil {
+		s.log.Error("failed to load assignment explanations", zap.String("pr_id", prId), zap.Error(err))
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrPrNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", getExplanationsError, err)
+	}
+
+	// Преобразуем объяснения в ответ, с user_id оставляем подборы, где пользователь был кандидатом
+	resp := &response.AssignmentExplainResponse{
+		PrId:         prId,
+		Explanations: make([]response.AssignmentExplanationResponse, 0, len(explanations)),
+	}
+	for _, explanation := range explanations {
+		if userId != "" && !explainsCandidate(explanation, userId) {
+			continue
+		}
+		resp.Explanations = append(resp.Explanations, toExplanationResponse(explanation))
+	}
+	return resp, nil
+}
+
+// explainsCandidate проверяет, что пользователь был в одном из рассмотренных пулов
+func explainsCandidate(explanation *domain.AssignmentExplanation, userId string) bool {
+	for _, pool := range explanation.Pools {
+		for _, candidate := range pool.Candidates {
+			if candidate.UserId == userId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func toExplanationResponse(explanation *domain.AssignmentExplanation) response.AssignmentExplanationResponse {
+	reviewers := explanation.Reviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	pools := make([]response.ExplainedPoolResponse, 0, len(explanation.Pools))
+	for _, pool := range explanation.Pools {
+		candidates := make([]response.ExplainedCandidateResponse, 0, len(pool.Candidates))
+		for _, candidate := range pool.Candidates {
+			candidates = append(candidates, response.ExplainedCandidateResponse{
+				UserId:         candidate.UserId,
+				OpenReviews:    candidate.OpenReviews,
+				RecentPairings: candidate.RecentPairings,
+				Lead:           candidate.Lead,
+				CodeOwner:      candidate.CodeOwner,
+				CoversTags:     candidate.CoversTags,
+				Excluded:       candidate.Excluded,
+				Selected:       candidate.Selected,
+			})
+		}
+		pools = append(pools, response.ExplainedPoolResponse{
+			TeamName:   pool.TeamName,
+			Strategy:   pool.Strategy,
+			Candidates: candidates,
+		})
+	}
+
+	return response.AssignmentExplanationResponse{
+		ExplanationId: explanation.Id,
+		Reason:        explanation.Reason,
+		TeamName:      explanation.TeamName,
+		Seed:          explanation.Seed,
+		Reviewers:     reviewers,
+		Pools:         pools,
+		CreatedAt:     formatTime(explanation.CreatedAt),
+	}
+}
+
+// reviewerPick итог подбора ревьюеров. FallbackTeam заполнена, если ревьюеры взяты из резервной команды,
+// ParentTeams - если часть ревьюеров добрана из родительских команд. LeadReviewer - лид среди Reviewers,
+// если PR требует ревью лида. Warnings описывают пожелания к подбору, которые не удалось выполнить
+// reviewerPick TeamName - команда автора, по настройкам которой шел подбор
+type reviewerPick struct {
+	TeamName     string
+	Reviewers    []string
+	FallbackTeam string
+	ParentTeams  []string
+	LeadReviewer string
+	Warnings     []string
+	Explanation  *domain.AssignmentExplanation
+}
+
+// pickReviewers подбирает ревьюеров для PR автора по настройкам его команды teamName (пусто - основной).
+// Если в ней не хватает кандидатов, недостающие места добираются из родительских команд, а если
+// не нашлось никого - ревьюеры берутся из резервных команд. Владельцы измененных файлов берутся из CODEOWNERS
+// команды автора, теги PR сверяются с тегами участников. Отсутствие кандидатов не ошибка: возвращается
+// пустой список. Исключение - requireLeadReview: если лида не нашлось ни в команде автора, ни выше по иерархии,
+// ни в резервных командах, возвращается noLeadReviewerError. Объяснение подбора записывается с причиной reason
+func (s *PrService) pickReviewers(ctx context.Context, authorId, teamName string, changedPaths, requiredTags []string, requireLeadReview bool, reason string) (*reviewerPick, error) {
+	// Читаем всех членов команды автора
+	var potentialReviewers *result.PotentialReviewersResult
+	var err error
+	if teamName != "" {
+		potentialReviewers, err = s.repo.SelectMemberPool(ctx, authorId, teamName)
+	} else {
+		potentialReviewers, err = s.repo.SelectPotentialReviewers(ctx, authorId)
+	}
+	if err != nil {
+		s.log.Error("failed to load potential reviewers",
+			zap.String("author_id", authorId),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	// Кандидаты, недавно ревьюившие автора, выбираются реже
+	pairings, err := s.replacer.recentPairings(ctx, authorId)
+	if err != nil {
+		return nil, err
+	}
+	applyPairings(potentialReviewers, pairings)
+
+	// Ищем активных ревьюеров по настройкам команды автора, исключая самого автора
+	settings := effectiveTeamSettings(potentialReviewers.TeamId, potentialReviewers.Settings)
+	owners := matchCodeOwners(potentialReviewers.CodeOwners, changedPaths)
+	explainer := newAssignmentExplainer(s.selectors, reason)
+	explainer.exclude(domain.ExclusionAuthor, authorId)
+	if settings.RequireLead {
+		explainer.lead = settings.LeadUserId
+	}
+	explainer.owners = owners
+	explainer.requiredTags = requiredTags
+
+	// PR требует ревью лида - лид занимает первое место среди ревьюеров
+	var leadIds []string
+	var leadTeam string
+	if requireLeadReview {
+		lead, team, err := s.replacer.findLeadReviewer(ctx, explainer, potentialReviewers, []string{authorId}, pairings)
+		if err != nil {
+			s.log.Warn("no lead reviewer available", zap.String("author_id", authorId), zap.Error(err))
+			return nil, err
+		}
+		leadIds, leadTeam = []string{lead}, team
+	}
+
+	selector := explainer.selectorFor(potentialReviewers)
+	reviewers, err := selectCreateReviewers(selector, potentialReviewers, settings, authorId, leadIds, owners, requiredTags)
+
+	// Команде автора не хватает кандидатов - поднимаемся по иерархии команд
+	var parents *parentPick
+	if (err == nil || errors.Is(err, noPotentialReviewerError)) && len(reviewers) < settings.ReviewerCount {
+		excludedIds := append([]string{authorId}, reviewers...)
+		var parentErr error
+		parents, parentErr = s.replacer.findParentReviewers(ctx, explainer, potentialReviewers, excludedIds, settings.ReviewerCount-len(reviewers), requiredTags, settings.RequireTags, pairings)
+		switch {
+		case parentErr == nil:
+			reviewers = append(reviewers, parents.Reviewers...)
+			err = nil
+		case !errors.Is(parentErr, noPotentialReviewerError):
+			err = parentErr
+		}
+	}
+
+	// Ни в команде автора, ни выше по иерархии кандидатов кроме лида нет - обращаемся к резервным командам
+	var fallbackTeam string
+	if (err == nil || errors.Is(err, noPotentialReviewerError)) && len(reviewers) == len(leadIds) && settings.ReviewerCount > len(leadIds) {
+		excludedIds := append([]string{authorId}, leadIds...)
+		fallback, team, fallbackErr := s.replacer.findFallbackReviewers(ctx, explainer, potentialReviewers.TeamId, excludedIds, settings.ReviewerCount-len(leadIds), pairings)
+		switch {
+		case fallbackErr == nil:
+			reviewers, fallbackTeam, err = append(slices.Clone(leadIds), fallback...), team, nil
+		case !errors.Is(fallbackErr, noPotentialReviewerError):
+			err = fallbackErr
+		}
+	}
+	if err != nil {
+		if errors.Is(err, noPotentialReviewerError) {
+			s.log.Info("no reviewers available, using empty reviewers list",
+				zap.String("author_id", authorId),
+			)
+			// Пустой массив ревьюеров
+			return &reviewerPick{TeamName: potentialReviewers.TeamName, Reviewers: []string{}, Explanation: explainer.finish("", []string{})}, nil
+		}
+		s.log.Warn("error finding reviewers",
+			zap.String("author_id", authorId),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	pickedTeam := potentialReviewers.TeamName
+	pools := []*result.PotentialReviewersResult{potentialReviewers}
+	var parentTeams []string
+	if parents != nil {
+		// Все ревьюеры из родительских команд - подбор считается сделанным в ближайшей из них
+		if len(parents.Reviewers) == len(reviewers) {
+			pickedTeam = parents.Teams[0]
+		}
+		parentTeams = parents.Teams
+		pools = append(pools, parents.Pools...)
+	}
+	if fallbackTeam != "" {
+		pickedTeam = fallbackTeam
+	}
+	pick := &reviewerPick{
+		TeamName:     potentialReviewers.TeamName,
+		Reviewers:    reviewers,
+		FallbackTeam: fallbackTeam,
+		ParentTeams:  parentTeams,
+		Explanation:  explainer.finish(pickedTeam, reviewers),
+	}
+	if len(leadIds) > 0 {
+		pick.LeadReviewer = leadIds[0]
+		s.log.Info("lead reviewer assigned",
+			zap.String("author_id", authorId),
+			zap.String("lead_reviewer", pick.LeadReviewer),
+			zap.String("lead_team", leadTeam),
+		)
+	}
+	// Теги PR не покрывает ни один ревьюер - выбор шел из всей команды
+	covered := slices.ContainsFunc(pools, func(pool *result.PotentialReviewersResult) bool {
+		return anyCoversTags(pool, reviewers, requiredTags)
+	})
+	if len(requiredTags) > 0 && len(reviewers) > 0 && !covered {
+		s.log.Warn("no reviewer covers required tags",
+			zap.String("author_id", authorId),
+			zap.Strings("required_tags", requiredTags),
+		)
+		pick.Warnings = append(pick.Warnings, fmt.Sprintf("no available reviewer covers tags %s, reviewers are picked from the team pool", strings.Join(requiredTags, ", ")))
+	}
+
+	return pick, nil
+}
+
 func (s *PrService) Merge(ctx context.Context, req *request.MergeRequest) (*response.MergeResponse, error) {
 	prId, err := normalizeID(req.PrId, "pull_request_id")
 	if err != nil {
@@ -155,12 +474,15 @@ func (s *PrService) Merge(ctx context.Context, req *request.MergeRequest) (*resp
 	}
 	s.log.Info("merge PR request accepted", zap.String("pr_id", prId))
 
+	// Одобрения проверяются в транзакции слияния. Слияние, уже произошедшее у провайдера,
+	// отражается без проверки
 	dto := &dto.MergePrDTO{
-		PrId: prId,
+		PrId:          prId,
+		SkipApprovals: req.External,
 	}
 
 	// Запрос в бд на изменение статуса
-	res, err := s.repo.Merge(ctx, dto)
+	merge, err := s.repo.Merge(ctx, dto)
 	if err != nil {
 		s.log.Error("failed to merge PR",
 			zap.String("pr_id", prId),
@@ -171,15 +493,29 @@ func (s *PrService) Merge(ctx context.Context, req *request.MergeRequest) (*resp
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, WrapError(ErrPrNotFound, err)
 		}
+		if errors.Is(err, repository.ErrPrClosedStatus) {
+			return nil, WrapError(ErrPrClosed, err)
+		}
+		if errors.Is(err, repository.ErrPrStatusConflict) {
+			return nil, WrapError(ErrInvalidTransition, err)
+		}
+		if errors.Is(err, repository.ErrNotApproved) {
+			return nil, WrapError(ErrNotApproved, err)
+		}
 
 		// Неизвестная ошибка
 		return nil, fmt.Errorf("%w: %w", mergeError, err)
 	}
 
+	res := merge.Pr
 	s.log.Info("PR merged",
 		zap.String("pr_id", res.Id),
 		zap.String("status", res.Status),
 	)
+	// Повторный merge идемпотентен и события не порождает
+	if merge.Merged {
+		s.publishPrEvent(ctx, domain.EventPrMerged, res)
+	}
 
 	return &response.MergeResponse{
 		PrId:              res.Id,
@@ -203,13 +539,21 @@ func (s *PrService) Reassign(ctx context.Context, req *request.ReassignRequest)
 	if err != nil {
 		return nil, WrapError(ErrPrNotFound, err)
 	}
+	// Причина попадает в журнал назначений
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = domain.AssignReasonManualReassign
+	}
+	if len(reason) > maxReasonLength {
+		return nil, WrapError(ErrInvalidReason, fmt.Errorf("reason must be at most %d characters", maxReasonLength))
+	}
 	s.log.Info("reassign reviewer request accepted",
 		zap.String("pr_id", prId),
 		zap.String("old_user_id", oldReviewerId),
 	)
 
 	// Проверяем что ревьюер назначен на PR и получаем информацию о PR
-	isAssigned, prAuthorId, err := s.repo.CheckReviewerAssignedWithPR(ctx, prId, oldReviewerId)
+	isAssigned, pr, err := s.repo.CheckReviewerAssignedWithPR(ctx, prId, oldReviewerId)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, WrapError(ErrPrNotFound, err)
@@ -217,11 +561,15 @@ func (s *PrService) Reassign(ctx context.Context, req *request.ReassignRequest)
 		if errors.Is(err, repository.ErrPrMergedStatus) {
 			return nil, WrapError(ErrPrMerged, err)
 		}
+		if errors.Is(err, repository.ErrPrClosedStatus) {
+			return nil, WrapError(ErrPrClosed, err)
+		}
 		return nil, fmt.Errorf("%w: %w", reassignError, err)
 	}
 	if !isAssigned {
 		return nil, WrapError(ErrReviewerNotAssigned, errors.New("reviewer is not assigned to this PR"))
 	}
+	prAuthorId := pr.AuthorId
 
 	// Читаем всех членов команды старого ревьюера
 	potentialReviewers, err := s.repo.SelectPotentialReviewers(ctx, oldReviewerId)
@@ -240,8 +588,11 @@ func (s *PrService) Reassign(ctx context.Context, req *request.ReassignRequest)
 		return nil, fmt.Errorf("%w: %w", reassignError, err)
 	}
 
-	// Ищем нового активного ревьюера, исключая старого и автора PR
-	newReviewer, err := findReviewersExcludingAuthor(potentialReviewers, oldReviewerId, prAuthorId, reviewerCountForReassign)
+	// Ищем нового активного ревьюера тем же подбором, что и при деактивации и изменении состава команд
+	explainer := newAssignmentExplainer(s.selectors, reason)
+	explainer.exclude(domain.ExclusionReplaced, oldReviewerId)
+	explainer.exclude(domain.ExclusionAuthor, prAuthorId)
+	replaced, err := s.replacer.replace(ctx, explainer, potentialReviewers, pr, oldReviewerId, []string{oldReviewerId, prAuthorId})
 	if err != nil {
 		s.log.Warn("no replacement reviewer available",
 			zap.String("pr_id", prId),
@@ -252,15 +603,20 @@ func (s *PrService) Reassign(ctx context.Context, req *request.ReassignRequest)
 		if errors.Is(err, noPotentialReviewerError) {
 			return nil, ErrNoCandidate
 		}
+		if errors.Is(err, noLeadReviewerError) {
+			return nil, WrapError(ErrNoLeadCandidate, err)
+		}
 		return nil, fmt.Errorf("%w: %w", reassignError, err)
 	}
-	newReviewerId := newReviewer[0]
+	newReviewerId := replaced.ReviewerId
 
 	// Собираем dto для репозитория
 	dto := &dto.ReassignPrDTO{
 		PrId:          prId,
 		OldReviewerId: oldReviewerId,
 		ReplacedBy:    newReviewerId,
+		Reason:        reason,
+		Explanation:   replaced.Explanation,
 	}
 
 	// Запрос в бд на переназначение ревьюеров
@@ -283,6 +639,9 @@ func (s *PrService) Reassign(ctx context.Context, req *request.ReassignRequest)
 		if errors.Is(err, repository.ErrPrMergedStatus) {
 			return nil, WrapError(ErrPrMerged, err)
 		}
+		if errors.Is(err, repository.ErrPrClosedStatus) {
+			return nil, WrapError(ErrPrClosed, err)
+		}
 		if errors.Is(err, repository.ErrReviewerNotAssigned) {
 			return nil, WrapError(ErrReviewerNotAssigned, err)
 		}
@@ -294,6 +653,7 @@ func (s *PrService) Reassign(ctx context.Context, req *request.ReassignRequest)
 		zap.Strings("assigned_reviewers", res.Pr.AssignedReviewers),
 		zap.String("replaced_by", res.ReplacedBy),
 	)
+	s.publishReassigned(ctx, res.Pr, oldReviewerId, res.ReplacedBy, reason)
 
 	return &response.ReassignResponse{
 		PrId:              res.Pr.Id,
@@ -304,63 +664,141 @@ func (s *PrService) Reassign(ctx context.Context, req *request.ReassignRequest)
 		ReplacedBy:        res.ReplacedBy,
 		CreatedAt:         formatTime(res.Pr.CreatedAt),
 		MergedAt:          formatTimePtr(res.Pr.MergedAt),
+		LeadReviewer:      res.Pr.LeadReviewerId,
+		CrossTeam:         replaced.FallbackTeam != "",
+		FallbackTeam:      replaced.FallbackTeam,
+		ParentTeam:        replaced.ParentTeam,
 	}, nil
 }
 
-func findReviewers(potentialReviewers []*domain.User, excludedId string, reviewerCount int) ([]string, error) {
-	var reviewers []*domain.User
-	for _, potentialReviewer := range potentialReviewers {
-		if potentialReviewer == nil {
-			continue
-		}
+// findFallbackReviewers перебирает резервные команды по порядку и возвращает ревьюеров
+// из первой, где нашлись кандидаты, вместе с ее названием. Цепочки резервных команд не наследуются.
+// Рассмотренные пулы попадают в объяснение подбора
+func (r *reviewerReplacer) findFallbackReviewers(ctx context.Context, explainer *assignmentExplainer, teamId string, excludedIds []string, count int, pairings map[string]int) ([]string, string, error) {
+	pools, err := r.repo.SelectFallbackPools(ctx, teamId)
+	if err != nil {
+		r.log.Error("failed to load fallback pools",
+			zap.String("team_id", teamId),
+			zap.Error(err),
+		)
+		return nil, "", err
+	}
 
-		if potentialReviewer.Id == excludedId {
-			continue
+	for _, pool := range pools {
+		applyPairings(pool, pairings)
+		selector := explainer.selectorFor(pool)
+		reviewers, err := findReviewers(selector, pool, excludedIds, count)
+		if err == nil {
+			r.log.Info("reviewers taken from fallback team",
+				zap.String("team_id", teamId),
+				zap.String("fallback_team", pool.TeamName),
+				zap.Strings("reviewers", reviewers),
+			)
+			return reviewers, pool.TeamName, nil
 		}
+	}
+	return nil, "", noPotentialReviewerError
+}
 
-		if potentialReviewer.IsActive == false {
-			continue
-		}
+// parentPick ревьюеры, добранные из родительских команд, названия команд, в которых они нашлись,
+// и пулы этих команд
+type parentPick struct {
+	Reviewers []string
+	Teams     []string
+	Pools     []*result.PotentialReviewersResult
+}
 
-		reviewers = append(reviewers, potentialReviewer)
+// findParentReviewers поднимается по иерархии от ближайшей родительской команды pool и добирает
+// ревьюеров, пока их не станет count или пока команды не закончатся. Теги PR учитываются так же,
+// как в команде автора. Для команды без родителя бд не опрашивается. Если не нашлось ни одного
+// ревьюера, возвращается noPotentialReviewerError. Рассмотренные пулы попадают в объяснение подбора
+func (r *reviewerReplacer) findParentReviewers(ctx context.Context, explainer *assignmentExplainer, pool *result.PotentialReviewersResult, excludedIds []string, count int, requiredTags []string, requireTags bool, pairings map[string]int) (*parentPick, error) {
+	if pool.ParentId == "" || count <= 0 {
+		return nil, noPotentialReviewerError
 	}
 
-	if len(reviewers) == 0 {
+	pools, err := r.repo.SelectParentPools(ctx, pool.TeamId)
+	if err != nil {
+		r.log.Error("failed to load parent pools",
+			zap.String("team_id", pool.TeamId),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	pick := &parentPick{}
+	excludedIds = slices.Clone(excludedIds)
+	for _, parent := range pools {
+		if len(pick.Reviewers) >= count {
+			break
+		}
+		applyPairings(parent, pairings)
+		selector := explainer.selectorFor(parent)
+		reviewers, err := findTaggedReviewers(selector, parent, excludedIds, count-len(pick.Reviewers), requiredTags, requireTags)
+		if errors.Is(err, noPotentialReviewerError) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pick.Reviewers = append(pick.Reviewers, reviewers...)
+		pick.Teams = append(pick.Teams, parent.TeamName)
+		pick.Pools = append(pick.Pools, parent)
+		excludedIds = append(excludedIds, reviewers...)
+	}
+	if len(pick.Reviewers) == 0 {
 		return nil, noPotentialReviewerError
 	}
 
-	rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(reviewers), func(i, j int) {
-		reviewers[i], reviewers[j] = reviewers[j], reviewers[i]
-	})
+	r.log.Info("reviewers taken from parent teams",
+		zap.String("team_id", pool.TeamId),
+		zap.Strings("parent_teams", pick.Teams),
+		zap.Strings("reviewers", pick.Reviewers),
+	)
+	return pick, nil
+}
 
-	if len(reviewers) < reviewerCount {
-		reviewerCount = len(reviewers)
+// recentPairings возвращает, сколько из последних PR автора ревьюил каждый пользователь.
+// При отключенном учете пар бд не опрашивается
+func (r *reviewerReplacer) recentPairings(ctx context.Context, authorId string) (map[string]int, error) {
+	window := r.selectors.PairingWindow()
+	if window == 0 {
+		return nil, nil
 	}
 
-	result := make([]string, 0, reviewerCount)
-	for i := 0; i < reviewerCount; i++ {
-		result = append(result, reviewers[i].Id)
+	pairings, err := r.repo.SelectRecentPairings(ctx, authorId, window)
+	if err != nil {
+		r.log.Error("failed to load recent pairings",
+			zap.String("author_id", authorId),
+			zap.Error(err),
+		)
+		return nil, err
 	}
-
-	return result, nil
+	return pairings, nil
 }
 
-func findReviewersExcludingAuthor(potentialReviewers []*domain.User, excludedId, authorId string, reviewerCount int) ([]string, error) {
-	var reviewers []*domain.User
-	for _, potentialReviewer := range potentialReviewers {
-		if potentialReviewer == nil {
-			continue
+// applyPairings проставляет кандидатам пула число недавних ревью PR автора
+func applyPairings(pool *result.PotentialReviewersResult, pairings map[string]int) {
+	for _, candidate := range pool.Candidates {
+		if candidate != nil && candidate.User != nil {
+			candidate.RecentPairings = pairings[candidate.User.Id]
 		}
+	}
+}
 
-		if potentialReviewer.Id == excludedId {
+// findReviewers отбирает активных кандидатов, исключая переданных пользователей, и передает их стратегии
+func findReviewers(selector ReviewerSelector, pool *result.PotentialReviewersResult, excludedIds []string, reviewerCount int) ([]string, error) {
+	var reviewers []*result.ReviewerCandidate
+	for _, potentialReviewer := range pool.Candidates {
+		if potentialReviewer == nil || potentialReviewer.User == nil {
 			continue
 		}
 
-		if potentialReviewer.Id == authorId {
+		if slices.Contains(excludedIds, potentialReviewer.User.Id) {
 			continue
 		}
 
-		if potentialReviewer.IsActive == false {
+		if !isEligible(potentialReviewer) {
 			continue
 		}
 
@@ -371,20 +809,91 @@ func findReviewersExcludingAuthor(potentialReviewers []*domain.User, excludedId,
 		return nil, noPotentialReviewerError
 	}
 
-	rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(reviewers), func(i, j int) {
-		reviewers[i], reviewers[j] = reviewers[j], reviewers[i]
-	})
+	result := selector.Select(pool.TeamName, reviewers, reviewerCount)
+	if len(result) == 0 {
+		return nil, noPotentialReviewerError
+	}
+
+	return result, nil
+}
 
-	if len(reviewers) < reviewerCount {
-		reviewerCount = len(reviewers)
+// selectCreateReviewers выбирает ревьюеров нового PR с учетом настроек команды автора.
+// Уже выбранные picked, например лид для обязательного ревью лида, идут первыми и занимают места.
+// Если настройки требуют лида команды, он назначается следующим. Затем, если у измененных файлов есть
+// владельцы и среди выбранных их нет, стратегия выбирает одного из доступных владельцев.
+// Остальные места заполняет стратегия, отдавая предпочтение кандидатам с тегами PR
+func selectCreateReviewers(selector ReviewerSelector, pool *result.PotentialReviewersResult, settings *domain.TeamSettings, authorId string, picked, owners, requiredTags []string) ([]string, error) {
+	reviewerCount := settings.ReviewerCount - len(picked)
+	excludedIds := append([]string{authorId}, picked...)
+	reviewers := make([]string, 0, settings.ReviewerCount)
+	reviewers = append(reviewers, picked...)
+
+	if settings.RequireLead && settings.LeadUserId != "" && !slices.Contains(excludedIds, settings.LeadUserId) {
+		lead := findCandidate(pool, settings.LeadUserId)
+		if lead != nil && isEligible(lead) {
+			reviewers = append(reviewers, lead.User.Id)
+			excludedIds = append(excludedIds, lead.User.Id)
+			reviewerCount--
+		}
 	}
 
-	result := make([]string, 0, reviewerCount)
-	for i := 0; i < reviewerCount; i++ {
-		result = append(result, reviewers[i].Id)
+	// Если лид не владеет измененными файлами, одно место отдаем владельцу
+	ownerChosen := slices.ContainsFunc(reviewers, func(id string) bool { return slices.Contains(owners, id) })
+	if len(owners) > 0 && reviewerCount > 0 && !ownerChosen {
+		owner, err := findReviewers(selector, ownersPool(pool, owners), excludedIds, 1)
+		if err == nil {
+			reviewers = append(reviewers, owner...)
+			excludedIds = append(excludedIds, owner...)
+			reviewerCount--
+		}
 	}
 
-	return result, nil
+	if reviewerCount <= 0 {
+		return reviewers, nil
+	}
+
+	others, err := findTaggedReviewers(selector, pool, excludedIds, reviewerCount, requiredTags, settings.RequireTags)
+	if err != nil {
+		if errors.Is(err, noPotentialReviewerError) && len(reviewers) > 0 {
+			return reviewers, nil
+		}
+		return nil, err
+	}
+
+	return append(reviewers, others...), nil
+}
+
+// isEligible проверяет, что кандидата можно назначить прямо сейчас:
+// он активен, у него не идет период недоступности и не исчерпан предел открытых ревью
+func isEligible(candidate *result.ReviewerCandidate) bool {
+	return candidate.User.IsActive &&
+		!candidate.Unavailable &&
+		!atCapacity(candidate.OpenReviews, candidate.User.MaxOpenReviews)
+}
+
+func atCapacity(openReviews int, maxOpenReviews *int) bool {
+	return maxOpenReviews != nil && openReviews >= *maxOpenReviews
+}
+
+func findCandidate(pool *result.PotentialReviewersResult, userId string) *result.ReviewerCandidate {
+	for _, candidate := range pool.Candidates {
+		if candidate != nil && candidate.User != nil && candidate.User.Id == userId {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// effectiveTeamSettings возвращает настройки команды или значения по умолчанию, если они не заданы
+func effectiveTeamSettings(teamId string, settings *domain.TeamSettings) *domain.TeamSettings {
+	if settings != nil {
+		return settings
+	}
+	return &domain.TeamSettings{
+		TeamId:        teamId,
+		ReviewerCount: defaultReviewerCount,
+		SlaPolicy:     domain.SlaPolicyNotify,
+	}
 }
 
 func formatTime(t time.Time) string {
@@ -412,9 +921,12 @@ func (s *PrService) GetStats(ctx context.Context) (*response.StatsResponse, erro
 	users := make([]response.UserStat, 0, len(stats.Users))
 	for _, u := range stats.Users {
 		users = append(users, response.UserStat{
-			UserId:      u.UserId,
-			Username:    u.Username,
-			Assignments: u.Assignments,
+			UserId:         u.UserId,
+			Username:       u.Username,
+			Assignments:    u.Assignments,
+			OpenReviews:    u.OpenReviews,
+			MaxOpenReviews: u.MaxOpenReviews,
+			AtCapacity:     atCapacity(u.OpenReviews, u.MaxOpenReviews),
 		})
 	}
 
@@ -427,14 +939,26 @@ func (s *PrService) GetStats(ctx context.Context) (*response.StatsResponse, erro
 		})
 	}
 
+	pairs := make([]response.PairStat, 0, len(stats.Pairs))
+	for _, p := range stats.Pairs {
+		pairs = append(pairs, response.PairStat{
+			AuthorId:   p.AuthorId,
+			ReviewerId: p.ReviewerId,
+			Reviews:    p.Reviews,
+		})
+	}
+
 	s.log.Info("statistics retrieved",
 		zap.Int("users_count", len(users)),
 		zap.Int("prs_count", len(prs)),
+		zap.Int("pairs_count", len(pairs)),
 	)
 
 	return &response.StatsResponse{
 		Users: users,
 		PRs:   prs,
+		Pairs: pairs,
+		Teams: teamStats(stats.Teams, stats.Users),
 	}, nil
 }
 
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
 	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -29,12 +32,12 @@ func (m *MockPrRepository) Create(ctx context.Context, dto *dto.CreatPrDTO, prRe
 	return args.Get(0).(*result.PrResult), args.Error(1)
 }
 
-func (m *MockPrRepository) Merge(ctx context.Context, dto *dto.MergePrDTO) (*result.PrResult, error) {
+func (m *MockPrRepository) Merge(ctx context.Context, dto *dto.MergePrDTO) (*result.MergeResult, error) {
 	args := m.Called(ctx, dto)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*result.PrResult), args.Error(1)
+	return args.Get(0).(*result.MergeResult), args.Error(1)
 }
 
 func (m *MockPrRepository) Reassign(ctx context.Context, dto *dto.ReassignPrDTO) (*result.ReassignResult, error) {
@@ -45,12 +48,114 @@ func (m *MockPrRepository) Reassign(ctx context.Context, dto *dto.ReassignPrDTO)
 	return args.Get(0).(*result.ReassignResult), args.Error(1)
 }
 
-func (m *MockPrRepository) SelectPotentialReviewers(ctx context.Context, userId string) ([]*domain.User, error) {
+func (m *MockPrRepository) Get(ctx context.Context, prId string) (*result.PrResult, error) {
+	args := m.Called(ctx, prId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PrResult), args.Error(1)
+}
+
+func (m *MockPrRepository) ChangeStatus(ctx context.Context, dto *dto.ChangePrStatusDTO) (*result.PrResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PrResult), args.Error(1)
+}
+
+func (m *MockPrRepository) AddReview(ctx context.Context, dto *dto.AddReviewDTO) (*domain.Review, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockPrRepository) GetReviewState(ctx context.Context, prId string) (*result.ReviewStateResult, error) {
+	args := m.Called(ctx, prId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.ReviewStateResult), args.Error(1)
+}
+
+func (m *MockPrRepository) GetHistory(ctx context.Context, prId string) ([]*domain.AssignmentEvent, error) {
+	args := m.Called(ctx, prId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AssignmentEvent), args.Error(1)
+}
+
+func (m *MockPrRepository) GetAssignmentExplanations(ctx context.Context, prId string) ([]*domain.AssignmentExplanation, error) {
+	args := m.Called(ctx, prId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AssignmentExplanation), args.Error(1)
+}
+
+func (m *MockPrRepository) SelectPotentialReviewers(ctx context.Context, userId string) (*result.PotentialReviewersResult, error) {
 	args := m.Called(ctx, userId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*domain.User), args.Error(1)
+	return args.Get(0).(*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockPrRepository) SelectMemberPool(ctx context.Context, userId, teamName string) (*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, userId, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockPrRepository) SelectFallbackPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockPrRepository) SelectParentPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockPrRepository) SelectRecentPairings(ctx context.Context, authorId string, window int) (map[string]int, error) {
+	args := m.Called(ctx, authorId, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+// toPool оборачивает пользователей в команду кандидатов без открытых ревью
+func toPool(users []*domain.User) *result.PotentialReviewersResult {
+	candidates := make([]*result.ReviewerCandidate, 0, len(users))
+	for _, u := range users {
+		candidates = append(candidates, &result.ReviewerCandidate{User: u})
+	}
+	return &result.PotentialReviewersResult{
+		TeamId:     "team1-id",
+		TeamName:   "team1",
+		Candidates: candidates,
+	}
+}
+
+// testSelectors стратегии по умолчанию для тестов сервиса
+func testSelectors(t *testing.T) *ReviewerSelectors {
+	selectors, err := NewReviewerSelectors(StrategyLeastLoaded, nil, 0)
+	if err != nil {
+		t.Fatalf("failed to init selectors: %v", err)
+	}
+	return selectors
 }
 
 func (m *MockPrRepository) GetStats(ctx context.Context) (*result.StatsResult, error) {
@@ -61,25 +166,45 @@ func (m *MockPrRepository) GetStats(ctx context.Context) (*result.StatsResult, e
 	return args.Get(0).(*result.StatsResult), args.Error(1)
 }
 
+func (m *MockPrRepository) SelectSlaReviews(ctx context.Context, teamName string) ([]*result.SlaReviewResult, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.SlaReviewResult), args.Error(1)
+}
+
+func (m *MockPrRepository) EscalateReview(ctx context.Context, dto *dto.EscalateReviewDTO) (*result.PrResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PrResult), args.Error(1)
+}
+
 func (m *MockPrRepository) CheckReviewerAssigned(ctx context.Context, prId, reviewerId string) (bool, error) {
 	args := m.Called(ctx, prId, reviewerId)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockPrRepository) CheckReviewerAssignedWithPR(ctx context.Context, prId, reviewerId string) (bool, string, error) {
+func (m *MockPrRepository) CheckReviewerAssignedWithPR(ctx context.Context, prId, reviewerId string) (bool, *result.PrResult, error) {
 	args := m.Called(ctx, prId, reviewerId)
-	return args.Bool(0), args.String(1), args.Error(2)
+	if args.Get(1) == nil {
+		return args.Bool(0), nil, args.Error(2)
+	}
+	return args.Bool(0), args.Get(1).(*result.PrResult), args.Error(2)
 }
 
 func TestPrService_GetStats_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
+	aliceLimit := 2
 	expectedStats := &result.StatsResult{
 		Users: []result.UserStats{
-			{UserId: "u1", Username: "Alice", Assignments: 5},
-			{UserId: "u2", Username: "Bob", Assignments: 3},
+			{UserId: "u1", Username: "Alice", Assignments: 5, OpenReviews: 2, MaxOpenReviews: &aliceLimit},
+			{UserId: "u2", Username: "Bob", Assignments: 3, OpenReviews: 1},
 			{UserId: "u3", Username: "Charlie", Assignments: 0},
 		},
 		PRs: []result.PrStats{
@@ -87,6 +212,10 @@ func TestPrService_GetStats_Success(t *testing.T) {
 			{PrId: "pr2", PrName: "PR 2", ReviewersCount: 1},
 			{PrId: "pr3", PrName: "PR 3", ReviewersCount: 0},
 		},
+		Pairs: []result.PairStats{
+			{AuthorId: "u1", ReviewerId: "u2", Reviews: 4},
+			{AuthorId: "u2", ReviewerId: "u1", Reviews: 1},
+		},
 	}
 
 	mockRepo.On("GetStats", mock.Anything).Return(expectedStats, nil)
@@ -100,16 +229,25 @@ func TestPrService_GetStats_Success(t *testing.T) {
 	assert.Equal(t, "u1", resp.Users[0].UserId)
 	assert.Equal(t, "Alice", resp.Users[0].Username)
 	assert.Equal(t, 5, resp.Users[0].Assignments)
+	assert.Equal(t, 2, resp.Users[0].OpenReviews)
+	assert.Equal(t, &aliceLimit, resp.Users[0].MaxOpenReviews)
+	assert.True(t, resp.Users[0].AtCapacity)
+	assert.Nil(t, resp.Users[1].MaxOpenReviews)
+	assert.False(t, resp.Users[1].AtCapacity)
 	assert.Equal(t, "pr1", resp.PRs[0].PrId)
 	assert.Equal(t, "PR 1", resp.PRs[0].PrName)
 	assert.Equal(t, 2, resp.PRs[0].ReviewersCount)
+	assert.Equal(t, []response.PairStat{
+		{AuthorId: "u1", ReviewerId: "u2", Reviews: 4},
+		{AuthorId: "u2", ReviewerId: "u1", Reviews: 1},
+	}, resp.Pairs)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestPrService_GetStats_EmptyStats(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	expectedStats := &result.StatsResult{
 		Users: []result.UserStats{},
@@ -124,13 +262,15 @@ func TestPrService_GetStats_EmptyStats(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.Len(t, resp.Users, 0)
 	assert.Len(t, resp.PRs, 0)
+	assert.NotNil(t, resp.Pairs)
+	assert.Len(t, resp.Pairs, 0)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestPrService_GetStats_RepositoryError(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	expectedError := errors.New("database error")
 	mockRepo.On("GetStats", mock.Anything).Return(nil, expectedError)
@@ -146,7 +286,7 @@ func TestPrService_GetStats_RepositoryError(t *testing.T) {
 func TestPrService_Create_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.CreateRequest{
 		PrId:     "pr1",
@@ -170,7 +310,7 @@ func TestPrService_Create_Success(t *testing.T) {
 		MergedAt:          nil,
 	}
 
-	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(potentialReviewers, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(toPool(potentialReviewers), nil)
 	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
 		return d.PrId == "pr1" && d.PrName == "Test PR" && d.AuthorId == "author1"
 	}), mock.AnythingOfType("[]string")).Return(expectedPrResult, nil)
@@ -187,10 +327,41 @@ func TestPrService_Create_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestPrService_Create_AvoidsRecentPairs(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	selectors, err := NewReviewerSelectors(StrategyLeastLoaded, nil, 5)
+	assert.NoError(t, err)
+	service := NewPrService(mockRepo, selectors, nil, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "reviewer1", IsActive: true},
+		{Id: "reviewer2", IsActive: true},
+		{Id: "reviewer3", IsActive: true},
+	})
+	pool.Settings = &domain.TeamSettings{TeamId: "team1-id", ReviewerCount: 1}
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("SelectRecentPairings", mock.Anything, "author1", 5).Return(map[string]int{"reviewer1": 3, "reviewer2": 1}, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"reviewer3"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"reviewer3"},
+	}, nil)
+
+	for i := 0; i < 10; i++ {
+		resp, err := service.Create(context.Background(), &request.CreateRequest{PrId: "pr1", PrName: "PR", AuthorId: "author1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"reviewer3"}, resp.AssignedReviewers)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
 func TestPrService_Create_AuthorNotFound(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.CreateRequest{
 		PrId:     "pr1",
@@ -210,10 +381,90 @@ func TestPrService_Create_AuthorNotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestPrService_Create_UsesRequestedTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "reviewer3", IsActive: true},
+	})
+	pool.TeamName = "payments"
+
+	mockRepo.On("SelectMemberPool", mock.Anything, "author1", "payments").Return(pool, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
+		return d.TeamName == "payments" && d.Explanation.TeamName == "payments"
+	}), []string{"reviewer3"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"reviewer3"},
+		TeamName:          "payments",
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "PR",
+		AuthorId: "author1",
+		TeamName: " payments ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"reviewer3"}, resp.AssignedReviewers)
+	mockRepo.AssertNotCalled(t, "SelectPotentialReviewers", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Create_AuthorNotInRequestedTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SelectMemberPool", mock.Anything, "author1", "payments").Return(nil, repository.ErrNotTeamMember)
+
+	_, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "PR",
+		AuthorId: "author1",
+		TeamName: "payments",
+	})
+
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrNotTeamMember.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPrService_Create_DraftAuthorNotInRequestedTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	// Для черновика ревьюеры не подбираются, членство проверяет репозиторий при вставке
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
+		return d.Status == domain.PrStatusDraft && d.TeamName == "payments"
+	}), []string{}).Return(nil, repository.ErrNotTeamMember)
+
+	_, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "PR",
+		AuthorId: "author1",
+		TeamName: "payments",
+		Draft:    true,
+	})
+
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrNotTeamMember.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "SelectMemberPool", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestPrService_Create_InvalidInput_EmptyPrId(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.CreateRequest{
 		PrId:     "",
@@ -225,7 +476,7 @@ func TestPrService_Create_InvalidInput_EmptyPrId(t *testing.T) {
 	potentialReviewers := []*domain.User{
 		{Id: "reviewer1", Name: "Reviewer 1", IsActive: true},
 	}
-	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(potentialReviewers, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(toPool(potentialReviewers), nil)
 
 	resp, err := service.Create(context.Background(), req)
 
@@ -240,7 +491,7 @@ func TestPrService_Create_InvalidInput_EmptyPrId(t *testing.T) {
 func TestPrService_Create_InvalidInput_EmptyAuthorId(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.CreateRequest{
 		PrId:     "pr1",
@@ -261,7 +512,7 @@ func TestPrService_Create_InvalidInput_EmptyAuthorId(t *testing.T) {
 func TestPrService_Create_NoReviewersAvailable(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.CreateRequest{
 		PrId:     "pr1",
@@ -274,7 +525,8 @@ func TestPrService_Create_NoReviewersAvailable(t *testing.T) {
 		{Id: "author1", Name: "Author", IsActive: true}, // Автор исключается
 		{Id: "user1", Name: "User 1", IsActive: false},  // Неактивный
 	}
-	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(potentialReviewers, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(toPool(potentialReviewers), nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{}, nil)
 
 	expectedPrResult := &result.PrResult{
 		Id:                "pr1",
@@ -305,7 +557,7 @@ func TestPrService_Create_NoReviewersAvailable(t *testing.T) {
 func TestPrService_Merge_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.MergeRequest{
 		PrId: "pr1",
@@ -323,8 +575,8 @@ func TestPrService_Merge_Success(t *testing.T) {
 	}
 
 	mockRepo.On("Merge", mock.Anything, mock.MatchedBy(func(d *dto.MergePrDTO) bool {
-		return d.PrId == "pr1"
-	})).Return(expectedPrResult, nil)
+		return d.PrId == "pr1" && !d.SkipApprovals
+	})).Return(&result.MergeResult{Pr: expectedPrResult, Merged: true}, nil)
 
 	resp, err := service.Merge(context.Background(), req)
 
@@ -339,7 +591,7 @@ func TestPrService_Merge_Success(t *testing.T) {
 func TestPrService_Merge_PrNotFound(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.MergeRequest{
 		PrId: "nonexistent",
@@ -357,10 +609,42 @@ func TestPrService_Merge_PrNotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestPrService_Merge_ClosedPr(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("Merge", mock.Anything, mock.Anything).Return(nil, repository.ErrPrClosedStatus)
+
+	resp, err := service.Merge(context.Background(), &request.MergeRequest{PrId: "pr1"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "PR_CLOSED", domainErr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Merge_DraftPr(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("Merge", mock.Anything, mock.Anything).Return(nil, repository.ErrPrStatusConflict)
+
+	resp, err := service.Merge(context.Background(), &request.MergeRequest{PrId: "pr1"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_TRANSITION", domainErr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestPrService_Reassign_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.ReassignRequest{
 		PrId:      "pr1",
@@ -385,10 +669,11 @@ func TestPrService_Reassign_Success(t *testing.T) {
 		ReplacedBy: "new_reviewer",
 	}
 
-	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, "author1", nil)
-	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(potentialReviewers, nil)
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, &result.PrResult{Id: "pr1", AuthorId: "author1"}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(toPool(potentialReviewers), nil)
 	mockRepo.On("Reassign", mock.Anything, mock.MatchedBy(func(d *dto.ReassignPrDTO) bool {
-		return d.PrId == "pr1" && d.OldReviewerId == "old_reviewer" && d.ReplacedBy == "new_reviewer"
+		return d.PrId == "pr1" && d.OldReviewerId == "old_reviewer" && d.ReplacedBy == "new_reviewer" &&
+			d.Reason == domain.AssignReasonManualReassign
 	})).Return(expectedReassignResult, nil)
 
 	resp, err := service.Reassign(context.Background(), req)
@@ -400,17 +685,133 @@ func TestPrService_Reassign_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestPrService_Reassign_AvoidsRecentPairsWithAuthor(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	selectors, err := NewReviewerSelectors(StrategyLeastLoaded, nil, 3)
+	assert.NoError(t, err)
+	service := NewPrService(mockRepo, selectors, nil, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "old_reviewer", IsActive: true},
+		{Id: "frequent", IsActive: true},
+		{Id: "fresh", IsActive: true},
+	})
+
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, &result.PrResult{Id: "pr1", AuthorId: "author1"}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(pool, nil)
+	// Пары считаются для автора PR, а не для заменяемого ревьюера
+	mockRepo.On("SelectRecentPairings", mock.Anything, "author1", 3).Return(map[string]int{"frequent": 2}, nil)
+	mockRepo.On("Reassign", mock.Anything, mock.MatchedBy(func(d *dto.ReassignPrDTO) bool {
+		return d.ReplacedBy == "fresh"
+	})).Return(&result.ReassignResult{
+		Pr:         &result.PrResult{Id: "pr1", AuthorId: "author1", AssignedReviewers: []string{"fresh"}},
+		ReplacedBy: "fresh",
+	}, nil)
+
+	resp, err := service.Reassign(context.Background(), &request.ReassignRequest{PrId: "pr1", OldUserId: "old_reviewer"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "fresh", resp.ReplacedBy)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Reassign_CustomReason(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	potentialReviewers := []*domain.User{
+		{Id: "new_reviewer", IsActive: true},
+		{Id: "old_reviewer", IsActive: true},
+	}
+
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, &result.PrResult{Id: "pr1", AuthorId: "author1"}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(toPool(potentialReviewers), nil)
+	mockRepo.On("Reassign", mock.Anything, mock.MatchedBy(func(d *dto.ReassignPrDTO) bool {
+		return d.Reason == "reviewer is on sick leave"
+	})).Return(&result.ReassignResult{
+		Pr:         &result.PrResult{Id: "pr1", Status: "OPEN", AssignedReviewers: []string{"new_reviewer"}},
+		ReplacedBy: "new_reviewer",
+	}, nil)
+
+	_, err := service.Reassign(context.Background(), &request.ReassignRequest{
+		PrId:      "pr1",
+		OldUserId: "old_reviewer",
+		Reason:    "  reviewer is on sick leave ",
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Reassign_ReasonTooLong(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	resp, err := service.Reassign(context.Background(), &request.ReassignRequest{
+		PrId:      "pr1",
+		OldUserId: "old_reviewer",
+		Reason:    strings.Repeat("a", maxReasonLength+1),
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+	mockRepo.AssertNotCalled(t, "CheckReviewerAssignedWithPR", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPrService_GetHistory_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	events := []*domain.AssignmentEvent{
+		{Id: 1, PrId: "pr1", Type: domain.AssignmentEventAssign, UserId: "r1", Actor: domain.SystemActor, Reason: domain.AssignReasonPrCreated, CreatedAt: time.Now()},
+		{Id: 2, PrId: "pr1", Type: domain.AssignmentEventReassign, UserId: "r2", PreviousUserId: "r1", Actor: "manager", Reason: domain.AssignReasonManualReassign, RequestId: "req-1", CreatedAt: time.Now()},
+	}
+	mockRepo.On("GetHistory", mock.Anything, "pr1").Return(events, nil)
+
+	resp, err := service.GetHistory(context.Background(), &request.GetHistoryRequest{PrId: " pr1 "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pr1", resp.PrId)
+	assert.Len(t, resp.Events, 2)
+	assert.Equal(t, "REASSIGN", resp.Events[1].Type)
+	assert.Equal(t, "r1", resp.Events[1].PreviousUserId)
+	assert.Equal(t, "manager", resp.Events[1].Actor)
+	assert.Equal(t, "req-1", resp.Events[1].RequestId)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_GetHistory_PrNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetHistory", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+
+	resp, err := service.GetHistory(context.Background(), &request.GetHistoryRequest{PrId: "missing"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+}
+
 func TestPrService_Reassign_PrMerged(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.ReassignRequest{
 		PrId:      "pr1",
 		OldUserId: "old_reviewer",
 	}
 
-	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(false, "", repository.ErrPrMergedStatus)
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(false, nil, repository.ErrPrMergedStatus)
 
 	resp, err := service.Reassign(context.Background(), req)
 
@@ -425,14 +826,14 @@ func TestPrService_Reassign_PrMerged(t *testing.T) {
 func TestPrService_Reassign_ReviewerNotAssigned(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.ReassignRequest{
 		PrId:      "pr1",
 		OldUserId: "old_reviewer",
 	}
 
-	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(false, "author1", nil)
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(false, &result.PrResult{Id: "pr1", AuthorId: "author1"}, nil)
 
 	resp, err := service.Reassign(context.Background(), req)
 
@@ -447,14 +848,14 @@ func TestPrService_Reassign_ReviewerNotAssigned(t *testing.T) {
 func TestPrService_Reassign_PrNotFound(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.ReassignRequest{
 		PrId:      "pr1",
 		OldUserId: "old_reviewer",
 	}
 
-	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(false, "", repository.ErrNotFound)
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(false, nil, repository.ErrNotFound)
 
 	resp, err := service.Reassign(context.Background(), req)
 
@@ -469,7 +870,7 @@ func TestPrService_Reassign_PrNotFound(t *testing.T) {
 func TestPrService_Reassign_NoCandidate(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockPrRepository)
-	service := NewPrService(mockRepo, logger)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.ReassignRequest{
 		PrId:      "pr1",
@@ -482,8 +883,9 @@ func TestPrService_Reassign_NoCandidate(t *testing.T) {
 		{Id: "author1", Name: "Author", IsActive: true},
 	}
 
-	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, "author1", nil)
-	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(potentialReviewers, nil)
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, &result.PrResult{Id: "pr1", AuthorId: "author1"}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(toPool(potentialReviewers), nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return(nil, nil)
 
 	resp, err := service.Reassign(context.Background(), req)
 
@@ -495,6 +897,269 @@ func TestPrService_Reassign_NoCandidate(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// fallbackPool команда резервных ревьюеров с переданными пользователями
+func fallbackPool(teamName string, users []*domain.User) *result.PotentialReviewersResult {
+	pool := toPool(users)
+	pool.TeamId = teamName + "-id"
+	pool.TeamName = teamName
+	return pool
+}
+
+func TestPrService_Create_UsesFallbackTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	// В команде автора больше никого нет
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+	}), nil)
+	// Первая резервная команда тоже без кандидатов, берем из второй
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("empty", []*domain.User{{Id: "away", IsActive: false}}),
+		fallbackPool("backup", []*domain.User{
+			{Id: "b1", IsActive: true},
+			{Id: "b2", IsActive: true},
+			{Id: "b3", IsActive: true},
+		}),
+	}, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(reviewers []string) bool {
+		return len(reviewers) == defaultReviewerCount
+	})).Return(&result.PrResult{
+		Id:                "pr1",
+		Name:              "Test PR",
+		AuthorId:          "author1",
+		Status:            "OPEN",
+		AssignedReviewers: []string{"b1", "b2"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "Test PR",
+		AuthorId: "author1",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.CrossTeam)
+	assert.Equal(t, "backup", resp.FallbackTeam)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Create_OwnTeamNotCrossTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+	}), nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"user1"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            "OPEN",
+		AssignedReviewers: []string{"user1"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "Test PR",
+		AuthorId: "author1",
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, resp.CrossTeam)
+	assert.Empty(t, resp.FallbackTeam)
+	// Резервные команды не читаются, пока своя команда справляется
+	mockRepo.AssertNotCalled(t, "SelectFallbackPools", mock.Anything, mock.Anything)
+}
+
+func TestPrService_Reassign_UsesFallbackTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, &result.PrResult{Id: "pr1", AuthorId: "author1"}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(toPool([]*domain.User{
+		{Id: "old_reviewer", IsActive: true},
+		{Id: "author1", IsActive: true},
+	}), nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backup", []*domain.User{{Id: "b1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("Reassign", mock.Anything, mock.MatchedBy(func(d *dto.ReassignPrDTO) bool {
+		return d.PrId == "pr1" && d.OldReviewerId == "old_reviewer" && d.ReplacedBy == "b1" &&
+			d.Reason == domain.AssignReasonManualReassign
+	})).Return(&result.ReassignResult{
+		Pr: &result.PrResult{
+			Id:                "pr1",
+			AuthorId:          "author1",
+			Status:            "OPEN",
+			AssignedReviewers: []string{"b1"},
+			CreatedAt:         time.Now(),
+		},
+		ReplacedBy: "b1",
+	}, nil)
+
+	resp, err := service.Reassign(context.Background(), &request.ReassignRequest{
+		PrId:      "pr1",
+		OldUserId: "old_reviewer",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", resp.ReplacedBy)
+	assert.True(t, resp.CrossTeam)
+	assert.Equal(t, "backup", resp.FallbackTeam)
+
+	// Объяснение содержит оба рассмотренных пула, выбранный отмечен в резервном
+	d := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(1).(*dto.ReassignPrDTO)
+	assert.Equal(t, "backup", d.Explanation.TeamName)
+	assert.Equal(t, []string{"b1"}, d.Explanation.Reviewers)
+	assert.Equal(t, []domain.ExplainedPool{
+		{TeamName: "team1", Strategy: StrategyLeastLoaded, Candidates: []domain.ExplainedCandidate{
+			{UserId: "old_reviewer", Excluded: domain.ExclusionReplaced},
+			{UserId: "author1", Excluded: domain.ExclusionAuthor},
+		}},
+		{TeamName: "backup", Strategy: StrategyLeastLoaded, Candidates: []domain.ExplainedCandidate{
+			{UserId: "b1", Selected: true},
+		}},
+	}, d.Explanation.Pools)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Create_RecordsExplanation(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	selectors := testSelectors(t)
+	selectors.seedSource = func() int64 { return 42 }
+	service := NewPrService(mockRepo, selectors, nil, logger)
+
+	limit := 1
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "inactive", IsActive: false},
+		{Id: "vacation", IsActive: true},
+		{Id: "busy", IsActive: true, MaxOpenReviews: &limit},
+		{Id: "lead", IsActive: true},
+		{Id: "free", IsActive: true},
+	})
+	pool.Candidates[2].Unavailable = true
+	pool.Candidates[3].OpenReviews = 1
+	pool.Settings = &domain.TeamSettings{TeamId: "team1-id", ReviewerCount: 2, LeadUserId: "lead", RequireLead: true}
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
+		return d.Explanation != nil
+	}), []string{"lead", "free"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"lead", "free"},
+	}, nil)
+
+	_, err := service.Create(context.Background(), &request.CreateRequest{PrId: "pr1", PrName: "PR", AuthorId: "author1"})
+	assert.NoError(t, err)
+
+	d := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(1).(*dto.CreatPrDTO)
+	assert.Equal(t, &domain.AssignmentExplanation{
+		Reason:    domain.AssignReasonPrCreated,
+		TeamName:  "team1",
+		Seed:      42,
+		Reviewers: []string{"lead", "free"},
+		Pools: []domain.ExplainedPool{{
+			TeamName: "team1",
+			Strategy: StrategyLeastLoaded,
+			Candidates: []domain.ExplainedCandidate{
+				{UserId: "author1", Excluded: domain.ExclusionAuthor},
+				{UserId: "inactive", Excluded: domain.ExclusionInactive},
+				{UserId: "vacation", Excluded: domain.ExclusionUnavailable},
+				{UserId: "busy", OpenReviews: 1, Excluded: domain.ExclusionAtCapacity},
+				{UserId: "lead", Lead: true, Selected: true},
+				{UserId: "free", Selected: true},
+			},
+		}},
+	}, d.Explanation)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Create_ReplaysWithFixedSeed(t *testing.T) {
+	logger := zap.NewNop()
+	users := make([]*domain.User, 0, 20)
+	for i := 0; i < 20; i++ {
+		users = append(users, &domain.User{Id: fmt.Sprintf("user%02d", i), IsActive: true})
+	}
+
+	// Один и тот же seed и пул дают один и тот же выбор случайной стратегии
+	pick := func(seed int64) []string {
+		mockRepo := new(MockPrRepository)
+		selectors, err := NewReviewerSelectors(StrategyRandom, nil, 0)
+		assert.NoError(t, err)
+		selectors.seedSource = func() int64 { return seed }
+		service := NewPrService(mockRepo, selectors, nil, logger)
+
+		mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(toPool(users), nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(&result.PrResult{Id: "pr1"}, nil)
+
+		_, err = service.Create(context.Background(), &request.CreateRequest{PrId: "pr1", PrName: "PR", AuthorId: "author1"})
+		assert.NoError(t, err)
+		return mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(2).([]string)
+	}
+
+	first := pick(7)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, first, pick(7))
+	}
+}
+
+func TestPrService_GetAssignmentExplain_FiltersByUser(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetAssignmentExplanations", mock.Anything, "pr1").Return([]*domain.AssignmentExplanation{
+		{Id: 1, Reason: domain.AssignReasonPrCreated, TeamName: "team1", Seed: 1, Reviewers: []string{"r1"}, Pools: []domain.ExplainedPool{
+			{TeamName: "team1", Strategy: StrategyLeastLoaded, Candidates: []domain.ExplainedCandidate{{UserId: "r1", Selected: true}}},
+		}},
+		{Id: 2, Reason: domain.AssignReasonManualReassign, TeamName: "team1", Seed: 2, Reviewers: []string{"r2"}, Pools: []domain.ExplainedPool{
+			{TeamName: "team1", Strategy: StrategyLeastLoaded, Candidates: []domain.ExplainedCandidate{
+				{UserId: "r1", Excluded: domain.ExclusionReplaced},
+				{UserId: "r2", Selected: true},
+			}},
+		}},
+	}, nil)
+
+	resp, err := service.GetAssignmentExplain(context.Background(), &request.GetAssignmentExplainRequest{PrId: "pr1", UserId: "r2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pr1", resp.PrId)
+	assert.Len(t, resp.Explanations, 1)
+	assert.Equal(t, int64(2), resp.Explanations[0].ExplanationId)
+	assert.Equal(t, []string{"r2"}, resp.Explanations[0].Reviewers)
+	assert.True(t, resp.Explanations[0].Pools[0].Candidates[1].Selected)
+
+	resp, err = service.GetAssignmentExplain(context.Background(), &request.GetAssignmentExplainRequest{PrId: "pr1"})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Explanations, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_GetAssignmentExplain_PrNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetAssignmentExplanations", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+
+	resp, err := service.GetAssignmentExplain(context.Background(), &request.GetAssignmentExplainRequest{PrId: "missing"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestFindReviewers(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -541,7 +1206,7 @@ func TestFindReviewers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := findReviewers(tt.potential, tt.excludedId, tt.reviewerCount)
+			result, err := findReviewers(&leastLoadedSelector{}, toPool(tt.potential), []string{tt.excludedId}, tt.reviewerCount)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				return
@@ -555,3 +1220,178 @@ func TestFindReviewers(t *testing.T) {
 		})
 	}
 }
+
+func TestFindReviewers_ExcludesAllGivenIds(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "old", IsActive: true},
+		{Id: "author", IsActive: true},
+		{Id: "free", IsActive: true},
+	})
+
+	result, err := findReviewers(&randomSelector{}, pool, []string{"old", "author"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"free"}, result)
+}
+
+func TestFindReviewers_SkipsUnavailable(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "vacation", IsActive: true},
+		{Id: "free", IsActive: true},
+	})
+	pool.Candidates[0].Unavailable = true
+
+	result, err := findReviewers(&leastLoadedSelector{}, pool, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"free"}, result)
+
+	// Недоступен единственный кандидат - замены нет
+	pool.Candidates[1].Unavailable = true
+	_, err = findReviewers(&leastLoadedSelector{}, pool, nil, 1)
+	assert.ErrorIs(t, err, noPotentialReviewerError)
+}
+
+func TestFindReviewers_SkipsAtCapacity(t *testing.T) {
+	limit := 2
+	pool := toPool([]*domain.User{
+		{Id: "senior", IsActive: true, MaxOpenReviews: &limit},
+		{Id: "junior", IsActive: true},
+	})
+	pool.Candidates[0].OpenReviews = 2
+	pool.Candidates[1].OpenReviews = 5
+
+	result, err := findReviewers(&leastLoadedSelector{}, pool, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"junior"}, result)
+
+	// Ниже предела кандидат снова доступен
+	pool.Candidates[0].OpenReviews = 1
+	result, err = findReviewers(&leastLoadedSelector{}, pool, nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"senior"}, result)
+}
+
+func TestPlanReassignments_RespectsCapacity(t *testing.T) {
+	limit := 1
+	pool := toPool([]*domain.User{
+		{Id: "leaving", IsActive: true},
+		{Id: "capped", IsActive: true, MaxOpenReviews: &limit},
+		{Id: "free", IsActive: true},
+	})
+	pool.Candidates[2].OpenReviews = 3
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "x", ReviewerId: "leaving", Reviewers: []string{"leaving"}},
+		{PrId: "pr2", AuthorId: "x", ReviewerId: "leaving", Reviewers: []string{"leaving"}},
+	}
+
+	reassignments, noCandidate, err := testReplacer(t).planReassignments(context.Background(), pool, reviews, []string{"leaving"}, domain.AssignReasonUserDeactivated)
+
+	assert.NoError(t, err)
+	assert.Empty(t, noCandidate)
+	assert.Len(t, reassignments, 2)
+	// capped наименее загружен, но после первого назначения упирается в предел
+	assert.Equal(t, "capped", reassignments[0].ReplacedBy)
+	assert.Equal(t, "free", reassignments[1].ReplacedBy)
+}
+
+func TestSelectCreateReviewers_SkipsUnavailableLead(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "lead", IsActive: true},
+		{Id: "user1", IsActive: true},
+	})
+	pool.Candidates[1].Unavailable = true
+	settings := &domain.TeamSettings{ReviewerCount: 2, LeadUserId: "lead", RequireLead: true}
+
+	reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user1"}, reviewers)
+}
+
+func TestSelectCreateReviewers_UsesTeamReviewerCount(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+		{Id: "user3", IsActive: true},
+	})
+	settings := &domain.TeamSettings{ReviewerCount: 3}
+
+	reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user1", "user2", "user3"}, reviewers)
+
+	settings.ReviewerCount = 1
+	reviewers, err = selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, reviewers, 1)
+}
+
+func TestSelectCreateReviewers_IncludesRequiredLead(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "lead", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+	})
+	settings := &domain.TeamSettings{ReviewerCount: 2, LeadUserId: "lead", RequireLead: true}
+
+	for i := 0; i < 20; i++ {
+		reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, reviewers, 2)
+		assert.Equal(t, "lead", reviewers[0])
+	}
+
+	// Лид назначается, даже если команда не требует других ревьюеров
+	settings.ReviewerCount = 0
+	reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lead"}, reviewers)
+}
+
+func TestSelectCreateReviewers_AuthorIsLead(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "lead", IsActive: true},
+		{Id: "user1", IsActive: true},
+	})
+	settings := &domain.TeamSettings{ReviewerCount: 2, LeadUserId: "lead", RequireLead: true}
+
+	reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "lead", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user1"}, reviewers)
+}
+
+func TestPrService_Create_UsesTeamSettings(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+		{Id: "user3", IsActive: true},
+	})
+	pool.Settings = &domain.TeamSettings{TeamId: pool.TeamId, ReviewerCount: 3}
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(pool, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(reviewers []string) bool {
+		return len(reviewers) == 3
+	})).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            "OPEN",
+		AssignedReviewers: []string{"user1", "user2", "user3"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "Test PR",
+		AuthorId: "author1",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.AssignedReviewers, 3)
+	mockRepo.AssertExpectations(t)
+}
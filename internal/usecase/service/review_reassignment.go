@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"go.uber.org/zap"
+)
+
+// Интерфейс репозитория пулов кандидатов, общий для всех сервисов, которые подбирают ревьюеров
+type ReviewerPoolRepository interface {
+	SelectPotentialReviewers(ctx context.Context, userId string) (*result.PotentialReviewersResult, error)
+	SelectMemberPool(ctx context.Context, userId, teamName string) (*result.PotentialReviewersResult, error)
+	SelectFallbackPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error)
+	SelectParentPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error)
+	SelectRecentPairings(ctx context.Context, authorId string, window int) (map[string]int, error)
+}
+
+// reviewerReplacer подбирает ревьюеров за пределами команды - в родительских и резервных командах -
+// и замену ревьюеру. Один и тот же подбор замены используется в /pullRequest/reassign, при деактивации
+// пользователей и при изменении состава команд
+type reviewerReplacer struct {
+	repo      ReviewerPoolRepository
+	selectors *ReviewerSelectors
+	log       *zap.Logger
+}
+
+func newReviewerReplacer(repo ReviewerPoolRepository, selectors *ReviewerSelectors, log *zap.Logger) *reviewerReplacer {
+	return &reviewerReplacer{
+		repo:      repo,
+		selectors: selectors,
+		log:       log,
+	}
+}
+
+// replacement подобранная замена ревьюера. TeamName - команда, из которой взята замена, ParentTeam
+// и FallbackTeam заполнены, если это родительская или резервная команда
+type replacement struct {
+	ReviewerId   string
+	TeamName     string
+	ParentTeam   string
+	FallbackTeam string
+	Explanation  *domain.AssignmentExplanation
+}
+
+// replace подбирает замену ревьюеру oldReviewerId на PR по правилам /pullRequest/reassign: стратегией команды
+// в pool, затем в родительских командах pool от ближайшей и в его резервных командах. Кандидаты, недавно
+// ревьюившие автора PR, выбираются реже. Если уходит лид PR с обязательным ревью лида, замена ищется только
+// среди лидов от команды автора, а при их отсутствии возвращается noLeadReviewerError. pool может быть nil,
+// если у ревьюера нет команды. Если замены нет, возвращается noPotentialReviewerError
+func (r *reviewerReplacer) replace(ctx context.Context, explainer *assignmentExplainer, pool *result.PotentialReviewersResult, pr *result.PrResult, oldReviewerId string, excludedIds []string) (*replacement, error) {
+	// Кандидаты, недавно ревьюившие автора PR, выбираются реже
+	pairings, err := r.recentPairings(ctx, pr.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Уходит лид PR с обязательным ревью лида - замену ищем только среди лидов
+	if pr.RequiresLeadReview && pr.LeadReviewerId == oldReviewerId {
+		lead, team, err := r.replaceLeadReviewer(ctx, explainer, pr, append(slices.Clone(excludedIds), pr.AssignedReviewers...), pairings)
+		if err != nil {
+			return nil, err
+		}
+		return &replacement{
+			ReviewerId:  lead,
+			TeamName:    team,
+			Explanation: explainer.finish(team, []string{lead}),
+		}, nil
+	}
+	if pool == nil {
+		return nil, noPotentialReviewerError
+	}
+
+	applyPairings(pool, pairings)
+	reviewers, err := findReviewers(explainer.selectorFor(pool), pool, excludedIds, reviewerCountForReassign)
+	if err == nil {
+		return &replacement{
+			ReviewerId:  reviewers[0],
+			TeamName:    pool.TeamName,
+			Explanation: explainer.finish(pool.TeamName, reviewers),
+		}, nil
+	}
+	if !errors.Is(err, noPotentialReviewerError) {
+		return nil, err
+	}
+
+	// В команде замены нет - поднимаемся по иерархии команд
+	parents, err := r.findParentReviewers(ctx, explainer, pool, excludedIds, reviewerCountForReassign, nil, false, pairings)
+	if err == nil {
+		return &replacement{
+			ReviewerId:  parents.Reviewers[0],
+			TeamName:    parents.Teams[0],
+			ParentTeam:  parents.Teams[0],
+			Explanation: explainer.finish(parents.Teams[0], parents.Reviewers),
+		}, nil
+	}
+	if !errors.Is(err, noPotentialReviewerError) {
+		return nil, err
+	}
+
+	// Замены нет и в родительских командах - обращаемся к резервным командам
+	reviewers, fallbackTeam, err := r.findFallbackReviewers(ctx, explainer, pool.TeamId, excludedIds, reviewerCountForReassign, pairings)
+	if err != nil {
+		return nil, err
+	}
+	return &replacement{
+		ReviewerId:   reviewers[0],
+		TeamName:     fallbackTeam,
+		FallbackTeam: fallbackTeam,
+		Explanation:  explainer.finish(fallbackTeam, reviewers),
+	}, nil
+}
+
+// replaceLeadReviewer подбирает лида на замену уходящему лиду PR. Замена ищется от команды автора,
+// как при создании PR, и не может быть одним из исключенных
+func (r *reviewerReplacer) replaceLeadReviewer(ctx context.Context, explainer *assignmentExplainer, pr *result.PrResult, excludedIds []string, pairings map[string]int) (string, string, error) {
+	pool, err := r.authorPool(ctx, pr)
+	if err != nil {
+		r.log.Error("failed to load author team for lead replacement",
+			zap.String("pr_id", pr.Id),
+			zap.String("author_id", pr.AuthorId),
+			zap.Error(err),
+		)
+		return "", "", err
+	}
+	applyPairings(pool, pairings)
+	return r.findLeadReviewer(ctx, explainer, pool, excludedIds, pairings)
+}
+
+// planReassignments подбирает замену для каждого ревью выбывающих ревьюеров тем же подбором, что и
+// /pullRequest/reassign, начиная с pool: не выбывающий, не автор PR и еще не назначенный на этот PR.
+// Нагрузка выбранных кандидатов увеличивается, чтобы ревью распределялись равномерно. Ревью без замены
+// возвращаются отдельно. Если нельзя заменить лида PR с обязательным ревью лида, возвращается noLeadReviewerError
+func (r *reviewerReplacer) planReassignments(
+	ctx context.Context,
+	pool *result.PotentialReviewersResult,
+	reviews []*result.OpenReviewResult,
+	leavingIds []string,
+	reason string,
+) ([]*dto.ReassignPrDTO, []*result.OpenReviewResult, error) {
+	pools := newPlannedPools(r.repo)
+	planner := newReviewerReplacer(pools, r.selectors, r.log)
+
+	// Новые ревьюеры по PR, чтобы не назначить одного человека дважды
+	assigned := make(map[string][]string)
+
+	var reassignments []*dto.ReassignPrDTO
+	var noCandidate []*result.OpenReviewResult
+	for _, review := range reviews {
+		excludedIds := make([]string, 0, len(leavingIds)+len(review.Reviewers)+1)
+		excludedIds = append(excludedIds, leavingIds...)
+		excludedIds = append(excludedIds, review.AuthorId)
+		excludedIds = append(excludedIds, review.Reviewers...)
+		excludedIds = append(excludedIds, assigned[review.PrId]...)
+
+		explainer := newAssignmentExplainer(r.selectors, reason)
+		explainer.exclude(domain.ExclusionReplaced, review.ReviewerId)
+		explainer.exclude(domain.ExclusionLeaving, leavingIds...)
+		explainer.exclude(domain.ExclusionAuthor, review.AuthorId)
+		explainer.exclude(domain.ExclusionAlreadyAssigned, review.Reviewers...)
+		explainer.exclude(domain.ExclusionAlreadyAssigned, assigned[review.PrId]...)
+
+		pr := &result.PrResult{
+			Id:                 review.PrId,
+			AuthorId:           review.AuthorId,
+			AssignedReviewers:  append(slices.Clone(review.Reviewers), assigned[review.PrId]...),
+			TeamName:           review.TeamName,
+			RequiresLeadReview: review.RequiresLeadReview,
+			LeadReviewerId:     review.LeadReviewerId,
+		}
+		var startPool *result.PotentialReviewersResult
+		if pool != nil {
+			startPool = pools.withLoad(pool)
+		}
+
+		replaced, err := planner.replace(ctx, explainer, startPool, pr, review.ReviewerId, excludedIds)
+		if errors.Is(err, noPotentialReviewerError) {
+			noCandidate = append(noCandidate, review)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pools.load[replaced.ReviewerId]++
+		assigned[review.PrId] = append(assigned[review.PrId], replaced.ReviewerId)
+		reassignments = append(reassignments, &dto.ReassignPrDTO{
+			PrId:          review.PrId,
+			OldReviewerId: review.ReviewerId,
+			ReplacedBy:    replaced.ReviewerId,
+			Reason:        reason,
+			Explanation:   replaced.Explanation,
+		})
+	}
+
+	return reassignments, noCandidate, nil
+}
+
+// plannedPools пулы кандидатов для подбора набора замен за один проход. Каждый пул читается из бд
+// один раз, а кандидатам добавляется нагрузка ревью, уже распределенных планом. Вызывающая сторона
+// получает копии и может их менять
+type plannedPools struct {
+	repo      ReviewerPoolRepository
+	load      map[string]int
+	members   map[string]*result.PotentialReviewersResult
+	parents   map[string][]*result.PotentialReviewersResult
+	fallbacks map[string][]*result.PotentialReviewersResult
+	pairings  map[string]map[string]int
+}
+
+func newPlannedPools(repo ReviewerPoolRepository) *plannedPools {
+	return &plannedPools{
+		repo:      repo,
+		load:      make(map[string]int),
+		members:   make(map[string]*result.PotentialReviewersResult),
+		parents:   make(map[string][]*result.PotentialReviewersResult),
+		fallbacks: make(map[string][]*result.PotentialReviewersResult),
+		pairings:  make(map[string]map[string]int),
+	}
+}
+
+func (p *plannedPools) SelectPotentialReviewers(ctx context.Context, userId string) (*result.PotentialReviewersResult, error) {
+	return p.SelectMemberPool(ctx, userId, "")
+}
+
+func (p *plannedPools) SelectMemberPool(ctx context.Context, userId, teamName string) (*result.PotentialReviewersResult, error) {
+	key := userId + "/" + teamName
+	pool, ok := p.members[key]
+	if !ok {
+		var err error
+		if teamName == "" {
+			pool, err = p.repo.SelectPotentialReviewers(ctx, userId)
+		} else {
+			pool, err = p.repo.SelectMemberPool(ctx, userId, teamName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.members[key] = pool
+	}
+	return p.withLoad(pool), nil
+}
+
+func (p *plannedPools) SelectFallbackPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	return p.cachedPools(ctx, p.fallbacks, teamId, p.repo.SelectFallbackPools)
+}
+
+func (p *plannedPools) SelectParentPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	return p.cachedPools(ctx, p.parents, teamId, p.repo.SelectParentPools)
+}
+
+func (p *plannedPools) SelectRecentPairings(ctx context.Context, authorId string, window int) (map[string]int, error) {
+	if pairings, ok := p.pairings[authorId]; ok {
+		return pairings, nil
+	}
+	pairings, err := p.repo.SelectRecentPairings(ctx, authorId, window)
+	if err != nil {
+		return nil, err
+	}
+	p.pairings[authorId] = pairings
+	return pairings, nil
+}
+
+func (p *plannedPools) cachedPools(
+	ctx context.Context,
+	cache map[string][]*result.PotentialReviewersResult,
+	teamId string,
+	read func(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error),
+) ([]*result.PotentialReviewersResult, error) {
+	pools, ok := cache[teamId]
+	if !ok {
+		var err error
+		if pools, err = read(ctx, teamId); err != nil {
+			return nil, err
+		}
+		cache[teamId] = pools
+	}
+	loaded := make([]*result.PotentialReviewersResult, 0, len(pools))
+	for _, pool := range pools {
+		loaded = append(loaded, p.withLoad(pool))
+	}
+	return loaded, nil
+}
+
+// withLoad копия пула с нагрузкой, добавленной планом
+func (p *plannedPools) withLoad(pool *result.PotentialReviewersResult) *result.PotentialReviewersResult {
+	loaded := *pool
+	loaded.Candidates = make([]*result.ReviewerCandidate, 0, len(pool.Candidates))
+	for _, candidate := range pool.Candidates {
+		if candidate == nil || candidate.User == nil {
+			continue
+		}
+		candidateCopy := *candidate
+		candidateCopy.OpenReviews += p.load[candidate.User.Id]
+		loaded.Candidates = append(loaded.Candidates, &candidateCopy)
+	}
+	return &loaded
+}
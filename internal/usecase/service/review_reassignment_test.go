@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// testReplacer подбор замен без родительских и резервных команд
+func testReplacer(t *testing.T) *reviewerReplacer {
+	mockRepo := new(MockPrRepository)
+	mockRepo.On("SelectFallbackPools", mock.Anything, mock.Anything).Return([]*result.PotentialReviewersResult{}, nil)
+	return newReviewerReplacer(mockRepo, testSelectors(t), zap.NewNop())
+}
+
+func TestPlanReassignments_SpreadsLoad(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "leaving", IsActive: true},
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author", ReviewerId: "leaving", Reviewers: []string{"leaving"}},
+		{PrId: "pr2", AuthorId: "author", ReviewerId: "leaving", Reviewers: []string{"leaving"}},
+	}
+
+	reassignments, noCandidate, err := testReplacer(t).planReassignments(context.Background(), pool, reviews, []string{"leaving"}, domain.AssignReasonUserDeactivated)
+
+	assert.NoError(t, err)
+	assert.Empty(t, noCandidate)
+	assert.Len(t, reassignments, 2)
+	assert.NotEqual(t, reassignments[0].ReplacedBy, reassignments[1].ReplacedBy)
+
+	// Нагрузка в исходном пуле не меняется
+	for _, candidate := range pool.Candidates {
+		assert.Zero(t, candidate.OpenReviews)
+	}
+}
+
+func TestPlanReassignments_SamePrTwoLeavingReviewers(t *testing.T) {
+	pool := toPool([]*domain.User{
+		{Id: "leaving1", IsActive: true},
+		{Id: "leaving2", IsActive: true},
+		{Id: "user1", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author", ReviewerId: "leaving1", Reviewers: []string{"leaving1", "leaving2"}},
+		{PrId: "pr1", AuthorId: "author", ReviewerId: "leaving2", Reviewers: []string{"leaving1", "leaving2"}},
+	}
+
+	reassignments, noCandidate, err := testReplacer(t).planReassignments(context.Background(), pool, reviews, []string{"leaving1", "leaving2"}, domain.AssignReasonUserDeactivated)
+
+	assert.NoError(t, err)
+	assert.Len(t, reassignments, 1)
+	assert.Equal(t, "user1", reassignments[0].ReplacedBy)
+	assert.Len(t, noCandidate, 1)
+	assert.Equal(t, "leaving2", noCandidate[0].ReviewerId)
+}
+
+func TestPlanReassignments_ClimbsToParentThenFallback(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backend", []*domain.User{{Id: "p1", IsActive: true}}),
+	}, nil).Once()
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backup", []*domain.User{{Id: "b1", IsActive: true}}),
+	}, nil).Once()
+	replacer := newReviewerReplacer(mockRepo, testSelectors(t), zap.NewNop())
+
+	pool := subteamPool([]*domain.User{{Id: "leaving", IsActive: true}})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author", ReviewerId: "leaving", Reviewers: []string{"leaving"}},
+		{PrId: "pr2", AuthorId: "author", ReviewerId: "leaving", Reviewers: []string{"leaving", "p1"}},
+	}
+
+	reassignments, noCandidate, err := replacer.planReassignments(context.Background(), pool, reviews, []string{"leaving"}, domain.AssignReasonTeamDeleted)
+
+	assert.NoError(t, err)
+	assert.Empty(t, noCandidate)
+	assert.Len(t, reassignments, 2)
+	assert.Equal(t, "p1", reassignments[0].ReplacedBy)
+	assert.Equal(t, "backend", reassignments[0].Explanation.TeamName)
+	// p1 уже ревьюер pr2, замена берется из резервной команды; пулы читаются из бд один раз
+	assert.Equal(t, "b1", reassignments[1].ReplacedBy)
+	mockRepo.AssertExpectations(t)
+}
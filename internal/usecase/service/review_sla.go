@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	getOverdueError      = errors.New("get overdue reviews error")
+	escalateOverdueError = errors.New("escalate overdue reviews error")
+)
+
+// Срок ревью не больше 720 рабочих часов - 80 рабочих дней по 9 часов
+const maxReviewSlaHours = 720
+
+var slaPolicies = []string{
+	domain.SlaPolicyNotify,
+	domain.SlaPolicyAddReviewer,
+	domain.SlaPolicyReassign,
+}
+
+// overdueReview ревью вместе со сроком, к которому ревьюер должен был оставить вердикт
+type overdueReview struct {
+	*result.SlaReviewResult
+	DueAt time.Time
+}
+
+// SetBusinessHours задает рабочее время, в котором отсчитываются сроки ревью
+func (s *PrService) SetBusinessHours(hours BusinessHours) {
+	s.hours = hours
+}
+
+func (s *PrService) GetOverdue(ctx context.Context, req *request.GetOverdueRequest) (*response.OverdueResponse, error) {
+	teamName := strings.TrimSpace(req.TeamName)
+	s.log.Info("get overdue reviews request accepted", zap.String("team_name", teamName))
+
+	reviews, err := s.overdueReviews(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", getOverdueError, err)
+	}
+
+	// Преобразуем просроченные ревью в ответ
+	resp := &response.OverdueResponse{
+		Reviews: make([]response.OverdueReviewResponse, 0, len(reviews)),
+	}
+	for _, review := range reviews {
+		resp.Reviews = append(resp.Reviews, response.OverdueReviewResponse{
+			PrId:        review.PrId,
+			PrName:      review.PrName,
+			AuthorId:    review.AuthorId,
+			ReviewerId:  review.ReviewerId,
+			TeamName:    review.TeamName,
+			SlaHours:    review.SlaHours,
+			SlaPolicy:   review.SlaPolicy,
+			AssignedAt:  formatTime(review.AssignedAt),
+			DueAt:       formatTime(review.DueAt),
+			EscalatedAt: formatTimePtr(review.EscalatedAt),
+		})
+	}
+	return resp, nil
+}
+
+// overdueReviews возвращает ревью, срок которых истек к текущему моменту, начиная с самых давних
+func (s *PrService) overdueReviews(ctx context.Context, teamName string) ([]*overdueReview, error) {
+	reviews, err := s.repo.SelectSlaReviews(ctx, teamName)
+	if err != nil {
+		s.log.Error("failed to load SLA reviews", zap.String("team_name", teamName), zap.Error(err))
+		return nil, err
+	}
+
+	now := s.now()
+	var overdue []*overdueReview
+	for _, review := range reviews {
+		dueAt := s.hours.Deadline(review.AssignedAt, review.SlaHours)
+		if now.After(dueAt) {
+			overdue = append(overdue, &overdueReview{SlaReviewResult: review, DueAt: dueAt})
+		}
+	}
+	slices.SortStableFunc(overdue, func(a, b *overdueReview) int {
+		return a.DueAt.Compare(b.DueAt)
+	})
+	return overdue, nil
+}
+
+// EscalateOverdue задача планировщика: эскалирует просроченные ревью, которые еще не эскалировались,
+// по политике команды автора. Ошибка одного ревью не мешает обработать остальные
+func (s *PrService) EscalateOverdue(ctx context.Context) error {
+	reviews, err := s.overdueReviews(ctx, "")
+	if err != nil {
+		return fmt.Errorf("%w: %w", escalateOverdueError, err)
+	}
+
+	var errs []error
+	escalated := 0
+	for _, review := range reviews {
+		if review.EscalatedAt != nil {
+			continue
+		}
+		if err := s.escalate(ctx, review); err != nil {
+			s.log.Error("failed to escalate overdue review",
+				zap.String("pr_id", review.PrId),
+				zap.String("reviewer_id", review.ReviewerId),
+				zap.Error(err),
+			)
+			errs = append(errs, err)
+			continue
+		}
+		escalated++
+	}
+
+	if escalated > 0 {
+		s.log.Info("overdue reviews escalated", zap.Int("count", escalated))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", escalateOverdueError, errors.Join(errs...))
+	}
+	return nil
+}
+
+// escalate публикует просрочку ревью и применяет политику команды. Если дополнительного ревьюера
+// или замену подобрать не из кого, остается только уведомление
+func (s *PrService) escalate(ctx context.Context, review *overdueReview) error {
+	overdue := &domain.ReviewOverduePayload{
+		PrId:       review.PrId,
+		AuthorId:   review.AuthorId,
+		UserId:     review.ReviewerId,
+		TeamName:   review.TeamName,
+		AssignedAt: review.AssignedAt,
+		DueAt:      review.DueAt,
+		Policy:     review.SlaPolicy,
+		Actor:      domain.AuditInfoFrom(ctx).Actor,
+	}
+	dto := &dto.EscalateReviewDTO{
+		PrId:       review.PrId,
+		ReviewerId: review.ReviewerId,
+		Overdue:    overdue,
+	}
+
+	if review.SlaPolicy == domain.SlaPolicyAddReviewer {
+		added, explanation, err := s.pickAdditionalReviewer(ctx, review)
+		switch {
+		case err == nil:
+			dto.AddedReviewer = added
+			dto.Explanation = explanation
+			overdue.AddedUserId = added
+		case errors.Is(err, noPotentialReviewerError):
+			s.log.Warn("no additional reviewer for overdue review",
+				zap.String("pr_id", review.PrId),
+				zap.String("reviewer_id", review.ReviewerId),
+			)
+		default:
+			return err
+		}
+	}
+
+	pr, err := s.repo.EscalateReview(ctx, dto)
+	if err != nil {
+		// Ревью уже эскалировано или перестало быть открытым
+		if errors.Is(err, repository.ErrReviewerNotAssigned) {
+			return nil
+		}
+		return err
+	}
+
+	s.log.Info("overdue review escalated",
+		zap.String("pr_id", review.PrId),
+		zap.String("reviewer_id", review.ReviewerId),
+		zap.String("policy", review.SlaPolicy),
+		zap.String("added_reviewer", dto.AddedReviewer),
+	)
+	s.publishOverdue(pr, overdue)
+	if dto.AddedReviewer != "" {
+		s.publishAssigned(ctx, pr, []string{dto.AddedReviewer}, domain.AssignReasonReviewOverdue)
+	}
+
+	if review.SlaPolicy == domain.SlaPolicyReassign {
+		_, err := s.Reassign(ctx, &request.ReassignRequest{
+			PrId:      review.PrId,
+			OldUserId: review.ReviewerId,
+			Reason:    domain.AssignReasonReviewOverdue,
+		})
+		if errors.Is(err, ErrNoCandidate) {
+			s.log.Warn("no replacement for overdue reviewer",
+				zap.String("pr_id", review.PrId),
+				zap.String("reviewer_id", review.ReviewerId),
+			)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// pickAdditionalReviewer подбирает еще одного ревьюера из команды просрочившего ревьюера,
+// а если в ней кандидатов нет - из родительских, затем из резервных команд. Уже назначенные ревьюеры
+// и автор исключаются
+func (s *PrService) pickAdditionalReviewer(ctx context.Context, review *overdueReview) (string, *domain.AssignmentExplanation, error) {
+	potentialReviewers, err := s.repo.SelectPotentialReviewers(ctx, review.ReviewerId)
+	if err != nil {
+		s.log.Error("failed to load team members for overdue review",
+			zap.String("reviewer_id", review.ReviewerId),
+			zap.Error(err),
+		)
+		return "", nil, err
+	}
+
+	// Кандидаты, недавно ревьюившие автора PR, выбираются реже
+	pairings, err := s.replacer.recentPairings(ctx, review.AuthorId)
+	if err != nil {
+		return "", nil, err
+	}
+	applyPairings(potentialReviewers, pairings)
+
+	explainer := newAssignmentExplainer(s.selectors, domain.AssignReasonReviewOverdue)
+	explainer.exclude(domain.ExclusionAuthor, review.AuthorId)
+	explainer.exclude(domain.ExclusionAlreadyAssigned, review.Reviewers...)
+	selector := explainer.selectorFor(potentialReviewers)
+	excludedIds := append([]string{review.AuthorId}, review.Reviewers...)
+	reviewers, err := findReviewers(selector, potentialReviewers, excludedIds, 1)
+
+	// В команде ревьюера кандидатов нет - поднимаемся по иерархии команд
+	teamName := potentialReviewers.TeamName
+	if errors.Is(err, noPotentialReviewerError) {
+		var parents *parentPick
+		parents, err = s.replacer.findParentReviewers(ctx, explainer, potentialReviewers, excludedIds, 1, nil, false, pairings)
+		if err == nil {
+			reviewers, teamName = parents.Reviewers, parents.Teams[0]
+		}
+	}
+
+	// Кандидатов нет и в родительских командах - обращаемся к резервным командам
+	if errors.Is(err, noPotentialReviewerError) {
+		var fallbackTeam string
+		reviewers, fallbackTeam, err = s.replacer.findFallbackReviewers(ctx, explainer, potentialReviewers.TeamId, excludedIds, 1, pairings)
+		teamName = fallbackTeam
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	return reviewers[0], explainer.finish(teamName, reviewers), nil
+}
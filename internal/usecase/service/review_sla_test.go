@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestBusinessHours_Deadline(t *testing.T) {
+	hours := DefaultBusinessHours()
+	// 6 января 2025 - понедельник
+	monday := func(hour int) time.Time { return time.Date(2025, 1, 6, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name string
+		from time.Time
+		sla  int
+		want time.Time
+	}{
+		{"within one day", monday(10), 3, monday(13)},
+		{"spans days", monday(9), 24, time.Date(2025, 1, 8, 15, 0, 0, 0, time.UTC)},
+		{"before workday", monday(7), 1, monday(10)},
+		{"after workday", monday(20), 1, time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC)},
+		{"skips weekend", time.Date(2025, 1, 10, 17, 0, 0, 0, time.UTC), 2, time.Date(2025, 1, 13, 10, 0, 0, 0, time.UTC)},
+		{"assigned on weekend", time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC), 1, time.Date(2025, 1, 13, 10, 0, 0, 0, time.UTC)},
+		{"ends at day end", monday(9), 9, monday(18)},
+		{"no sla", monday(20), 0, monday(20)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(hours.Deadline(tt.from, tt.sla)), hours.Deadline(tt.from, tt.sla))
+		})
+	}
+}
+
+func TestBusinessHours_DeadlineInTimezone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+	hours := BusinessHours{Location: moscow, Start: 9, End: 18}
+
+	// 17:00 UTC понедельника - уже 20:00 по Москве, срок отсчитывается с 9:00 вторника по Москве
+	deadline := hours.Deadline(time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC), 2)
+	assert.True(t, time.Date(2025, 1, 7, 8, 0, 0, 0, time.UTC).Equal(deadline), deadline)
+}
+
+// slaService сервис PR, для которого сейчас среда 8 января 2025 12:00 UTC
+func slaService(t *testing.T, mockRepo *MockPrRepository) *PrService {
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+	service.now = func() time.Time { return time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC) }
+	return service
+}
+
+// slaReview ревью PR pr1 автора author с ревьюером reviewer1 и сроком 8 рабочих часов
+func slaReview(assignedAt time.Time, policy string) *result.SlaReviewResult {
+	return &result.SlaReviewResult{
+		PrId:       "pr1",
+		PrName:     "Fix",
+		AuthorId:   "author",
+		ReviewerId: "reviewer1",
+		TeamName:   "team1",
+		AssignedAt: assignedAt,
+		SlaHours:   8,
+		SlaPolicy:  policy,
+		Reviewers:  []string{"reviewer1"},
+	}
+}
+
+func TestPrService_GetOverdue_ReturnsOnlyOverdue(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := slaService(t, mockRepo)
+
+	// Назначено в понедельник в 9:00 - срок истек в понедельник в 17:00
+	overdue := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyNotify)
+	// Назначено в среду в 10:00 - срок в среду в 18:00
+	fresh := slaReview(time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC), domain.SlaPolicyNotify)
+	fresh.PrId = "pr2"
+	mockRepo.On("SelectSlaReviews", mock.Anything, "team1").Return([]*result.SlaReviewResult{fresh, overdue}, nil)
+
+	resp, err := service.GetOverdue(context.Background(), &request.GetOverdueRequest{TeamName: " team1 "})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Reviews, 1)
+	assert.Equal(t, "pr1", resp.Reviews[0].PrId)
+	assert.Equal(t, "2025-01-06T17:00:00Z", resp.Reviews[0].DueAt)
+	assert.Nil(t, resp.Reviews[0].EscalatedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_EscalateOverdue_Notify(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := slaService(t, mockRepo)
+
+	review := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyNotify)
+	escalatedAt := time.Date(2025, 1, 7, 9, 0, 0, 0, time.UTC)
+	escalated := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyNotify)
+	escalated.ReviewerId = "reviewer2"
+	escalated.EscalatedAt = &escalatedAt
+
+	mockRepo.On("SelectSlaReviews", mock.Anything, "").Return([]*result.SlaReviewResult{review, escalated}, nil)
+	mockRepo.On("EscalateReview", mock.Anything, mock.MatchedBy(func(d *dto.EscalateReviewDTO) bool {
+		return d.PrId == "pr1" && d.ReviewerId == "reviewer1" && d.AddedReviewer == "" &&
+			d.Overdue.Policy == domain.SlaPolicyNotify && d.Overdue.Actor == domain.SystemActor
+	})).Return(&result.PrResult{Id: "pr1", AuthorId: "author", AssignedReviewers: []string{"reviewer1"}}, nil).Once()
+
+	err := service.EscalateOverdue(context.Background())
+
+	assert.NoError(t, err)
+	// Уже эскалированное ревью повторно не эскалируется
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_EscalateOverdue_AddsReviewer(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := slaService(t, mockRepo)
+
+	review := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyAddReviewer)
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "reviewer1", IsActive: true},
+		{Id: "reviewer2", IsActive: true},
+	})
+
+	mockRepo.On("SelectSlaReviews", mock.Anything, "").Return([]*result.SlaReviewResult{review}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "reviewer1").Return(pool, nil)
+	mockRepo.On("EscalateReview", mock.Anything, mock.MatchedBy(func(d *dto.EscalateReviewDTO) bool {
+		return d.AddedReviewer == "reviewer2" && d.Overdue.AddedUserId == "reviewer2" &&
+			d.Explanation != nil && d.Explanation.Reason == domain.AssignReasonReviewOverdue
+	})).Return(&result.PrResult{Id: "pr1", AuthorId: "author", AssignedReviewers: []string{"reviewer1", "reviewer2"}}, nil)
+
+	err := service.EscalateOverdue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_EscalateOverdue_AddReviewerWithoutCandidatesNotifies(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := slaService(t, mockRepo)
+
+	review := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyAddReviewer)
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "reviewer1", IsActive: true},
+	})
+
+	mockRepo.On("SelectSlaReviews", mock.Anything, "").Return([]*result.SlaReviewResult{review}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "reviewer1").Return(pool, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{}, nil)
+	mockRepo.On("EscalateReview", mock.Anything, mock.MatchedBy(func(d *dto.EscalateReviewDTO) bool {
+		return d.AddedReviewer == "" && d.Overdue.Policy == domain.SlaPolicyAddReviewer
+	})).Return(&result.PrResult{Id: "pr1", AuthorId: "author", AssignedReviewers: []string{"reviewer1"}}, nil)
+
+	err := service.EscalateOverdue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_EscalateOverdue_Reassigns(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := slaService(t, mockRepo)
+
+	review := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyReassign)
+	pool := toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "reviewer1", IsActive: true},
+		{Id: "reviewer2", IsActive: true},
+	})
+	pr := &result.PrResult{Id: "pr1", AuthorId: "author", Status: domain.PrStatusOpen, AssignedReviewers: []string{"reviewer1"}}
+
+	mockRepo.On("SelectSlaReviews", mock.Anything, "").Return([]*result.SlaReviewResult{review}, nil)
+	mockRepo.On("EscalateReview", mock.Anything, mock.MatchedBy(func(d *dto.EscalateReviewDTO) bool {
+		return d.AddedReviewer == "" && d.Overdue.Policy == domain.SlaPolicyReassign
+	})).Return(pr, nil)
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "reviewer1").Return(true, &result.PrResult{Id: "pr1", AuthorId: "author"}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "reviewer1").Return(pool, nil)
+	mockRepo.On("Reassign", mock.Anything, mock.MatchedBy(func(d *dto.ReassignPrDTO) bool {
+		return d.OldReviewerId == "reviewer1" && d.ReplacedBy == "reviewer2" && d.Reason == domain.AssignReasonReviewOverdue
+	})).Return(&result.ReassignResult{Pr: pr, ReplacedBy: "reviewer2"}, nil)
+
+	err := service.EscalateOverdue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_EscalateOverdue_SkipsAlreadyEscalated(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := slaService(t, mockRepo)
+
+	// Другой экземпляр сервиса эскалировал ревью между чтением и записью
+	review := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyReassign)
+	mockRepo.On("SelectSlaReviews", mock.Anything, "").Return([]*result.SlaReviewResult{review}, nil)
+	mockRepo.On("EscalateReview", mock.Anything, mock.Anything).Return(nil, repository.ErrReviewerNotAssigned)
+
+	err := service.EscalateOverdue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "CheckReviewerAssignedWithPR", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_EscalateOverdue_ContinuesAfterError(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := slaService(t, mockRepo)
+
+	first := slaReview(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), domain.SlaPolicyNotify)
+	second := slaReview(time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), domain.SlaPolicyNotify)
+	second.PrId = "pr2"
+	dbErr := errors.New("db error")
+
+	mockRepo.On("SelectSlaReviews", mock.Anything, "").Return([]*result.SlaReviewResult{first, second}, nil)
+	mockRepo.On("EscalateReview", mock.Anything, mock.MatchedBy(func(d *dto.EscalateReviewDTO) bool {
+		return d.PrId == "pr1"
+	})).Return(nil, dbErr)
+	mockRepo.On("EscalateReview", mock.Anything, mock.MatchedBy(func(d *dto.EscalateReviewDTO) bool {
+		return d.PrId == "pr2"
+	})).Return(&result.PrResult{Id: "pr2", AuthorId: "author"}, nil)
+
+	err := service.EscalateOverdue(context.Background())
+
+	assert.ErrorIs(t, err, dbErr)
+	mockRepo.AssertExpectations(t)
+}
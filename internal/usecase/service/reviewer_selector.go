@@ -0,0 +1,296 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+)
+
+var (
+	unknownStrategyError = errors.New("unknown reviewer selection strategy")
+)
+
+// Названия встроенных стратегий выбора ревьюеров
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyWeighted    = "weighted"
+)
+
+var builtinStrategies = []string{
+	StrategyRandom,
+	StrategyRoundRobin,
+	StrategyLeastLoaded,
+	StrategyWeighted,
+}
+
+// ReviewerSelector выбирает до count ревьюеров из уже отфильтрованных кандидатов команды
+type ReviewerSelector interface {
+	Select(teamName string, candidates []*result.ReviewerCandidate, count int) []string
+}
+
+// randomizedSelector стратегия, которая может взять случайность из переданного генератора.
+// Тот же генератор и те же кандидаты в том же порядке дают тот же выбор
+type randomizedSelector interface {
+	selectWithRand(rnd *rand.Rand, candidates []*result.ReviewerCandidate, count int) []string
+}
+
+// seededSelector направляет выбор стратегии через генератор одного подбора
+type seededSelector struct {
+	selector randomizedSelector
+	rnd      *rand.Rand
+}
+
+func (s *seededSelector) Select(_ string, candidates []*result.ReviewerCandidate, count int) []string {
+	return s.selector.selectWithRand(s.rnd, candidates, count)
+}
+
+// ReviewerSelectors хранит экземпляры стратегий, стратегию по умолчанию и переопределения для команд.
+// pairingWindow - сколько последних PR автора учитывается, чтобы реже назначать одних и тех же
+// ревьюеров, 0 отключает учет. seedSource выдает seed генератора для каждого подбора, тесты пакета
+// подменяют его фиксированным до передачи селекторов сервису
+type ReviewerSelectors struct {
+	byStrategy      map[string]ReviewerSelector
+	defaultStrategy string
+	teamStrategies  map[string]string
+	pairingWindow   int
+	seedSource      func() int64
+}
+
+func NewReviewerSelectors(defaultStrategy string, teamStrategies map[string]string, pairingWindow int) (*ReviewerSelectors, error) {
+	// Экземпляр каждой стратегии общий, чтобы round-robin хранил единое состояние
+	byStrategy := make(map[string]ReviewerSelector, len(builtinStrategies))
+	for _, strategy := range builtinStrategies {
+		selector, err := newReviewerSelector(strategy)
+		if err != nil {
+			return nil, err
+		}
+		byStrategy[strategy] = selector
+	}
+
+	if _, ok := byStrategy[defaultStrategy]; !ok {
+		return nil, fmt.Errorf("%w: %q", unknownStrategyError, defaultStrategy)
+	}
+	for teamName, strategy := range teamStrategies {
+		if _, ok := byStrategy[strategy]; !ok {
+			return nil, fmt.Errorf("team %s: %w: %q", teamName, unknownStrategyError, strategy)
+		}
+	}
+
+	if pairingWindow < 0 {
+		return nil, fmt.Errorf("pairing window must not be negative: %d", pairingWindow)
+	}
+
+	return &ReviewerSelectors{
+		byStrategy:      byStrategy,
+		defaultStrategy: defaultStrategy,
+		teamStrategies:  teamStrategies,
+		pairingWindow:   pairingWindow,
+		seedSource:      timeSeed,
+	}, nil
+}
+
+// NextSeed возвращает seed генератора для очередного подбора
+func (s *ReviewerSelectors) NextSeed() int64 {
+	return s.seedSource()
+}
+
+// StrategyFor возвращает название стратегии команды. Стратегия из настроек команды приоритетнее
+// переопределения из конфигурации, которое приоритетнее стратегии по умолчанию
+func (s *ReviewerSelectors) StrategyFor(teamName, settingsStrategy string) string {
+	if _, ok := s.byStrategy[settingsStrategy]; ok {
+		return settingsStrategy
+	}
+	if strategy := s.teamStrategies[teamName]; strategy != "" {
+		if _, ok := s.byStrategy[strategy]; ok {
+			return strategy
+		}
+	}
+	return s.defaultStrategy
+}
+
+// ForTeam возвращает стратегию команды, выбранную по правилам StrategyFor
+func (s *ReviewerSelectors) ForTeam(teamName, settingsStrategy string) ReviewerSelector {
+	return s.byStrategy[s.StrategyFor(teamName, settingsStrategy)]
+}
+
+// WithRand возвращает стратегию, которая берет случайность из rnd. Round-robin случайность
+// не использует и возвращается как есть: его выбор зависит от положения очереди
+func (s *ReviewerSelectors) WithRand(strategy string, rnd *rand.Rand) ReviewerSelector {
+	selector := s.byStrategy[strategy]
+	if randomized, ok := selector.(randomizedSelector); ok {
+		return &seededSelector{selector: randomized, rnd: rnd}
+	}
+	return selector
+}
+
+// PairingWindow возвращает число последних PR автора, по которым считаются повторные пары
+func (s *ReviewerSelectors) PairingWindow() int {
+	return s.pairingWindow
+}
+
+// isKnownStrategy проверяет, что стратегия входит во встроенные
+func isKnownStrategy(strategy string) bool {
+	return slices.Contains(builtinStrategies, strategy)
+}
+
+func newReviewerSelector(strategy string) (ReviewerSelector, error) {
+	switch strategy {
+	case StrategyRandom:
+		return &randomSelector{}, nil
+	case StrategyRoundRobin:
+		return &roundRobinSelector{cursors: make(map[string]int)}, nil
+	case StrategyLeastLoaded:
+		return &leastLoadedSelector{}, nil
+	case StrategyWeighted:
+		return &weightedSelector{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", unknownStrategyError, strategy)
+	}
+}
+
+// randomSelector выбирает ревьюеров случайно. Кандидаты, недавно ревьюившие автора, выпадают реже,
+// без таких кандидатов выбор равновероятный
+type randomSelector struct{}
+
+func (s *randomSelector) Select(_ string, candidates []*result.ReviewerCandidate, count int) []string {
+	return s.selectWithRand(newRand(), candidates, count)
+}
+
+func (s *randomSelector) selectWithRand(rnd *rand.Rand, candidates []*result.ReviewerCandidate, count int) []string {
+	return takeIds(orderByWeight(rnd, candidates, pairingWeight), count)
+}
+
+// roundRobinSelector выбирает ревьюеров команды по очереди. Недавние пары не учитываются:
+// очередь и так распределяет ревью поровну
+type roundRobinSelector struct {
+	mu      sync.Mutex
+	cursors map[string]int
+}
+
+func (s *roundRobinSelector) Select(teamName string, candidates []*result.ReviewerCandidate, count int) []string {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Стабильный порядок, чтобы очередь не зависела от порядка строк из бд
+	ordered := append([]*result.ReviewerCandidate(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].User.Id < ordered[j].User.Id
+	})
+
+	if count > len(ordered) {
+		count = len(ordered)
+	}
+
+	s.mu.Lock()
+	start := s.cursors[teamName] % len(ordered)
+	s.cursors[teamName] = start + count
+	s.mu.Unlock()
+
+	ids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		ids = append(ids, ordered[(start+i)%len(ordered)].User.Id)
+	}
+	return ids
+}
+
+// leastLoadedSelector выбирает ревьюеров с наименьшим числом открытых ревью
+type leastLoadedSelector struct{}
+
+func (s *leastLoadedSelector) Select(_ string, candidates []*result.ReviewerCandidate, count int) []string {
+	return s.selectWithRand(newRand(), candidates, count)
+}
+
+func (s *leastLoadedSelector) selectWithRand(rnd *rand.Rand, candidates []*result.ReviewerCandidate, count int) []string {
+	ordered := append([]*result.ReviewerCandidate(nil), candidates...)
+	orderByLoad(rnd, ordered)
+	return takeIds(ordered, count)
+}
+
+// weightedSelector выбирает случайно, но с весом обратно пропорциональным нагрузке
+// и числу недавних ревью PR автора
+type weightedSelector struct{}
+
+func (s *weightedSelector) Select(_ string, candidates []*result.ReviewerCandidate, count int) []string {
+	return s.selectWithRand(newRand(), candidates, count)
+}
+
+func (s *weightedSelector) selectWithRand(rnd *rand.Rand, candidates []*result.ReviewerCandidate, count int) []string {
+	return takeIds(orderByWeight(rnd, candidates, func(c *result.ReviewerCandidate) float64 {
+		return pairingWeight(c) / float64(1+c.OpenReviews)
+	}), count)
+}
+
+// orderByWeight возвращает кандидатов в случайном порядке, где кандидат с большим весом
+// чаще оказывается ближе к началу
+func orderByWeight(rnd *rand.Rand, candidates []*result.ReviewerCandidate, weightOf func(*result.ReviewerCandidate) float64) []*result.ReviewerCandidate {
+	// Взвешенная выборка без возвращения (Efraimidis-Spirakis): ключ u^(1/w), берем наибольшие
+	type keyed struct {
+		candidate *result.ReviewerCandidate
+		key       float64
+	}
+	keys := make([]keyed, 0, len(candidates))
+	for _, c := range candidates {
+		keys = append(keys, keyed{
+			candidate: c,
+			key:       math.Pow(rnd.Float64(), 1/weightOf(c)),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].key > keys[j].key
+	})
+
+	ordered := make([]*result.ReviewerCandidate, 0, len(keys))
+	for _, k := range keys {
+		ordered = append(ordered, k.candidate)
+	}
+	return ordered
+}
+
+// pairingWeight уменьшает вес кандидата за каждый из последних PR автора, который он ревьюил
+func pairingWeight(c *result.ReviewerCandidate) float64 {
+	return 1 / float64(1+c.RecentPairings)
+}
+
+// orderByLoad сортирует кандидатов по возрастанию числа открытых ревью, при равной нагрузке
+// первыми идут реже ревьюившие автора. Предварительное перемешивание делает выбор среди равных случайным
+func orderByLoad(rnd *rand.Rand, reviewers []*result.ReviewerCandidate) {
+	rnd.Shuffle(len(reviewers), func(i, j int) {
+		reviewers[i], reviewers[j] = reviewers[j], reviewers[i]
+	})
+
+	sort.SliceStable(reviewers, func(i, j int) bool {
+		if reviewers[i].OpenReviews != reviewers[j].OpenReviews {
+			return reviewers[i].OpenReviews < reviewers[j].OpenReviews
+		}
+		return reviewers[i].RecentPairings < reviewers[j].RecentPairings
+	})
+}
+
+func takeIds(candidates []*result.ReviewerCandidate, count int) []string {
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	ids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		ids = append(ids, candidates[i].User.Id)
+	}
+	return ids
+}
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewSource(timeSeed()))
+}
+
+func timeSeed() int64 {
+	return time.Now().UnixNano()
+}
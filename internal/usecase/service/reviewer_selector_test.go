@@ -0,0 +1,173 @@
+package service
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func candidatesWithLoad(loads map[string]int) []*result.ReviewerCandidate {
+	candidates := make([]*result.ReviewerCandidate, 0, len(loads))
+	for id, load := range loads {
+		candidates = append(candidates, &result.ReviewerCandidate{
+			User:        &domain.User{Id: id, IsActive: true},
+			OpenReviews: load,
+		})
+	}
+	return candidates
+}
+
+func TestNewReviewerSelectors_UnknownStrategy(t *testing.T) {
+	_, err := NewReviewerSelectors("unknown", nil, 0)
+	assert.ErrorIs(t, err, unknownStrategyError)
+
+	_, err = NewReviewerSelectors(StrategyRandom, map[string]string{"backend": "unknown"}, 0)
+	assert.ErrorIs(t, err, unknownStrategyError)
+}
+
+func TestNewReviewerSelectors_PairingWindow(t *testing.T) {
+	selectors, err := NewReviewerSelectors(StrategyRandom, nil, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, selectors.PairingWindow())
+
+	_, err = NewReviewerSelectors(StrategyRandom, nil, -1)
+	assert.Error(t, err)
+}
+
+func TestReviewerSelectors_ForTeam(t *testing.T) {
+	selectors, err := NewReviewerSelectors(StrategyLeastLoaded, map[string]string{
+		"backend": StrategyRoundRobin,
+		"docs":    StrategyRandom,
+	}, 0)
+	assert.NoError(t, err)
+
+	assert.IsType(t, &roundRobinSelector{}, selectors.ForTeam("backend", ""))
+	assert.IsType(t, &randomSelector{}, selectors.ForTeam("docs", ""))
+	assert.IsType(t, &leastLoadedSelector{}, selectors.ForTeam("frontend", ""))
+
+	// Стратегия из настроек команды приоритетнее конфигурации
+	assert.IsType(t, &weightedSelector{}, selectors.ForTeam("backend", StrategyWeighted))
+
+	// Экземпляры стратегий общие для всех команд
+	assert.Same(t, selectors.ForTeam("backend", ""), selectors.ForTeam("frontend", StrategyRoundRobin))
+}
+
+func TestLeastLoadedSelector_PrefersLeastLoaded(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"busy": 5, "free": 0, "medium": 2})
+	selector := &leastLoadedSelector{}
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, []string{"free", "medium"}, selector.Select("team", candidates, 2))
+	}
+}
+
+func TestLeastLoadedSelector_TiesBrokenRandomly(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"user1": 1, "user2": 1, "user3": 1})
+	selector := &leastLoadedSelector{}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 200; i++ {
+		seen[selector.Select("team", candidates, 1)[0]] = true
+	}
+	assert.Len(t, seen, 3)
+}
+
+func TestLeastLoadedSelector_TiesPreferFewerRecentPairings(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"frequent": 1, "fresh": 1, "busy": 3})
+	for _, c := range candidates {
+		if c.User.Id == "frequent" {
+			c.RecentPairings = 4
+		}
+	}
+	selector := &leastLoadedSelector{}
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, []string{"fresh", "frequent"}, selector.Select("team", candidates, 2))
+	}
+}
+
+func TestRandomSelector_DownWeightsRecentPairings(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"frequent": 0, "fresh": 0})
+	for _, c := range candidates {
+		if c.User.Id == "frequent" {
+			c.RecentPairings = 5
+		}
+	}
+	selector := &randomSelector{}
+
+	picks := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		picks[selector.Select("team", candidates, 1)[0]]++
+	}
+	assert.Greater(t, picks["fresh"], picks["frequent"])
+	assert.Greater(t, picks["frequent"], 0)
+}
+
+func TestRandomSelector_ReturnsRequestedCount(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"user1": 0, "user2": 0, "user3": 0})
+	selector := &randomSelector{}
+
+	assert.Len(t, selector.Select("team", candidates, 2), 2)
+	assert.Len(t, selector.Select("team", candidates, 5), 3)
+}
+
+func TestRoundRobinSelector_RotatesPerTeam(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"a": 0, "b": 0, "c": 0})
+	selector := &roundRobinSelector{cursors: make(map[string]int)}
+
+	assert.Equal(t, []string{"a", "b"}, selector.Select("backend", candidates, 2))
+	assert.Equal(t, []string{"c", "a"}, selector.Select("backend", candidates, 2))
+	assert.Equal(t, []string{"b"}, selector.Select("backend", candidates, 1))
+
+	// Очередь другой команды независима
+	assert.Equal(t, []string{"a"}, selector.Select("frontend", candidates, 1))
+}
+
+func TestWeightedSelector_FavorsLessLoaded(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"busy": 9, "free": 0})
+	selector := &weightedSelector{}
+
+	picks := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		picks[selector.Select("team", candidates, 1)[0]]++
+	}
+	assert.Greater(t, picks["free"], picks["busy"])
+	assert.Greater(t, picks["busy"], 0)
+}
+
+func TestWeightedSelector_DownWeightsRecentPairings(t *testing.T) {
+	candidates := candidatesWithLoad(map[string]int{"frequent": 0, "fresh": 0})
+	for _, c := range candidates {
+		if c.User.Id == "frequent" {
+			c.RecentPairings = 9
+		}
+	}
+	selector := &weightedSelector{}
+
+	picks := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		picks[selector.Select("team", candidates, 1)[0]]++
+	}
+	assert.Greater(t, picks["fresh"], picks["frequent"])
+}
+
+func TestReviewerSelectors_WithRandReplaysSelection(t *testing.T) {
+	selectors, err := NewReviewerSelectors(StrategyRandom, map[string]string{"backend": StrategyRoundRobin}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, StrategyRoundRobin, selectors.StrategyFor("backend", ""))
+	assert.Equal(t, StrategyWeighted, selectors.StrategyFor("backend", StrategyWeighted))
+	assert.Equal(t, StrategyRandom, selectors.StrategyFor("frontend", "unknown"))
+
+	candidates := candidatesWithLoad(map[string]int{"a": 0, "b": 1, "c": 2, "d": 0, "e": 3})
+	for _, strategy := range []string{StrategyRandom, StrategyLeastLoaded, StrategyWeighted} {
+		first := selectors.WithRand(strategy, rand.New(rand.NewSource(7))).Select("team", candidates, 3)
+		again := selectors.WithRand(strategy, rand.New(rand.NewSource(7))).Select("team", candidates, 3)
+		assert.Equal(t, first, again, strategy)
+	}
+
+	// Round-robin случайность не использует
+	assert.Same(t, selectors.ForTeam("backend", ""), selectors.WithRand(StrategyRoundRobin, rand.New(rand.NewSource(7))))
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job фоновая задача планировщика. Ошибка записывается в лог и не останавливает следующие запуски
+type Job func(ctx context.Context) error
+
+type scheduledJob struct {
+	name string
+	// next возвращает время следующего запуска после момента now
+	next func(now time.Time) time.Time
+	run  Job
+}
+
+// Scheduler запускает фоновые задачи по расписанию. Каждая задача выполняется в своей горутине,
+// запуски одной задачи не пересекаются
+type Scheduler struct {
+	jobs []*scheduledJob
+	log  *zap.Logger
+	now  func() time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	// Контекст выполняемых задач, отменяется, если Shutdown не дождался их завершения
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewScheduler(log *zap.Logger) *Scheduler {
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	return &Scheduler{
+		log:       log,
+		now:       time.Now,
+		stop:      make(chan struct{}),
+		runCtx:    runCtx,
+		cancelRun: cancelRun,
+	}
+}
+
+// Every добавляет задачу, которая запускается каждые interval. Задачи добавляются до Start
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.jobs = append(s.jobs, &scheduledJob{
+		name: name,
+		next: func(now time.Time) time.Time { return now.Add(interval) },
+		run:  job,
+	})
+}
+
+// Daily добавляет задачу, которая запускается каждый день в hour:minute в часовом поясе loc
+func (s *Scheduler) Daily(name string, hour, minute int, loc *time.Location, job Job) {
+	s.jobs = append(s.jobs, &scheduledJob{
+		name: name,
+		next: func(now time.Time) time.Time { return nextDailyRun(now, hour, minute, loc) },
+		run:  job,
+	})
+}
+
+// Start запускает все добавленные задачи
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+	s.log.Info("scheduler started", zap.Int("jobs", len(s.jobs)))
+}
+
+// Shutdown прекращает новые запуски и ждет завершения выполняемых задач. Если ctx истекает раньше,
+// контекст задач отменяется и возвращается ошибка ctx
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancelRun()
+		s.log.Info("scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		s.cancelRun()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(job *scheduledJob) {
+	defer s.wg.Done()
+
+	for {
+		now := s.now()
+		timer := time.NewTimer(job.next(now).Sub(now))
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Таймер мог сработать одновременно с остановкой
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		s.runJob(job)
+	}
+}
+
+// runJob выполняет задачу один раз, паника задачи не останавливает планировщик
+func (s *Scheduler) runJob(job *scheduledJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("scheduled job panicked", zap.String("job", job.name), zap.Any("panic", r))
+		}
+	}()
+
+	startedAt := s.now()
+	if err := job.run(s.runCtx); err != nil {
+		s.log.Error("scheduled job failed",
+			zap.String("job", job.name),
+			zap.Duration("duration", s.now().Sub(startedAt)),
+			zap.Error(err),
+		)
+		return
+	}
+	s.log.Debug("scheduled job finished",
+		zap.String("job", job.name),
+		zap.Duration("duration", s.now().Sub(startedAt)),
+	)
+}
+
+// nextDailyRun ближайший после now момент hour:minute в часовом поясе loc.
+// Следующий день считается по календарю, поэтому переход на летнее время не сдвигает запуск
+func nextDailyRun(now time.Time, hour, minute int, loc *time.Location) time.Time {
+	local := now.In(loc)
+	run := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !run.After(now) {
+		run = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, loc)
+	}
+	return run
+}
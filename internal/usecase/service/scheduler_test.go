@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestScheduler_RunsJobUntilShutdown(t *testing.T) {
+	scheduler := NewScheduler(zap.NewNop())
+	var runs atomic.Int32
+	scheduler.Every("counter", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		// Ошибка задачи не останавливает следующие запуски
+		return errors.New("job error")
+	})
+	scheduler.Start()
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, scheduler.Shutdown(ctx))
+
+	// После остановки задача больше не запускается
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
+
+func TestScheduler_ShutdownWaitsForRunningJob(t *testing.T) {
+	scheduler := NewScheduler(zap.NewNop())
+	started := make(chan struct{})
+	var finished atomic.Bool
+	scheduler.Every("slow", time.Millisecond, func(ctx context.Context) error {
+		if finished.Load() {
+			return nil
+		}
+		close(started)
+		time.Sleep(30 * time.Millisecond)
+		finished.Store(true)
+		return nil
+	})
+	scheduler.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, scheduler.Shutdown(ctx))
+	assert.True(t, finished.Load())
+}
+
+func TestScheduler_ShutdownTimeoutCancelsJob(t *testing.T) {
+	scheduler := NewScheduler(zap.NewNop())
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	scheduler.Every("stuck", time.Millisecond, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+	scheduler.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, scheduler.Shutdown(ctx), context.DeadlineExceeded)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("job context was not cancelled")
+	}
+}
+
+func TestNextDailyRun(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{
+			name: "later today",
+			now:  time.Date(2025, 1, 8, 7, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: time.Date(2025, 1, 8, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "exactly at run time moves to tomorrow",
+			now:  time.Date(2025, 1, 8, 9, 30, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: time.Date(2025, 1, 9, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "time zone of the schedule",
+			// 07:00 UTC - уже 10:00 по Москве
+			now:  time.Date(2025, 1, 8, 7, 0, 0, 0, time.UTC),
+			loc:  moscow,
+			want: time.Date(2025, 1, 9, 9, 30, 0, 0, moscow),
+		},
+		{
+			name: "daylight saving change",
+			now:  time.Date(2025, 3, 29, 10, 0, 0, 0, berlin),
+			loc:  berlin,
+			want: time.Date(2025, 3, 30, 9, 30, 0, 0, berlin),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextDailyRun(tt.now, 9, 30, tt.loc)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+)
+
+const (
+	// Ограничения тегов пользователя и PR
+	maxTags      = 20
+	maxTagLength = 64
+)
+
+// normalizeTags приводит теги к нижнему регистру, убирает повторы и сортирует.
+// Тег состоит из латинских букв, цифр и символов "-", "_", ".", "+", "#", например c++ или c#
+func normalizeTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	for _, rawTag := range raw {
+		tag := strings.ToLower(strings.TrimSpace(rawTag))
+		if tag == "" {
+			return nil, errors.New("tag is empty")
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		for _, r := range tag {
+			if !isTagRune(r) {
+				return nil, fmt.Errorf("tag %q contains %q", tag, r)
+			}
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxTags {
+		return nil, fmt.Errorf("too many tags, at most %d allowed", maxTags)
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
+
+func isTagRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || strings.ContainsRune("-_.+#", r)
+}
+
+// coversTags проверяет, что у кандидата есть все теги PR
+func coversTags(candidate *result.ReviewerCandidate, requiredTags []string) bool {
+	for _, tag := range requiredTags {
+		if !slices.Contains(candidate.User.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyCoversTags проверяет, что теги PR покрывает хотя бы один из выбранных ревьюеров пула
+func anyCoversTags(pool *result.PotentialReviewersResult, reviewers []string, requiredTags []string) bool {
+	for _, reviewerId := range reviewers {
+		candidate := findCandidate(pool, reviewerId)
+		if candidate != nil && coversTags(candidate, requiredTags) {
+			return true
+		}
+	}
+	return false
+}
+
+// taggedPool возвращает копию пула, в которой остались только кандидаты, покрывающие теги PR
+func taggedPool(pool *result.PotentialReviewersResult, requiredTags []string) *result.PotentialReviewersResult {
+	filtered := *pool
+	filtered.Candidates = nil
+	for _, candidate := range pool.Candidates {
+		if candidate != nil && candidate.User != nil && coversTags(candidate, requiredTags) {
+			filtered.Candidates = append(filtered.Candidates, candidate)
+		}
+	}
+	return &filtered
+}
+
+// findTaggedReviewers сначала отдает места кандидатам, чьи теги покрывают теги PR. Если команда
+// не требует тегов, недостающие места заполняются из всей команды. Когда подходящих по тегам
+// кандидатов нет совсем, выбор идет из всей команды независимо от настроек
+func findTaggedReviewers(selector ReviewerSelector, pool *result.PotentialReviewersResult, excludedIds []string, count int, requiredTags []string, requireTags bool) ([]string, error) {
+	if len(requiredTags) == 0 {
+		return findReviewers(selector, pool, excludedIds, count)
+	}
+
+	tagged, err := findReviewers(selector, taggedPool(pool, requiredTags), excludedIds, count)
+	if errors.Is(err, noPotentialReviewerError) {
+		return findReviewers(selector, pool, excludedIds, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if requireTags || len(tagged) >= count {
+		return tagged, nil
+	}
+
+	rest, err := findReviewers(selector, pool, append(slices.Clone(excludedIds), tagged...), count-len(tagged))
+	if errors.Is(err, noPotentialReviewerError) {
+		return tagged, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(tagged, rest...), nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := normalizeTags([]string{" Go", "postgres", "go", "C#"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c#", "go", "postgres"}, tags)
+
+	tags, err = normalizeTags(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+
+	for _, raw := range [][]string{{" "}, {"go lang"}, {"go/postgres"}} {
+		_, err := normalizeTags(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+// taggedTeam команда, где только у backend1 есть оба тега go и postgres
+func taggedTeam() *result.PotentialReviewersResult {
+	return toPool([]*domain.User{
+		{Id: "author", IsActive: true},
+		{Id: "backend1", IsActive: true, Tags: []string{"go", "postgres"}},
+		{Id: "backend2", IsActive: true, Tags: []string{"go"}},
+		{Id: "frontend", IsActive: true, Tags: []string{"frontend"}},
+	})
+}
+
+func TestSelectCreateReviewers_PrefersTaggedReviewers(t *testing.T) {
+	pool := taggedTeam()
+	settings := &domain.TeamSettings{ReviewerCount: 2}
+
+	for i := 0; i < 20; i++ {
+		reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, []string{"go", "postgres"})
+		assert.NoError(t, err)
+		assert.Len(t, reviewers, 2)
+		assert.Equal(t, "backend1", reviewers[0])
+	}
+}
+
+func TestSelectCreateReviewers_RequiresTaggedReviewers(t *testing.T) {
+	pool := taggedTeam()
+	settings := &domain.TeamSettings{ReviewerCount: 2, RequireTags: true}
+
+	reviewers, err := selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, []string{"go", "postgres"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backend1"}, reviewers)
+
+	// Подходящих по тегам нет - выбор идет из всей команды
+	reviewers, err = selectCreateReviewers(&randomSelector{}, pool, settings, "author", nil, nil, []string{"rust"})
+	assert.NoError(t, err)
+	assert.Len(t, reviewers, 2)
+}
+
+func TestPrService_Create_WarnsWhenTagsNotCovered(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	pool := taggedTeam()
+	pool.Settings = &domain.TeamSettings{TeamId: pool.TeamId, ReviewerCount: 1}
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author").Return(pool, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreatPrDTO) bool {
+		return assert.ObjectsAreEqual([]string{"kotlin", "mobile"}, d.RequiredTags)
+	}), mock.Anything).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"backend2"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:         "pr1",
+		PrName:       "Mobile PR",
+		AuthorId:     "author",
+		RequiredTags: []string{"mobile", "Kotlin"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kotlin", "mobile"}, resp.RequiredTags)
+	assert.Len(t, resp.Warnings, 1)
+	assert.Contains(t, resp.Warnings[0], "kotlin, mobile")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Create_NoWarningWhenTagsCovered(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	pool := taggedTeam()
+	pool.Settings = &domain.TeamSettings{TeamId: pool.TeamId, ReviewerCount: 1}
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author").Return(pool, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"backend1"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"backend1"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:         "pr1",
+		PrName:       "Backend PR",
+		AuthorId:     "author",
+		RequiredTags: []string{"postgres", "go"},
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Warnings)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Create_InvalidTags(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, logger)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:         "pr1",
+		AuthorId:     "author",
+		RequiredTags: []string{"two words"},
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrInvalidTags.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "SelectPotentialReviewers", mock.Anything, mock.Anything)
+}
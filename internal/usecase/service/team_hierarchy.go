@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var setTeamParentError = errors.New("set team parent error")
+
+// SetParent делает parent_team_name родительской командой team_name. Пустой parent_team_name
+// делает команду командой верхнего уровня
+func (s *TeamService) SetParent(ctx context.Context, req *request.SetTeamParentRequest) (*response.GetTeamResponse, error) {
+	teamName := strings.TrimSpace(req.TeamName)
+	parentName := strings.TrimSpace(req.ParentTeamName)
+	s.log.Info("set team parent request accepted",
+		zap.String("team_name", teamName),
+		zap.String("parent_team_name", parentName),
+	)
+
+	if teamName == "" {
+		return nil, WrapError(ErrInvalidTeamName, errors.New("team_name is empty"))
+	}
+	if parentName == teamName {
+		return nil, WrapError(ErrInvalidParentTeam, fmt.Errorf("team %s cannot be its own parent", teamName))
+	}
+
+	// Собираем dto
+	dto := &dto.SetTeamParentDTO{
+		TeamName:       teamName,
+		ParentTeamName: parentName,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.SetParent(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to set team parent", zap.String("team_name", teamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+		if errors.Is(err, repository.ErrTeamCycle) {
+			return nil, WrapError(ErrInvalidParentTeam, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", setTeamParentError, err)
+	}
+
+	s.log.Info("team parent set",
+		zap.String("team_name", res.TeamName),
+		zap.String("parent_team_name", res.ParentTeamName),
+	)
+	// Ответ
+	return toTeamTreeResponse(res), nil
+}
+
+// toTeamTreeResponse команда с участниками и, если репозиторий прочитал иерархию, деревом подкоманд
+func toTeamTreeResponse(res *result.GetTeamResult) *response.GetTeamResponse {
+	resp := &response.GetTeamResponse{
+		TeamName:       res.TeamName,
+		Members:        res.Members,
+		ParentTeamName: res.ParentTeamName,
+	}
+	hierarchy := newTeamHierarchy(res.Subtree)
+	for _, node := range res.Subtree {
+		if node.TeamName == res.TeamName {
+			resp.Tree = hierarchy.tree(node.TeamId)
+			break
+		}
+	}
+	return resp
+}
+
+// teamStats сводка по каждой команде и ее поддереву. Нагрузка берется из статистики пользователей
+func teamStats(teams []*result.TeamNodeResult, users []result.UserStats) []response.TeamStat {
+	byId := make(map[string]result.UserStats, len(users))
+	for _, u := range users {
+		byId[u.UserId] = u
+	}
+
+	hierarchy := newTeamHierarchy(teams)
+	stats := make([]response.TeamStat, 0, len(teams))
+	for _, team := range teams {
+		stat := response.TeamStat{
+			TeamName:    team.TeamName,
+			MemberCount: len(team.MemberIds),
+		}
+		if parent, ok := hierarchy.nodes[team.ParentId]; ok {
+			stat.ParentTeamName = parent.TeamName
+		}
+		for userId := range hierarchy.members(team.TeamId) {
+			stat.TotalMemberCount++
+			stat.Assignments += byId[userId].Assignments
+			stat.OpenReviews += byId[userId].OpenReviews
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// teamHierarchy дерево команд, построенное по плоскому списку. Подкоманды идут в порядке списка
+type teamHierarchy struct {
+	nodes    map[string]*result.TeamNodeResult
+	children map[string][]*result.TeamNodeResult
+}
+
+func newTeamHierarchy(nodes []*result.TeamNodeResult) *teamHierarchy {
+	h := &teamHierarchy{
+		nodes:    make(map[string]*result.TeamNodeResult, len(nodes)),
+		children: make(map[string][]*result.TeamNodeResult),
+	}
+	for _, node := range nodes {
+		h.nodes[node.TeamId] = node
+	}
+	for _, node := range nodes {
+		if _, ok := h.nodes[node.ParentId]; ok {
+			h.children[node.ParentId] = append(h.children[node.ParentId], node)
+		}
+	}
+	return h
+}
+
+// members пользователи команды и всех ее подкоманд без повторов
+func (h *teamHierarchy) members(teamId string) map[string]struct{} {
+	members := make(map[string]struct{})
+	h.walk(teamId, make(map[string]bool), func(node *result.TeamNodeResult) {
+		for _, userId := range node.MemberIds {
+			members[userId] = struct{}{}
+		}
+	})
+	return members
+}
+
+// tree узел ответа для команды с ее подкомандами
+func (h *teamHierarchy) tree(teamId string) *response.TeamTreeNode {
+	return h.treeNode(h.nodes[teamId], make(map[string]bool))
+}
+
+func (h *teamHierarchy) treeNode(node *result.TeamNodeResult, visited map[string]bool) *response.TeamTreeNode {
+	visited[node.TeamId] = true
+	resp := &response.TeamTreeNode{
+		TeamName:         node.TeamName,
+		MemberCount:      len(node.MemberIds),
+		TotalMemberCount: len(h.members(node.TeamId)),
+		Subteams:         []*response.TeamTreeNode{},
+	}
+	for _, child := range h.children[node.TeamId] {
+		if !visited[child.TeamId] {
+			resp.Subteams = append(resp.Subteams, h.treeNode(child, visited))
+		}
+	}
+	return resp
+}
+
+// walk обходит команду и ее подкоманды, каждую один раз даже при цикле в данных
+func (h *teamHierarchy) walk(teamId string, visited map[string]bool, visit func(node *result.TeamNodeResult)) {
+	node, ok := h.nodes[teamId]
+	if !ok || visited[teamId] {
+		return
+	}
+	visited[teamId] = true
+	visit(node)
+	for _, child := range h.children[teamId] {
+		h.walk(child.TeamId, visited, visit)
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// subteamPool команда team1 с родительской командой
+func subteamPool(users []*domain.User) *result.PotentialReviewersResult {
+	pool := toPool(users)
+	pool.ParentId = "backend-id"
+	return pool
+}
+
+func TestPrService_Create_FillsFromParentTeams(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	// В подкоманде один кандидат из двух нужных
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(subteamPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+	}), nil)
+	// Ближайший родитель без активных кандидатов, добираем из следующего
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backend", []*domain.User{{Id: "away", IsActive: false}, {Id: "user1", IsActive: true}}),
+		fallbackPool("engineering", []*domain.User{{Id: "e1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"user1", "e1"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"user1", "e1"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "Test PR",
+		AuthorId: "author1",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"engineering"}, resp.ParentTeams)
+	assert.False(t, resp.CrossTeam)
+	mockRepo.AssertNotCalled(t, "SelectFallbackPools", mock.Anything, mock.Anything)
+
+	// Подбор засчитан команде автора, рассмотренные родители попали в объяснение
+	d := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(1).(*dto.CreatPrDTO)
+	assert.Equal(t, "team1", d.Explanation.TeamName)
+	assert.Len(t, d.Explanation.Pools, 3)
+}
+
+func TestPrService_Create_MarksSelectedInEveryPool(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	// user1 выбран из подкоманды, e1 - из родительской команды
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(subteamPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+		{Id: "user1", IsActive: true},
+	}), nil)
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("engineering", []*domain.User{{Id: "e1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"user1", "e1"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"user1", "e1"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	_, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "Test PR",
+		AuthorId: "author1",
+	})
+	assert.NoError(t, err)
+
+	d := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(1).(*dto.CreatPrDTO)
+	selected := make(map[string]map[string]bool)
+	for _, pool := range d.Explanation.Pools {
+		selected[pool.TeamName] = make(map[string]bool)
+		for _, candidate := range pool.Candidates {
+			selected[pool.TeamName][candidate.UserId] = candidate.Selected
+		}
+	}
+	assert.Equal(t, map[string]map[string]bool{
+		"team1":       {"author1": false, "user1": true},
+		"engineering": {"e1": true},
+	}, selected)
+}
+
+func TestPrService_Create_FallbackAfterEmptyParents(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "author1").Return(subteamPool([]*domain.User{
+		{Id: "author1", IsActive: true},
+	}), nil)
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backend", []*domain.User{{Id: "author1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backup", []*domain.User{{Id: "b1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("Create", mock.Anything, mock.Anything, []string{"b1"}).Return(&result.PrResult{
+		Id:                "pr1",
+		AuthorId:          "author1",
+		Status:            domain.PrStatusOpen,
+		AssignedReviewers: []string{"b1"},
+		CreatedAt:         time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateRequest{
+		PrId:     "pr1",
+		PrName:   "Test PR",
+		AuthorId: "author1",
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.ParentTeams)
+	assert.Equal(t, "backup", resp.FallbackTeam)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrService_Reassign_ClimbsToParentTeam(t *testing.T) {
+	mockRepo := new(MockPrRepository)
+	service := NewPrService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("CheckReviewerAssignedWithPR", mock.Anything, "pr1", "old_reviewer").Return(true, &result.PrResult{Id: "pr1", AuthorId: "author1"}, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "old_reviewer").Return(subteamPool([]*domain.User{
+		{Id: "old_reviewer", IsActive: true},
+		{Id: "author1", IsActive: true},
+	}), nil)
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backend", []*domain.User{{Id: "p1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("Reassign", mock.Anything, mock.MatchedBy(func(d *dto.ReassignPrDTO) bool {
+		return d.ReplacedBy == "p1" && d.Explanation.TeamName == "backend"
+	})).Return(&result.ReassignResult{
+		Pr: &result.PrResult{
+			Id:                "pr1",
+			AuthorId:          "author1",
+			Status:            domain.PrStatusOpen,
+			AssignedReviewers: []string{"p1"},
+			CreatedAt:         time.Now(),
+		},
+		ReplacedBy: "p1",
+	}, nil)
+
+	resp, err := service.Reassign(context.Background(), &request.ReassignRequest{
+		PrId:      "pr1",
+		OldUserId: "old_reviewer",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", resp.ReplacedBy)
+	assert.Equal(t, "backend", resp.ParentTeam)
+	assert.False(t, resp.CrossTeam)
+	mockRepo.AssertNotCalled(t, "SelectFallbackPools", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetParent_RejectsOwnTeam(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	resp, err := service.SetParent(context.Background(), &request.SetTeamParentRequest{
+		TeamName:       "backend",
+		ParentTeamName: " backend ",
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+	mockRepo.AssertNotCalled(t, "SetParent", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetParent_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		wantCode string
+	}{
+		{name: "cycle", repoErr: repository.ErrTeamCycle, wantCode: "INVALID_INPUT"},
+		{name: "team not found", repoErr: repository.ErrNotFound, wantCode: "NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTeamRepository)
+			service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+			mockRepo.On("SetParent", mock.Anything, &dto.SetTeamParentDTO{
+				TeamName:       "backend",
+				ParentTeamName: "backend-payments",
+			}).Return(nil, tt.repoErr)
+
+			_, err := service.SetParent(context.Background(), &request.SetTeamParentRequest{
+				TeamName:       "backend",
+				ParentTeamName: "backend-payments",
+			})
+
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, tt.wantCode, domainErr.Code)
+		})
+	}
+}
+
+func TestTeamService_Get_ReturnsTreeWithRolledUpCounts(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	// u2 состоит в обеих подкомандах и учитывается в backend один раз
+	mockRepo.On("Get", mock.Anything, &dto.GetTeamDTO{TeamName: "backend"}).Return(&result.GetTeamResult{
+		TeamName:       "backend",
+		Members:        []*domain.User{{Id: "u1", IsActive: true}},
+		ParentTeamName: "engineering",
+		Subtree: []*result.TeamNodeResult{
+			{TeamId: "backend-id", TeamName: "backend", ParentId: "engineering-id", MemberIds: []string{"u1"}},
+			{TeamId: "payments-id", TeamName: "backend-payments", ParentId: "backend-id", MemberIds: []string{"u2", "u3"}},
+			{TeamId: "billing-id", TeamName: "billing", ParentId: "payments-id", MemberIds: []string{"u4"}},
+			{TeamId: "search-id", TeamName: "search", ParentId: "backend-id", MemberIds: []string{"u2"}},
+		},
+	}, nil)
+
+	resp, err := service.Get(context.Background(), &request.GetTeamRequest{TeamName: "backend"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "engineering", resp.ParentTeamName)
+	assert.Equal(t, &response.TeamTreeNode{
+		TeamName:         "backend",
+		MemberCount:      1,
+		TotalMemberCount: 4,
+		Subteams: []*response.TeamTreeNode{
+			{
+				TeamName:         "backend-payments",
+				MemberCount:      2,
+				TotalMemberCount: 3,
+				Subteams: []*response.TeamTreeNode{
+					{TeamName: "billing", MemberCount: 1, TotalMemberCount: 1, Subteams: []*response.TeamTreeNode{}},
+				},
+			},
+			{TeamName: "search", MemberCount: 1, TotalMemberCount: 1, Subteams: []*response.TeamTreeNode{}},
+		},
+	}, resp.Tree)
+}
+
+func TestTeamStats_AggregatesSubtrees(t *testing.T) {
+	teams := []*result.TeamNodeResult{
+		{TeamId: "backend-id", TeamName: "backend", MemberIds: []string{"u1"}},
+		{TeamId: "payments-id", TeamName: "backend-payments", ParentId: "backend-id", MemberIds: []string{"u1", "u2"}},
+		{TeamId: "mobile-id", TeamName: "mobile", MemberIds: nil},
+	}
+	users := []result.UserStats{
+		{UserId: "u1", Assignments: 4, OpenReviews: 1},
+		{UserId: "u2", Assignments: 2, OpenReviews: 2},
+	}
+
+	stats := teamStats(teams, users)
+
+	assert.Equal(t, []response.TeamStat{
+		{TeamName: "backend", MemberCount: 1, TotalMemberCount: 2, Assignments: 6, OpenReviews: 3},
+		{TeamName: "backend-payments", ParentTeamName: "backend", MemberCount: 2, TotalMemberCount: 2, Assignments: 6, OpenReviews: 3},
+		{TeamName: "mobile"},
+	}, stats)
+}
+
+func TestTeamHierarchy_IgnoresCycles(t *testing.T) {
+	hierarchy := newTeamHierarchy([]*result.TeamNodeResult{
+		{TeamId: "a", TeamName: "a", ParentId: "b", MemberIds: []string{"u1"}},
+		{TeamId: "b", TeamName: "b", ParentId: "a", MemberIds: []string{"u2"}},
+	})
+
+	assert.Len(t, hierarchy.members("a"), 2)
+	assert.Len(t, hierarchy.tree("a").Subteams, 1)
+	assert.Empty(t, hierarchy.tree("a").Subteams[0].Subteams)
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	addTeamMembersError   = errors.New("add team members error")
+	removeTeamMemberError = errors.New("remove team member error")
+	renameTeamError       = errors.New("rename team error")
+	deleteTeamError       = errors.New("delete team error")
+)
+
+// Ограничение колонки teams.name
+const maxTeamNameLength = 255
+
+// AddMembers добавляет участников в существующую команду и возвращает ее полный состав
+func (s *TeamService) AddMembers(ctx context.Context, req *request.AddTeamMembersRequest) (*response.GetTeamResponse, error) {
+	s.log.Info("add team members request accepted",
+		zap.String("team_name", req.TeamName),
+		zap.Int("members", len(req.Members)),
+	)
+
+	// Проверяем участников и убираем повторы, побеждает последнее вхождение
+	members := make([]*domain.User, 0, len(req.Members))
+	for _, member := range req.Members {
+		if member == nil {
+			continue
+		}
+		userId, err := normalizeID(member.Id, "user_id")
+		if err != nil {
+			return nil, WrapError(ErrEmptyMembers, err)
+		}
+		member.Id = userId
+		members = slices.DeleteFunc(members, func(m *domain.User) bool { return m.Id == userId })
+		members = append(members, member)
+	}
+	if len(members) == 0 {
+		return nil, WrapError(ErrEmptyMembers, errors.New("no members to add"))
+	}
+
+	// Собираем dto
+	dto := &dto.AddTeamMembersDTO{
+		TeamName: req.TeamName,
+		Members:  members,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.AddMembers(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to add team members", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", addTeamMembersError, err)
+	}
+
+	s.log.Info("team members added", zap.String("team_name", res.TeamName), zap.Int("members", len(res.Members)))
+	// Ответ
+	return &response.GetTeamResponse{
+		TeamName:       res.TeamName,
+		Members:        res.Members,
+		IgnoredUserIds: res.IgnoredUserIds,
+	}, nil
+}
+
+// RemoveMember исключает пользователя из команды. Его открытые ревью PR авторов этой команды
+// переназначаются на оставшихся участников, а при их нехватке - на резервные команды.
+// Если хотя бы одно ревью передать некому, ничего не меняется
+func (s *TeamService) RemoveMember(ctx context.Context, req *request.RemoveTeamMemberRequest) (*response.RemoveTeamMembersResponse, error) {
+	s.log.Info("remove team member request accepted",
+		zap.String("team_name", req.TeamName),
+		zap.String("user_id", req.UserId),
+	)
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Читаем команду с нагрузкой участников
+	pool, err := s.repo.SelectTeamPool(ctx, &dto.GetTeamDTO{TeamName: req.TeamName})
+	if err != nil {
+		s.log.Error("failed to load team for member removal", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", removeTeamMemberError, err)
+	}
+
+	candidate := findCandidate(pool, userId)
+	if candidate == nil {
+		return nil, WrapError(ErrNotTeamMember, fmt.Errorf("user %s is not in team %s", userId, pool.TeamName))
+	}
+	// Лид по роли или из настроек команды
+	if isTeamLead(pool, candidate) {
+		return nil, WrapError(ErrRemoveTeamLead, fmt.Errorf("user %s leads team %s", userId, pool.TeamName))
+	}
+
+	// Подбираем замену для ревью, которые пользователь получил как участник команды
+	reviews, err := s.teamOpenReviews(ctx, pool, []string{userId})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", removeTeamMemberError, err)
+	}
+	reassignments, err := s.planLeavingReassignments(ctx, pool, reviews, []string{userId}, domain.AssignReasonMemberRemoved)
+	if err != nil {
+		return nil, err
+	}
+
+	// Собираем dto
+	dto := &dto.RemoveTeamMemberDTO{
+		TeamId:        pool.TeamId,
+		TeamName:      pool.TeamName,
+		UserId:        userId,
+		Reassignments: reassignments,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.RemoveMember(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to remove team member", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			return nil, WrapError(ErrNotTeamMember, err)
+		}
+		if errors.Is(err, repository.ErrTeamLead) {
+			return nil, WrapError(ErrRemoveTeamLead, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", removeTeamMemberError, err)
+	}
+
+	s.log.Info("team member removed",
+		zap.String("team_name", pool.TeamName),
+		zap.String("user_id", userId),
+		zap.Int("reviews", len(reviews)),
+		zap.Int("reassigned", len(res.Reassigned)),
+	)
+	publishReassignments(ctx, s.events, reviews, res.Reassigned)
+	// Ответ
+	return &response.RemoveTeamMembersResponse{
+		TeamName:   pool.TeamName,
+		RemovedIds: res.RemovedIds,
+		Reviews:    reviewOutcomes(reassignments, res.Reassigned, nil),
+	}, nil
+}
+
+// Rename меняет название команды. Занятое название дает TEAM_EXISTS
+func (s *TeamService) Rename(ctx context.Context, req *request.RenameTeamRequest) (*response.GetTeamResponse, error) {
+	s.log.Info("rename team request accepted",
+		zap.String("team_name", req.TeamName),
+		zap.String("new_team_name", req.NewTeamName),
+	)
+
+	newTeamName, err := normalizeTeamName(req.NewTeamName)
+	if err != nil {
+		return nil, WrapError(ErrInvalidTeamName, err)
+	}
+	if newTeamName == req.TeamName {
+		return nil, WrapError(ErrInvalidTeamName, errors.New("new_team_name must differ from team_name"))
+	}
+
+	// Собираем dto
+	dto := &dto.RenameTeamDTO{
+		TeamName:    req.TeamName,
+		NewTeamName: newTeamName,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.Rename(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to rename team", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, WrapError(ErrTeamExists, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", renameTeamError, err)
+	}
+
+	s.log.Info("team renamed", zap.String("team_name", req.TeamName), zap.String("new_team_name", res.TeamName))
+	// Ответ
+	return &response.GetTeamResponse{
+		TeamName: res.TeamName,
+		Members:  res.Members,
+	}, nil
+}
+
+// Delete удаляет команду. Открытые ревью PR авторов команды, назначенные ее участникам,
+// переназначаются на резервные команды. Если хотя бы одно ревью передать некому, команда не удаляется
+func (s *TeamService) Delete(ctx context.Context, req *request.DeleteTeamRequest) (*response.RemoveTeamMembersResponse, error) {
+	s.log.Info("delete team request accepted", zap.String("team_name", req.TeamName))
+
+	// Читаем команду с нагрузкой участников
+	pool, err := s.repo.SelectTeamPool(ctx, &dto.GetTeamDTO{TeamName: req.TeamName})
+	if err != nil {
+		s.log.Error("failed to load team for deletion", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", deleteTeamError, err)
+	}
+
+	// Команду покидают все участники
+	memberIds := make([]string, 0, len(pool.Candidates))
+	for _, candidate := range pool.Candidates {
+		memberIds = append(memberIds, candidate.User.Id)
+	}
+	reviews, err := s.teamOpenReviews(ctx, pool, memberIds)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", deleteTeamError, err)
+	}
+	reassignments, err := s.planLeavingReassignments(ctx, pool, reviews, memberIds, domain.AssignReasonTeamDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	// Собираем dto
+	dto := &dto.DeleteTeamDTO{
+		TeamId:        pool.TeamId,
+		TeamName:      pool.TeamName,
+		Reassignments: reassignments,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.Delete(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to delete team", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", deleteTeamError, err)
+	}
+
+	removedIds := res.RemovedIds
+	if removedIds == nil {
+		removedIds = []string{}
+	}
+	s.log.Info("team deleted",
+		zap.String("team_name", pool.TeamName),
+		zap.Int("members", len(removedIds)),
+		zap.Int("reviews", len(reviews)),
+		zap.Int("reassigned", len(res.Reassigned)),
+	)
+	publishReassignments(ctx, s.events, reviews, res.Reassigned)
+	// Ответ
+	return &response.RemoveTeamMembersResponse{
+		TeamName:   pool.TeamName,
+		RemovedIds: removedIds,
+		Reviews:    reviewOutcomes(reassignments, res.Reassigned, nil),
+	}, nil
+}
+
+// teamOpenReviews открытые ревью выбывающих участников на PR авторов из этой команды.
+// Ревью, полученные через другие команды, остаются за ревьюером
+func (s *TeamService) teamOpenReviews(ctx context.Context, pool *result.PotentialReviewersResult, leavingIds []string) ([]*result.OpenReviewResult, error) {
+	if len(leavingIds) == 0 {
+		return nil, nil
+	}
+	reviews, err := s.repo.SelectOpenReviews(ctx, leavingIds)
+	if err != nil {
+		s.log.Error("failed to load open reviews of leaving members", zap.String("team_name", pool.TeamName), zap.Error(err))
+		return nil, err
+	}
+	return slices.DeleteFunc(reviews, func(review *result.OpenReviewResult) bool {
+		return findCandidate(pool, review.AuthorId) == nil
+	}), nil
+}
+
+// planLeavingReassignments подбирает замену ревью выбывающих участников так же, как /pullRequest/reassign:
+// в самой команде, в ее родительских и резервных командах. Если для какого-то ревью замены нет, возвращает NO_CANDIDATE
+func (s *TeamService) planLeavingReassignments(
+	ctx context.Context,
+	pool *result.PotentialReviewersResult,
+	reviews []*result.OpenReviewResult,
+	leavingIds []string,
+	reason string,
+) ([]*dto.ReassignPrDTO, error) {
+	reassignments, noCandidate, err := s.replacer.planReassignments(ctx, pool, reviews, leavingIds, reason)
+	if err != nil {
+		s.log.Error("failed to plan reassignments of leaving members", zap.String("team_name", pool.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, noLeadReviewerError) {
+			return nil, WrapError(ErrNoLeadCandidate, err)
+		}
+		return nil, err
+	}
+
+	if len(noCandidate) > 0 {
+		prIds := make([]string, 0, len(noCandidate))
+		for _, review := range noCandidate {
+			prIds = append(prIds, review.PrId)
+		}
+		s.log.Warn("open reviews of leaving members cannot be reassigned",
+			zap.String("team_name", pool.TeamName),
+			zap.Strings("pr_ids", prIds),
+		)
+		return nil, WrapError(ErrOpenReviewsNoCandidate, fmt.Errorf("no replacement for reviews on %s", strings.Join(prIds, ", ")))
+	}
+	return reassignments, nil
+}
+
+// reviewOutcomes итог по каждому ревью в порядке планирования: примененные замены, пропущенные
+// и ревью без кандидата
+func reviewOutcomes(planned, applied []*dto.ReassignPrDTO, noCandidate []*result.OpenReviewResult) []response.ReviewOutcome {
+	appliedKeys := make(map[string]struct{}, len(applied))
+	for _, r := range applied {
+		appliedKeys[r.PrId+"/"+r.OldReviewerId] = struct{}{}
+	}
+	outcomes := make([]response.ReviewOutcome, 0, len(planned)+len(noCandidate))
+	for _, r := range planned {
+		outcome := response.ReviewOutcome{
+			PrId:          r.PrId,
+			OldReviewerId: r.OldReviewerId,
+			Status:        response.ReviewOutcomeSkipped,
+		}
+		if _, ok := appliedKeys[r.PrId+"/"+r.OldReviewerId]; ok {
+			outcome.Status = response.ReviewOutcomeReassigned
+			outcome.ReplacedBy = r.ReplacedBy
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	for _, review := range noCandidate {
+		outcomes = append(outcomes, response.ReviewOutcome{
+			PrId:          review.PrId,
+			OldReviewerId: review.ReviewerId,
+			Status:        response.ReviewOutcomeNoCandidate,
+		})
+	}
+	return outcomes
+}
+
+// normalizeTeamName обрезает пробелы и проверяет длину названия команды
+func normalizeTeamName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", errors.New("team name is empty")
+	}
+	if len(name) > maxTeamNameLength {
+		return "", fmt.Errorf("team name is longer than %d characters", maxTeamNameLength)
+	}
+	return name, nil
+}
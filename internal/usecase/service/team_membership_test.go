@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestTeamService_AddMembers_Success(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	members := []*domain.User{
+		{Id: "u1", Name: "User 1", TeamName: "team1", IsActive: true},
+		{Id: "u2", Name: "User 2", TeamName: "team1", IsActive: true},
+	}
+	mockRepo.On("AddMembers", mock.Anything, mock.MatchedBy(func(d *dto.AddTeamMembersDTO) bool {
+		// Повтор пользователя схлопывается в последнее вхождение
+		return d.TeamName == "team1" && len(d.Members) == 1 && d.Members[0].Id == "u2" && d.Members[0].Name == "User 2"
+	})).Return(&result.GetTeamResult{TeamName: "team1", Members: members}, nil)
+
+	resp, err := service.AddMembers(context.Background(), &request.AddTeamMembersRequest{
+		TeamName: "team1",
+		Members: []*domain.User{
+			{Id: "u2", Name: "Old name", IsActive: true},
+			{Id: " u2 ", Name: "User 2", IsActive: true},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Members, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_AddMembers_Errors(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	_, err := service.AddMembers(context.Background(), &request.AddTeamMembersRequest{TeamName: "team1"})
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrEmptyMembers.Code, domainErr.Code)
+
+	mockRepo.On("AddMembers", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+	_, err = service.AddMembers(context.Background(), &request.AddTeamMembersRequest{
+		TeamName: "ghost",
+		Members:  []*domain.User{{Id: "u1", Name: "User 1"}},
+	})
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrTeamNotFound.Code, domainErr.Code)
+}
+
+func TestTeamService_RemoveMember_ReassignsTeamReviews(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	events := &recordingPublisher{}
+	service := NewTeamService(mockRepo, testSelectors(t), events, zap.NewNop())
+
+	pool := toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+		{Id: "u3", Name: "User 3", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u1", Reviewers: []string{"u1"}},
+		// Автор не из команды - ревью получено через другую команду и остается за ревьюером
+		{PrId: "pr2", AuthorId: "outsider", ReviewerId: "u1", Reviewers: []string{"u1"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, &dto.GetTeamDTO{TeamName: "team1"}).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"u1"}).Return(reviews, nil)
+	mockRepo.On("RemoveMember", mock.Anything, mock.MatchedBy(func(d *dto.RemoveTeamMemberDTO) bool {
+		return d.TeamId == "team1-id" && d.UserId == "u1" && len(d.Reassignments) == 1 &&
+			d.Reassignments[0].PrId == "pr1" && d.Reassignments[0].ReplacedBy == "u3" &&
+			d.Reassignments[0].Reason == domain.AssignReasonMemberRemoved
+	})).Return(&result.RemoveMembersResult{
+		RemovedIds: []string{"u1"},
+		Reassigned: []*dto.ReassignPrDTO{{PrId: "pr1", OldReviewerId: "u1", ReplacedBy: "u3"}},
+	}, nil)
+
+	resp, err := service.RemoveMember(context.Background(), &request.RemoveTeamMemberRequest{TeamName: "team1", UserId: "u1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, resp.RemovedIds)
+	if assert.Len(t, resp.Reviews, 1) {
+		assert.Equal(t, response.ReviewOutcomeReassigned, resp.Reviews[0].Status)
+		assert.Equal(t, "u3", resp.Reviews[0].ReplacedBy)
+	}
+	if assert.Len(t, events.events, 1) {
+		assert.Equal(t, domain.EventReviewerReassigned, events.events[0].Type)
+		assert.Equal(t, "pr1", events.events[0].PrId)
+		assert.Equal(t, []string{"u2", "u1", "u3"}, events.events[0].UserIds)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_RemoveMember_FallsBackToFallbackTeam(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	pool := toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u1", Reviewers: []string{"u1"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, &dto.GetTeamDTO{TeamName: "team1"}).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"u1"}).Return(reviews, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backup", []*domain.User{{Id: "f1", Name: "Fallback 1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("RemoveMember", mock.Anything, mock.MatchedBy(func(d *dto.RemoveTeamMemberDTO) bool {
+		return len(d.Reassignments) == 1 && d.Reassignments[0].ReplacedBy == "f1" &&
+			d.Reassignments[0].Explanation.TeamName == "backup"
+	})).Return(&result.RemoveMembersResult{
+		RemovedIds: []string{"u1"},
+		Reassigned: []*dto.ReassignPrDTO{{PrId: "pr1", OldReviewerId: "u1", ReplacedBy: "f1"}},
+	}, nil)
+
+	resp, err := service.RemoveMember(context.Background(), &request.RemoveTeamMemberRequest{TeamName: "team1", UserId: "u1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "f1", resp.Reviews[0].ReplacedBy)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_RemoveMember_NoCandidate(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	pool := toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u1", Reviewers: []string{"u1"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, mock.Anything).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"u1"}).Return(reviews, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{}, nil)
+
+	_, err := service.RemoveMember(context.Background(), &request.RemoveTeamMemberRequest{TeamName: "team1", UserId: "u1"})
+
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrOpenReviewsNoCandidate.Code, domainErr.Code)
+	assert.ErrorContains(t, err, "pr1")
+	mockRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_RemoveMember_Rejected(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	pool := markLeads(toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+		{Id: "u3", Name: "User 3", IsActive: true},
+	}), "u3")
+	pool.Settings = &domain.TeamSettings{TeamId: "team1-id", ReviewerCount: 2, LeadUserId: "u1"}
+	mockRepo.On("SelectTeamPool", mock.Anything, mock.Anything).Return(pool, nil)
+
+	tests := []struct {
+		name   string
+		userId string
+		want   *DomainError
+	}{
+		{name: "not a member", userId: "ghost", want: ErrNotTeamMember},
+		{name: "team lead", userId: "u1", want: ErrRemoveTeamLead},
+		{name: "lead by role", userId: "u3", want: ErrRemoveTeamLead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.RemoveMember(context.Background(), &request.RemoveTeamMemberRequest{TeamName: "team1", UserId: tt.userId})
+
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, tt.want.Message, domainErr.Message)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_Rename(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("Rename", mock.Anything, &dto.RenameTeamDTO{TeamName: "team1", NewTeamName: "platform"}).
+		Return(&result.GetTeamResult{TeamName: "platform"}, nil)
+	mockRepo.On("Rename", mock.Anything, &dto.RenameTeamDTO{TeamName: "team1", NewTeamName: "taken"}).
+		Return(nil, repository.ErrAlreadyExists)
+
+	resp, err := service.Rename(context.Background(), &request.RenameTeamRequest{TeamName: "team1", NewTeamName: " platform "})
+	assert.NoError(t, err)
+	assert.Equal(t, "platform", resp.TeamName)
+
+	var domainErr *DomainError
+	_, err = service.Rename(context.Background(), &request.RenameTeamRequest{TeamName: "team1", NewTeamName: "taken"})
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrTeamExists.Code, domainErr.Code)
+
+	_, err = service.Rename(context.Background(), &request.RenameTeamRequest{TeamName: "team1", NewTeamName: "  "})
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrInvalidTeamName.Code, domainErr.Code)
+}
+
+func TestTeamService_Delete_ReassignsToFallbackTeam(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	pool := toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+	})
+	// Оба ревьюера PR покидают команду, замены не должны совпасть
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u1", Reviewers: []string{"u1", "u2"}},
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u2", Reviewers: []string{"u1", "u2"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, &dto.GetTeamDTO{TeamName: "team1"}).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"u1", "u2"}).Return(reviews, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backup", []*domain.User{
+			{Id: "f1", Name: "Fallback 1", IsActive: true},
+			{Id: "f2", Name: "Fallback 2", IsActive: true},
+		}),
+	}, nil)
+	mockRepo.On("Delete", mock.Anything, mock.MatchedBy(func(d *dto.DeleteTeamDTO) bool {
+		return d.TeamId == "team1-id" && len(d.Reassignments) == 2 &&
+			d.Reassignments[0].ReplacedBy != d.Reassignments[1].ReplacedBy &&
+			d.Reassignments[0].Reason == domain.AssignReasonTeamDeleted
+	})).Return(&result.RemoveMembersResult{RemovedIds: []string{"u1", "u2"}}, nil)
+
+	resp, err := service.Delete(context.Background(), &request.DeleteTeamRequest{TeamName: "team1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, resp.RemovedIds)
+	// Репозиторий не применил замены, например PR смержили в промежутке
+	assert.Len(t, resp.Reviews, 2)
+	assert.Equal(t, response.ReviewOutcomeSkipped, resp.Reviews[0].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_Delete_ClimbsToParentTeam(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	pool := subteamPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u1", Reviewers: []string{"u1"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, &dto.GetTeamDTO{TeamName: "team1"}).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"u1", "u2"}).Return(reviews, nil)
+	// Участники удаляемой команды состоят и в родительской, но выбывают из подбора
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backend", []*domain.User{
+			{Id: "u1", IsActive: true},
+			{Id: "b1", IsActive: true},
+		}),
+	}, nil)
+	mockRepo.On("Delete", mock.Anything, mock.MatchedBy(func(d *dto.DeleteTeamDTO) bool {
+		return len(d.Reassignments) == 1 && d.Reassignments[0].ReplacedBy == "b1" &&
+			d.Reassignments[0].Explanation.TeamName == "backend"
+	})).Return(&result.RemoveMembersResult{
+		RemovedIds: []string{"u1", "u2"},
+		Reassigned: []*dto.ReassignPrDTO{{PrId: "pr1", OldReviewerId: "u1", ReplacedBy: "b1"}},
+	}, nil)
+
+	resp, err := service.Delete(context.Background(), &request.DeleteTeamRequest{TeamName: "team1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", resp.Reviews[0].ReplacedBy)
+	mockRepo.AssertNotCalled(t, "SelectFallbackPools", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_Delete_NoCandidate(t *testing.T) {
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	pool := toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u1", Reviewers: []string{"u1"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, mock.Anything).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, mock.Anything).Return(reviews, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{}, nil)
+
+	_, err := service.Delete(context.Background(), &request.DeleteTeamRequest{TeamName: "team1"})
+
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrOpenReviewsNoCandidate.Code, domainErr.Code)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
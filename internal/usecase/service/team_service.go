@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
 
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
 	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
@@ -14,25 +18,51 @@ import (
 )
 
 var (
-	addTeamError = errors.New("add team error")
-	getTeamError = errors.New("get team error")
+	addTeamError            = errors.New("add team error")
+	getTeamError            = errors.New("get team error")
+	getTeamSettingsError    = errors.New("get team settings error")
+	updateTeamSettingsError = errors.New("update team settings error")
+	deactivateMembersError  = errors.New("deactivate team members error")
+	getCodeOwnersError      = errors.New("get code owners error")
+	setCodeOwnersError      = errors.New("set code owners error")
 )
 
 // Интерфейс репозитория
 type TeamRepository interface {
 	Add(ctx context.Context, dto *dto.AddTeamDTO) (*result.AddTeamResult, error)
 	Get(ctx context.Context, dto *dto.GetTeamDTO) (*result.GetTeamResult, error)
+	GetSettings(ctx context.Context, dto *dto.GetTeamDTO) (*result.TeamSettingsResult, error)
+	UpdateSettings(ctx context.Context, dto *dto.UpdateTeamSettingsDTO) (*result.TeamSettingsResult, error)
+	SelectTeamPool(ctx context.Context, dto *dto.GetTeamDTO) (*result.PotentialReviewersResult, error)
+	ReviewerPoolRepository
+	SelectOpenReviews(ctx context.Context, userIds []string) ([]*result.OpenReviewResult, error)
+	DeactivateMembers(ctx context.Context, dto *dto.DeactivateMembersDTO) (*result.DeactivateMembersResult, error)
+	GetCodeOwners(ctx context.Context, dto *dto.GetTeamDTO) (*result.CodeOwnersResult, error)
+	SetCodeOwners(ctx context.Context, dto *dto.SetCodeOwnersDTO) (*result.CodeOwnersResult, error)
+	AddMembers(ctx context.Context, dto *dto.AddTeamMembersDTO) (*result.GetTeamResult, error)
+	RemoveMember(ctx context.Context, dto *dto.RemoveTeamMemberDTO) (*result.RemoveMembersResult, error)
+	Rename(ctx context.Context, dto *dto.RenameTeamDTO) (*result.GetTeamResult, error)
+	Delete(ctx context.Context, dto *dto.DeleteTeamDTO) (*result.RemoveMembersResult, error)
+	SetParent(ctx context.Context, dto *dto.SetTeamParentDTO) (*result.GetTeamResult, error)
+	SetMemberRole(ctx context.Context, dto *dto.SetTeamMemberRoleDTO) (*result.GetTeamResult, error)
 }
 
 type TeamService struct {
-	repo TeamRepository
-	log  *zap.Logger
+	repo      TeamRepository
+	selectors *ReviewerSelectors
+	replacer  *reviewerReplacer
+	events    EventPublisher
+	log       *zap.Logger
 }
 
-func NewTeamService(repo TeamRepository, log *zap.Logger) *TeamService {
+// NewTeamService events может быть nil, тогда события о переназначениях не публикуются
+func NewTeamService(repo TeamRepository, selectors *ReviewerSelectors, events EventPublisher, log *zap.Logger) *TeamService {
 	return &TeamService{
-		repo: repo,
-		log:  log,
+		repo:      repo,
+		selectors: selectors,
+		replacer:  newReviewerReplacer(repo, selectors, log),
+		events:    events,
+		log:       log,
 	}
 }
 
@@ -40,8 +70,9 @@ func (s *TeamService) Add(ctx context.Context, req *request.AddTeamRequest) (*re
 	s.log.Info("add team request accepted", zap.String("team_name", req.TeamName))
 	// Собираем dto
 	dto := &dto.AddTeamDTO{
-		TeamName: req.TeamName,
-		Members:  req.Members,
+		TeamName:       req.TeamName,
+		Members:        req.Members,
+		AllowMultiTeam: req.AllowMultiTeam,
 	}
 
 	// Запрос в бд
@@ -53,6 +84,9 @@ func (s *TeamService) Add(ctx context.Context, req *request.AddTeamRequest) (*re
 		if errors.Is(err, repository.ErrAlreadyExists) {
 			return nil, WrapError(ErrTeamExists, err)
 		}
+		if errors.Is(err, repository.ErrMemberOfAnotherTeam) {
+			return nil, WrapError(ErrUserInAnotherTeam, err)
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, WrapError(ErrTeamNotFound, err)
 		}
@@ -64,8 +98,9 @@ func (s *TeamService) Add(ctx context.Context, req *request.AddTeamRequest) (*re
 	s.log.Info("team added", zap.String("team_name", res.TeamName), zap.Int("members", len(res.Members)))
 	// Ответ
 	return &response.AddTeamResponse{
-		TeamName: res.TeamName,
-		Members:  res.Members,
+		TeamName:       res.TeamName,
+		Members:        res.Members,
+		IgnoredUserIds: res.IgnoredUserIds,
 	}, nil
 }
 
@@ -95,8 +130,374 @@ func (s *TeamService) Get(ctx context.Context, req *request.GetTeamRequest) (*re
 
 	s.log.Info("team fetched", zap.String("team_name", res.TeamName), zap.Int("members", len(res.Members)))
 	// Ответ
-	return &response.GetTeamResponse{
-		TeamName: res.TeamName,
-		Members:  res.Members,
+	return toTeamTreeResponse(res), nil
+}
+
+func (s *TeamService) GetSettings(ctx context.Context, req *request.GetTeamSettingsRequest) (*response.TeamSettingsResponse, error) {
+	s.log.Info("get team settings request accepted", zap.String("team_name", req.TeamName))
+	// Собираем dto
+	dto := &dto.GetTeamDTO{
+		TeamName: req.TeamName,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.GetSettings(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to get team settings", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", getTeamSettingsError, err)
+	}
+
+	// Ответ
+	return toTeamSettingsResponse(res), nil
+}
+
+func (s *TeamService) UpdateSettings(ctx context.Context, req *request.UpdateTeamSettingsRequest) (*response.TeamSettingsResponse, error) {
+	s.log.Info("update team settings request accepted", zap.String("team_name", req.TeamName))
+
+	// Читаем текущие настройки, чтобы применить частичное обновление
+	current, err := s.repo.GetSettings(ctx, &dto.GetTeamDTO{TeamName: req.TeamName})
+	if err != nil {
+		s.log.Error("failed to load team settings before update", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", updateTeamSettingsError, err)
+	}
+	settings := effectiveTeamSettings(current.TeamId, current.Settings)
+
+	// Собираем dto из текущих настроек и переданных полей
+	dto := &dto.UpdateTeamSettingsDTO{
+		TeamName:          req.TeamName,
+		ReviewerCount:     settings.ReviewerCount,
+		Strategy:          settings.Strategy,
+		LeadUserId:        settings.LeadUserId,
+		RequireLead:       settings.RequireLead,
+		FallbackTeams:     current.FallbackTeams,
+		RequiredApprovals: settings.RequiredApprovals,
+		RequireTags:       settings.RequireTags,
+		ReviewSlaHours:    settings.ReviewSlaHours,
+		SlaPolicy:         settings.SlaPolicy,
+	}
+	if req.ReviewerCount != nil {
+		dto.ReviewerCount = *req.ReviewerCount
+	}
+	if req.Strategy != nil {
+		dto.Strategy = strings.TrimSpace(*req.Strategy)
+	}
+	if req.LeadUserId != nil {
+		dto.LeadUserId = strings.TrimSpace(*req.LeadUserId)
+	}
+	if req.RequireLead != nil {
+		dto.RequireLead = *req.RequireLead
+	}
+	if req.RequiredApprovals != nil {
+		dto.RequiredApprovals = *req.RequiredApprovals
+	}
+	if req.RequireTags != nil {
+		dto.RequireTags = *req.RequireTags
+	}
+	if req.ReviewSlaHours != nil {
+		dto.ReviewSlaHours = *req.ReviewSlaHours
+	}
+	if req.SlaPolicy != nil {
+		dto.SlaPolicy = strings.TrimSpace(*req.SlaPolicy)
+	}
+	if dto.SlaPolicy == "" {
+		dto.SlaPolicy = domain.SlaPolicyNotify
+	}
+	if req.FallbackTeams != nil {
+		fallbackTeams, err := normalizeFallbackTeams(req.TeamName, *req.FallbackTeams)
+		if err != nil {
+			return nil, WrapError(ErrInvalidTeamSettings, err)
+		}
+		dto.FallbackTeams = fallbackTeams
+	}
+
+	// Валидируем итоговые настройки
+	if dto.ReviewerCount < 0 || dto.ReviewerCount > maxReviewerCount {
+		return nil, WrapError(ErrInvalidTeamSettings, fmt.Errorf("reviewer_count must be between 0 and %d", maxReviewerCount))
+	}
+	if dto.RequiredApprovals < 0 || dto.RequiredApprovals > maxReviewerCount {
+		return nil, WrapError(ErrInvalidTeamSettings, fmt.Errorf("required_approvals must be between 0 and %d", maxReviewerCount))
+	}
+	if dto.ReviewSlaHours < 0 || dto.ReviewSlaHours > maxReviewSlaHours {
+		return nil, WrapError(ErrInvalidTeamSettings, fmt.Errorf("review_sla_hours must be between 0 and %d", maxReviewSlaHours))
+	}
+	if !slices.Contains(slaPolicies, dto.SlaPolicy) {
+		return nil, WrapError(ErrInvalidTeamSettings, fmt.Errorf("unknown sla_policy %q", dto.SlaPolicy))
+	}
+	if dto.Strategy != "" && !isKnownStrategy(dto.Strategy) {
+		return nil, WrapError(ErrInvalidTeamSettings, fmt.Errorf("%w: %q", unknownStrategyError, dto.Strategy))
+	}
+	if dto.RequireLead && dto.LeadUserId == "" {
+		return nil, WrapError(ErrInvalidTeamSettings, errors.New("require_lead needs lead_user_id"))
+	}
+
+	// Запрос в бд
+	res, err := s.repo.UpdateSettings(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to update team settings", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			return nil, WrapError(ErrLeadNotTeamMember, err)
+		}
+		if errors.Is(err, repository.ErrFallbackTeamMissing) {
+			return nil, WrapError(ErrFallbackTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", updateTeamSettingsError, err)
+	}
+
+	s.log.Info("team settings updated",
+		zap.String("team_name", res.TeamName),
+		zap.Int("reviewer_count", dto.ReviewerCount),
+		zap.String("strategy", dto.Strategy),
+	)
+	// Ответ
+	return toTeamSettingsResponse(res), nil
+}
+
+func (s *TeamService) GetCodeOwners(ctx context.Context, req *request.GetCodeOwnersRequest) (*response.CodeOwnersResponse, error) {
+	s.log.Info("get code owners request accepted", zap.String("team_name", req.TeamName))
+	// Собираем dto
+	dto := &dto.GetTeamDTO{
+		TeamName: req.TeamName,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.GetCodeOwners(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to get code owners", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", getCodeOwnersError, err)
+	}
+
+	// Ответ
+	return toCodeOwnersResponse(res), nil
+}
+
+// SetCodeOwners разбирает загруженный CODEOWNERS и заменяет им правила команды
+func (s *TeamService) SetCodeOwners(ctx context.Context, req *request.SetCodeOwnersRequest) (*response.CodeOwnersResponse, error) {
+	s.log.Info("set code owners request accepted", zap.String("team_name", req.TeamName))
+
+	rules, err := parseCodeOwners(req.Content)
+	if err != nil {
+		return nil, WrapError(ErrInvalidCodeOwners, err)
+	}
+
+	// Собираем dto
+	dto := &dto.SetCodeOwnersDTO{
+		TeamName: req.TeamName,
+		Rules:    rules,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.SetCodeOwners(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to set code owners", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			return nil, WrapError(ErrCodeOwnersNotTeamMembers, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", setCodeOwnersError, err)
+	}
+
+	s.log.Info("code owners updated", zap.String("team_name", res.TeamName), zap.Int("rules", len(res.Rules)))
+	// Ответ
+	return toCodeOwnersResponse(res), nil
+}
+
+// DeactivateMembers выключает набор участников команды и перераспределяет их открытые ревью
+// между оставшимися активными участниками. Все изменения применяются в одной транзакции
+func (s *TeamService) DeactivateMembers(ctx context.Context, req *request.DeactivateMembersRequest) (*response.DeactivateMembersResponse, error) {
+	s.log.Info("deactivate team members request accepted",
+		zap.String("team_name", req.TeamName),
+		zap.Int("users", len(req.UserIds)),
+	)
+
+	// Проверяем идентификаторы и убираем дубликаты
+	userIds := make([]string, 0, len(req.UserIds))
+	for _, rawId := range req.UserIds {
+		userId, err := normalizeID(rawId, "user_id")
+		if err != nil {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+		if !slices.Contains(userIds, userId) {
+			userIds = append(userIds, userId)
+		}
+	}
+	if len(userIds) == 0 {
+		return nil, WrapError(ErrEmptyUserIds, errors.New("no users to deactivate"))
+	}
+
+	// Читаем команду с нагрузкой участников
+	pool, err := s.repo.SelectTeamPool(ctx, &dto.GetTeamDTO{TeamName: req.TeamName})
+	if err != nil {
+		s.log.Error("failed to load team for deactivation", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", deactivateMembersError, err)
+	}
+
+	// Выключать можно только участников команды
+	var outsiders []string
+	for _, userId := range userIds {
+		if findCandidate(pool, userId) == nil {
+			outsiders = append(outsiders, userId)
+		}
+	}
+	if len(outsiders) > 0 {
+		return nil, WrapError(ErrUsersNotTeamMembers, fmt.Errorf("not members: %s", strings.Join(outsiders, ", ")))
+	}
+
+	// Читаем открытые ревью всех выключаемых пользователей одним запросом
+	reviews, err := s.repo.SelectOpenReviews(ctx, userIds)
+	if err != nil {
+		s.log.Error("failed to load open reviews for deactivation", zap.String("team_name", req.TeamName), zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", deactivateMembersError, err)
+	}
+
+	// Подбираем замену так же, как /pullRequest/reassign: в команде, родительских и резервных командах
+	reassignments, noCandidate, err := s.replacer.planReassignments(ctx, pool, reviews, userIds, domain.AssignReasonUserDeactivated)
+	if err != nil {
+		s.log.Error("failed to plan reassignments for deactivation", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, noLeadReviewerError) {
+			return nil, WrapError(ErrNoLeadCandidate, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", deactivateMembersError, err)
+	}
+
+	// Собираем dto
+	dto := &dto.DeactivateMembersDTO{
+		TeamId:        pool.TeamId,
+		TeamName:      pool.TeamName,
+		UserIds:       userIds,
+		Reassignments: reassignments,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.DeactivateMembers(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to deactivate team members", zap.String("team_name", req.TeamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			return nil, WrapError(ErrUsersNotTeamMembers, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", deactivateMembersError, err)
+	}
+
+	// Итог по каждому ревью в порядке планирования
+	outcomes := reviewOutcomes(reassignments, res.Reassigned, noCandidate)
+
+	s.log.Info("team members deactivated",
+		zap.String("team_name", pool.TeamName),
+		zap.Int("users", len(res.DeactivatedIds)),
+		zap.Int("reviews", len(reviews)),
+		zap.Int("reassigned", len(res.Reassigned)),
+		zap.Int("no_candidate", len(noCandidate)),
+	)
+	publishReassignments(ctx, s.events, reviews, res.Reassigned)
+	// Ответ
+	return &response.DeactivateMembersResponse{
+		TeamName:       pool.TeamName,
+		DeactivatedIds: userIds,
+		Reviews:        outcomes,
 	}, nil
 }
+
+func toTeamSettingsResponse(res *result.TeamSettingsResult) *response.TeamSettingsResponse {
+	settings := effectiveTeamSettings(res.TeamId, res.Settings)
+	fallbackTeams := res.FallbackTeams
+	if fallbackTeams == nil {
+		fallbackTeams = []string{}
+	}
+	return &response.TeamSettingsResponse{
+		TeamName:          res.TeamName,
+		ReviewerCount:     settings.ReviewerCount,
+		Strategy:          settings.Strategy,
+		LeadUserId:        settings.LeadUserId,
+		RequireLead:       settings.RequireLead,
+		FallbackTeams:     fallbackTeams,
+		RequiredApprovals: settings.RequiredApprovals,
+		RequireTags:       settings.RequireTags,
+		ReviewSlaHours:    settings.ReviewSlaHours,
+		SlaPolicy:         settings.SlaPolicy,
+	}
+}
+
+func toCodeOwnersResponse(res *result.CodeOwnersResult) *response.CodeOwnersResponse {
+	rules := make([]response.CodeOwnerRuleResponse, 0, len(res.Rules))
+	for _, rule := range res.Rules {
+		owners := rule.Owners
+		if owners == nil {
+			owners = []string{}
+		}
+		rules = append(rules, response.CodeOwnerRuleResponse{
+			Pattern: rule.Pattern,
+			Owners:  owners,
+		})
+	}
+	return &response.CodeOwnersResponse{
+		TeamName: res.TeamName,
+		Rules:    rules,
+	}
+}
+
+// normalizeFallbackTeams проверяет цепочку резервных команд: без пустых названий, повторов и самой команды
+func normalizeFallbackTeams(teamName string, raw []string) ([]string, error) {
+	fallbackTeams := make([]string, 0, len(raw))
+	for _, name := range raw {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.New("fallback team name is empty")
+		}
+		if name == teamName {
+			return nil, errors.New("team cannot be its own fallback")
+		}
+		if slices.Contains(fallbackTeams, name) {
+			return nil, fmt.Errorf("fallback team %s is duplicated", name)
+		}
+		fallbackTeams = append(fallbackTeams, name)
+	}
+	return fallbackTeams, nil
+}
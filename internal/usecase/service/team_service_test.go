@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
 	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -36,10 +38,154 @@ func (m *MockTeamRepository) Get(ctx context.Context, dto *dto.GetTeamDTO) (*res
 	return args.Get(0).(*result.GetTeamResult), args.Error(1)
 }
 
+func (m *MockTeamRepository) GetSettings(ctx context.Context, dto *dto.GetTeamDTO) (*result.TeamSettingsResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.TeamSettingsResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) UpdateSettings(ctx context.Context, dto *dto.UpdateTeamSettingsDTO) (*result.TeamSettingsResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.TeamSettingsResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SelectTeamPool(ctx context.Context, dto *dto.GetTeamDTO) (*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SelectPotentialReviewers(ctx context.Context, userId string) (*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SelectMemberPool(ctx context.Context, userId, teamName string) (*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, userId, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SelectFallbackPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SelectParentPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SelectRecentPairings(ctx context.Context, authorId string, window int) (map[string]int, error) {
+	args := m.Called(ctx, authorId, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockTeamRepository) SelectOpenReviews(ctx context.Context, userIds []string) ([]*result.OpenReviewResult, error) {
+	args := m.Called(ctx, userIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.OpenReviewResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) DeactivateMembers(ctx context.Context, dto *dto.DeactivateMembersDTO) (*result.DeactivateMembersResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.DeactivateMembersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetCodeOwners(ctx context.Context, dto *dto.GetTeamDTO) (*result.CodeOwnersResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.CodeOwnersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SetCodeOwners(ctx context.Context, dto *dto.SetCodeOwnersDTO) (*result.CodeOwnersResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.CodeOwnersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) AddMembers(ctx context.Context, dto *dto.AddTeamMembersDTO) (*result.GetTeamResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.GetTeamResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) RemoveMember(ctx context.Context, dto *dto.RemoveTeamMemberDTO) (*result.RemoveMembersResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.RemoveMembersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) Rename(ctx context.Context, dto *dto.RenameTeamDTO) (*result.GetTeamResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.GetTeamResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) Delete(ctx context.Context, dto *dto.DeleteTeamDTO) (*result.RemoveMembersResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.RemoveMembersResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SetParent(ctx context.Context, dto *dto.SetTeamParentDTO) (*result.GetTeamResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.GetTeamResult), args.Error(1)
+}
+
+func (m *MockTeamRepository) SetMemberRole(ctx context.Context, dto *dto.SetTeamMemberRoleDTO) (*result.GetTeamResult, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.GetTeamResult), args.Error(1)
+}
+
 func TestTeamService_Add_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockTeamRepository)
-	service := NewTeamService(mockRepo, logger)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
 
 	members := []*domain.User{
 		{
@@ -84,7 +230,7 @@ func TestTeamService_Add_Success(t *testing.T) {
 func TestTeamService_Add_TeamExists(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockTeamRepository)
-	service := NewTeamService(mockRepo, logger)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.AddTeamRequest{
 		TeamName: "team1",
@@ -103,10 +249,33 @@ func TestTeamService_Add_TeamExists(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTeamService_Add_MemberOfAnotherTeam(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	req := &request.AddTeamRequest{
+		TeamName: "team2",
+		Members:  []*domain.User{{Id: "user1", Name: "Alice", IsActive: true}},
+	}
+
+	mockRepo.On("Add", mock.Anything, mock.MatchedBy(func(d *dto.AddTeamDTO) bool {
+		return !d.AllowMultiTeam
+	})).Return(nil, fmt.Errorf("%w: user1", repository.ErrMemberOfAnotherTeam))
+
+	resp, err := service.Add(context.Background(), req)
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "USER_IN_TEAM", domainErr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestTeamService_Get_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockTeamRepository)
-	service := NewTeamService(mockRepo, logger)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.GetTeamRequest{
 		TeamName: "team1",
@@ -143,7 +312,7 @@ func TestTeamService_Get_Success(t *testing.T) {
 func TestTeamService_Get_TeamNotFound(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockTeamRepository)
-	service := NewTeamService(mockRepo, logger)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.GetTeamRequest{
 		TeamName: "nonexistent",
@@ -160,3 +329,493 @@ func TestTeamService_Get_TeamNotFound(t *testing.T) {
 	assert.Equal(t, "NOT_FOUND", domainErr.Code)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestTeamService_GetSettings_Defaults(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+	}, nil)
+
+	resp, err := service.GetSettings(context.Background(), &request.GetTeamSettingsRequest{TeamName: "team1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "team1", resp.TeamName)
+	assert.Equal(t, defaultReviewerCount, resp.ReviewerCount)
+	assert.Empty(t, resp.Strategy)
+	assert.False(t, resp.RequireLead)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_UpdateSettings_PartialUpdate(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+		Settings: &domain.TeamSettings{
+			TeamId:        "team1-id",
+			ReviewerCount: 3,
+			Strategy:      StrategyRandom,
+			LeadUserId:    "lead",
+		},
+	}, nil)
+	mockRepo.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(d *dto.UpdateTeamSettingsDTO) bool {
+		// reviewer_count и lead_user_id сохраняются, strategy и require_lead меняются
+		return d.TeamName == "team1" && d.ReviewerCount == 3 && d.LeadUserId == "lead" &&
+			d.Strategy == StrategyRoundRobin && d.RequireLead
+	})).Return(&result.TeamSettingsResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+		Settings: &domain.TeamSettings{
+			TeamId:        "team1-id",
+			ReviewerCount: 3,
+			Strategy:      StrategyRoundRobin,
+			LeadUserId:    "lead",
+			RequireLead:   true,
+		},
+	}, nil)
+
+	strategy := StrategyRoundRobin
+	requireLead := true
+	resp, err := service.UpdateSettings(context.Background(), &request.UpdateTeamSettingsRequest{
+		TeamName:    "team1",
+		Strategy:    &strategy,
+		RequireLead: &requireLead,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.ReviewerCount)
+	assert.Equal(t, StrategyRoundRobin, resp.Strategy)
+	assert.True(t, resp.RequireLead)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_UpdateSettings_ReviewSla(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+	}, nil)
+	mockRepo.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(d *dto.UpdateTeamSettingsDTO) bool {
+		return d.ReviewSlaHours == 24 && d.SlaPolicy == domain.SlaPolicyReassign
+	})).Return(&result.TeamSettingsResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+		Settings: &domain.TeamSettings{
+			TeamId:         "team1-id",
+			ReviewerCount:  defaultReviewerCount,
+			ReviewSlaHours: 24,
+			SlaPolicy:      domain.SlaPolicyReassign,
+		},
+	}, nil)
+
+	slaHours := 24
+	policy := " reassign "
+	resp, err := service.UpdateSettings(context.Background(), &request.UpdateTeamSettingsRequest{
+		TeamName:       "team1",
+		ReviewSlaHours: &slaHours,
+		SlaPolicy:      &policy,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 24, resp.ReviewSlaHours)
+	assert.Equal(t, domain.SlaPolicyReassign, resp.SlaPolicy)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_UpdateSettings_Invalid(t *testing.T) {
+	negative := -1
+	tooMany := maxReviewerCount + 1
+	unknown := "unknown"
+	requireLead := true
+	tooLongSla := maxReviewSlaHours + 1
+
+	tests := []struct {
+		name string
+		req  *request.UpdateTeamSettingsRequest
+	}{
+		{name: "negative reviewer count", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", ReviewerCount: &negative}},
+		{name: "too many reviewers", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", ReviewerCount: &tooMany}},
+		{name: "unknown strategy", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", Strategy: &unknown}},
+		{name: "require lead without lead", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", RequireLead: &requireLead}},
+		{name: "negative required approvals", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", RequiredApprovals: &negative}},
+		{name: "too many required approvals", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", RequiredApprovals: &tooMany}},
+		{name: "negative review sla", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", ReviewSlaHours: &negative}},
+		{name: "too long review sla", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", ReviewSlaHours: &tooLongSla}},
+		{name: "unknown sla policy", req: &request.UpdateTeamSettingsRequest{TeamName: "team1", SlaPolicy: &unknown}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTeamRepository)
+			service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+			mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+				TeamId:   "team1-id",
+				TeamName: "team1",
+			}, nil)
+
+			resp, err := service.UpdateSettings(context.Background(), tt.req)
+
+			assert.Nil(t, resp)
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+			mockRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTeamService_UpdateSettings_LeadNotMember(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+	}, nil)
+	mockRepo.On("UpdateSettings", mock.Anything, mock.Anything).Return(nil, repository.ErrNotTeamMember)
+
+	lead := "stranger"
+	resp, err := service.UpdateSettings(context.Background(), &request.UpdateTeamSettingsRequest{
+		TeamName:   "team1",
+		LeadUserId: &lead,
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrLeadNotTeamMember.Message, domainErr.Message)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_DeactivateMembers_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+		{Id: "u3", Name: "User 3", IsActive: true},
+		{Id: "u4", Name: "User 4", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u4", ReviewerId: "u1", Reviewers: []string{"u1", "u2"}},
+		{PrId: "pr2", AuthorId: "u3", ReviewerId: "u2", Reviewers: []string{"u2", "u4"}},
+		{PrId: "pr3", AuthorId: "u3", ReviewerId: "u1", Reviewers: []string{"u1", "u4"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, &dto.GetTeamDTO{TeamName: "team1"}).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"u1", "u2"}).Return(reviews, nil)
+	// Резервных команд нет
+	mockRepo.On("SelectFallbackPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{}, nil)
+	mockRepo.On("DeactivateMembers", mock.Anything, mock.MatchedBy(func(d *dto.DeactivateMembersDTO) bool {
+		return d.TeamId == "team1-id" && len(d.UserIds) == 2 && len(d.Reassignments) == 1
+	})).Return(&result.DeactivateMembersResult{
+		DeactivatedIds: []string{"u1", "u2"},
+		Reassigned: []*dto.ReassignPrDTO{
+			{PrId: "pr1", OldReviewerId: "u1", ReplacedBy: "u3"},
+		},
+	}, nil)
+
+	resp, err := service.DeactivateMembers(context.Background(), &request.DeactivateMembersRequest{
+		TeamName: "team1",
+		UserIds:  []string{"u1", " u2 ", "u1"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, resp.DeactivatedIds)
+	assert.Len(t, resp.Reviews, 3)
+
+	// pr1: автор u4, остальные ревьюеры выключаются, замена только u3
+	assert.Equal(t, "pr1", resp.Reviews[0].PrId)
+	assert.Equal(t, response.ReviewOutcomeReassigned, resp.Reviews[0].Status)
+	assert.Equal(t, "u3", resp.Reviews[0].ReplacedBy)
+	// pr2 и pr3: автор u3, u4 уже назначен, кандидатов нет ни в команде, ни в резервных
+	assert.Equal(t, response.ReviewOutcomeNoCandidate, resp.Reviews[1].Status)
+	assert.Equal(t, response.ReviewOutcomeNoCandidate, resp.Reviews[2].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_DeactivateMembers_SkippedWhenNotApplied(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	events := &recordingPublisher{}
+	service := NewTeamService(mockRepo, testSelectors(t), events, logger)
+
+	pool := toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+		{Id: "u2", Name: "User 2", IsActive: true},
+		{Id: "u3", Name: "User 3", IsActive: true},
+	})
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "u2", ReviewerId: "u1", Reviewers: []string{"u1"}},
+	}
+
+	mockRepo.On("SelectTeamPool", mock.Anything, mock.Anything).Return(pool, nil)
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"u1"}).Return(reviews, nil)
+	// PR смержили между планированием и применением
+	mockRepo.On("DeactivateMembers", mock.Anything, mock.Anything).Return(&result.DeactivateMembersResult{
+		DeactivatedIds: []string{"u1"},
+	}, nil)
+
+	resp, err := service.DeactivateMembers(context.Background(), &request.DeactivateMembersRequest{
+		TeamName: "team1",
+		UserIds:  []string{"u1"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Reviews, 1)
+	assert.Equal(t, response.ReviewOutcomeSkipped, resp.Reviews[0].Status)
+	assert.Empty(t, resp.Reviews[0].ReplacedBy)
+	// Непримененная замена не публикуется
+	assert.Empty(t, events.events)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_DeactivateMembers_NotMember(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SelectTeamPool", mock.Anything, mock.Anything).Return(toPool([]*domain.User{
+		{Id: "u1", Name: "User 1", IsActive: true},
+	}), nil)
+
+	resp, err := service.DeactivateMembers(context.Background(), &request.DeactivateMembersRequest{
+		TeamName: "team1",
+		UserIds:  []string{"u1", "stranger"},
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrUsersNotTeamMembers.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "DeactivateMembers", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_DeactivateMembers_EmptyUserIds(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	resp, err := service.DeactivateMembers(context.Background(), &request.DeactivateMembersRequest{
+		TeamName: "team1",
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrEmptyUserIds.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "SelectTeamPool", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_DeactivateMembers_TeamNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SelectTeamPool", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+
+	resp, err := service.DeactivateMembers(context.Background(), &request.DeactivateMembersRequest{
+		TeamName: "missing",
+		UserIds:  []string{"u1"},
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrTeamNotFound.Message, domainErr.Message)
+}
+
+func TestTeamService_UpdateSettings_FallbackTeams(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+		TeamId:        "team1-id",
+		TeamName:      "team1",
+		FallbackTeams: []string{"old"},
+	}, nil)
+	mockRepo.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(d *dto.UpdateTeamSettingsDTO) bool {
+		return assert.ObjectsAreEqual([]string{"platform", "infra"}, d.FallbackTeams)
+	})).Return(&result.TeamSettingsResult{
+		TeamId:        "team1-id",
+		TeamName:      "team1",
+		FallbackTeams: []string{"platform", "infra"},
+	}, nil)
+
+	fallbackTeams := []string{" platform ", "infra"}
+	resp, err := service.UpdateSettings(context.Background(), &request.UpdateTeamSettingsRequest{
+		TeamName:      "team1",
+		FallbackTeams: &fallbackTeams,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"platform", "infra"}, resp.FallbackTeams)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_UpdateSettings_KeepsFallbackTeams(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+		TeamId:        "team1-id",
+		TeamName:      "team1",
+		FallbackTeams: []string{"platform"},
+	}, nil)
+	mockRepo.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(d *dto.UpdateTeamSettingsDTO) bool {
+		return assert.ObjectsAreEqual([]string{"platform"}, d.FallbackTeams) && d.ReviewerCount == 1
+	})).Return(&result.TeamSettingsResult{
+		TeamId:        "team1-id",
+		TeamName:      "team1",
+		FallbackTeams: []string{"platform"},
+	}, nil)
+
+	count := 1
+	_, err := service.UpdateSettings(context.Background(), &request.UpdateTeamSettingsRequest{
+		TeamName:      "team1",
+		ReviewerCount: &count,
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_UpdateSettings_InvalidFallbackTeams(t *testing.T) {
+	tests := []struct {
+		name          string
+		fallbackTeams []string
+	}{
+		{name: "own team", fallbackTeams: []string{"team1"}},
+		{name: "duplicate", fallbackTeams: []string{"platform", "platform"}},
+		{name: "empty name", fallbackTeams: []string{" "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTeamRepository)
+			service := NewTeamService(mockRepo, testSelectors(t), nil, zap.NewNop())
+			mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+				TeamId:   "team1-id",
+				TeamName: "team1",
+			}, nil)
+
+			resp, err := service.UpdateSettings(context.Background(), &request.UpdateTeamSettingsRequest{
+				TeamName:      "team1",
+				FallbackTeams: &tt.fallbackTeams,
+			})
+
+			assert.Nil(t, resp)
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, ErrInvalidTeamSettings.Message, domainErr.Message)
+			mockRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTeamService_UpdateSettings_FallbackTeamNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetSettings", mock.Anything, mock.Anything).Return(&result.TeamSettingsResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+	}, nil)
+	mockRepo.On("UpdateSettings", mock.Anything, mock.Anything).Return(nil, repository.ErrFallbackTeamMissing)
+
+	fallbackTeams := []string{"ghost"}
+	resp, err := service.UpdateSettings(context.Background(), &request.UpdateTeamSettingsRequest{
+		TeamName:      "team1",
+		FallbackTeams: &fallbackTeams,
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrFallbackTeamNotFound.Message, domainErr.Message)
+}
+
+func TestTeamService_SetCodeOwners_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SetCodeOwners", mock.Anything, mock.MatchedBy(func(d *dto.SetCodeOwnersDTO) bool {
+		return d.TeamName == "team1" &&
+			len(d.Rules) == 2 &&
+			d.Rules[0].Pattern == "*.go" &&
+			assert.ObjectsAreEqual([]string{"u1", "u2"}, d.Rules[0].Owners) &&
+			d.Rules[1].Pattern == "/docs/"
+	})).Return(&result.CodeOwnersResult{
+		TeamId:   "team1-id",
+		TeamName: "team1",
+		Rules: []*domain.CodeOwnerRule{
+			{Pattern: "*.go", Owners: []string{"u1", "u2"}},
+			{Pattern: "/docs/", Owners: []string{"u3"}},
+		},
+	}, nil)
+
+	resp, err := service.SetCodeOwners(context.Background(), &request.SetCodeOwnersRequest{
+		TeamName: "team1",
+		Content:  "# backend\n*.go @u1 u2\n\n/docs/ u3 # docs team\n",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "team1", resp.TeamName)
+	assert.Len(t, resp.Rules, 2)
+	assert.Equal(t, []string{"u3"}, resp.Rules[1].Owners)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTeamService_SetCodeOwners_InvalidContent(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	resp, err := service.SetCodeOwners(context.Background(), &request.SetCodeOwnersRequest{
+		TeamName: "team1",
+		Content:  "*.go u1\n[ u2\n",
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrInvalidCodeOwners.Message, domainErr.Message)
+	assert.Contains(t, err.Error(), "line 2")
+	mockRepo.AssertNotCalled(t, "SetCodeOwners", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetCodeOwners_OwnerNotMember(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockTeamRepository)
+	service := NewTeamService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SetCodeOwners", mock.Anything, mock.Anything).Return(nil, repository.ErrNotTeamMember)
+
+	resp, err := service.SetCodeOwners(context.Background(), &request.SetCodeOwnersRequest{
+		TeamName: "team1",
+		Content:  "* stranger",
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrCodeOwnersNotTeamMembers.Message, domainErr.Message)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	createUserError = errors.New("create user error")
+	getUserError    = errors.New("get user error")
+	updateUserError = errors.New("update user error")
+	listUsersError  = errors.New("list users error")
+)
+
+// maxUsernameLength совпадает с длиной колонки users.name
+const maxUsernameLength = 255
+
+// Create создает пользователя без создания команды. Существующий пользователь не перезаписывается
+func (s *UserService) Create(ctx context.Context, req *request.CreateUserRequest) (*response.UserResponse, error) {
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrInvalidUser, err)
+	}
+	username, err := normalizeUsername(req.Username)
+	if err != nil {
+		return nil, WrapError(ErrInvalidUser, err)
+	}
+	teamName := strings.TrimSpace(req.TeamName)
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	s.log.Info("create user request accepted",
+		zap.String("user_id", userId),
+		zap.String("team_name", teamName),
+	)
+
+	// Запрос в бд
+	res, err := s.repo.Create(ctx, &dto.CreateUserDTO{
+		UserId:   userId,
+		Username: username,
+		TeamName: teamName,
+		IsActive: isActive,
+	})
+	if err != nil {
+		s.log.Error("failed to create user", zap.String("user_id", userId), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, WrapError(ErrUserExists, err)
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", createUserError, err)
+	}
+
+	s.log.Info("user created", zap.String("user_id", res.Id))
+	// Ответ
+	return toUserResponse(res), nil
+}
+
+func (s *UserService) Get(ctx context.Context, req *request.GetUserRequest) (*response.UserResponse, error) {
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+	s.log.Info("get user request accepted", zap.String("user_id", userId))
+
+	// Запрос в бд
+	res, err := s.repo.Get(ctx, userId)
+	if err != nil {
+		s.log.Error("failed to get user", zap.String("user_id", userId), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", getUserError, err)
+	}
+
+	// Ответ
+	return toUserResponse(res), nil
+}
+
+// Update меняет имя и активность пользователя. Команды пользователя меняются через эндпоинты команд
+func (s *UserService) Update(ctx context.Context, req *request.UpdateUserRequest) (*response.UserResponse, error) {
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+	if req.Username == nil && req.IsActive == nil {
+		return nil, WrapError(ErrEmptyUserUpdate, errors.New("username or is_active is required"))
+	}
+	var username *string
+	if req.Username != nil {
+		name, err := normalizeUsername(*req.Username)
+		if err != nil {
+			return nil, WrapError(ErrInvalidUser, err)
+		}
+		username = &name
+	}
+	s.log.Info("update user request accepted", zap.String("user_id", userId))
+
+	// Запрос в бд
+	res, err := s.repo.Update(ctx, &dto.UpdateUserDTO{
+		UserId:   userId,
+		Username: username,
+		IsActive: req.IsActive,
+	})
+	if err != nil {
+		s.log.Error("failed to update user", zap.String("user_id", userId), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", updateUserError, err)
+	}
+
+	s.log.Info("user updated", zap.String("user_id", res.Id), zap.Bool("is_active", res.IsActive))
+	// Ответ
+	return toUserResponse(res), nil
+}
+
+// List пользователи в порядке user_id, при необходимости только из команды и/или с заданной активностью
+func (s *UserService) List(ctx context.Context, req *request.ListUsersRequest) (*response.ListUsersResponse, error) {
+	teamName := strings.TrimSpace(req.TeamName)
+	var isActive *bool
+	if raw := strings.TrimSpace(req.IsActive); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, WrapError(ErrInvalidUser, fmt.Errorf("is_active must be true or false: %w", err))
+		}
+		isActive = &value
+	}
+	s.log.Info("list users request accepted", zap.String("team_name", teamName))
+
+	// Запрос в бд
+	res, err := s.repo.List(ctx, &dto.ListUsersDTO{
+		TeamName: teamName,
+		IsActive: isActive,
+	})
+	if err != nil {
+		s.log.Error("failed to list users", zap.String("team_name", teamName), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrTeamNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", listUsersError, err)
+	}
+
+	users := make([]*response.UserResponse, 0, len(res))
+	for _, user := range res {
+		users = append(users, toUserResponse(user))
+	}
+	// Ответ
+	return &response.ListUsersResponse{Users: users}, nil
+}
+
+// normalizeUsername имя без пробелов по краям длиной от 1 до maxUsernameLength символов
+func normalizeUsername(raw string) (string, error) {
+	username := strings.TrimSpace(raw)
+	if username == "" {
+		return "", errors.New("username is empty")
+	}
+	if utf8.RuneCountInString(username) > maxUsernameLength {
+		return "", fmt.Errorf("username must be at most %d characters", maxUsernameLength)
+	}
+	return username, nil
+}
+
+func toUserResponse(user *domain.User) *response.UserResponse {
+	return &response.UserResponse{
+		UserId:   user.Id,
+		Username: user.Name,
+		TeamName: user.TeamName,
+		Teams:    userTeams(user),
+		IsActive: user.IsActive,
+	}
+}
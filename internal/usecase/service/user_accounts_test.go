@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestUserService_Create_DefaultsToActive(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("Create", mock.Anything, &dto.CreateUserDTO{
+		UserId:   "u1",
+		Username: "Alice",
+		TeamName: "backend",
+		IsActive: true,
+	}).Return(&domain.User{Id: "u1", Name: "Alice", TeamName: "backend", Teams: []string{"backend"}, IsActive: true}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateUserRequest{
+		UserId:   " u1 ",
+		Username: " Alice ",
+		TeamName: "backend",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "backend", resp.TeamName)
+	assert.True(t, resp.IsActive)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_Create_RejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *request.CreateUserRequest
+	}{
+		{name: "empty user_id", req: &request.CreateUserRequest{UserId: " ", Username: "Alice"}},
+		{name: "empty username", req: &request.CreateUserRequest{UserId: "u1", Username: " "}},
+		{name: "long username", req: &request.CreateUserRequest{UserId: "u1", Username: strings.Repeat("я", maxUsernameLength+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+			resp, err := service.Create(context.Background(), tt.req)
+
+			assert.Nil(t, resp)
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUserService_Create_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		wantCode string
+	}{
+		{name: "user exists", repoErr: repository.ErrAlreadyExists, wantCode: "USER_EXISTS"},
+		{name: "team not found", repoErr: repository.ErrNotFound, wantCode: "NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+			mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil, tt.repoErr)
+
+			_, err := service.Create(context.Background(), &request.CreateUserRequest{UserId: "u1", Username: "Alice", TeamName: "backend"})
+
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, tt.wantCode, domainErr.Code)
+		})
+	}
+}
+
+func TestUserService_Update_RequiresField(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	resp, err := service.Update(context.Background(), &request.UpdateUserRequest{UserId: "u1"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUserService_Update_PassesOnlyGivenFields(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	inactive := false
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(d *dto.UpdateUserDTO) bool {
+		return d.UserId == "u1" && d.Username == nil && d.IsActive != nil && !*d.IsActive
+	})).Return(&domain.User{Id: "u1", Name: "Alice", IsActive: false}, nil)
+
+	resp, err := service.Update(context.Background(), &request.UpdateUserRequest{UserId: "u1", IsActive: &inactive})
+
+	assert.NoError(t, err)
+	assert.False(t, resp.IsActive)
+	assert.Equal(t, []string{}, resp.Teams)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_List_ParsesActivityFilter(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(d *dto.ListUsersDTO) bool {
+		return d.TeamName == "backend" && d.IsActive != nil && *d.IsActive
+	})).Return([]*domain.User{{Id: "u1", Name: "Alice", TeamName: "backend", IsActive: true}}, nil)
+
+	resp, err := service.List(context.Background(), &request.ListUsersRequest{TeamName: "backend", IsActive: "true"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 1)
+
+	// Некорректное значение фильтра не доходит до бд
+	_, err = service.List(context.Background(), &request.ListUsersRequest{IsActive: "maybe"})
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+	mockRepo.AssertNumberOfCalls(t, "List", 1)
+}
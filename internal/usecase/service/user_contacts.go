@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	getContactsError = errors.New("get user contacts error")
+	setContactsError = errors.New("set user contacts error")
+)
+
+// Ограничение колонок email и chat_handle в user_contacts
+const maxContactLength = 255
+
+func (s *UserService) GetContacts(ctx context.Context, req *request.GetContactsRequest) (*response.UserContactsResponse, error) {
+	s.log.Info("getContacts request accepted", zap.String("user_id", req.UserId))
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Запрос в бд
+	contact, err := s.repo.GetContacts(ctx, userId)
+	if err != nil {
+		s.log.Error("failed to get user contacts", zap.String("user_id", userId), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", getContactsError, err)
+	}
+
+	// Ответ
+	return toUserContactsResponse(contact), nil
+}
+
+func (s *UserService) SetContacts(ctx context.Context, req *request.SetContactsRequest) (*response.UserContactsResponse, error) {
+	s.log.Info("setContacts request accepted", zap.String("user_id", req.UserId))
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Проверяем контакты
+	email, chatHandle, err := normalizeContacts(req.Email, req.ChatHandle)
+	if err != nil {
+		return nil, WrapError(ErrInvalidContacts, err)
+	}
+
+	// Собираем dto
+	dto := &dto.SetUserContactsDTO{
+		UserId:        userId,
+		Email:         email,
+		ChatHandle:    chatHandle,
+		DigestEnabled: req.DigestEnabled == nil || *req.DigestEnabled,
+	}
+
+	// Запрос в бд
+	contact, err := s.repo.SetContacts(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to set user contacts", zap.String("user_id", userId), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", setContactsError, err)
+	}
+
+	s.log.Info("user contacts updated", zap.String("user_id", userId))
+
+	// Ответ
+	return toUserContactsResponse(contact), nil
+}
+
+// normalizeContacts проверяет email и обрезает пробелы. Пустые значения допустимы и удаляют контакт
+func normalizeContacts(rawEmail, rawChatHandle string) (string, string, error) {
+	email := strings.TrimSpace(rawEmail)
+	chatHandle := strings.TrimSpace(rawChatHandle)
+
+	if len(email) > maxContactLength {
+		return "", "", fmt.Errorf("email is longer than %d characters", maxContactLength)
+	}
+	if email != "" {
+		// Принимаем только голый адрес, без имени и угловых скобок
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return "", "", fmt.Errorf("email %q is not a valid address", email)
+		}
+	}
+
+	if len(chatHandle) > maxContactLength {
+		return "", "", fmt.Errorf("chat_handle is longer than %d characters", maxContactLength)
+	}
+	if strings.ContainsFunc(chatHandle, unicode.IsSpace) {
+		return "", "", fmt.Errorf("chat_handle %q contains whitespace", chatHandle)
+	}
+
+	return email, chatHandle, nil
+}
+
+func toUserContactsResponse(contact *domain.UserContact) *response.UserContactsResponse {
+	resp := &response.UserContactsResponse{
+		UserId:        contact.UserId,
+		Email:         contact.Email,
+		ChatHandle:    contact.ChatHandle,
+		DigestEnabled: contact.DigestEnabled,
+	}
+	if !contact.UpdatedAt.IsZero() {
+		resp.UpdatedAt = formatTime(contact.UpdatedAt)
+	}
+	return resp
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
 
@@ -16,26 +18,56 @@ import (
 )
 
 var (
-	setIsActiveError = errors.New("set user status error")
-	getReviewError   = errors.New("get review error")
+	setIsActiveError       = errors.New("set user status error")
+	getReviewError         = errors.New("get review error")
+	deactivateError        = errors.New("deactivate user error")
+	availabilityError      = errors.New("availability error")
+	setMaxOpenReviewsError = errors.New("set max open reviews error")
+	getTagsError           = errors.New("get user tags error")
+	setTagsError           = errors.New("set user tags error")
 )
 
+const maxAvailabilityReasonLength = 255
+
 // Интерфейс репозитория
 type UserRepository interface {
 	SetIsActive(ctx context.Context, d *dto.SetIsActiveDTO) (*domain.User, error)
 	GetReview(ctx context.Context, d *dto.GetReviewDTO) (*result.GetReviewResult, error)
+	ReviewerPoolRepository
 	CheckUserExists(ctx context.Context, userId string) (bool, error)
+	SelectOpenReviews(ctx context.Context, userIds []string) ([]*result.OpenReviewResult, error)
+	Deactivate(ctx context.Context, d *dto.DeactivateUserDTO) (*result.DeactivateUserResult, error)
+	SetMaxOpenReviews(ctx context.Context, d *dto.SetMaxOpenReviewsDTO) (*domain.User, error)
+	AddAvailability(ctx context.Context, d *dto.AddAvailabilityDTO) (*domain.Availability, error)
+	ListAvailability(ctx context.Context, d *dto.ListAvailabilityDTO) ([]*domain.Availability, error)
+	DeleteAvailability(ctx context.Context, d *dto.DeleteAvailabilityDTO) error
+	GetTags(ctx context.Context, userId string) ([]string, error)
+	SetTags(ctx context.Context, d *dto.SetUserTagsDTO) ([]string, error)
+	GetContacts(ctx context.Context, userId string) (*domain.UserContact, error)
+	SetContacts(ctx context.Context, d *dto.SetUserContactsDTO) (*domain.UserContact, error)
+	SetPrimaryTeam(ctx context.Context, d *dto.SetPrimaryTeamDTO) (*domain.User, error)
+	Create(ctx context.Context, d *dto.CreateUserDTO) (*domain.User, error)
+	Get(ctx context.Context, userId string) (*domain.User, error)
+	Update(ctx context.Context, d *dto.UpdateUserDTO) (*domain.User, error)
+	List(ctx context.Context, d *dto.ListUsersDTO) ([]*domain.User, error)
 }
 
 type UserService struct {
-	repo UserRepository
-	log  *zap.Logger
+	repo      UserRepository
+	selectors *ReviewerSelectors
+	replacer  *reviewerReplacer
+	events    EventPublisher
+	log       *zap.Logger
 }
 
-func NewUserService(repo UserRepository, log *zap.Logger) *UserService {
+// NewUserService events может быть nil, тогда события о переназначениях не публикуются
+func NewUserService(repo UserRepository, selectors *ReviewerSelectors, events EventPublisher, log *zap.Logger) *UserService {
 	return &UserService{
-		repo: repo,
-		log:  log,
+		repo:      repo,
+		selectors: selectors,
+		replacer:  newReviewerReplacer(repo, selectors, log),
+		events:    events,
+		log:       log,
 	}
 }
 
@@ -51,6 +83,11 @@ func (s *UserService) SetIsActive(ctx context.Context, req *request.SetIsActiveR
 		return nil, WrapError(ErrUserNotFound, err)
 	}
 
+	// При деактивации по запросу переназначаем открытые ревью пользователя
+	if !req.IsActive && req.ReassignReviews {
+		return s.deactivateWithReassign(ctx, userId)
+	}
+
 	// Собираем dto
 	dto := &dto.SetIsActiveDTO{
 		UserId:   userId,
@@ -86,10 +123,108 @@ func (s *UserService) SetIsActive(ctx context.Context, req *request.SetIsActiveR
 		UserId:   userId,
 		Username: res.Name,
 		TeamName: res.TeamName,
+		Teams:    userTeams(res),
 		IsActive: res.IsActive,
 	}, nil
 }
 
+// deactivateWithReassign выключает пользователя и в одной транзакции переназначает его открытые ревью
+func (s *UserService) deactivateWithReassign(ctx context.Context, userId string) (*response.SetIsActiveResponse, error) {
+	// Читаем открытые ревью пользователя
+	reviews, err := s.repo.SelectOpenReviews(ctx, []string{userId})
+	if err != nil {
+		s.log.Error("failed to load open reviews for deactivation",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("%w: %w", deactivateError, err)
+	}
+
+	// Подбираем замену тем же подбором, что и /pullRequest/reassign: команда пользователя,
+	// затем ее родительские и резервные команды
+	var reassignments []*dto.ReassignPrDTO
+	var noCandidate []*result.OpenReviewResult
+	if len(reviews) > 0 {
+		pool, err := s.repo.SelectPotentialReviewers(ctx, userId)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			s.log.Error("failed to load team for deactivation",
+				zap.String("user_id", userId),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("%w: %w", deactivateError, err)
+		}
+		// У пользователя без команды замена ищется только для лида PR с обязательным ревью лида
+		reassignments, noCandidate, err = s.replacer.planReassignments(ctx, pool, reviews, []string{userId}, domain.AssignReasonUserDeactivated)
+		if err != nil {
+			s.log.Error("failed to plan reviews reassignment",
+				zap.String("user_id", userId),
+				zap.Error(err),
+			)
+
+			// Маппим ошибки
+			if errors.Is(err, noLeadReviewerError) {
+				return nil, WrapError(ErrNoLeadCandidate, err)
+			}
+
+			// Неизвестная ошибка
+			return nil, fmt.Errorf("%w: %w", deactivateError, err)
+		}
+	}
+
+	// Собираем dto
+	dto := &dto.DeactivateUserDTO{
+		UserId:        userId,
+		Reassignments: reassignments,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.Deactivate(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to deactivate user",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", deactivateError, err)
+	}
+
+	reassigned := make([]response.ReviewReassignment, 0, len(res.Reassigned))
+	for _, r := range res.Reassigned {
+		reassigned = append(reassigned, response.ReviewReassignment{
+			PrId:       r.PrId,
+			ReplacedBy: r.ReplacedBy,
+		})
+	}
+	noCandidatePrs := make([]string, 0, len(noCandidate))
+	for _, review := range noCandidate {
+		noCandidatePrs = append(noCandidatePrs, review.PrId)
+	}
+
+	s.log.Info("user deactivated with reviews reassignment",
+		zap.String("user_id", userId),
+		zap.Int("reassigned", len(reassigned)),
+		zap.Strings("no_candidate", noCandidatePrs),
+	)
+	publishReassignments(ctx, s.events, reviews, res.Reassigned)
+
+	// Ответ
+	return &response.SetIsActiveResponse{
+		UserId:         userId,
+		Username:       res.User.Name,
+		TeamName:       res.User.TeamName,
+		Teams:          userTeams(res.User),
+		IsActive:       res.User.IsActive,
+		Reassigned:     reassigned,
+		NoCandidatePrs: noCandidatePrs,
+	}, nil
+}
+
 func (s *UserService) GetReview(ctx context.Context, req *request.GetReviewRequest) (*response.GetReviewResponse, error) {
 	s.log.Info("getReview request accepted",
 		zap.String("user_id", req.UserId),
@@ -147,3 +282,291 @@ func (s *UserService) GetReview(ctx context.Context, req *request.GetReviewReque
 		Prs:    res.Prs,
 	}, nil
 }
+
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, req *request.SetMaxOpenReviewsRequest) (*response.SetMaxOpenReviewsResponse, error) {
+	s.log.Info("setMaxOpenReviews request accepted",
+		zap.String("user_id", req.UserId),
+		zap.Any("max_open_reviews", req.MaxOpenReviews),
+	)
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Предел должен быть положительным, null снимает ограничение
+	if req.MaxOpenReviews != nil && *req.MaxOpenReviews <= 0 {
+		return nil, WrapError(ErrInvalidMaxOpenReviews, fmt.Errorf("got %d", *req.MaxOpenReviews))
+	}
+
+	// Собираем dto
+	dto := &dto.SetMaxOpenReviewsDTO{
+		UserId:         userId,
+		MaxOpenReviews: req.MaxOpenReviews,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.SetMaxOpenReviews(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to set user max open reviews",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", setMaxOpenReviewsError, err)
+	}
+
+	s.log.Info("user max open reviews updated", zap.String("user_id", userId))
+
+	// Ответ
+	return &response.SetMaxOpenReviewsResponse{
+		UserId:         res.Id,
+		Username:       res.Name,
+		TeamName:       res.TeamName,
+		Teams:          userTeams(res),
+		IsActive:       res.IsActive,
+		MaxOpenReviews: res.MaxOpenReviews,
+	}, nil
+}
+
+func (s *UserService) GetTags(ctx context.Context, req *request.GetTagsRequest) (*response.UserTagsResponse, error) {
+	s.log.Info("getTags request accepted", zap.String("user_id", req.UserId))
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Запрос в бд
+	tags, err := s.repo.GetTags(ctx, userId)
+	if err != nil {
+		s.log.Error("failed to get user tags", zap.String("user_id", userId), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", getTagsError, err)
+	}
+
+	// Ответ
+	return toUserTagsResponse(userId, tags), nil
+}
+
+func (s *UserService) SetTags(ctx context.Context, req *request.SetTagsRequest) (*response.UserTagsResponse, error) {
+	s.log.Info("setTags request accepted",
+		zap.String("user_id", req.UserId),
+		zap.Strings("tags", req.Tags),
+	)
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Проверяем теги
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, WrapError(ErrInvalidTags, err)
+	}
+
+	// Собираем dto
+	dto := &dto.SetUserTagsDTO{
+		UserId: userId,
+		Tags:   tags,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.SetTags(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to set user tags", zap.String("user_id", userId), zap.Error(err))
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", setTagsError, err)
+	}
+
+	s.log.Info("user tags updated", zap.String("user_id", userId), zap.Strings("tags", res))
+
+	// Ответ
+	return toUserTagsResponse(userId, res), nil
+}
+
+func toUserTagsResponse(userId string, tags []string) *response.UserTagsResponse {
+	if tags == nil {
+		tags = []string{}
+	}
+	return &response.UserTagsResponse{
+		UserId: userId,
+		Tags:   tags,
+	}
+}
+
+func (s *UserService) AddAvailability(ctx context.Context, req *request.AddAvailabilityRequest) (*response.AvailabilityResponse, error) {
+	s.log.Info("addAvailability request accepted",
+		zap.String("user_id", req.UserId),
+		zap.String("starts_at", req.StartsAt),
+		zap.String("ends_at", req.EndsAt),
+	)
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Проверяем период
+	startsAt, err := time.Parse(time.RFC3339, strings.TrimSpace(req.StartsAt))
+	if err != nil {
+		return nil, WrapError(ErrInvalidAvailability, fmt.Errorf("starts_at: %w", err))
+	}
+	endsAt, err := time.Parse(time.RFC3339, strings.TrimSpace(req.EndsAt))
+	if err != nil {
+		return nil, WrapError(ErrInvalidAvailability, fmt.Errorf("ends_at: %w", err))
+	}
+	if !endsAt.After(startsAt) {
+		return nil, WrapError(ErrInvalidAvailability, errors.New("ends_at must be after starts_at"))
+	}
+	if !endsAt.After(time.Now()) {
+		return nil, WrapError(ErrInvalidAvailability, errors.New("ends_at must be in the future"))
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > maxAvailabilityReasonLength {
+		return nil, WrapError(ErrInvalidAvailability, fmt.Errorf("reason must be at most %d characters", maxAvailabilityReasonLength))
+	}
+
+	// Собираем dto
+	dto := &dto.AddAvailabilityDTO{
+		UserId:   userId,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Reason:   reason,
+	}
+
+	// Запрос в бд
+	res, err := s.repo.AddAvailability(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to add availability period",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", availabilityError, err)
+	}
+
+	s.log.Info("availability period added",
+		zap.String("user_id", userId),
+		zap.String("availability_id", res.Id),
+	)
+
+	// Ответ
+	resp := toAvailabilityResponse(res, time.Now())
+	return &resp, nil
+}
+
+func (s *UserService) ListAvailability(ctx context.Context, req *request.ListAvailabilityRequest) (*response.ListAvailabilityResponse, error) {
+	s.log.Info("listAvailability request accepted", zap.String("user_id", req.UserId))
+
+	// Проверяем корректность идентификатора
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+
+	// Проверяем что пользователь существует
+	exists, err := s.repo.CheckUserExists(ctx, userId)
+	if err != nil {
+		s.log.Error("failed to check user existence",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("%w: %w", availabilityError, err)
+	}
+	if !exists {
+		return nil, WrapError(ErrUserNotFound, errors.New("user not found"))
+	}
+
+	// Запрос в бд
+	res, err := s.repo.ListAvailability(ctx, &dto.ListAvailabilityDTO{UserId: userId})
+	if err != nil {
+		s.log.Error("failed to list availability periods",
+			zap.String("user_id", userId),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("%w: %w", availabilityError, err)
+	}
+
+	now := time.Now()
+	periods := make([]response.AvailabilityResponse, 0, len(res))
+	for _, period := range res {
+		periods = append(periods, toAvailabilityResponse(period, now))
+	}
+
+	// Ответ
+	return &response.ListAvailabilityResponse{
+		UserId:  userId,
+		Periods: periods,
+	}, nil
+}
+
+func (s *UserService) DeleteAvailability(ctx context.Context, req *request.DeleteAvailabilityRequest) error {
+	s.log.Info("deleteAvailability request accepted", zap.String("availability_id", req.AvailabilityId))
+
+	// Проверяем корректность идентификатора
+	availabilityId, err := normalizeID(req.AvailabilityId, "availability_id")
+	if err != nil {
+		return WrapError(ErrAvailabilityNotFound, err)
+	}
+
+	// Запрос в бд
+	err = s.repo.DeleteAvailability(ctx, &dto.DeleteAvailabilityDTO{AvailabilityId: availabilityId})
+	if err != nil {
+		s.log.Error("failed to delete availability period",
+			zap.String("availability_id", availabilityId),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) {
+			return WrapError(ErrAvailabilityNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return fmt.Errorf("%w: %w", availabilityError, err)
+	}
+
+	s.log.Info("availability period deleted", zap.String("availability_id", availabilityId))
+	return nil
+}
+
+func toAvailabilityResponse(period *domain.Availability, now time.Time) response.AvailabilityResponse {
+	return response.AvailabilityResponse{
+		AvailabilityId: period.Id,
+		UserId:         period.UserId,
+		StartsAt:       formatTime(period.StartsAt),
+		EndsAt:         formatTime(period.EndsAt),
+		Reason:         period.Reason,
+		Current:        !period.StartsAt.After(now) && period.EndsAt.After(now),
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -41,10 +42,176 @@ func (m *MockUserRepository) CheckUserExists(ctx context.Context, userId string)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) SelectPotentialReviewers(ctx context.Context, userId string) (*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockUserRepository) SelectOpenReviews(ctx context.Context, userIds []string) ([]*result.OpenReviewResult, error) {
+	args := m.Called(ctx, userIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.OpenReviewResult), args.Error(1)
+}
+
+func (m *MockUserRepository) Deactivate(ctx context.Context, d *dto.DeactivateUserDTO) (*result.DeactivateUserResult, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.DeactivateUserResult), args.Error(1)
+}
+
+func (m *MockUserRepository) SetMaxOpenReviews(ctx context.Context, d *dto.SetMaxOpenReviewsDTO) (*domain.User, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) AddAvailability(ctx context.Context, d *dto.AddAvailabilityDTO) (*domain.Availability, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Availability), args.Error(1)
+}
+
+func (m *MockUserRepository) ListAvailability(ctx context.Context, d *dto.ListAvailabilityDTO) ([]*domain.Availability, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Availability), args.Error(1)
+}
+
+func (m *MockUserRepository) DeleteAvailability(ctx context.Context, d *dto.DeleteAvailabilityDTO) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetTags(ctx context.Context, userId string) ([]string, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserRepository) SetTags(ctx context.Context, d *dto.SetUserTagsDTO) ([]string, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserRepository) GetContacts(ctx context.Context, userId string) (*domain.UserContact, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserContact), args.Error(1)
+}
+
+func (m *MockUserRepository) SetContacts(ctx context.Context, d *dto.SetUserContactsDTO) (*domain.UserContact, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserContact), args.Error(1)
+}
+
+func (m *MockUserRepository) SetPrimaryTeam(ctx context.Context, d *dto.SetPrimaryTeamDTO) (*domain.User, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SelectMemberPool(ctx context.Context, userId, teamName string) (*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, userId, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockUserRepository) SelectFallbackPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockUserRepository) SelectParentPools(ctx context.Context, teamId string) ([]*result.PotentialReviewersResult, error) {
+	args := m.Called(ctx, teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.PotentialReviewersResult), args.Error(1)
+}
+
+func (m *MockUserRepository) SelectRecentPairings(ctx context.Context, authorId string, window int) (map[string]int, error) {
+	args := m.Called(ctx, authorId, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, d *dto.CreateUserDTO) (*domain.User, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Get(ctx context.Context, userId string) (*domain.User, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, d *dto.UpdateUserDTO) (*domain.User, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, d *dto.ListUsersDTO) ([]*domain.User, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+// recordingPublisher запоминает опубликованные события
+type recordingPublisher struct {
+	events []*domain.ActivityEvent
+}
+
+func (p *recordingPublisher) Publish(event *domain.ActivityEvent) {
+	p.events = append(p.events, event)
+}
+
 func TestUserService_SetIsActive_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo, logger)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.SetIsActiveRequest{
 		UserId:   "user1",
@@ -77,7 +244,7 @@ func TestUserService_SetIsActive_Success(t *testing.T) {
 func TestUserService_SetIsActive_UserNotFound(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo, logger)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.SetIsActiveRequest{
 		UserId:   "user1",
@@ -99,7 +266,7 @@ func TestUserService_SetIsActive_UserNotFound(t *testing.T) {
 func TestUserService_SetIsActive_InvalidInput(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo, logger)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.SetIsActiveRequest{
 		UserId:   "",
@@ -119,7 +286,7 @@ func TestUserService_SetIsActive_InvalidInput(t *testing.T) {
 func TestUserService_GetReview_Success(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo, logger)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.GetReviewRequest{
 		UserId: "user1",
@@ -167,7 +334,7 @@ func TestUserService_GetReview_Success(t *testing.T) {
 func TestUserService_GetReview_UserNotFound(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo, logger)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.GetReviewRequest{
 		UserId: "user1",
@@ -188,7 +355,7 @@ func TestUserService_GetReview_UserNotFound(t *testing.T) {
 func TestUserService_GetReview_InvalidInput(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo, logger)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
 
 	req := &request.GetReviewRequest{
 		UserId: "",
@@ -203,3 +370,540 @@ func TestUserService_GetReview_InvalidInput(t *testing.T) {
 	assert.Equal(t, "NOT_FOUND", domainErr.Code)
 	mockRepo.AssertNotCalled(t, "GetReview")
 }
+
+func TestUserService_SetIsActive_DeactivateWithReassign(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	events := &recordingPublisher{}
+	service := NewUserService(mockRepo, testSelectors(t), events, logger)
+
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author1", ReviewerId: "user1", Reviewers: []string{"user1", "user2"}},
+		{PrId: "pr2", AuthorId: "user2", ReviewerId: "user1", Reviewers: []string{"user1", "user3"}},
+	}
+	pool := toPool([]*domain.User{
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+		{Id: "user3", IsActive: true},
+		{Id: "author1", IsActive: true},
+	})
+
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"user1"}).Return(reviews, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "user1").Return(pool, nil)
+	mockRepo.On("Deactivate", mock.Anything, mock.MatchedBy(func(d *dto.DeactivateUserDTO) bool {
+		// pr1: занято user1, user2 и автор -> user3; pr2: занято user1, user3 и автор user2 -> author1
+		return d.UserId == "user1" && len(d.Reassignments) == 2 &&
+			d.Reassignments[0].PrId == "pr1" && d.Reassignments[0].ReplacedBy == "user3" &&
+			d.Reassignments[1].PrId == "pr2" && d.Reassignments[1].ReplacedBy == "author1"
+	})).Return(&result.DeactivateUserResult{
+		User: &domain.User{Id: "user1", Name: "User 1", TeamName: "team1", IsActive: false},
+		Reassigned: []*dto.ReassignPrDTO{
+			{PrId: "pr1", OldReviewerId: "user1", ReplacedBy: "user3"},
+			{PrId: "pr2", OldReviewerId: "user1", ReplacedBy: "author1"},
+		},
+	}, nil)
+
+	resp, err := service.SetIsActive(context.Background(), &request.SetIsActiveRequest{
+		UserId:          "user1",
+		IsActive:        false,
+		ReassignReviews: true,
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, resp.IsActive)
+	assert.Len(t, resp.Reassigned, 2)
+	assert.Empty(t, resp.NoCandidatePrs)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "SetIsActive", mock.Anything, mock.Anything)
+
+	// Примененные замены публикуются как reviewer.reassigned
+	if assert.Len(t, events.events, 2) {
+		assert.Equal(t, domain.EventReviewerReassigned, events.events[0].Type)
+		assert.Equal(t, []string{"author1", "user1", "user3"}, events.events[0].UserIds)
+		assert.Equal(t, []string{"user2", "user1", "author1"}, events.events[1].UserIds)
+	}
+}
+
+func TestUserService_SetIsActive_DeactivateWithReassign_PublishesOnlyApplied(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	events := &recordingPublisher{}
+	service := NewUserService(mockRepo, testSelectors(t), events, logger)
+
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author1", ReviewerId: "user1", Reviewers: []string{"user1", "user2"}},
+		{PrId: "pr2", AuthorId: "user2", ReviewerId: "user1", Reviewers: []string{"user1", "user3"}},
+	}
+	pool := toPool([]*domain.User{
+		{Id: "user1", IsActive: true},
+		{Id: "user2", IsActive: true},
+		{Id: "user3", IsActive: true},
+		{Id: "author1", IsActive: true},
+	})
+
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"user1"}).Return(reviews, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "user1").Return(pool, nil)
+	// pr2 смержили между планированием и применением, замена не применена
+	mockRepo.On("Deactivate", mock.Anything, mock.Anything).Return(&result.DeactivateUserResult{
+		User: &domain.User{Id: "user1", Name: "User 1", TeamName: "team1", IsActive: false},
+		Reassigned: []*dto.ReassignPrDTO{
+			{PrId: "pr1", OldReviewerId: "user1", ReplacedBy: "user3"},
+		},
+	}, nil)
+
+	resp, err := service.SetIsActive(context.Background(), &request.SetIsActiveRequest{
+		UserId:          "user1",
+		IsActive:        false,
+		ReassignReviews: true,
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Reassigned, 1) {
+		assert.Equal(t, "pr1", resp.Reassigned[0].PrId)
+	}
+	if assert.Len(t, events.events, 1) {
+		assert.Equal(t, "pr1", events.events[0].PrId)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetIsActive_DeactivateWithReassign_NoCandidate(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author1", ReviewerId: "user1", Reviewers: []string{"user1"}},
+	}
+	pool := toPool([]*domain.User{
+		{Id: "user1", IsActive: true},
+		{Id: "author1", IsActive: true},
+		{Id: "user2", IsActive: false},
+	})
+
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"user1"}).Return(reviews, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "user1").Return(pool, nil)
+	mockRepo.On("SelectFallbackPools", mock.Anything, mock.Anything).Return([]*result.PotentialReviewersResult{}, nil)
+	mockRepo.On("Deactivate", mock.Anything, mock.MatchedBy(func(d *dto.DeactivateUserDTO) bool {
+		return d.UserId == "user1" && len(d.Reassignments) == 0
+	})).Return(&result.DeactivateUserResult{
+		User: &domain.User{Id: "user1", IsActive: false},
+	}, nil)
+
+	resp, err := service.SetIsActive(context.Background(), &request.SetIsActiveRequest{
+		UserId:          "user1",
+		IsActive:        false,
+		ReassignReviews: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Reassigned)
+	assert.Equal(t, []string{"pr1"}, resp.NoCandidatePrs)
+	mockRepo.AssertExpectations(t)
+}
+
+// Замена при выключении подбирается так же, как в /pullRequest/reassign: с подъемом к родительским командам
+func TestUserService_SetIsActive_DeactivateWithReassign_ClimbsToParentTeam(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+	reviews := []*result.OpenReviewResult{
+		{PrId: "pr1", AuthorId: "author1", ReviewerId: "user1", Reviewers: []string{"user1"}},
+	}
+	pool := toPool([]*domain.User{
+		{Id: "user1", IsActive: true},
+		{Id: "author1", IsActive: true},
+	})
+	pool.ParentId = "backend-id"
+
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"user1"}).Return(reviews, nil)
+	mockRepo.On("SelectPotentialReviewers", mock.Anything, "user1").Return(pool, nil)
+	mockRepo.On("SelectParentPools", mock.Anything, "team1-id").Return([]*result.PotentialReviewersResult{
+		fallbackPool("backend", []*domain.User{{Id: "b1", IsActive: true}}),
+	}, nil)
+	mockRepo.On("Deactivate", mock.Anything, mock.MatchedBy(func(d *dto.DeactivateUserDTO) bool {
+		return len(d.Reassignments) == 1 && d.Reassignments[0].ReplacedBy == "b1" &&
+			d.Reassignments[0].Reason == domain.AssignReasonUserDeactivated
+	})).Return(&result.DeactivateUserResult{
+		User:       &domain.User{Id: "user1", IsActive: false},
+		Reassigned: []*dto.ReassignPrDTO{{PrId: "pr1", OldReviewerId: "user1", ReplacedBy: "b1"}},
+	}, nil)
+
+	resp, err := service.SetIsActive(context.Background(), &request.SetIsActiveRequest{
+		UserId:          "user1",
+		IsActive:        false,
+		ReassignReviews: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Reassigned, 1)
+	assert.Empty(t, resp.NoCandidatePrs)
+	mockRepo.AssertNotCalled(t, "SelectFallbackPools", mock.Anything, mock.Anything)
+}
+
+func TestUserService_SetIsActive_DeactivateWithReassign_UserNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SelectOpenReviews", mock.Anything, []string{"ghost"}).Return([]*result.OpenReviewResult{}, nil)
+	mockRepo.On("Deactivate", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+
+	resp, err := service.SetIsActive(context.Background(), &request.SetIsActiveRequest{
+		UserId:          "ghost",
+		IsActive:        false,
+		ReassignReviews: true,
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+	mockRepo.AssertNotCalled(t, "SelectPotentialReviewers", mock.Anything, mock.Anything)
+}
+
+func TestUserService_AddAvailability_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	startsAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	endsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	mockRepo.On("AddAvailability", mock.Anything, mock.MatchedBy(func(d *dto.AddAvailabilityDTO) bool {
+		return d.UserId == "user1" && d.StartsAt.Equal(startsAt) && d.EndsAt.Equal(endsAt) && d.Reason == "vacation"
+	})).Return(&domain.Availability{
+		Id:       "period1",
+		UserId:   "user1",
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Reason:   "vacation",
+	}, nil)
+
+	resp, err := service.AddAvailability(context.Background(), &request.AddAvailabilityRequest{
+		UserId:   "user1",
+		StartsAt: startsAt.Format(time.RFC3339),
+		EndsAt:   endsAt.Format(time.RFC3339),
+		Reason:   " vacation ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "period1", resp.AvailabilityId)
+	assert.True(t, resp.Current)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_AddAvailability_InvalidPeriod(t *testing.T) {
+	future := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	later := time.Now().Add(72 * time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	longAgo := time.Now().Add(-72 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name string
+		req  *request.AddAvailabilityRequest
+	}{
+		{name: "bad starts_at", req: &request.AddAvailabilityRequest{UserId: "user1", StartsAt: "tomorrow", EndsAt: later}},
+		{name: "missing ends_at", req: &request.AddAvailabilityRequest{UserId: "user1", StartsAt: future}},
+		{name: "ends before start", req: &request.AddAvailabilityRequest{UserId: "user1", StartsAt: later, EndsAt: future}},
+		{name: "already over", req: &request.AddAvailabilityRequest{UserId: "user1", StartsAt: longAgo, EndsAt: past}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			service := NewUserService(mockRepo, testSelectors(t), nil, zap.NewNop())
+
+			resp, err := service.AddAvailability(context.Background(), tt.req)
+
+			assert.Nil(t, resp)
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, ErrInvalidAvailability.Message, domainErr.Message)
+			mockRepo.AssertNotCalled(t, "AddAvailability", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUserService_AddAvailability_UserNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("AddAvailability", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+
+	resp, err := service.AddAvailability(context.Background(), &request.AddAvailabilityRequest{
+		UserId:   "ghost",
+		StartsAt: time.Now().UTC().Format(time.RFC3339),
+		EndsAt:   time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrUserNotFound.Message, domainErr.Message)
+}
+
+func TestUserService_ListAvailability_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	now := time.Now()
+	mockRepo.On("CheckUserExists", mock.Anything, "user1").Return(true, nil)
+	mockRepo.On("ListAvailability", mock.Anything, &dto.ListAvailabilityDTO{UserId: "user1"}).Return([]*domain.Availability{
+		{Id: "current", UserId: "user1", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{Id: "planned", UserId: "user1", StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(48 * time.Hour)},
+	}, nil)
+
+	resp, err := service.ListAvailability(context.Background(), &request.ListAvailabilityRequest{UserId: "user1"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Periods, 2)
+	assert.True(t, resp.Periods[0].Current)
+	assert.False(t, resp.Periods[1].Current)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_DeleteAvailability_NotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("DeleteAvailability", mock.Anything, &dto.DeleteAvailabilityDTO{AvailabilityId: "missing"}).Return(repository.ErrNotFound)
+
+	err := service.DeleteAvailability(context.Background(), &request.DeleteAvailabilityRequest{AvailabilityId: "missing"})
+
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrAvailabilityNotFound.Message, domainErr.Message)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetMaxOpenReviews_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	limit := 3
+	mockRepo.On("SetMaxOpenReviews", mock.Anything, &dto.SetMaxOpenReviewsDTO{UserId: "user1", MaxOpenReviews: &limit}).Return(&domain.User{
+		Id:             "user1",
+		Name:           "User 1",
+		TeamName:       "team1",
+		IsActive:       true,
+		MaxOpenReviews: &limit,
+	}, nil)
+
+	resp, err := service.SetMaxOpenReviews(context.Background(), &request.SetMaxOpenReviewsRequest{
+		UserId:         "user1",
+		MaxOpenReviews: &limit,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &limit, resp.MaxOpenReviews)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetMaxOpenReviews_Clear(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SetMaxOpenReviews", mock.Anything, &dto.SetMaxOpenReviewsDTO{UserId: "user1"}).Return(&domain.User{
+		Id:       "user1",
+		Name:     "User 1",
+		IsActive: true,
+	}, nil)
+
+	resp, err := service.SetMaxOpenReviews(context.Background(), &request.SetMaxOpenReviewsRequest{UserId: "user1"})
+
+	assert.NoError(t, err)
+	assert.Nil(t, resp.MaxOpenReviews)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetMaxOpenReviews_Invalid(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	zero := 0
+	resp, err := service.SetMaxOpenReviews(context.Background(), &request.SetMaxOpenReviewsRequest{
+		UserId:         "user1",
+		MaxOpenReviews: &zero,
+	})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrInvalidMaxOpenReviews.Message, domainErr.Message)
+	mockRepo.AssertNotCalled(t, "SetMaxOpenReviews", mock.Anything, mock.Anything)
+}
+
+func TestUserService_SetTags_Normalizes(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SetTags", mock.Anything, &dto.SetUserTagsDTO{
+		UserId: "u1",
+		Tags:   []string{"c++", "go", "postgres"},
+	}).Return([]string{"c++", "go", "postgres"}, nil)
+
+	resp, err := service.SetTags(context.Background(), &request.SetTagsRequest{
+		UserId: " u1 ",
+		Tags:   []string{"Postgres", " go", "c++", "GO"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", resp.UserId)
+	assert.Equal(t, []string{"c++", "go", "postgres"}, resp.Tags)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetTags_Invalid(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	for _, tags := range [][]string{{""}, {"front end"}, {"бэкенд"}} {
+		resp, err := service.SetTags(context.Background(), &request.SetTagsRequest{UserId: "u1", Tags: tags})
+		assert.Nil(t, resp)
+		var domainErr *DomainError
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, ErrInvalidTags.Message, domainErr.Message)
+	}
+	mockRepo.AssertNotCalled(t, "SetTags", mock.Anything, mock.Anything)
+}
+
+func TestUserService_GetTags_UserNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("GetTags", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+
+	resp, err := service.GetTags(context.Background(), &request.GetTagsRequest{UserId: "missing"})
+
+	assert.Nil(t, resp)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrUserNotFound.Code, domainErr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetContacts_Normalizes(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	updatedAt := time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC)
+	mockRepo.On("SetContacts", mock.Anything, mock.MatchedBy(func(d *dto.SetUserContactsDTO) bool {
+		return d.UserId == "u1" && d.Email == "alice@example.com" && d.ChatHandle == "@alice" && d.DigestEnabled
+	})).Return(&domain.UserContact{
+		UserId:        "u1",
+		Email:         "alice@example.com",
+		ChatHandle:    "@alice",
+		DigestEnabled: true,
+		UpdatedAt:     updatedAt,
+	}, nil)
+
+	resp, err := service.SetContacts(context.Background(), &request.SetContactsRequest{
+		UserId:     " u1 ",
+		Email:      " alice@example.com ",
+		ChatHandle: "@alice",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "alice@example.com", resp.Email)
+	assert.True(t, resp.DigestEnabled)
+	assert.Equal(t, "2025-01-08T12:00:00Z", resp.UpdatedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetContacts_Invalid(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	cases := map[string]*request.SetContactsRequest{
+		"bad email":          {UserId: "u1", Email: "not-an-email"},
+		"email with name":    {UserId: "u1", Email: "Alice <alice@example.com>"},
+		"handle with spaces": {UserId: "u1", ChatHandle: "@alice smith"},
+		"long handle":        {UserId: "u1", ChatHandle: strings.Repeat("a", 256)},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.SetContacts(context.Background(), req)
+
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, ErrInvalidContacts.Code, domainErr.Code)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "SetContacts", mock.Anything, mock.Anything)
+}
+
+func TestUserService_SetContacts_UserNotFound(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SetContacts", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+
+	_, err := service.SetContacts(context.Background(), &request.SetContactsRequest{UserId: "ghost", Email: "ghost@example.com"})
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, ErrUserNotFound.Code, domainErr.Code)
+}
+
+func TestUserService_SetPrimaryTeam_Success(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SetPrimaryTeam", mock.Anything, &dto.SetPrimaryTeamDTO{UserId: "user1", TeamName: "payments"}).Return(&domain.User{
+		Id:       "user1",
+		Name:     "Test User",
+		TeamName: "payments",
+		Teams:    []string{"payments", "backend"},
+		IsActive: true,
+	}, nil)
+
+	resp, err := service.SetPrimaryTeam(context.Background(), &request.SetPrimaryTeamRequest{UserId: " user1 ", TeamName: " payments "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", resp.TeamName)
+	assert.Equal(t, []string{"payments", "backend"}, resp.Teams)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetPrimaryTeam_Errors(t *testing.T) {
+	logger := zap.NewNop()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, testSelectors(t), nil, logger)
+
+	mockRepo.On("SetPrimaryTeam", mock.Anything, &dto.SetPrimaryTeamDTO{UserId: "user1", TeamName: "docs"}).Return(nil, repository.ErrNotTeamMember)
+	mockRepo.On("SetPrimaryTeam", mock.Anything, &dto.SetPrimaryTeamDTO{UserId: "ghost", TeamName: "docs"}).Return(nil, repository.ErrNotFound)
+
+	tests := []struct {
+		name string
+		req  *request.SetPrimaryTeamRequest
+		want *DomainError
+	}{
+		{name: "empty team", req: &request.SetPrimaryTeamRequest{UserId: "user1"}, want: ErrInvalidTeamName},
+		{name: "not a member", req: &request.SetPrimaryTeamRequest{UserId: "user1", TeamName: "docs"}, want: ErrNotTeamMember},
+		{name: "user not found", req: &request.SetPrimaryTeamRequest{UserId: "ghost", TeamName: "docs"}, want: ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SetPrimaryTeam(context.Background(), tt.req)
+
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, tt.want.Message, domainErr.Message)
+		})
+	}
+}
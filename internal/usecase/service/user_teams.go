@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var setPrimaryTeamError = errors.New("set primary team error")
+
+// SetPrimaryTeam делает одну из команд пользователя основной: из нее подбираются ревьюеры его PR
+// и замены, когда он сам уходит с ревью
+func (s *UserService) SetPrimaryTeam(ctx context.Context, req *request.SetPrimaryTeamRequest) (*response.UserResponse, error) {
+	userId, err := normalizeID(req.UserId, "user_id")
+	if err != nil {
+		return nil, WrapError(ErrUserNotFound, err)
+	}
+	teamName := strings.TrimSpace(req.TeamName)
+	if teamName == "" {
+		return nil, WrapError(ErrInvalidTeamName, errors.New("team_name is empty"))
+	}
+	s.log.Info("set primary team request accepted",
+		zap.String("user_id", userId),
+		zap.String("team_name", teamName),
+	)
+
+	// Запрос в бд
+	res, err := s.repo.SetPrimaryTeam(ctx, &dto.SetPrimaryTeamDTO{
+		UserId:   userId,
+		TeamName: teamName,
+	})
+	if err != nil {
+		s.log.Error("failed to set primary team",
+			zap.String("user_id", userId),
+			zap.String("team_name", teamName),
+			zap.Error(err),
+		)
+
+		// Маппим ошибки
+		if errors.Is(err, repository.ErrNotTeamMember) {
+			return nil, WrapError(ErrNotTeamMember, err)
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrUserNotFound, err)
+		}
+
+		// Неизвестная ошибка
+		return nil, fmt.Errorf("%w: %w", setPrimaryTeamError, err)
+	}
+
+	s.log.Info("primary team updated", zap.String("user_id", userId), zap.String("team_name", res.TeamName))
+
+	// Ответ
+	return toUserResponse(res), nil
+}
+
+// userTeams команды пользователя для ответа, пустой список вместо nil
+func userTeams(user *domain.User) []string {
+	if user.Teams == nil {
+		return []string{}
+	}
+	return user.Teams
+}
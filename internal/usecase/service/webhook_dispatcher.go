@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"go.uber.org/zap"
+)
+
+// Заголовки запроса на вебхук
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// Сколько текста ошибки сохраняем в доставке
+	maxDeliveryErrorLength = 1024
+	// Сколько тела ответа вычитываем, чтобы переиспользовать соединение
+	maxResponseDrain = 64 << 10
+)
+
+// DeliveryRepository очередь доставок, которую разбирает диспетчер
+type DeliveryRepository interface {
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*result.DeliveryJob, error)
+	MarkDelivered(ctx context.Context, deliveryId int64) error
+	MarkFailed(ctx context.Context, d *dto.DeliveryFailureDTO) error
+}
+
+// HTTPClient отправляет запросы на вебхуки, в тестах подменяется
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type WebhookDispatcherConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// После MaxAttempts неудачных попыток доставка переходит в DEAD
+	MaxAttempts int
+	// Задержка перед повтором: BaseBackoff * 2^(попытка-1), но не больше MaxBackoff
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	RequestTimeout time.Duration
+}
+
+// webhookEnvelope тело запроса на вебхук
+type webhookEnvelope struct {
+	Id        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookDispatcher доставляет события из outbox на зарегистрированные вебхуки
+type WebhookDispatcher struct {
+	repo   DeliveryRepository
+	client HTTPClient
+	cfg    WebhookDispatcherConfig
+	log    *zap.Logger
+	now    func() time.Time
+}
+
+func NewWebhookDispatcher(repo DeliveryRepository, client HTTPClient, cfg WebhookDispatcherConfig, log *zap.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: client,
+		cfg:    cfg,
+		log:    log,
+		now:    time.Now,
+	}
+}
+
+// Run разбирает очередь до отмены ctx. Уже захваченные доставки при остановке отправляются до конца
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	d.log.Info("webhook dispatcher started",
+		zap.Duration("poll_interval", d.cfg.PollInterval),
+		zap.Int("max_attempts", d.cfg.MaxAttempts),
+	)
+
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Пока пачки полные, разбираем очередь без ожидания следующего тика
+		for ctx.Err() == nil {
+			processed, err := d.DispatchOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					d.log.Error("webhook dispatch failed", zap.Error(err))
+				}
+				break
+			}
+			if processed < d.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			d.log.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce захватывает одну пачку доставок, отправляет их параллельно и возвращает размер пачки
+func (d *WebhookDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	// Аренда покрывает отправку с запасом, после нее доставку сможет взять другой экземпляр
+	jobs, err := d.repo.ClaimDeliveries(ctx, d.cfg.BatchSize, 2*d.cfg.RequestTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	// Захваченные доставки завершаем и при остановке сервиса, иначе попытка будет потеряна
+	workCtx := context.WithoutCancel(ctx)
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job *result.DeliveryJob) {
+			defer wg.Done()
+			d.process(workCtx, job)
+		}(job)
+	}
+	wg.Wait()
+
+	return len(jobs), nil
+}
+
+func (d *WebhookDispatcher) process(ctx context.Context, job *result.DeliveryJob) {
+	sendErr := d.send(ctx, job)
+	if sendErr == nil {
+		if err := d.repo.MarkDelivered(ctx, job.DeliveryId); err != nil {
+			d.log.Error("failed to mark webhook delivery delivered", zap.Int64("delivery_id", job.DeliveryId), zap.Error(err))
+			return
+		}
+		d.log.Debug("webhook event delivered",
+			zap.Int64("delivery_id", job.DeliveryId),
+			zap.String("webhook_id", job.WebhookId),
+			zap.String("event_type", job.Event.Type),
+			zap.Int("attempt", job.Attempt),
+		)
+		return
+	}
+
+	failure := d.failure(job, sendErr)
+	if err := d.repo.MarkFailed(ctx, failure); err != nil {
+		d.log.Error("failed to record webhook delivery failure", zap.Int64("delivery_id", job.DeliveryId), zap.Error(err))
+		return
+	}
+
+	if failure.Status == domain.WebhookDeliveryDead {
+		d.log.Error("webhook delivery moved to dead-letter",
+			zap.Int64("delivery_id", job.DeliveryId),
+			zap.String("webhook_id", job.WebhookId),
+			zap.Int("attempts", job.Attempt),
+			zap.Error(sendErr),
+		)
+		return
+	}
+	d.log.Warn("webhook delivery failed, will retry",
+		zap.Int64("delivery_id", job.DeliveryId),
+		zap.String("webhook_id", job.WebhookId),
+		zap.Int("attempt", job.Attempt),
+		zap.Duration("retry_in", failure.RetryIn),
+		zap.Error(sendErr),
+	)
+}
+
+// failure решает судьбу неудачной попытки: повтор с экспоненциальной задержкой или dead-letter
+func (d *WebhookDispatcher) failure(job *result.DeliveryJob, sendErr error) *dto.DeliveryFailureDTO {
+	message := sendErr.Error()
+	if len(message) > maxDeliveryErrorLength {
+		message = message[:maxDeliveryErrorLength]
+	}
+
+	if job.Attempt >= d.cfg.MaxAttempts {
+		return &dto.DeliveryFailureDTO{
+			DeliveryId: job.DeliveryId,
+			Status:     domain.WebhookDeliveryDead,
+			Error:      message,
+		}
+	}
+	return &dto.DeliveryFailureDTO{
+		DeliveryId: job.DeliveryId,
+		Status:     domain.WebhookDeliveryPending,
+		RetryIn:    backoffDelay(job.Attempt, d.cfg.BaseBackoff, d.cfg.MaxBackoff),
+		Error:      message,
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, job *result.DeliveryJob) error {
+	body, err := json.Marshal(&webhookEnvelope{
+		Id:        job.Event.Id,
+		Type:      job.Event.Type,
+		CreatedAt: formatTime(job.Event.CreatedAt),
+		Data:      job.Event.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, job.Event.Type)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(job.DeliveryId, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(job.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload подпись тела запроса: HMAC-SHA256 от "<timestamp>.<body>" в hex.
+// Метка времени в подписи не дает переиграть перехваченный запрос позже
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoffDelay задержка перед следующей попыткой после попытки с номером attempt
+func backoffDelay(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
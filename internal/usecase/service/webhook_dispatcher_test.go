@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockDeliveryRepository мок очереди доставок для тестов
+type MockDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*result.DeliveryJob, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*result.DeliveryJob), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) MarkDelivered(ctx context.Context, deliveryId int64) error {
+	args := m.Called(ctx, deliveryId)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryRepository) MarkFailed(ctx context.Context, d *dto.DeliveryFailureDTO) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+// fakeHTTPClient запоминает запрос и отвечает заданным статусом или ошибкой
+type fakeHTTPClient struct {
+	status   int
+	err      error
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (c *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	c.requests = append(c.requests, req)
+	c.bodies = append(c.bodies, body)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &http.Response{StatusCode: c.status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func testDispatcherConfig() WebhookDispatcherConfig {
+	return WebhookDispatcherConfig{
+		PollInterval:   time.Second,
+		BatchSize:      10,
+		MaxAttempts:    3,
+		BaseBackoff:    time.Second,
+		MaxBackoff:     time.Minute,
+		RequestTimeout: time.Second,
+	}
+}
+
+func deliveryJob(attempt int) *result.DeliveryJob {
+	return &result.DeliveryJob{
+		DeliveryId: 5,
+		WebhookId:  "hook1",
+		Url:        "http://example.test/hook",
+		Secret:     "0123456789abcdef",
+		Attempt:    attempt,
+		Event: &domain.OutboxEvent{
+			Id:        42,
+			Type:      domain.EventReviewerAssigned,
+			Payload:   []byte(`{"pull_request_id":"pr1","user_id":"u2"}`),
+			CreatedAt: time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	assert.Equal(t, time.Second, backoffDelay(1, time.Second, time.Minute))
+	assert.Equal(t, 2*time.Second, backoffDelay(2, time.Second, time.Minute))
+	assert.Equal(t, 16*time.Second, backoffDelay(5, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, backoffDelay(10, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, backoffDelay(1000, time.Second, time.Minute))
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("secret", 1700000000, []byte(`{"id":1}`))
+
+	assert.True(t, strings.HasPrefix(signature, "sha256="))
+	assert.Len(t, signature, len("sha256=")+64)
+	assert.Equal(t, signature, SignWebhookPayload("secret", 1700000000, []byte(`{"id":1}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("other", 1700000000, []byte(`{"id":1}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("secret", 1700000001, []byte(`{"id":1}`)))
+}
+
+func TestWebhookDispatcher_DispatchOnce_Delivered(t *testing.T) {
+	repo := new(MockDeliveryRepository)
+	client := &fakeHTTPClient{status: http.StatusNoContent}
+	dispatcher := NewWebhookDispatcher(repo, client, testDispatcherConfig(), zap.NewNop())
+	dispatcher.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	repo.On("ClaimDeliveries", mock.Anything, 10, 2*time.Second).Return([]*result.DeliveryJob{deliveryJob(1)}, nil)
+	repo.On("MarkDelivered", mock.Anything, int64(5)).Return(nil)
+
+	processed, err := dispatcher.DispatchOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Len(t, client.requests, 1)
+
+	req := client.requests[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, domain.EventReviewerAssigned, req.Header.Get(WebhookEventHeader))
+	assert.Equal(t, "5", req.Header.Get(WebhookDeliveryHeader))
+	assert.Equal(t, "1700000000", req.Header.Get(WebhookTimestampHeader))
+	assert.Equal(t, SignWebhookPayload("0123456789abcdef", 1700000000, client.bodies[0]), req.Header.Get(WebhookSignatureHeader))
+
+	var envelope map[string]any
+	assert.NoError(t, json.Unmarshal(client.bodies[0], &envelope))
+	assert.Equal(t, float64(42), envelope["id"])
+	assert.Equal(t, domain.EventReviewerAssigned, envelope["type"])
+	assert.Equal(t, "pr1", envelope["data"].(map[string]any)["pull_request_id"])
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcher_DispatchOnce_RetriesWithBackoff(t *testing.T) {
+	repo := new(MockDeliveryRepository)
+	client := &fakeHTTPClient{status: http.StatusInternalServerError}
+	dispatcher := NewWebhookDispatcher(repo, client, testDispatcherConfig(), zap.NewNop())
+
+	repo.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]*result.DeliveryJob{deliveryJob(2)}, nil)
+	repo.On("MarkFailed", mock.Anything, mock.MatchedBy(func(d *dto.DeliveryFailureDTO) bool {
+		return d.DeliveryId == 5 &&
+			d.Status == domain.WebhookDeliveryPending &&
+			d.RetryIn == 2*time.Second &&
+			strings.Contains(d.Error, "500")
+	})).Return(nil)
+
+	_, err := dispatcher.DispatchOnce(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkDelivered", mock.Anything, mock.Anything)
+}
+
+func TestWebhookDispatcher_DispatchOnce_DeadLetterAfterMaxAttempts(t *testing.T) {
+	repo := new(MockDeliveryRepository)
+	client := &fakeHTTPClient{err: errors.New("connection refused")}
+	dispatcher := NewWebhookDispatcher(repo, client, testDispatcherConfig(), zap.NewNop())
+
+	repo.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]*result.DeliveryJob{deliveryJob(3)}, nil)
+	repo.On("MarkFailed", mock.Anything, mock.MatchedBy(func(d *dto.DeliveryFailureDTO) bool {
+		return d.Status == domain.WebhookDeliveryDead && strings.Contains(d.Error, "connection refused")
+	})).Return(nil)
+
+	_, err := dispatcher.DispatchOnce(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcher_Run_StopsOnCancel(t *testing.T) {
+	repo := new(MockDeliveryRepository)
+	dispatcher := NewWebhookDispatcher(repo, &fakeHTTPClient{}, testDispatcherConfig(), zap.NewNop())
+
+	repo.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]*result.DeliveryJob{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("dispatcher did not stop after cancel")
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/response"
+	"go.uber.org/zap"
+)
+
+var (
+	createWebhookError  = errors.New("create webhook error")
+	listWebhooksError   = errors.New("list webhooks error")
+	deleteWebhookError  = errors.New("delete webhook error")
+	listDeliveriesError = errors.New("list webhook deliveries error")
+	redeliverError      = errors.New("redeliver webhook event error")
+)
+
+const (
+	// Минимальная длина секрета, заданного администратором
+	minWebhookSecretLength = 16
+	// Длина генерируемого секрета в байтах
+	generatedSecretBytes  = 32
+	defaultDeliveriesPage = 50
+	maxDeliveriesPage     = 500
+)
+
+var deliveryStatuses = []string{
+	domain.WebhookDeliveryPending,
+	domain.WebhookDeliveryDelivered,
+	domain.WebhookDeliveryDead,
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, d *dto.CreateWebhookDTO) (*domain.Webhook, error)
+	List(ctx context.Context) ([]*domain.Webhook, error)
+	Delete(ctx context.Context, d *dto.DeleteWebhookDTO) error
+	ListDeliveries(ctx context.Context, d *dto.ListDeliveriesDTO) ([]*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, d *dto.RedeliverDTO) (*domain.WebhookDelivery, error)
+}
+
+type WebhookService struct {
+	repo WebhookRepository
+	log  *zap.Logger
+}
+
+func NewWebhookService(repo WebhookRepository, log *zap.Logger) *WebhookService {
+	return &WebhookService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *WebhookService) Create(ctx context.Context, req *request.CreateWebhookRequest) (*response.WebhookResponse, error) {
+	s.log.Info("create webhook request accepted",
+		zap.String("url", req.Url),
+		zap.Strings("event_types", req.EventTypes),
+	)
+
+	// Проверяем адрес
+	rawUrl := strings.TrimSpace(req.Url)
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, WrapError(ErrInvalidWebhook, fmt.Errorf("url must be an absolute http(s) URL: %q", req.Url))
+	}
+
+	// Проверяем типы событий, дубликаты отбрасываем
+	eventTypes := make([]string, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !slices.Contains(domain.EventTypes, eventType) {
+			return nil, WrapError(ErrInvalidWebhook, fmt.Errorf("unknown event type %q", eventType))
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	// Секрет подписи: заданный администратором или сгенерированный
+	secret := strings.TrimSpace(req.Secret)
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", createWebhookError, err)
+		}
+	} else if len(secret) < minWebhookSecretLength {
+		return nil, WrapError(ErrInvalidWebhook, fmt.Errorf("secret must be at least %d characters", minWebhookSecretLength))
+	}
+
+	dto := &dto.CreateWebhookDTO{
+		Url:        rawUrl,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	}
+
+	// Запрос в бд
+	webhook, err := s.repo.Create(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to create webhook", zap.String("url", rawUrl), zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", createWebhookError, err)
+	}
+
+	s.log.Info("webhook created", zap.String("webhook_id", webhook.Id))
+
+	// Ответ, секрет показываем только один раз
+	resp := toWebhookResponse(webhook)
+	resp.Secret = webhook.Secret
+	return &resp, nil
+}
+
+func (s *WebhookService) List(ctx context.Context) (*response.ListWebhooksResponse, error) {
+	s.log.Info("list webhooks request accepted")
+
+	webhooks, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Error("failed to list webhooks", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", listWebhooksError, err)
+	}
+
+	resp := &response.ListWebhooksResponse{
+		Webhooks: make([]response.WebhookResponse, 0, len(webhooks)),
+	}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, toWebhookResponse(webhook))
+	}
+	return resp, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, req *request.DeleteWebhookRequest) error {
+	webhookId, err := normalizeID(req.WebhookId, "webhook_id")
+	if err != nil {
+		return WrapError(ErrWebhookNotFound, err)
+	}
+	s.log.Info("delete webhook request accepted", zap.String("webhook_id", webhookId))
+
+	if err := s.repo.Delete(ctx, &dto.DeleteWebhookDTO{WebhookId: webhookId}); err != nil {
+		s.log.Error("failed to delete webhook", zap.String("webhook_id", webhookId), zap.Error(err))
+		if errors.Is(err, repository.ErrNotFound) {
+			return WrapError(ErrWebhookNotFound, err)
+		}
+		return fmt.Errorf("%w: %w", deleteWebhookError, err)
+	}
+
+	s.log.Info("webhook deleted", zap.String("webhook_id", webhookId))
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, req *request.ListDeliveriesRequest) (*response.ListDeliveriesResponse, error) {
+	s.log.Info("list webhook deliveries request accepted",
+		zap.String("webhook_id", req.WebhookId),
+		zap.String("status", req.Status),
+	)
+
+	// Проверяем фильтры
+	status := strings.ToUpper(strings.TrimSpace(req.Status))
+	if status != "" && !slices.Contains(deliveryStatuses, status) {
+		return nil, WrapError(ErrInvalidDeliveryFilter, fmt.Errorf("unknown status %q", req.Status))
+	}
+	limit := defaultDeliveriesPage
+	if raw := strings.TrimSpace(req.Limit); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxDeliveriesPage {
+			return nil, WrapError(ErrInvalidDeliveryFilter, fmt.Errorf("limit must be between 1 and %d", maxDeliveriesPage))
+		}
+		limit = parsed
+	}
+
+	dto := &dto.ListDeliveriesDTO{
+		WebhookId: strings.TrimSpace(req.WebhookId),
+		Status:    status,
+		Limit:     limit,
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, dto)
+	if err != nil {
+		s.log.Error("failed to list webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", listDeliveriesError, err)
+	}
+
+	resp := &response.ListDeliveriesResponse{
+		Deliveries: make([]response.WebhookDeliveryResponse, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, toDeliveryResponse(delivery))
+	}
+	return resp, nil
+}
+
+// Redeliver ставит доставку в очередь заново, в том числе из dead-letter
+func (s *WebhookService) Redeliver(ctx context.Context, req *request.RedeliverRequest) (*response.WebhookDeliveryResponse, error) {
+	if req.DeliveryId <= 0 {
+		return nil, WrapError(ErrDeliveryNotFound, fmt.Errorf("%w: delivery_id must be positive", incorrectIdError))
+	}
+	s.log.Info("redeliver request accepted", zap.Int64("delivery_id", req.DeliveryId))
+
+	delivery, err := s.repo.Redeliver(ctx, &dto.RedeliverDTO{DeliveryId: req.DeliveryId})
+	if err != nil {
+		s.log.Error("failed to requeue webhook delivery", zap.Int64("delivery_id", req.DeliveryId), zap.Error(err))
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, WrapError(ErrDeliveryNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", redeliverError, err)
+	}
+
+	s.log.Info("webhook delivery requeued", zap.Int64("delivery_id", delivery.Id))
+	resp := toDeliveryResponse(delivery)
+	return &resp, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, generatedSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func toWebhookResponse(webhook *domain.Webhook) response.WebhookResponse {
+	eventTypes := webhook.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return response.WebhookResponse{
+		WebhookId:  webhook.Id,
+		Url:        webhook.Url,
+		EventTypes: eventTypes,
+		CreatedAt:  formatTime(webhook.CreatedAt),
+	}
+}
+
+func toDeliveryResponse(delivery *domain.WebhookDelivery) response.WebhookDeliveryResponse {
+	return response.WebhookDeliveryResponse{
+		DeliveryId:    delivery.Id,
+		WebhookId:     delivery.WebhookId,
+		EventId:       delivery.EventId,
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: formatTime(delivery.NextAttemptAt),
+		LastError:     delivery.LastError,
+		DeliveredAt:   formatTimePtr(delivery.DeliveredAt),
+		CreatedAt:     formatTime(delivery.CreatedAt),
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/domain"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/models/dto"
+	"github.com/niklvrr/AvitoInternship2025/internal/infrastructure/repository"
+	"github.com/niklvrr/AvitoInternship2025/internal/transport/dto/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockWebhookRepository мок репозитория вебхуков для тестов
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, d *dto.CreateWebhookDTO) (*domain.Webhook, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, d *dto.DeleteWebhookDTO) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, d *dto.ListDeliveriesDTO) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Redeliver(ctx context.Context, d *dto.RedeliverDTO) (*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+
+func TestWebhookService_Create_GeneratesSecret(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	service := NewWebhookService(mockRepo, zap.NewNop())
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *dto.CreateWebhookDTO) bool {
+		return d.Url == "https://bot.example.com/hook" &&
+			len(d.Secret) == 2*generatedSecretBytes &&
+			assert.Equal(t, []string{domain.EventReviewerAssigned}, d.EventTypes)
+	})).Return(&domain.Webhook{
+		Id:         "hook1",
+		Url:        "https://bot.example.com/hook",
+		Secret:     "generated",
+		EventTypes: []string{domain.EventReviewerAssigned},
+		CreatedAt:  time.Now(),
+	}, nil)
+
+	resp, err := service.Create(context.Background(), &request.CreateWebhookRequest{
+		Url:        " https://bot.example.com/hook ",
+		EventTypes: []string{domain.EventReviewerAssigned, domain.EventReviewerAssigned},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hook1", resp.WebhookId)
+	assert.Equal(t, "generated", resp.Secret)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_Create_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *request.CreateWebhookRequest
+	}{
+		{"relative url", &request.CreateWebhookRequest{Url: "/hook"}},
+		{"unsupported scheme", &request.CreateWebhookRequest{Url: "ftp://example.com/hook"}},
+		{"unknown event", &request.CreateWebhookRequest{Url: "https://example.com", EventTypes: []string{"pr.deleted"}}},
+		{"short secret", &request.CreateWebhookRequest{Url: "https://example.com", Secret: "short"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWebhookRepository)
+			service := NewWebhookService(mockRepo, zap.NewNop())
+
+			resp, err := service.Create(context.Background(), tt.req)
+
+			assert.Nil(t, resp)
+			var domainErr *DomainError
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWebhookService_List_HidesSecret(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	service := NewWebhookService(mockRepo, zap.NewNop())
+
+	mockRepo.On("List", mock.Anything).Return([]*domain.Webhook{
+		{Id: "hook1", Url: "https://example.com", Secret: "0123456789abcdef", CreatedAt: time.Now()},
+	}, nil)
+
+	resp, err := service.List(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Webhooks, 1)
+	assert.Empty(t, resp.Webhooks[0].Secret)
+	assert.Equal(t, []string{}, resp.Webhooks[0].EventTypes)
+}
+
+func TestWebhookService_ListDeliveries_Filters(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	service := NewWebhookService(mockRepo, zap.NewNop())
+
+	mockRepo.On("ListDeliveries", mock.Anything, &dto.ListDeliveriesDTO{
+		WebhookId: "hook1",
+		Status:    domain.WebhookDeliveryDead,
+		Limit:     defaultDeliveriesPage,
+	}).Return([]*domain.WebhookDelivery{}, nil)
+
+	resp, err := service.ListDeliveries(context.Background(), &request.ListDeliveriesRequest{
+		WebhookId: "hook1",
+		Status:    "dead",
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Deliveries)
+	mockRepo.AssertExpectations(t)
+
+	_, err = service.ListDeliveries(context.Background(), &request.ListDeliveriesRequest{Status: "LOST"})
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+
+	_, err = service.ListDeliveries(context.Background(), &request.ListDeliveriesRequest{Limit: "0"})
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "INVALID_INPUT", domainErr.Code)
+}
+
+func TestWebhookService_Delete_NotFound(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	service := NewWebhookService(mockRepo, zap.NewNop())
+
+	mockRepo.On("Delete", mock.Anything, &dto.DeleteWebhookDTO{WebhookId: "missing"}).Return(repository.ErrNotFound)
+
+	err := service.Delete(context.Background(), &request.DeleteWebhookRequest{WebhookId: "missing"})
+
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	service := NewWebhookService(mockRepo, zap.NewNop())
+
+	mockRepo.On("Redeliver", mock.Anything, &dto.RedeliverDTO{DeliveryId: 7}).Return(&domain.WebhookDelivery{
+		Id:            7,
+		WebhookId:     "hook1",
+		EventId:       3,
+		EventType:     domain.EventPrMerged,
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}, nil)
+
+	resp, err := service.Redeliver(context.Background(), &request.RedeliverRequest{DeliveryId: 7})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryPending, resp.Status)
+	assert.Nil(t, resp.DeliveredAt)
+
+	_, err = service.Redeliver(context.Background(), &request.RedeliverRequest{})
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "NOT_FOUND", domainErr.Code)
+}
@@ -0,0 +1,8 @@
+CREATE TABLE team_settings (
+    team_id TEXT PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    reviewer_count INT NOT NULL DEFAULT 2 CHECK (reviewer_count >= 0),
+    strategy VARCHAR(32),
+    lead_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    require_lead BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
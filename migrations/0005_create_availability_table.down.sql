@@ -0,0 +1 @@
+DROP TABLE IF EXISTS availability;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN max_open_reviews INT CHECK (max_open_reviews > 0);
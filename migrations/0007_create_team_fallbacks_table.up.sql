@@ -0,0 +1,9 @@
+CREATE TABLE team_fallbacks (
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    fallback_team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (team_id, fallback_team_id),
+    CHECK (team_id <> fallback_team_id)
+);
+
+CREATE INDEX idx_team_fallbacks_team_position ON team_fallbacks(team_id, position);
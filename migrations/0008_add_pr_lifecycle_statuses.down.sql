@@ -0,0 +1,11 @@
+-- Значения enum нельзя удалить, поэтому пересоздаем тип
+UPDATE prs SET status = 'OPEN' WHERE status::text IN ('DRAFT', 'CLOSED');
+
+ALTER TABLE prs DROP COLUMN IF EXISTS closed_at;
+
+ALTER TABLE prs ALTER COLUMN status DROP DEFAULT;
+ALTER TYPE pr_status RENAME TO pr_status_old;
+CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED');
+ALTER TABLE prs ALTER COLUMN status TYPE pr_status USING status::text::pr_status;
+ALTER TABLE prs ALTER COLUMN status SET DEFAULT 'OPEN';
+DROP TYPE pr_status_old;
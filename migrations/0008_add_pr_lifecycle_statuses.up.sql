@@ -0,0 +1,5 @@
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'DRAFT';
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'CLOSED';
+
+ALTER TABLE prs
+    ADD COLUMN closed_at TIMESTAMP DEFAULT NULL;
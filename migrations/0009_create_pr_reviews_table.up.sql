@@ -0,0 +1,14 @@
+CREATE TYPE review_verdict AS ENUM ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED');
+
+CREATE TABLE pr_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    verdict review_verdict NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pr_reviews_pr_reviewer ON pr_reviews(pr_id, reviewer_id, id);
+
+ALTER TABLE team_settings ADD COLUMN required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS assignment_events;
+
+DROP FUNCTION IF EXISTS assignment_events_append_only();
+
+DROP TYPE IF EXISTS assignment_event_type;
@@ -0,0 +1,26 @@
+CREATE TYPE assignment_event_type AS ENUM ('ASSIGN', 'UNASSIGN', 'REASSIGN');
+
+CREATE TABLE assignment_events (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES prs(id),
+    event_type assignment_event_type NOT NULL,
+    user_id TEXT NOT NULL,
+    previous_user_id TEXT,
+    actor VARCHAR(255) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_assignment_events_pr ON assignment_events(pr_id, id);
+
+-- Журнал только дополняется: изменение и удаление записей запрещены
+CREATE FUNCTION assignment_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'assignment_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER assignment_events_no_change
+    BEFORE UPDATE OR DELETE ON assignment_events
+    FOR EACH ROW EXECUTE FUNCTION assignment_events_append_only();
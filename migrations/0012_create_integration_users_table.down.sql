@@ -0,0 +1 @@
+DROP TABLE IF EXISTS integration_users;
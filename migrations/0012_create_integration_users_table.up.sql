@@ -0,0 +1,11 @@
+-- Сопоставление пользователей GitHub/GitLab с users.id.
+-- external_id - числовой идентификатор пользователя у провайдера, нужен API GitLab для назначения ревьюеров
+CREATE TABLE integration_users (
+    provider VARCHAR(16) NOT NULL,
+    external_login VARCHAR(255) NOT NULL,
+    external_id VARCHAR(64) NOT NULL DEFAULT '',
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, external_login),
+    UNIQUE (provider, user_id)
+);
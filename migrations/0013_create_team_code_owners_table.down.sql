@@ -0,0 +1,4 @@
+ALTER TABLE prs
+    DROP COLUMN IF EXISTS changed_paths;
+
+DROP TABLE IF EXISTS team_code_owners;
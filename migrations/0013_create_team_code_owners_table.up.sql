@@ -0,0 +1,10 @@
+CREATE TABLE team_code_owners (
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    pattern VARCHAR(255) NOT NULL,
+    owner_ids TEXT[] NOT NULL,
+    PRIMARY KEY (team_id, position)
+);
+
+ALTER TABLE prs
+    ADD COLUMN changed_paths TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,7 @@
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS require_tags;
+
+ALTER TABLE prs
+    DROP COLUMN IF EXISTS required_tags;
+
+DROP TABLE IF EXISTS user_tags;
@@ -0,0 +1,13 @@
+CREATE TABLE user_tags (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tag VARCHAR(64) NOT NULL,
+    PRIMARY KEY (user_id, tag)
+);
+
+CREATE INDEX idx_user_tags_tag ON user_tags(tag);
+
+ALTER TABLE prs
+    ADD COLUMN required_tags TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE team_settings
+    ADD COLUMN require_tags BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_prs_author_created;
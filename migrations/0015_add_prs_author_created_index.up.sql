@@ -0,0 +1,2 @@
+-- Последние PR автора читаются при каждом подборе ревьюеров
+CREATE INDEX idx_prs_author_created ON prs(author_id, created_at DESC);
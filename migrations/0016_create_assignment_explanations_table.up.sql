@@ -0,0 +1,12 @@
+CREATE TABLE assignment_explanations (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES prs(id),
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    team_name VARCHAR(255) NOT NULL DEFAULT '',
+    seed BIGINT NOT NULL,
+    reviewers TEXT[] NOT NULL DEFAULT '{}',
+    pools JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_assignment_explanations_pr ON assignment_explanations(pr_id, id);
@@ -0,0 +1,6 @@
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS escalated_at;
+
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS sla_policy,
+    DROP COLUMN IF EXISTS review_sla_hours;
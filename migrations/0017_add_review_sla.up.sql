@@ -0,0 +1,7 @@
+ALTER TABLE team_settings
+    ADD COLUMN review_sla_hours INT NOT NULL DEFAULT 0 CHECK (review_sla_hours >= 0),
+    ADD COLUMN sla_policy VARCHAR(32) NOT NULL DEFAULT 'notify';
+
+-- Момент эскалации просроченного ревью, чтобы не эскалировать его повторно
+ALTER TABLE pr_reviewers
+    ADD COLUMN escalated_at TIMESTAMP DEFAULT NULL;
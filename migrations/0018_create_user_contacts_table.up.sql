@@ -0,0 +1,7 @@
+CREATE TABLE user_contacts (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    chat_handle VARCHAR(255) NOT NULL DEFAULT '',
+    digest_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,30 @@
+ALTER TABLE team_members
+    ADD COLUMN is_primary BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Основной становится команда из users.team_name, а если ее нет - команда, в которую пользователь вступил последней
+UPDATE team_members tm
+SET is_primary = TRUE
+FROM (
+    SELECT DISTINCT ON (tm.user_id) tm.user_id, tm.team_id
+    FROM team_members tm
+    JOIN teams t ON t.id = tm.team_id
+    JOIN users u ON u.id = tm.user_id
+    ORDER BY tm.user_id, (t.name = u.team_name) DESC, tm.joined_at DESC, tm.team_id ASC
+) p
+WHERE tm.user_id = p.user_id AND tm.team_id = p.team_id;
+
+-- У пользователя не больше одной основной команды
+CREATE UNIQUE INDEX idx_team_members_primary ON team_members(user_id) WHERE is_primary;
+
+-- users.team_name повторяет основную команду
+UPDATE users u
+SET team_name = (
+    SELECT t.name
+    FROM team_members tm
+    JOIN teams t ON t.id = tm.team_id
+    WHERE tm.user_id = u.id AND tm.is_primary
+);
+
+-- Команда, из которой подбираются ревьюеры PR, NULL - основная команда автора
+ALTER TABLE prs
+    ADD COLUMN team_id TEXT REFERENCES teams(id) ON DELETE SET NULL;
@@ -0,0 +1,7 @@
+-- Родительская команда, NULL - команда верхнего уровня.
+-- При удалении родителя подкоманды становятся командами верхнего уровня
+ALTER TABLE teams
+    ADD COLUMN parent_id TEXT REFERENCES teams(id) ON DELETE SET NULL,
+    ADD CONSTRAINT teams_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX idx_teams_parent_id ON teams(parent_id);
@@ -0,0 +1,7 @@
+ALTER TABLE prs
+    DROP COLUMN IF EXISTS lead_reviewer_id,
+    DROP COLUMN IF EXISTS requires_lead_review;
+
+ALTER TABLE team_members
+    DROP CONSTRAINT IF EXISTS team_members_role_check,
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,15 @@
+-- Роль участника в команде. Лид команды ревьюит PR с обязательным ревью лида
+ALTER TABLE team_members
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'MEMBER',
+    ADD CONSTRAINT team_members_role_check CHECK (role IN ('MEMBER', 'LEAD'));
+
+-- Лиды из настроек команд становятся лидами по роли
+UPDATE team_members tm
+SET role = 'LEAD'
+FROM team_settings ts
+WHERE ts.team_id = tm.team_id AND ts.lead_user_id = tm.user_id;
+
+-- PR требует ревью лида команды, lead_reviewer_id - назначенный лид
+ALTER TABLE prs
+    ADD COLUMN requires_lead_review BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN lead_reviewer_id TEXT REFERENCES users(id) ON DELETE SET NULL;
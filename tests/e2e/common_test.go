@@ -75,15 +75,30 @@ func TestMain(m *testing.M) {
 	userRepo := repository.NewUserRepository(database, log)
 	teamRepo := repository.NewTeamRepository(database, log)
 	prRepo := repository.NewPrRepository(database, log)
+	webhookRepo := repository.NewWebhookRepository(database, log)
+	integrationRepo := repository.NewIntegrationRepository(database, log)
 
-	userService := service.NewUserService(userRepo, log)
-	teamService := service.NewTeamService(teamRepo, log)
-	prService := service.NewPrService(prRepo, log)
+	selectors, err := service.NewReviewerSelectors(service.StrategyLeastLoaded, nil, 0)
+	if err != nil {
+		panic(fmt.Sprintf("failed to init reviewer selectors: %v", err))
+	}
+
+	eventBus := service.NewEventBus(0, 0, log)
+
+	userService := service.NewUserService(userRepo, selectors, nil, log)
+	teamService := service.NewTeamService(teamRepo, selectors, nil, log)
+	prService := service.NewPrService(prRepo, selectors, eventBus, log)
+	webhookService := service.NewWebhookService(webhookRepo, log)
+	eventStreamService := service.NewEventStreamService(eventBus, teamRepo, log)
+	integrationService := service.NewIntegrationService(integrationRepo, prService, service.IntegrationConfig{}, log)
 
 	userHandler := handler.NewUserHandler(userService, log)
 	teamHandler := handler.NewTeamHandler(teamService, log)
 	prHandler := handler.NewPrHandler(prService, log)
 	statsHandler := handler.NewStatsHandler(prService, log)
+	webhookHandler := handler.NewWebhookHandler(webhookService, log)
+	eventsHandler := handler.NewEventsHandler(eventStreamService, log)
+	integrationHandler := handler.NewIntegrationHandler(integrationService, log)
 	healthHandler := handler.NewHealthHandler(log)
 
 	router := transport.NewRouter(
@@ -91,6 +106,9 @@ func TestMain(m *testing.M) {
 		teamHandler,
 		prHandler,
 		statsHandler,
+		webhookHandler,
+		eventsHandler,
+		integrationHandler,
 		healthHandler,
 		log,
 	)
@@ -106,10 +124,24 @@ func TestMain(m *testing.M) {
 		}
 	}()
 
+	// Диспетчер вебхуков с коротким опросом, чтобы доставки из outbox уходили во время теста
+	dispatcherCtx, stopDispatcher := context.WithCancel(ctx)
+	dispatcher := service.NewWebhookDispatcher(webhookRepo, &http.Client{}, service.WebhookDispatcherConfig{
+		PollInterval:   100 * time.Millisecond,
+		BatchSize:      10,
+		MaxAttempts:    3,
+		BaseBackoff:    100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		RequestTimeout: 2 * time.Second,
+	}, log)
+	go dispatcher.Run(dispatcherCtx)
+
 	time.Sleep(500 * time.Millisecond)
 
 	code := m.Run()
 
+	stopDispatcher()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	testServer.Shutdown(ctx)
@@ -171,3 +171,209 @@ func TestTeam_Get_MissingTeamName(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
+
+func TestTeam_SetParent_RejectsCycle(t *testing.T) {
+	for _, teamName := range []string{"e2e-team-cycle-root", "e2e-team-cycle-child", "e2e-team-cycle-grandchild"} {
+		createResp := makeRequest(t, http.MethodPost, baseURL+"/team/add", map[string]interface{}{
+			"team_name": teamName,
+			"members":   []interface{}{},
+		})
+		createResp.Body.Close()
+		require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	}
+
+	// root <- child <- grandchild
+	for _, link := range [][2]string{
+		{"e2e-team-cycle-child", "e2e-team-cycle-root"},
+		{"e2e-team-cycle-grandchild", "e2e-team-cycle-child"},
+	} {
+		resp := makeRequest(t, http.MethodPost, baseURL+"/team/setParent", map[string]interface{}{
+			"team_name":        link[0],
+			"parent_team_name": link[1],
+		})
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// Корень не может стать подкомандой своего потомка
+	resp := makeRequest(t, http.MethodPost, baseURL+"/team/setParent", map[string]interface{}{
+		"team_name":        "e2e-team-cycle-root",
+		"parent_team_name": "e2e-team-cycle-grandchild",
+	})
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	errorResp := parseErrorResponse(t, resp)
+	errorObj, ok := errorResp["error"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "INVALID_INPUT", errorObj["code"])
+
+	// Иерархия не изменилась
+	getResp := makeRequest(t, http.MethodGet, baseURL+"/team/get?team_name=e2e-team-cycle-root", nil)
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var team map[string]interface{}
+	parseJSONResponse(t, getResp, &team)
+	assert.Nil(t, team["parent_team_name"])
+	subteams, ok := team["subteams"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, subteams, 1)
+	child, ok := subteams[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "e2e-team-cycle-child", child["team_name"])
+}
+
+func TestTeam_DeactivateMembers_ReassignsReviews(t *testing.T) {
+	teamReq := map[string]interface{}{
+		"team_name": "e2e-team-bulk",
+		"members": []map[string]interface{}{
+			{"user_id": "e2e-u-bulk-author", "username": "BulkAuthor", "is_active": true},
+			{"user_id": "e2e-u-bulk-1", "username": "Bulk1", "is_active": true},
+			{"user_id": "e2e-u-bulk-2", "username": "Bulk2", "is_active": true},
+			{"user_id": "e2e-u-bulk-3", "username": "Bulk3", "is_active": true},
+		},
+	}
+
+	createTeamResp := makeRequest(t, http.MethodPost, baseURL+"/team/add", teamReq)
+	createTeamResp.Body.Close()
+	require.Equal(t, http.StatusCreated, createTeamResp.StatusCode)
+
+	prReq := map[string]interface{}{
+		"pull_request_id":   "e2e-pr-bulk",
+		"pull_request_name": "Bulk PR",
+		"author_id":         "e2e-u-bulk-author",
+	}
+
+	createPrResp := makeRequest(t, http.MethodPost, baseURL+"/pullRequest/create", prReq)
+	require.Equal(t, http.StatusCreated, createPrResp.StatusCode)
+
+	var prResult map[string]interface{}
+	parseJSONResponse(t, createPrResp, &prResult)
+	pr, ok := prResult["pr"].(map[string]interface{})
+	require.True(t, ok)
+	assignedReviewers, ok := pr["assigned_reviewers"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, assignedReviewers, 2)
+
+	// Единственный свободный участник становится заменой выключенного ревьюера
+	leaving := assignedReviewers[0].(string)
+	replacement := ""
+	for _, userID := range []string{"e2e-u-bulk-1", "e2e-u-bulk-2", "e2e-u-bulk-3"} {
+		if userID != assignedReviewers[0] && userID != assignedReviewers[1] {
+			replacement = userID
+		}
+	}
+	require.NotEmpty(t, replacement)
+
+	resp := makeRequest(t, http.MethodPost, baseURL+"/team/deactivateMembers", map[string]interface{}{
+		"team_name": "e2e-team-bulk",
+		"user_ids":  []string{leaving},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	parseJSONResponse(t, resp, &result)
+	assert.Equal(t, []interface{}{leaving}, result["deactivated_user_ids"])
+
+	reviews, ok := result["reviews"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, reviews, 1)
+	review, ok := reviews[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "e2e-pr-bulk", review["pull_request_id"])
+	assert.Equal(t, leaving, review["old_reviewer_id"])
+	assert.Equal(t, "REASSIGNED", review["status"])
+	assert.Equal(t, replacement, review["replaced_by"])
+
+	// Замена сохранена в бд
+	reviewResp := makeRequest(t, http.MethodGet, baseURL+"/users/getReview?user_id="+replacement, nil)
+	require.Equal(t, http.StatusOK, reviewResp.StatusCode)
+
+	var reviewResult map[string]interface{}
+	parseJSONResponse(t, reviewResp, &reviewResult)
+	prs, ok := reviewResult["pull_requests"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "e2e-pr-bulk", prs[0].(map[string]interface{})["pull_request_id"])
+
+	leftResp := makeRequest(t, http.MethodGet, baseURL+"/users/getReview?user_id="+leaving, nil)
+	require.Equal(t, http.StatusOK, leftResp.StatusCode)
+
+	var leftResult map[string]interface{}
+	parseJSONResponse(t, leftResp, &leftResult)
+	assert.Empty(t, leftResult["pull_requests"])
+}
+
+func TestTeam_RemoveMember_LeadGuards(t *testing.T) {
+	teamReq := map[string]interface{}{
+		"team_name": "e2e-team-leads",
+		"members": []map[string]interface{}{
+			{"user_id": "e2e-u-settings-lead", "username": "SettingsLead", "is_active": true},
+			{"user_id": "e2e-u-role-lead", "username": "RoleLead", "is_active": true},
+			{"user_id": "e2e-u-lead-member", "username": "LeadMember", "is_active": true},
+		},
+	}
+
+	createTeamResp := makeRequest(t, http.MethodPost, baseURL+"/team/add", teamReq)
+	createTeamResp.Body.Close()
+	require.Equal(t, http.StatusCreated, createTeamResp.StatusCode)
+
+	settingsResp := makeRequest(t, http.MethodPost, baseURL+"/team/settings", map[string]interface{}{
+		"team_name":    "e2e-team-leads",
+		"lead_user_id": "e2e-u-settings-lead",
+	})
+	settingsResp.Body.Close()
+	require.Equal(t, http.StatusOK, settingsResp.StatusCode)
+
+	roleResp := makeRequest(t, http.MethodPost, baseURL+"/team/setMemberRole", map[string]interface{}{
+		"team_name": "e2e-team-leads",
+		"user_id":   "e2e-u-role-lead",
+		"role":      "LEAD",
+	})
+	roleResp.Body.Close()
+	require.Equal(t, http.StatusOK, roleResp.StatusCode)
+
+	// Лида из настроек и лида по роли исключить нельзя
+	for _, userID := range []string{"e2e-u-settings-lead", "e2e-u-role-lead"} {
+		resp := makeRequest(t, http.MethodPost, baseURL+"/team/removeMember", map[string]interface{}{
+			"team_name": "e2e-team-leads",
+			"user_id":   userID,
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, userID)
+
+		errorResp := parseErrorResponse(t, resp)
+		errorObj, ok := errorResp["error"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "INVALID_INPUT", errorObj["code"], userID)
+	}
+
+	// Лида из настроек нельзя понизить до участника
+	demoteResp := makeRequest(t, http.MethodPost, baseURL+"/team/setMemberRole", map[string]interface{}{
+		"team_name": "e2e-team-leads",
+		"user_id":   "e2e-u-settings-lead",
+		"role":      "MEMBER",
+	})
+	assert.Equal(t, http.StatusBadRequest, demoteResp.StatusCode)
+	demoteResp.Body.Close()
+
+	// Обычный участник исключается
+	resp := makeRequest(t, http.MethodPost, baseURL+"/team/removeMember", map[string]interface{}{
+		"team_name": "e2e-team-leads",
+		"user_id":   "e2e-u-lead-member",
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	parseJSONResponse(t, resp, &result)
+	assert.Equal(t, []interface{}{"e2e-u-lead-member"}, result["removed_user_ids"])
+
+	getResp := makeRequest(t, http.MethodGet, baseURL+"/team/get?team_name=e2e-team-leads", nil)
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var team map[string]interface{}
+	parseJSONResponse(t, getResp, &team)
+	members, ok := team["members"].([]interface{})
+	require.True(t, ok)
+	assert.Len(t, members, 2)
+}
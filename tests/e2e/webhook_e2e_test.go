@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/niklvrr/AvitoInternship2025/internal/usecase/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func TestWebhook_OutboxDelivery(t *testing.T) {
+	received := make(chan receivedWebhook, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedWebhook{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	webhookReq := map[string]interface{}{
+		"url":         receiver.URL,
+		"secret":      "e2e-secret",
+		"event_types": []string{"pr.created"},
+	}
+
+	createWebhookResp := makeRequest(t, http.MethodPost, baseURL+"/webhooks/create", webhookReq)
+	require.Equal(t, http.StatusCreated, createWebhookResp.StatusCode)
+
+	var webhookResult map[string]interface{}
+	parseJSONResponse(t, createWebhookResp, &webhookResult)
+	webhook, ok := webhookResult["webhook"].(map[string]interface{})
+	require.True(t, ok)
+	webhookID, ok := webhook["webhook_id"].(string)
+	require.True(t, ok)
+
+	// Удаляем вебхук, чтобы PR из других тестов не доставлялись на закрытый сервер
+	defer func() {
+		resp := makeRequest(t, http.MethodPost, baseURL+"/webhooks/delete", map[string]interface{}{"webhook_id": webhookID})
+		resp.Body.Close()
+	}()
+
+	teamReq := map[string]interface{}{
+		"team_name": "e2e-team-webhook",
+		"members": []map[string]interface{}{
+			{"user_id": "e2e-u-webhook-author", "username": "WebhookAuthor", "is_active": true},
+			{"user_id": "e2e-u-webhook-reviewer", "username": "WebhookReviewer", "is_active": true},
+		},
+	}
+
+	createTeamResp := makeRequest(t, http.MethodPost, baseURL+"/team/add", teamReq)
+	createTeamResp.Body.Close()
+	require.Equal(t, http.StatusCreated, createTeamResp.StatusCode)
+
+	prReq := map[string]interface{}{
+		"pull_request_id":   "e2e-pr-webhook",
+		"pull_request_name": "Webhook PR",
+		"author_id":         "e2e-u-webhook-author",
+	}
+
+	createPrResp := makeRequest(t, http.MethodPost, baseURL+"/pullRequest/create", prReq)
+	createPrResp.Body.Close()
+	require.Equal(t, http.StatusCreated, createPrResp.StatusCode)
+
+	var delivery receivedWebhook
+	select {
+	case delivery = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	assert.Equal(t, "pr.created", delivery.header.Get(service.WebhookEventHeader))
+	timestamp, err := strconv.ParseInt(delivery.header.Get(service.WebhookTimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t,
+		service.SignWebhookPayload("e2e-secret", timestamp, delivery.body),
+		delivery.header.Get(service.WebhookSignatureHeader),
+	)
+
+	var envelope map[string]interface{}
+	require.NoError(t, json.Unmarshal(delivery.body, &envelope))
+	assert.Equal(t, "pr.created", envelope["type"])
+	data, ok := envelope["data"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "e2e-pr-webhook", data["pull_request_id"])
+	assert.Equal(t, "e2e-u-webhook-author", data["author_id"])
+	assert.Equal(t, []interface{}{"e2e-u-webhook-reviewer"}, data["assigned_reviewers"])
+
+	// Доставка отмечена как выполненная после ответа 200
+	require.Eventually(t, func() bool {
+		resp := makeRequest(t, http.MethodGet, baseURL+"/webhooks/deliveries?webhook_id="+webhookID, nil)
+		var result map[string]interface{}
+		parseJSONResponse(t, resp, &result)
+		deliveries, ok := result["deliveries"].([]interface{})
+		if !ok || len(deliveries) != 1 {
+			return false
+		}
+		d, ok := deliveries[0].(map[string]interface{})
+		return ok && d["status"] == "DELIVERED" && d["event_type"] == "pr.created"
+	}, 5*time.Second, 100*time.Millisecond)
+}